@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli"
+)
+
+// BacktestCommand replays a range of historical L2 blocks through the gas
+// pricer using the configured pricing parameters
+var BacktestCommand = cli.Command{
+	Name:  "backtest",
+	Usage: "Replay a block range through the pricing algorithm",
+	Description: "Fetches the blocks in the range from an archive node at " +
+		"--layer-two-http-url and reports the resulting L2 gas price " +
+		"trajectory, the number of updates and the gas spent by the owner.",
+	Flags:  flags.BacktestFlags,
+	Action: backtest,
+}
+
+func backtest(ctx *cli.Context) error {
+	if !ctx.IsSet(flags.BacktestStartBlockFlag.Name) || !ctx.IsSet(flags.BacktestEndBlockFlag.Name) {
+		return errors.New("both --start-block and --end-block must be set")
+	}
+
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if cfg.MetricsPushgatewayURL != "" {
+		defer PushMetrics(cfg)
+	}
+	btCfg := &oracle.BacktestConfig{
+		StartBlock:  ctx.Uint64(flags.BacktestStartBlockFlag.Name),
+		EndBlock:    ctx.Uint64(flags.BacktestEndBlockFlag.Name),
+		UpdateTxGas: ctx.Uint64(flags.BacktestUpdateTxGasFlag.Name),
+	}
+	if ctx.IsSet(flags.BacktestInitialGasPriceFlag.Name) {
+		initial, err := gasprices.ParseWei(ctx.String(flags.BacktestInitialGasPriceFlag.Name))
+		if err != nil {
+			return fmt.Errorf("option %q: %w", flags.BacktestInitialGasPriceFlag.Name, err)
+		}
+		btCfg.InitialGasPrice = initial
+	}
+
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result, err := oracle.Backtest(cfg, client, btCfg)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(flags.JSONFlag.Name) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "BLOCK\tTIME\tGAS/SEC\tCOMPUTED\tGAS PRICE\tUPDATED\tREASON")
+	for _, epoch := range result.Epochs {
+		reason := string(epoch.Reason)
+		if epoch.Bound != "" {
+			reason += "," + string(epoch.Bound)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%.0f\t%s\t%s\t%t\t%s\n", epoch.BlockNumber,
+			epoch.Time.UTC().Format(time.RFC3339), epoch.AvgGasPerSecond,
+			epoch.ComputedPrice, epoch.GasPrice, epoch.Updated, reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Epochs:          %d\n", len(result.Epochs))
+	fmt.Printf("Updates:         %d\n", result.Updates)
+	fmt.Printf("Owner gas spend: %s wei\n", result.OwnerGasSpend)
+	fmt.Printf("Gas price:       %s -> %s (min %s, max %s)\n", result.InitialGasPrice,
+		result.FinalGasPrice, result.MinGasPrice, result.MaxGasPrice)
+	return nil
+}
+
+// PushMetrics pushes the final metrics of a short-lived run to the
+// Pushgateway, so that failed runs leave a trail too
+func PushMetrics(cfg *oracle.Config) {
+	if !cfg.MetricsEnabled {
+		log.Warn("Metrics are not enabled, pass --metrics to push them")
+		return
+	}
+	err := ometrics.Push(ometrics.DefaultRegistry, cfg.MetricsPushgatewayURL, cfg.MetricsPushgatewayJob)
+	if err != nil {
+		log.Error("cannot push metrics", "url", cfg.MetricsPushgatewayURL, "message", err)
+		return
+	}
+	log.Info("Pushed metrics", "url", cfg.MetricsPushgatewayURL, "job", cfg.MetricsPushgatewayJob)
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/urfave/cli"
+)
+
+// CheckCommand runs the preflight checks of a deploy
+var CheckCommand = cli.Command{
+	Name:  "check",
+	Usage: "Check that the oracle can run with the configuration",
+	Description: "Checks that --ethereum-http-url and --layer-two-http-url respond, that " +
+		"their chain IDs match the configuration, that the gas price oracle is deployed at " +
+		"--gas-price-oracle-address, that the signer is its owner and that the balance of the " +
+		"signer is at least --min-balance. Exits with 2 for an invalid configuration, 3 and 4 " +
+		"for the L1 and L2 RPC, 5 for the chain IDs, 6 for the contract, 7 for a missing " +
+		"signer, 8 for the owner and 9 for the balance.",
+	Flags:  flags.CheckFlags,
+	Action: check,
+}
+
+func check(ctx *cli.Context) error {
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return cli.NewExitError(err.Error(), oracle.PreflightConfig)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cli.NewExitError(err.Error(), oracle.PreflightConfig)
+	}
+	var minBalance *big.Int
+	if value := ctx.String(flags.CheckMinBalanceFlag.Name); value != "" {
+		if minBalance, err = gasprices.ParseWei(value); err != nil {
+			return cli.NewExitError(fmt.Sprintf("option %q: %v", flags.CheckMinBalanceFlag.Name, err),
+				oracle.PreflightConfig)
+		}
+	}
+
+	l1Client, err := cfg.Dial(cfg.EthereumHttpUrl())
+	if err != nil {
+		return cli.NewExitError(err.Error(), oracle.PreflightL1RPC)
+	}
+	defer l1Client.Close()
+	l2Client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return cli.NewExitError(err.Error(), oracle.PreflightL2RPC)
+	}
+	defer l2Client.Close()
+
+	report := oracle.Preflight(context.Background(), cfg, l1Client, l2Client, minBalance)
+
+	if ctx.Bool(flags.JSONFlag.Name) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		for _, c := range report.Checks {
+			result := "ok"
+			if c.Skipped {
+				result = "skip"
+			} else if !c.Passed {
+				result = "FAIL"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", result, c.Name, c.Detail)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if code := report.Code(); code != oracle.PreflightOK {
+		return cli.NewExitError("preflight checks failed", code)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli"
+)
+
+// EstimateFeeCommand estimates the total fee of an L2 transaction
+var EstimateFeeCommand = cli.Command{
+	Name:  "estimate-fee",
+	Usage: "Estimate the L2 fee of a transaction",
+	Description: "Computes the execution fee at the L2 gas price and the L1 data fee of " +
+		"a transaction with the current parameters of the gas price oracle. Pass the " +
+		"serialized transaction with --tx, or its size with --calldata-size along with " +
+		"--gas-limit, in which case every byte is assumed to be non-zero, which is the " +
+		"highest L1 data fee of a transaction of that size.",
+	Flags:  flags.EstimateFeeFlags,
+	Action: estimateFee,
+}
+
+func estimateFee(ctx *cli.Context) error {
+	hasTx := ctx.IsSet(flags.FeeTxFlag.Name)
+	if hasTx == ctx.IsSet(flags.FeeCalldataSizeFlag.Name) {
+		return errors.New("exactly one of --tx and --calldata-size must be set")
+	}
+
+	var data []byte
+	gasLimit := ctx.Uint64(flags.FeeGasLimitFlag.Name)
+	if hasTx {
+		var err error
+		data, err = hexutil.Decode(ctx.String(flags.FeeTxFlag.Name))
+		if err != nil {
+			return fmt.Errorf("option %q: %w", flags.FeeTxFlag.Name, err)
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(data); err == nil && !ctx.IsSet(flags.FeeGasLimitFlag.Name) {
+			gasLimit = tx.Gas()
+		}
+	} else {
+		data = bytes.Repeat([]byte{0xff}, int(ctx.Uint64(flags.FeeCalldataSizeFlag.Name)))
+	}
+	if gasLimit == 0 {
+		return errors.New("--gas-limit must be set when it cannot be read from --tx")
+	}
+
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	estimate, err := oracle.EstimateFee(context.Background(), cfg, client, data, gasLimit)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(flags.JSONFlag.Name) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(estimate)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Gas limit:\t%d\n", estimate.GasLimit)
+	fmt.Fprintf(w, "L2 gas price:\t%s wei (%s gwei)\n", estimate.GasPrice, formatUnit(estimate.GasPrice, params.GWei))
+	fmt.Fprintf(w, "Execution fee:\t%s wei (%s ether)\n", estimate.ExecutionFee, formatUnit(estimate.ExecutionFee, params.Ether))
+	fmt.Fprintf(w, "L1 gas used:\t%s\n", estimate.L1GasUsed)
+	fmt.Fprintf(w, "L1 base fee:\t%s wei (%s gwei)\n", estimate.L1BaseFee, formatUnit(estimate.L1BaseFee, params.GWei))
+	fmt.Fprintf(w, "L1 data fee:\t%s wei (%s ether)\n", estimate.L1Fee, formatUnit(estimate.L1Fee, params.Ether))
+	fmt.Fprintf(w, "Total fee:\t%s wei (%s ether)\n", estimate.TotalFee, formatUnit(estimate.TotalFee, params.Ether))
+	return w.Flush()
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/urfave/cli"
+)
+
+// ExportDecisionsCommand exports the decisions that are recorded in the
+// state database
+var ExportDecisionsCommand = cli.Command{
+	Name:  "export-decisions",
+	Usage: "Export the recorded decisions of the epochs as CSV or JSON",
+	Description: "Reads the decisions of the epochs from --from to --to that are recorded in " +
+		"the database of --state-db. The JSON export is a demand trace for backtest and " +
+		"simulate. The signed format exports the records that the oracle signed with its " +
+		"key, for verify-decisions. The database is locked while the oracle runs, use the " +
+		"/decision-history of the admin API then.",
+	Flags:  flags.ExportDecisionsFlags,
+	Action: exportDecisions,
+}
+
+func exportDecisions(ctx *cli.Context) error {
+	format := ctx.String(flags.DecisionsFormatFlag.Name)
+	if format != "csv" && format != "json" && format != "signed" {
+		return fmt.Errorf("option %q: invalid format: %q", flags.DecisionsFormatFlag.Name, format)
+	}
+	path := ctx.GlobalString(flags.StateDBFlag.Name)
+	if path == "" {
+		return errors.New("--state-db must be set")
+	}
+	var from time.Time
+	if v := ctx.String(flags.DecisionsFromFlag.Name); v != "" {
+		t, err := oracle.ParseHistoryTime(v)
+		if err != nil {
+			return fmt.Errorf("option %q: %w", flags.DecisionsFromFlag.Name, err)
+		}
+		from = t
+	}
+	to := time.Now()
+	if v := ctx.String(flags.DecisionsToFlag.Name); v != "" {
+		t, err := oracle.ParseHistoryTime(v)
+		if err != nil {
+			return fmt.Errorf("option %q: %w", flags.DecisionsToFlag.Name, err)
+		}
+		to = t
+	}
+
+	db, err := store.OpenStateDBReadOnly(path)
+	if err != nil {
+		return fmt.Errorf("cannot open %s: %w", path, err)
+	}
+	defer db.Close()
+	var write func(io.Writer) error
+	var n int
+	if format == "signed" {
+		decisions, err := oracle.ReadSignedDecisions(db, from, to)
+		if err != nil {
+			return err
+		}
+		n = len(decisions)
+		write = func(w io.Writer) error { return oracle.WriteSignedDecisions(w, decisions) }
+	} else {
+		decisions, err := oracle.ReadDecisionHistory(db, from, to)
+		if err != nil {
+			return err
+		}
+		n = len(decisions)
+		write = func(w io.Writer) error { return oracle.WriteDecisions(w, format, decisions) }
+	}
+
+	output := ctx.String(flags.HistoryOutputFlag.Name)
+	if output == "-" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d decisions to %s\n", n, output)
+	return nil
+}
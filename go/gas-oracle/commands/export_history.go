@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/urfave/cli"
+)
+
+// ExportHistoryCommand exports the updates of the gas price oracle
+var ExportHistoryCommand = cli.Command{
+	Name:  "export-history",
+	Usage: "Export the updates of the gas price oracle as CSV or JSON",
+	Description: "Scans the blocks from --start-block to --end-block of --layer-two-http-url " +
+		"for the GasPriceUpdated and L1BaseFeeUpdated events of the gas price oracle and " +
+		"exports the block, the timestamp, the old and the new value and the transaction " +
+		"hash of each. The old value of the first update of each kind is read from the " +
+		"previous block, which requires an archive node. With --source postgres the updates " +
+		"that the oracle recorded in the database of --postgres.url are exported instead, " +
+		"with the time that their receipt was seen as the timestamp.",
+	Flags:  flags.ExportHistoryFlags,
+	Action: exportHistory,
+}
+
+func exportHistory(ctx *cli.Context) error {
+	format := ctx.String(flags.HistoryFormatFlag.Name)
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("option %q: invalid format: %q", flags.HistoryFormatFlag.Name, format)
+	}
+
+	source := ctx.String(flags.HistorySourceFlag.Name)
+	if source != "chain" && source != "postgres" {
+		return fmt.Errorf("option %q: invalid source: %q", flags.HistorySourceFlag.Name, source)
+	}
+
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	var events []*oracle.HistoryEvent
+	if source == "postgres" {
+		end := uint64(math.MaxInt64)
+		if ctx.IsSet(flags.HistoryEndBlockFlag.Name) {
+			end = ctx.Uint64(flags.HistoryEndBlockFlag.Name)
+		}
+		events, err = oracle.FetchStoredHistory(context.Background(), cfg,
+			ctx.Uint64(flags.HistoryStartBlockFlag.Name), end)
+	} else {
+		events, err = fetchChainHistory(ctx, cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	path := ctx.String(flags.HistoryOutputFlag.Name)
+	if path == "-" {
+		return writeHistory(os.Stdout, format, events)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeHistory(file, format, events); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d updates to %s\n", len(events), path)
+	return nil
+}
+
+func fetchChainHistory(ctx *cli.Context, cfg *oracle.Config) ([]*oracle.HistoryEvent, error) {
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	end := ctx.Uint64(flags.HistoryEndBlockFlag.Name)
+	if !ctx.IsSet(flags.HistoryEndBlockFlag.Name) {
+		if end, err = client.BlockNumber(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return oracle.FetchHistory(context.Background(), cfg, client,
+		ctx.Uint64(flags.HistoryStartBlockFlag.Name), end)
+}
+
+func writeHistory(out io.Writer, format string, events []*oracle.HistoryEvent) error {
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	}
+	return writeHistoryCSV(out, events)
+}
+
+func writeHistoryCSV(out io.Writer, events []*oracle.HistoryEvent) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"kind", "block", "timestamp", "old_value", "new_value", "tx_hash"}); err != nil {
+		return err
+	}
+	for _, event := range events {
+		old := ""
+		if event.OldValue != nil {
+			old = event.OldValue.String()
+		}
+		record := []string{
+			string(event.Kind),
+			strconv.FormatUint(event.BlockNumber, 10),
+			strconv.FormatUint(event.Timestamp, 10),
+			old,
+			event.NewValue.String(),
+			event.TxHash.Hex(),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
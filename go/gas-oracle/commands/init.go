@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/urfave/cli"
+)
+
+// InitCommand writes a commented sample config file
+var InitCommand = cli.Command{
+	Name:  "init",
+	Usage: "Write a commented sample config file",
+	Description: "Writes a config file with a network profile that sets every " +
+		"option of the config file to its default, with an explanation of each. " +
+		"Use it with --config-file and --network.",
+	Flags:  flags.InitFlags,
+	Action: initConfig,
+}
+
+func initConfig(ctx *cli.Context) error {
+	path := ctx.String(flags.InitOutputFlag.Name)
+	if path == "-" {
+		return oracle.WriteSampleConfig(os.Stdout)
+	}
+
+	mode := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if ctx.Bool(flags.InitForceFlag.Name) {
+		mode = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, mode, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("%s already exists, use --%s to overwrite it", path, flags.InitForceFlag.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if err := oracle.WriteSampleConfig(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s, run with --%s %s --%s my-network\n", path, flags.ConfigFileFlag.Name, path,
+		flags.NetworkFlag.Name)
+	return nil
+}
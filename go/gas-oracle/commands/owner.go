@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+// OwnerCommand prints the owner of the gas price oracle
+var OwnerCommand = cli.Command{
+	Name:  "owner",
+	Usage: "Print the owner of the gas price oracle",
+	Description: "Reads the owner of the gas price oracle from --layer-two-http-url " +
+		"and whether the signer of the configuration is the owner.",
+	Flags:  flags.OwnerFlags,
+	Action: owner,
+}
+
+// TransferOwnershipCommand transfers the ownership of the gas price oracle
+var TransferOwnershipCommand = cli.Command{
+	Name:      "transfer-ownership",
+	Usage:     "Transfer the ownership of the gas price oracle",
+	ArgsUsage: "<new-owner>",
+	Description: "Sends a transferOwnership transaction with the signer of the configuration, " +
+		"which must be the current owner. Only the new owner can update the gas price " +
+		"afterwards, so the key of the oracle must be rotated along with it. The transaction " +
+		"is only sent after confirmation unless --yes is set, it is recorded in the " +
+		"--audit-log and --wait-for-receipt waits for it to be confirmed.",
+	Flags:  flags.TransferOwnershipFlags,
+	Action: transferOwnership,
+}
+
+// AcceptOwnershipCommand accepts the ownership of a gas price oracle with a
+// two-step ownership
+var AcceptOwnershipCommand = cli.Command{
+	Name:  "accept-ownership",
+	Usage: "Accept the ownership of the gas price oracle",
+	Description: "Sends an acceptOwnership transaction with the signer of the configuration, " +
+		"which must be the pending owner of a gas price oracle with a two-step ownership. " +
+		"A running oracle with the same key resumes the updates once the ownership is " +
+		"accepted. The transaction is only sent after confirmation unless --yes is set, it " +
+		"is recorded in the --audit-log and --wait-for-receipt waits for it to be confirmed.",
+	Flags:  flags.AcceptOwnershipFlags,
+	Action: acceptOwnership,
+}
+
+func owner(ctx *cli.Context) error {
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	st, err := oracle.FetchStatus(context.Background(), cfg, client)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(flags.JSONFlag.Name) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Owner                common.Address  `json:"owner"`
+			PendingOwner         *common.Address `json:"pendingOwner,omitempty"`
+			Signer               *common.Address `json:"signer,omitempty"`
+			SignerIsOwner        bool            `json:"signerIsOwner"`
+			SignerIsPendingOwner bool            `json:"signerIsPendingOwner,omitempty"`
+		}{st.Owner, st.PendingOwner, st.Signer, st.SignerIsOwner, st.SignerIsPendingOwner})
+	}
+
+	fmt.Println("Owner:                  ", st.Owner.Hex())
+	if st.PendingOwner != nil {
+		fmt.Println("Pending owner:          ", st.PendingOwner.Hex())
+	}
+	if st.Signer != nil {
+		fmt.Println("Signer:                 ", st.Signer.Hex())
+		fmt.Println("Signer is owner:        ", st.SignerIsOwner)
+		if st.PendingOwner != nil {
+			fmt.Println("Signer is pending owner:", st.SignerIsPendingOwner)
+		}
+	}
+	return nil
+}
+
+func transferOwnership(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("expected the new owner as the only argument")
+	}
+	if !common.IsHexAddress(ctx.Args().First()) {
+		return fmt.Errorf("invalid new owner: %s", ctx.Args().First())
+	}
+	newOwner := common.HexToAddress(ctx.Args().First())
+	// The contract would accept it, but nobody can update the gas price
+	// after ownership is transferred to the zero address
+	if newOwner == (common.Address{}) {
+		return errors.New("cannot transfer the ownership to the zero address")
+	}
+
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	st, err := oracle.FetchStatus(context.Background(), cfg, client)
+	if err != nil {
+		return err
+	}
+	if err := checkSigner(st); err != nil {
+		return err
+	}
+	if newOwner == st.Owner {
+		return fmt.Errorf("%s is already the owner", newOwner.Hex())
+	}
+	fmt.Printf("Gas price oracle %s on chain %s\n", st.Address.Hex(), st.ChainID)
+	fmt.Printf("Transferring the ownership from %s to %s\n", st.Owner.Hex(), newOwner.Hex())
+
+	return sendManual(ctx, cfg, func() (string, error) {
+		tx, err := oracle.TransferOwnership(context.Background(), cfg, client, newOwner)
+		if tx == nil {
+			return "", err
+		}
+		return tx.Hash().Hex(), err
+	})
+}
+
+func acceptOwnership(ctx *cli.Context) error {
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	st, err := oracle.FetchStatus(context.Background(), cfg, client)
+	if err != nil {
+		return err
+	}
+	if st.Signer == nil {
+		return errors.New("no private key provided")
+	}
+	if st.SignerIsOwner {
+		return fmt.Errorf("%s is already the owner", st.Signer.Hex())
+	}
+	if st.PendingOwner == nil {
+		return errors.New("the gas price oracle has no pending ownership transfer")
+	}
+	if !st.SignerIsPendingOwner {
+		return fmt.Errorf("signer %s is not the pending owner %s of the gas price oracle",
+			st.Signer.Hex(), st.PendingOwner.Hex())
+	}
+	fmt.Printf("Gas price oracle %s on chain %s\n", st.Address.Hex(), st.ChainID)
+	fmt.Printf("Accepting the ownership from %s with %s\n", st.Owner.Hex(), st.Signer.Hex())
+
+	return sendManual(ctx, cfg, func() (string, error) {
+		tx, err := oracle.AcceptOwnership(context.Background(), cfg, client)
+		if tx == nil {
+			return "", err
+		}
+		return tx.Hash().Hex(), err
+	})
+}
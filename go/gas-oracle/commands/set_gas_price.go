@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/urfave/cli"
+)
+
+// SetGasPriceCommand sends a one-off transaction that sets the L2 gas price
+var SetGasPriceCommand = cli.Command{
+	Name:      "set-gas-price",
+	Usage:     "Manually set the L2 gas price of the gas price oracle",
+	ArgsUsage: "<gas-price>",
+	Description: "Sends a single setGasPrice transaction with the signer of the configuration, " +
+		"such as 0.002gwei. The gas price must be within --floor-price, --max-gas-price and " +
+		"--max-percent-change-per-epoch of the current gas price unless --force is set, and " +
+		"the transaction is only sent after confirmation unless --yes is set. The transaction " +
+		"is recorded in the --audit-log and --wait-for-receipt waits for it to be confirmed.",
+	Flags:  flags.SetGasPriceFlags,
+	Action: setGasPrice,
+}
+
+func setGasPrice(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("expected the gas price as the only argument")
+	}
+	price, err := gasprices.ParseWei(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("invalid gas price: %w", err)
+	}
+
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	st, err := oracle.FetchStatus(context.Background(), cfg, client)
+	if err != nil {
+		return err
+	}
+	if err := checkSigner(st); err != nil {
+		return err
+	}
+	fmt.Printf("Gas price oracle %s on chain %s\n", st.Address.Hex(), st.ChainID)
+	fmt.Printf("Setting the gas price from %s wei to %s wei\n", st.GasPrice, price)
+
+	if err := checkBounds(ctx, oracle.CheckGasPrice(cfg, st.GasPrice, price)); err != nil {
+		return err
+	}
+	return sendManual(ctx, cfg, func() (string, error) {
+		tx, err := oracle.SetGasPrice(context.Background(), cfg, client, price)
+		if tx == nil {
+			return "", err
+		}
+		return tx.Hash().Hex(), err
+	})
+}
+
+// checkSigner returns an error when the signer of the configuration cannot
+// send transactions to the gas price oracle
+func checkSigner(st *oracle.Status) error {
+	if st.Signer == nil {
+		return errors.New("no private key provided")
+	}
+	if !st.SignerIsOwner {
+		return fmt.Errorf("signer %s is not the owner %s of the gas price oracle", st.Signer.Hex(), st.Owner.Hex())
+	}
+	return nil
+}
+
+// checkBounds returns an error when the value violates bounds of the
+// configuration, unless --force is set
+func checkBounds(ctx *cli.Context, violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	if !ctx.Bool(flags.ManualForceFlag.Name) {
+		return fmt.Errorf("%s, use --%s to send it anyway", strings.Join(violations, ", "), flags.ManualForceFlag.Name)
+	}
+	for _, violation := range violations {
+		fmt.Println("Warning:", violation)
+	}
+	return nil
+}
+
+// sendManual sends a manual transaction after confirmation, nothing is sent
+// in a dry run
+func sendManual(ctx *cli.Context, cfg *oracle.Config, send func() (string, error)) error {
+	if cfg.DryRun() {
+		fmt.Println("Dry run, not sending the transaction")
+		return nil
+	}
+	if !ctx.Bool(flags.ManualYesFlag.Name) {
+		ok, err := confirm(os.Stdin, os.Stdout, "Send the transaction?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("aborted")
+		}
+	}
+	hash, err := send()
+	if hash != "" {
+		fmt.Println("Transaction:", hash)
+	}
+	return err
+}
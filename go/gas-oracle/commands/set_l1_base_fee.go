@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/urfave/cli"
+)
+
+// SetL1BaseFeeCommand sends a one-off transaction that sets the L1 base fee
+var SetL1BaseFeeCommand = cli.Command{
+	Name:      "set-l1-basefee",
+	Usage:     "Manually set the L1 base fee of the gas price oracle",
+	ArgsUsage: "<base-fee>",
+	Description: "Sends a single setL1BaseFee transaction with the signer of the configuration, " +
+		"such as 30gwei. The base fee must be within --max-percent-change-per-epoch of the " +
+		"current L1 base fee of the gas price oracle and within --l1-base-fee-significance-factor " +
+		"of the base fee of the tip of --ethereum-http-url unless --force is set, and the " +
+		"transaction is only sent after confirmation unless --yes is set. The transaction is " +
+		"recorded in the --audit-log and --wait-for-receipt waits for it to be confirmed.",
+	Flags:  flags.SetL1BaseFeeFlags,
+	Action: setL1BaseFee,
+}
+
+func setL1BaseFee(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("expected the L1 base fee as the only argument")
+	}
+	baseFee, err := gasprices.ParseWei(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("invalid L1 base fee: %w", err)
+	}
+
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	st, err := oracle.FetchStatus(context.Background(), cfg, client)
+	if err != nil {
+		return err
+	}
+	if err := checkSigner(st); err != nil {
+		return err
+	}
+	// The L1 tip is only a reference, L1 may be what the operator is
+	// working around
+	tip, err := fetchL1BaseFee(cfg)
+	if err != nil {
+		fmt.Println("Warning: cannot fetch the base fee of the L1 tip:", err)
+	}
+	fmt.Printf("Gas price oracle %s on chain %s\n", st.Address.Hex(), st.ChainID)
+	if tip != nil {
+		fmt.Printf("Base fee of the L1 tip is %s wei\n", tip)
+	}
+	fmt.Printf("Setting the L1 base fee from %s wei to %s wei\n", st.L1BaseFee, baseFee)
+
+	if err := checkBounds(ctx, oracle.CheckL1BaseFee(cfg, st.L1BaseFee, tip, baseFee)); err != nil {
+		return err
+	}
+	return sendManual(ctx, cfg, func() (string, error) {
+		tx, err := oracle.SetL1BaseFee(context.Background(), cfg, client, baseFee)
+		if tx == nil {
+			return "", err
+		}
+		return tx.Hash().Hex(), err
+	})
+}
+
+// fetchL1BaseFee returns the base fee of the tip of L1
+func fetchL1BaseFee(cfg *oracle.Config) (*big.Int, error) {
+	if cfg.EthereumHttpUrl() == "" {
+		return nil, errors.New("no L1 HTTP endpoint provided")
+	}
+	client, err := cfg.Dial(cfg.EthereumHttpUrl())
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	header, err := client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if header.BaseFee == nil {
+		return nil, errors.New("base fee not found on block")
+	}
+	return header.BaseFee, nil
+}
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/urfave/cli"
+)
+
+// maxSweepValues bounds the number of values of a range, so that a typo in a
+// step does not run for hours
+const maxSweepValues = 1000
+
+// SimulateCommand sweeps pricing parameters against a recorded demand trace
+var SimulateCommand = cli.Command{
+	Name:  "simulate",
+	Usage: "Sweep pricing parameters against a recorded demand trace",
+	Description: "Replays the demand of the epochs of --trace through the gas pricer with " +
+		"every combination of --targets, --max-changes and --significance-factors and " +
+		"reports the number of updates, the updates per hour, the volatility of the gas " +
+		"price and its range. The trace is the JSON output of a backtest, a JSON array or " +
+		"a JSON object per line with the time and the avgGasPerSecond of each epoch. " +
+		"Parameters that are not swept use the configured value.",
+	Flags:  flags.SimulateFlags,
+	Action: simulateCommand,
+}
+
+func simulateCommand(ctx *cli.Context) error {
+	path := ctx.String(flags.SimulateTraceFlag.Name)
+	if path == "" {
+		return errors.New("--trace must be set")
+	}
+	targets, err := parseSweep(ctx.String(flags.SimulateTargetsFlag.Name))
+	if err != nil {
+		return fmt.Errorf("option %q: %w", flags.SimulateTargetsFlag.Name, err)
+	}
+	maxChanges, err := parseSweep(ctx.String(flags.SimulateMaxChangesFlag.Name))
+	if err != nil {
+		return fmt.Errorf("option %q: %w", flags.SimulateMaxChangesFlag.Name, err)
+	}
+	factors, err := parseSweep(ctx.String(flags.SimulateSignificanceFactorsFlag.Name))
+	if err != nil {
+		return fmt.Errorf("option %q: %w", flags.SimulateSignificanceFactorsFlag.Name, err)
+	}
+	var initialPrice *big.Int
+	if ctx.IsSet(flags.SimulateInitialGasPriceFlag.Name) {
+		initialPrice, err = gasprices.ParseWei(ctx.String(flags.SimulateInitialGasPriceFlag.Name))
+		if err != nil {
+			return fmt.Errorf("option %q: %w", flags.SimulateInitialGasPriceFlag.Name, err)
+		}
+	}
+
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+	trace, err := oracle.ReadDemandTrace(in)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	var targetValues []uint64
+	for _, target := range targets {
+		targetValues = append(targetValues, uint64(target))
+	}
+	results, err := oracle.Simulate(cfg, trace, initialPrice, targetValues, maxChanges, factors)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(flags.JSONFlag.Name) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TARGET\tMAX CHANGE\tSIGNIFICANCE\tUPDATES\tUPDATES/HOUR\tVOLATILITY\tMAX STEP\tMIN PRICE\tMAX PRICE\tFINAL PRICE")
+	for _, r := range results {
+		fmt.Fprintf(w, "%d\t%g\t%g\t%d\t%.2f\t%.4f\t%.4f\t%s\t%s\t%s\n", r.Params.TargetGasPerSecond,
+			r.Params.MaxPercentChangePerEpoch, r.Params.SignificanceFactor, r.Updates, r.UpdatesPerHour,
+			r.Volatility, r.MaxChange, r.MinGasPrice, r.MaxGasPrice, r.FinalGasPrice)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\nEpochs: %d\n", len(trace))
+	return nil
+}
+
+// parseSweep parses the values of a sweep, either a comma separated list or
+// an inclusive start:end:step range. An empty value is an empty sweep.
+func parseSweep(value string) ([]float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if parts := strings.Split(value, ":"); len(parts) > 1 {
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid range %q, expected start:end:step", value)
+		}
+		var bounds [3]float64
+		for i, part := range parts {
+			v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, err
+			}
+			bounds[i] = v
+		}
+		start, end, step := bounds[0], bounds[1], bounds[2]
+		if step <= 0 || end < start {
+			return nil, fmt.Errorf("invalid range %q", value)
+		}
+		n := int(math.Floor((end-start)/step+1e-9)) + 1
+		if n > maxSweepValues {
+			return nil, fmt.Errorf("range %q has %d values, more than %d", value, n, maxSweepValues)
+		}
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = start + float64(i)*step
+		}
+		return values, nil
+	}
+
+	var values []float64
+	for _, part := range strings.Split(value, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli"
+)
+
+// StatusCommand prints the on-chain state of the gas price oracle
+var StatusCommand = cli.Command{
+	Name:  "status",
+	Usage: "Print the on-chain state of the gas price oracle",
+	Description: "Reads the gas price, the L1 base fee, the overhead, the scalar and " +
+		"the owner of the gas price oracle from --layer-two-http-url, along with the " +
+		"balance of the signer and whether the signer is the owner.",
+	Flags:  flags.StatusFlags,
+	Action: status,
+}
+
+func status(ctx *cli.Context) error {
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	st, err := oracle.FetchStatus(context.Background(), cfg, client)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(flags.JSONFlag.Name) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Chain ID:\t%s\n", st.ChainID)
+	fmt.Fprintf(w, "Block:\t%d\n", st.BlockNumber)
+	fmt.Fprintf(w, "Gas price oracle:\t%s\n", st.Address.Hex())
+	if st.Implementation != nil {
+		fmt.Fprintf(w, "Implementation:\t%s\n", st.Implementation.Hex())
+	}
+	if st.SemVer != "" {
+		fmt.Fprintf(w, "Version:\t%s (%s)\n", st.Version, st.SemVer)
+	} else {
+		fmt.Fprintf(w, "Version:\t%s\n", st.Version)
+	}
+	fmt.Fprintf(w, "Gas price:\t%s wei (%s gwei)\n", st.GasPrice, formatUnit(st.GasPrice, params.GWei))
+	if st.L1BaseFee != nil {
+		fmt.Fprintf(w, "L1 base fee:\t%s wei (%s gwei)\n", st.L1BaseFee, formatUnit(st.L1BaseFee, params.GWei))
+	}
+	if st.Overhead != nil {
+		fmt.Fprintf(w, "Overhead:\t%s\n", st.Overhead)
+	}
+	if st.Scalar != nil {
+		fmt.Fprintf(w, "Scalar:\t%s (decimals %s)\n", st.Scalar, st.Decimals)
+	}
+	if st.Owner != (common.Address{}) {
+		fmt.Fprintf(w, "Owner:\t%s\n", st.Owner.Hex())
+	} else {
+		fmt.Fprintf(w, "Owner:\tnone\n")
+	}
+	if st.Signer != nil {
+		fmt.Fprintf(w, "Signer:\t%s\n", st.Signer.Hex())
+		fmt.Fprintf(w, "Signer balance:\t%s ether\n", formatUnit(st.SignerBalance, params.Ether))
+		fmt.Fprintf(w, "Signer is owner:\t%t\n", st.SignerIsOwner)
+	} else {
+		fmt.Fprintf(w, "Signer:\tnone\n")
+	}
+	return w.Flush()
+}
+
+// formatUnit formats an amount in wei in a larger unit, such as gwei
+func formatUnit(amount *big.Int, unit int64) string {
+	value := new(big.Float).Quo(new(big.Float).SetInt(amount), big.NewFloat(float64(unit)))
+	return value.Text('f', -1)
+}
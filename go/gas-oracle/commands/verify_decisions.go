@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+// VerifyDecisionsCommand verifies an export of the signed decisions
+var VerifyDecisionsCommand = cli.Command{
+	Name:  "verify-decisions",
+	Usage: "Verify the signatures and the chain of an export of the signed decisions",
+	Description: "Reads the records of export-decisions --format signed, or of the " +
+		"/decision-history of the admin API with format=signed, and checks that every record " +
+		"is signed and follows the previous record, so that no record was altered, removed " +
+		"or reordered. It does not need a node or the key of the oracle.",
+	Flags:  flags.VerifyDecisionsFlags,
+	Action: verifyDecisions,
+}
+
+func verifyDecisions(ctx *cli.Context) error {
+	var signer *common.Address
+	if v := ctx.String(flags.VerifyDecisionsSignerFlag.Name); v != "" {
+		if !common.IsHexAddress(v) {
+			return fmt.Errorf("option %q: invalid address: %q", flags.VerifyDecisionsSignerFlag.Name, v)
+		}
+		address := common.HexToAddress(v)
+		signer = &address
+	}
+	var input io.Reader = os.Stdin
+	if path := ctx.String(flags.VerifyDecisionsInputFlag.Name); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	n, err := oracle.VerifySignedDecisions(input, signer)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Verified %d decisions\n", n)
+	return nil
+}
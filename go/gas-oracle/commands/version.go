@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+// BuildInfo identifies a build of the gas oracle. It is embedded at compile
+// time with linker flags.
+type BuildInfo struct {
+	Version    string
+	Commit     string
+	CommitDate string
+	BuildDate  string
+}
+
+// Labels returns the build information as the labels of the build_info
+// metric
+func (b BuildInfo) Labels() map[string]string {
+	return map[string]string{
+		"version":     b.Version,
+		"commit":      b.Commit,
+		"commit_date": formatDate(b.CommitDate),
+		"build_date":  b.BuildDate,
+		"go_version":  runtime.Version(),
+	}
+}
+
+// NewVersionCommand returns the command that prints the build information
+func NewVersionCommand(info BuildInfo) cli.Command {
+	return cli.Command{
+		Name:  "version",
+		Usage: "Print the version, commit and build date",
+		Action: func(ctx *cli.Context) error {
+			fmt.Println("Version:    ", info.Version)
+			fmt.Println("Commit:     ", info.Commit)
+			fmt.Println("Commit date:", formatDate(info.CommitDate))
+			fmt.Println("Build date: ", info.BuildDate)
+			fmt.Println("Go version: ", runtime.Version())
+			fmt.Println("OS/Arch:    ", runtime.GOOS+"/"+runtime.GOARCH)
+			return nil
+		},
+	}
+}
+
+// formatDate formats a unix timestamp, such as the commit date from git
+func formatDate(date string) string {
+	seconds, err := strconv.ParseInt(date, 10, 64)
+	if err != nil {
+		return date
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
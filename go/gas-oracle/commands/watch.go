@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/urfave/cli"
+)
+
+// WatchCommand prints the updates of the gas price oracle as they happen
+var WatchCommand = cli.Command{
+	Name:  "watch",
+	Usage: "Print the updates of the gas price oracle as they happen",
+	Description: "Polls --layer-two-http-url every --poll-interval for the GasPriceUpdated " +
+		"and L1BaseFeeUpdated events of the gas price oracle and prints each update " +
+		"with its old and new value until interrupted. --json prints an object per line.",
+	Flags:  flags.WatchFlags,
+	Action: watch,
+}
+
+func watch(ctx *cli.Context) error {
+	interval := ctx.Duration(flags.WatchPollIntervalFlag.Name)
+	if interval <= 0 {
+		return fmt.Errorf("option %q: must be positive", flags.WatchPollIntervalFlag.Name)
+	}
+	cfg, err := oracle.NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.Dial(cfg.LayerTwoHttpUrl())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	start := ctx.Uint64(flags.WatchStartBlockFlag.Name)
+	if !ctx.IsSet(flags.WatchStartBlockFlag.Name) {
+		tip, err := client.BlockNumber(context.Background())
+		if err != nil {
+			return err
+		}
+		start = tip + 1
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-watchCtx.Done():
+		}
+	}()
+
+	asJSON := ctx.Bool(flags.JSONFlag.Name)
+	enc := json.NewEncoder(os.Stdout)
+	if !asJSON {
+		fmt.Printf("Watching gas price oracle %s from block %d\n", cfg.GasPriceOracleAddress().Hex(), start)
+	}
+	return oracle.WatchHistory(watchCtx, cfg, client, start, interval, func(event *oracle.HistoryEvent) error {
+		if asJSON {
+			return enc.Encode(event)
+		}
+		old := "?"
+		if event.OldValue != nil {
+			old = event.OldValue.String()
+		}
+		fmt.Printf("%s block=%d %s %s -> %s tx=%s\n",
+			time.Unix(int64(event.Timestamp), 0).UTC().Format(time.RFC3339), event.BlockNumber,
+			event.Kind, old, event.NewValue, event.TxHash.Hex())
+		return nil
+	})
+}
@@ -1,10 +1,27 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/urfave/cli"
 )
 
 var (
+	ConfigFileFlag = cli.StringFlag{
+		Name:   "config-file",
+		Usage:  "Path to a YAML config file",
+		EnvVar: "GAS_PRICE_ORACLE_CONFIG_FILE",
+	}
+	NetworkFlag = cli.StringFlag{
+		Name:   "network",
+		Usage:  "Name of the network profile of the config file, or of a built-in preset: mainnet, goerli or kovan",
+		EnvVar: "GAS_PRICE_ORACLE_NETWORK",
+	}
+	StrictConfigFlag = cli.BoolFlag{
+		Name:   "strict-config",
+		Usage:  "Fail on unknown config file keys, deprecated options and suspicious combinations of options",
+		EnvVar: "GAS_PRICE_ORACLE_STRICT_CONFIG",
+	}
 	EthereumHttpUrlFlag = cli.StringFlag{
 		Name:   "ethereum-http-url",
 		Value:  "http://127.0.0.1:8545",
@@ -17,6 +34,21 @@ var (
 		Usage:  "Sequencer HTTP Endpoint",
 		EnvVar: "GAS_PRICE_ORACLE_LAYER_TWO_HTTP_URL",
 	}
+	RPCTLSCertFlag = cli.StringFlag{
+		Name:   "rpc.tls-cert",
+		Usage:  "Path to the PEM client certificate of the connections to the L1 and L2 endpoints, for endpoints that require mutual TLS",
+		EnvVar: "GAS_PRICE_ORACLE_RPC_TLS_CERT",
+	}
+	RPCTLSKeyFlag = cli.StringFlag{
+		Name:   "rpc.tls-key",
+		Usage:  "Path to the PEM private key of the client certificate of --rpc.tls-cert",
+		EnvVar: "GAS_PRICE_ORACLE_RPC_TLS_KEY",
+	}
+	RPCTLSCAFlag = cli.StringFlag{
+		Name:   "rpc.tls-ca",
+		Usage:  "Path to the PEM CA bundle that verifies the L1 and L2 endpoints instead of the system roots",
+		EnvVar: "GAS_PRICE_ORACLE_RPC_TLS_CA",
+	}
 	L1ChainIDFlag = cli.Uint64Flag{
 		Name:   "l1-chain-id",
 		Usage:  "L1 Chain ID",
@@ -38,9 +70,14 @@ var (
 		Usage:  "Private Key corresponding to OVM_GasPriceOracle Owner",
 		EnvVar: "GAS_PRICE_ORACLE_PRIVATE_KEY",
 	}
-	TransactionGasPriceFlag = cli.Uint64Flag{
+	PrivateKeyFileFlag = cli.StringFlag{
+		Name:   "private-key-file",
+		Usage:  "Path to a file, such as a mounted secret, that contains the private key of the OVM_GasPriceOracle owner",
+		EnvVar: "GAS_PRICE_ORACLE_PRIVATE_KEY_FILE",
+	}
+	TransactionGasPriceFlag = cli.StringFlag{
 		Name:   "transaction-gas-price",
-		Usage:  "Hardcoded tx.gasPrice, not setting it uses gas estimation",
+		Usage:  "Hardcoded tx.gasPrice such as 1gwei, not setting it uses gas estimation",
 		EnvVar: "GAS_PRICE_ORACLE_TRANSACTION_GAS_PRICE",
 	}
 	EnableL1BaseFeeFlag = cli.BoolFlag{
@@ -53,18 +90,143 @@ var (
 		Usage:  "Enable updating the L2 gas price",
 		EnvVar: "GAS_PRICE_ORACLE_ENABLE_L2_GAS_PRICE",
 	}
+	SystemConfigAddressFlag = cli.StringFlag{
+		Name:   "system-config.address",
+		Usage:  "address of the L1 SystemConfig of a Bedrock chain, the overhead and the scalar are set with setGasConfig on L1 instead of updating the L2 gas price oracle",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_ADDRESS",
+	}
+	SystemConfigOverheadFlag = cli.Uint64Flag{
+		Name:   "system-config.overhead",
+		Usage:  "fixed L1 gas overhead per transaction that is set in the SystemConfig",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_OVERHEAD",
+	}
+	SystemConfigScalarFlag = cli.Uint64Flag{
+		Name:   "system-config.scalar",
+		Usage:  "dynamic L1 fee scalar, with 6 decimals, that is set in the SystemConfig",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_SCALAR",
+	}
+	SystemConfigEnableGasConfigFlag = cli.BoolTFlag{
+		Name:   "system-config.enable-gas-config",
+		Usage:  "Enable setting the overhead and the scalar in the SystemConfig, set to false to only set the operator fee",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_ENABLE_GAS_CONFIG",
+	}
+	SystemConfigIntervalFlag = cli.DurationFlag{
+		Name:   "system-config.interval",
+		Value:  time.Minute,
+		Usage:  "how often the gas config of the SystemConfig is compared with the configured overhead and scalar",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_INTERVAL",
+	}
+	SystemConfigOperatorFeeScalarFlag = cli.Uint64Flag{
+		Name:   "system-config.operator-fee-scalar",
+		Usage:  "operator fee scalar, with 6 decimals, that is set with setOperatorFeeScalars in a SystemConfig that supports the operator fee",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_OPERATOR_FEE_SCALAR",
+	}
+	SystemConfigOperatorFeeConstantFlag = cli.Uint64Flag{
+		Name:   "system-config.operator-fee-constant",
+		Usage:  "operator fee constant in wei per transaction that is set with setOperatorFeeScalars in a SystemConfig that supports the operator fee",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_OPERATOR_FEE_CONSTANT",
+	}
+	SystemConfigMaxOperatorFeeScalarFlag = cli.Uint64Flag{
+		Name:   "system-config.max-operator-fee-scalar",
+		Usage:  "cap of the operator fee scalar, uncapped when 0",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_MAX_OPERATOR_FEE_SCALAR",
+	}
+	SystemConfigMaxOperatorFeeConstantFlag = cli.Uint64Flag{
+		Name:   "system-config.max-operator-fee-constant",
+		Usage:  "cap of the operator fee constant, uncapped when 0",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_MAX_OPERATOR_FEE_CONSTANT",
+	}
+	SystemConfigOperatorFeeSignificanceFactorFlag = cli.Float64Flag{
+		Name:   "system-config.operator-fee-significant-factor",
+		Usage:  "only set the operator fee when the scalar or the constant changes by more than this factor, such as 0.05",
+		EnvVar: "GAS_PRICE_ORACLE_SYSTEM_CONFIG_OPERATOR_FEE_SIGNIFICANT_FACTOR",
+	}
+	L1TxResubmitTimeoutFlag = cli.DurationFlag{
+		Name:   "l1-tx.resubmit-timeout",
+		Value:  2 * time.Minute,
+		Usage:  "how long an L1 transaction may stay pending before it is resubmitted with a higher fee",
+		EnvVar: "GAS_PRICE_ORACLE_L1_TX_RESUBMIT_TIMEOUT",
+	}
+	L1TxMaxFeeFlag = cli.StringFlag{
+		Name:   "l1-tx.max-fee",
+		Usage:  "cap of the fee per gas of the L1 transactions such as 200gwei, uncapped when not set",
+		EnvVar: "GAS_PRICE_ORACLE_L1_TX_MAX_FEE",
+	}
+	SafeAddressFlag = cli.StringFlag{
+		Name:   "safe.address",
+		Usage:  "address of the Safe that owns the gas price oracle, the updates are executed through the Safe when its threshold is 1 and proposed to the Safe transaction service otherwise",
+		EnvVar: "GAS_PRICE_ORACLE_SAFE_ADDRESS",
+	}
+	SafeServiceURLFlag = cli.StringFlag{
+		Name:   "safe.transaction-service-url",
+		Usage:  "URL of the Safe transaction service that the updates are proposed to, such as https://safe-transaction-optimism.safe.global",
+		EnvVar: "GAS_PRICE_ORACLE_SAFE_TRANSACTION_SERVICE_URL",
+	}
 	LogLevelFlag = cli.IntFlag{
 		Name:   "loglevel",
 		Value:  3,
 		Usage:  "log level to emit to the screen",
 		EnvVar: "GAS_PRICE_ORACLE_LOG_LEVEL",
 	}
-	FloorPriceFlag = cli.Uint64Flag{
+	LogFormatFlag = cli.StringFlag{
+		Name:   "log-format",
+		Value:  "terminal",
+		Usage:  "format of the logs emitted to the screen: terminal or json",
+		EnvVar: "GAS_PRICE_ORACLE_LOG_FORMAT",
+	}
+	PricerFlag = cli.StringFlag{
+		Name:   "pricer",
+		Value:  "proportional",
+		Usage:  "pricing algorithm used to compute the L2 gas price: proportional or fixed",
+		EnvVar: "GAS_PRICE_ORACLE_PRICER",
+	}
+	CanaryIncumbentPricerFlag = cli.StringFlag{
+		Name:   "canary-incumbent-pricer",
+		Usage:  "pricing algorithm that bounds the output of --pricer during a canary rollout, disabled when unset",
+		EnvVar: "GAS_PRICE_ORACLE_CANARY_INCUMBENT_PRICER",
+	}
+	CanaryMaxDeltaFlag = cli.Float64Flag{
+		Name:   "canary-max-delta",
+		Value:  0.1,
+		Usage:  "max relative difference from the incumbent gas price that is accepted during a canary rollout",
+		EnvVar: "GAS_PRICE_ORACLE_CANARY_MAX_DELTA",
+	}
+	ShadowPricerFlag = cli.StringFlag{
+		Name:   "shadow-pricer",
+		Usage:  "pricing algorithm to run in shadow mode alongside the live pricer, disabled when unset",
+		EnvVar: "GAS_PRICE_ORACLE_SHADOW_PRICER",
+	}
+	ShadowTargetGasPerSecondFlag = cli.Uint64Flag{
+		Name:   "shadow-target-gas-per-second",
+		Usage:  "target gas per second of the shadow pricer, defaults to --target-gas-per-second",
+		EnvVar: "GAS_PRICE_ORACLE_SHADOW_TARGET_GAS_PER_SECOND",
+	}
+	ShadowMaxPercentChangePerEpochFlag = cli.Float64Flag{
+		Name:   "shadow-max-percent-change-per-epoch",
+		Usage:  "max percent change of the shadow pricer, defaults to --max-percent-change-per-epoch",
+		EnvVar: "GAS_PRICE_ORACLE_SHADOW_MAX_PERCENT_CHANGE_PER_EPOCH",
+	}
+	FloorPriceFlag = cli.StringFlag{
 		Name:   "floor-price",
-		Value:  1,
-		Usage:  "gas price floor",
+		Value:  "1",
+		Usage:  "gas price floor in wei, or with a unit such as 0.001gwei",
 		EnvVar: "GAS_PRICE_ORACLE_FLOOR_PRICE",
 	}
+	MaxGasPriceFlag = cli.StringFlag{
+		Name:   "max-gas-price",
+		Usage:  "hard cap that the gas price will never exceed, such as 10gwei",
+		EnvVar: "GAS_PRICE_ORACLE_MAX_GAS_PRICE",
+	}
+	SanityMinGasPriceFlag = cli.StringFlag{
+		Name:   "sanity.min-gas-price",
+		Usage:  "absolute lowest gas price that is sent, a lower gas price is refused with an alert instead of being sent, disabled when unset",
+		EnvVar: "GAS_PRICE_ORACLE_SANITY_MIN_GAS_PRICE",
+	}
+	SanityMaxGasPriceFlag = cli.StringFlag{
+		Name:   "sanity.max-gas-price",
+		Usage:  "absolute highest gas price that is sent, a higher gas price is refused with an alert instead of being sent, disabled when unset",
+		EnvVar: "GAS_PRICE_ORACLE_SANITY_MAX_GAS_PRICE",
+	}
 	TargetGasPerSecondFlag = cli.Uint64Flag{
 		Name:   "target-gas-per-second",
 		Value:  11_000_000,
@@ -74,25 +236,50 @@ var (
 	MaxPercentChangePerEpochFlag = cli.Float64Flag{
 		Name:   "max-percent-change-per-epoch",
 		Value:  0.1,
-		Usage:  "max percent change of gas price per second",
+		Usage:  "max percent change of gas price per epoch as an exact decimal fraction",
 		EnvVar: "GAS_PRICE_ORACLE_MAX_PERCENT_CHANGE_PER_EPOCH",
 	}
+	DailyPriceChangeBudgetFlag = cli.Float64Flag{
+		Name:   "daily-price-change-budget",
+		Usage:  "max cumulative change of gas price within any 24 hours, where 1 is 100%, 0 disables the budget",
+		EnvVar: "GAS_PRICE_ORACLE_DAILY_PRICE_CHANGE_BUDGET",
+	}
 	AverageBlockGasLimitPerEpochFlag = cli.Uint64Flag{
 		Name:   "average-block-gas-limit-per-epoch",
 		Value:  11_000_000,
 		Usage:  "average block gas limit per epoch",
 		EnvVar: "GAS_PRICE_ORACLE_AVERAGE_BLOCK_GAS_LIMIT_PER_EPOCH",
 	}
+	EpochLengthFlag = cli.DurationFlag{
+		Name:   "epoch-length",
+		Value:  10 * time.Second,
+		Usage:  "length of epochs, such as 15s or 2m",
+		EnvVar: "GAS_PRICE_ORACLE_EPOCH_LENGTH",
+	}
 	EpochLengthSecondsFlag = cli.Uint64Flag{
 		Name:   "epoch-length-seconds",
-		Value:  10,
-		Usage:  "length of epochs in seconds",
+		Usage:  "deprecated, use --epoch-length",
 		EnvVar: "GAS_PRICE_ORACLE_EPOCH_LENGTH_SECONDS",
 	}
+	MinUpdateIntervalFlag = cli.DurationFlag{
+		Name:   "min-update-interval",
+		Usage:  "minimum time between L2 gas price updates, 0 disables rate limiting",
+		EnvVar: "GAS_PRICE_ORACLE_MIN_UPDATE_INTERVAL",
+	}
+	MinUpdateIntervalSecondsFlag = cli.Uint64Flag{
+		Name:   "min-update-interval-seconds",
+		Usage:  "deprecated, use --min-update-interval",
+		EnvVar: "GAS_PRICE_ORACLE_MIN_UPDATE_INTERVAL_SECONDS",
+	}
+	L1BaseFeeEpochLengthFlag = cli.DurationFlag{
+		Name:   "l1-base-fee-epoch-length",
+		Value:  15 * time.Second,
+		Usage:  "polling time for updating the L1 base fee",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_EPOCH_LENGTH",
+	}
 	L1BaseFeeEpochLengthSecondsFlag = cli.Uint64Flag{
 		Name:   "l1-base-fee-epoch-length-seconds",
-		Value:  15,
-		Usage:  "polling time for updating the L1 base fee",
+		Usage:  "deprecated, use --l1-base-fee-epoch-length",
 		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_EPOCH_LENGTH_SECONDS",
 	}
 	L1BaseFeeSignificanceFactorFlag = cli.Float64Flag{
@@ -107,11 +294,461 @@ var (
 		Usage:  "only update when the gas price changes by more than this factor",
 		EnvVar: "GAS_PRICE_ORACLE_SIGNIFICANT_FACTOR",
 	}
+	EnableAdaptiveSignificanceFlag = cli.BoolFlag{
+		Name:   "enable-adaptive-significance",
+		Usage:  "Scale the gas price significance factor with recent demand volatility",
+		EnvVar: "GAS_PRICE_ORACLE_ENABLE_ADAPTIVE_SIGNIFICANCE",
+	}
+	AdaptiveSignificanceEpochsFlag = cli.Uint64Flag{
+		Name:   "adaptive-significance-epochs",
+		Value:  20,
+		Usage:  "number of epochs to measure demand volatility over",
+		EnvVar: "GAS_PRICE_ORACLE_ADAPTIVE_SIGNIFICANCE_EPOCHS",
+	}
+	AdaptiveSignificanceMinFactorFlag = cli.Float64Flag{
+		Name:   "adaptive-significance-min-factor",
+		Value:  0.01,
+		Usage:  "significance factor used when demand is very volatile",
+		EnvVar: "GAS_PRICE_ORACLE_ADAPTIVE_SIGNIFICANCE_MIN_FACTOR",
+	}
+	AdaptiveSignificanceMaxFactorFlag = cli.Float64Flag{
+		Name:   "adaptive-significance-max-factor",
+		Value:  0.2,
+		Usage:  "significance factor used when demand is constant",
+		EnvVar: "GAS_PRICE_ORACLE_ADAPTIVE_SIGNIFICANCE_MAX_FACTOR",
+	}
+	GasPriceRoundingFlag = cli.StringFlag{
+		Name:   "gas-price-rounding",
+		Value:  "none",
+		Usage:  "round computed gas prices up before submitting them: none, increment or significant-digits",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_PRICE_ROUNDING",
+	}
+	GasPriceRoundingIncrementFlag = cli.StringFlag{
+		Name:   "gas-price-rounding-increment",
+		Value:  "0.001gwei",
+		Usage:  "increment to round gas prices up to when using increment rounding",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_PRICE_ROUNDING_INCREMENT",
+	}
+	GasPriceRoundingDigitsFlag = cli.Uint64Flag{
+		Name:   "gas-price-rounding-digits",
+		Value:  3,
+		Usage:  "number of significant digits to keep when using significant-digits rounding",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_PRICE_ROUNDING_DIGITS",
+	}
+	EnableSurgePricingFlag = cli.BoolFlag{
+		Name:   "enable-surge-pricing",
+		Usage:  "Enable surge pricing during sustained above target demand",
+		EnvVar: "GAS_PRICE_ORACLE_ENABLE_SURGE_PRICING",
+	}
+	SurgeThresholdFlag = cli.Float64Flag{
+		Name:   "surge-threshold",
+		Value:  1.5,
+		Usage:  "proportion of the target gas per second that counts as a surging epoch",
+		EnvVar: "GAS_PRICE_ORACLE_SURGE_THRESHOLD",
+	}
+	SurgeEpochsFlag = cli.Uint64Flag{
+		Name:   "surge-epochs",
+		Value:  3,
+		Usage:  "consecutive surging epochs before the surge multiplier is applied",
+		EnvVar: "GAS_PRICE_ORACLE_SURGE_EPOCHS",
+	}
+	SurgeFactorFlag = cli.Float64Flag{
+		Name:   "surge-factor",
+		Value:  1.25,
+		Usage:  "growth of the surge multiplier per surging epoch",
+		EnvVar: "GAS_PRICE_ORACLE_SURGE_FACTOR",
+	}
+	SurgeMaxMultiplierFlag = cli.Float64Flag{
+		Name:   "surge-max-multiplier",
+		Value:  4,
+		Usage:  "maximum surge multiplier",
+		EnvVar: "GAS_PRICE_ORACLE_SURGE_MAX_MULTIPLIER",
+	}
+	SurgeDecayRateFlag = cli.Float64Flag{
+		Name:   "surge-decay-rate",
+		Value:  0.25,
+		Usage:  "fraction of the surge multiplier removed per epoch once demand drops",
+		EnvVar: "GAS_PRICE_ORACLE_SURGE_DECAY_RATE",
+	}
+	EnableIdleDecayFlag = cli.BoolFlag{
+		Name:   "enable-idle-decay",
+		Usage:  "Decay the gas price toward the floor price while the chain is idle",
+		EnvVar: "GAS_PRICE_ORACLE_ENABLE_IDLE_DECAY",
+	}
+	IdleDecayThresholdFlag = cli.Float64Flag{
+		Name:   "idle-decay-threshold",
+		Value:  1000,
+		Usage:  "average gas per second at or below which an epoch is idle",
+		EnvVar: "GAS_PRICE_ORACLE_IDLE_DECAY_THRESHOLD",
+	}
+	IdleDecayEpochsFlag = cli.Uint64Flag{
+		Name:   "idle-decay-epochs",
+		Value:  6,
+		Usage:  "consecutive idle epochs before the gas price decays",
+		EnvVar: "GAS_PRICE_ORACLE_IDLE_DECAY_EPOCHS",
+	}
+	IdleDecayRateFlag = cli.Float64Flag{
+		Name:   "idle-decay-rate",
+		Value:  0.1,
+		Usage:  "fraction of the distance to the floor price removed per idle epoch",
+		EnvVar: "GAS_PRICE_ORACLE_IDLE_DECAY_RATE",
+	}
+	DemandGasWeightFlag = cli.Float64Flag{
+		Name:   "demand-gas-weight",
+		Value:  1,
+		Usage:  "weight of each unit of gas used by blocks in the demand",
+		EnvVar: "GAS_PRICE_ORACLE_DEMAND_GAS_WEIGHT",
+	}
+	DemandTxWeightFlag = cli.Float64Flag{
+		Name:   "demand-tx-weight",
+		Usage:  "gas equivalent demand of each transaction",
+		EnvVar: "GAS_PRICE_ORACLE_DEMAND_TX_WEIGHT",
+	}
+	DemandCalldataWeightFlag = cli.Float64Flag{
+		Name:   "demand-calldata-weight",
+		Usage:  "gas equivalent demand of each byte of calldata",
+		EnvVar: "GAS_PRICE_ORACLE_DEMAND_CALLDATA_WEIGHT",
+	}
+	DemandBlockTagFlag = cli.StringFlag{
+		Name:   "demand-block-tag",
+		Value:  "latest",
+		Usage:  "block tag that the demand is measured up to, latest, or safe or finalized where the L2 node supports them",
+		EnvVar: "GAS_PRICE_ORACLE_DEMAND_BLOCK_TAG",
+	}
+	DemandConfirmationsFlag = cli.Uint64Flag{
+		Name:   "demand-confirmations",
+		Usage:  "number of blocks behind the block of --demand-block-tag that the demand is measured up to",
+		EnvVar: "GAS_PRICE_ORACLE_DEMAND_CONFIRMATIONS",
+	}
+	ReorgReuseDemandFlag = cli.BoolFlag{
+		Name:   "reorg-reuse-demand",
+		Usage:  "complete an epoch whose blocks were reorged away with the demand of the last recorded epoch instead of skipping it",
+		EnvVar: "GAS_PRICE_ORACLE_REORG_REUSE_DEMAND",
+	}
+	EnableOutlierRejectionFlag = cli.BoolFlag{
+		Name:   "enable-outlier-rejection",
+		Usage:  "Clamp the demand of anomalous epochs",
+		EnvVar: "GAS_PRICE_ORACLE_ENABLE_OUTLIER_REJECTION",
+	}
+	OutlierMethodFlag = cli.StringFlag{
+		Name:   "outlier-method",
+		Value:  "mad",
+		Usage:  "measure of the spread of recent demand: mad or stddev",
+		EnvVar: "GAS_PRICE_ORACLE_OUTLIER_METHOD",
+	}
+	OutlierWindowFlag = cli.Uint64Flag{
+		Name:   "outlier-window",
+		Value:  20,
+		Usage:  "number of recent epochs that outliers are detected against",
+		EnvVar: "GAS_PRICE_ORACLE_OUTLIER_WINDOW",
+	}
+	OutlierMinSamplesFlag = cli.Uint64Flag{
+		Name:   "outlier-min-samples",
+		Value:  5,
+		Usage:  "number of epochs observed before outliers are rejected",
+		EnvVar: "GAS_PRICE_ORACLE_OUTLIER_MIN_SAMPLES",
+	}
+	OutlierThresholdFlag = cli.Float64Flag{
+		Name:   "outlier-threshold",
+		Value:  3.5,
+		Usage:  "number of spreads from the center at which an epoch is an outlier",
+		EnvVar: "GAS_PRICE_ORACLE_OUTLIER_THRESHOLD",
+	}
+	SmoothingEpochsFlag = cli.Uint64Flag{
+		Name:   "smoothing-epochs",
+		Value:  1,
+		Usage:  "number of recent epochs whose demand is combined into each decision",
+		EnvVar: "GAS_PRICE_ORACLE_SMOOTHING_EPOCHS",
+	}
+	SmoothingMethodFlag = cli.StringFlag{
+		Name:   "smoothing-method",
+		Value:  "mean",
+		Usage:  "how the demand of recent epochs is combined: mean or median",
+		EnvVar: "GAS_PRICE_ORACLE_SMOOTHING_METHOD",
+	}
+	EnableTxPoolSignalFlag = cli.BoolFlag{
+		Name:   "enable-txpool-signal",
+		Usage:  "Blend the sequencer's txpool_status into the demand estimate",
+		EnvVar: "GAS_PRICE_ORACLE_ENABLE_TXPOOL_SIGNAL",
+	}
+	TxPoolSignalWeightFlag = cli.Float64Flag{
+		Name:   "txpool-signal-weight",
+		Value:  1,
+		Usage:  "weight of the gas waiting in the txpool relative to the gas used by blocks",
+		EnvVar: "GAS_PRICE_ORACLE_TXPOOL_SIGNAL_WEIGHT",
+	}
+	TxPoolQueuedWeightFlag = cli.Float64Flag{
+		Name:   "txpool-queued-weight",
+		Value:  0,
+		Usage:  "weight of a queued transaction relative to a pending transaction",
+		EnvVar: "GAS_PRICE_ORACLE_TXPOOL_QUEUED_WEIGHT",
+	}
+	TxPoolGasPerTxFlag = cli.Uint64Flag{
+		Name:   "txpool-gas-per-tx",
+		Value:  100000,
+		Usage:  "estimated gas used by each transaction in the txpool",
+		EnvVar: "GAS_PRICE_ORACLE_TXPOOL_GAS_PER_TX",
+	}
+	BalanceCheckIntervalFlag = cli.DurationFlag{
+		Name:   "balance-check-interval",
+		Value:  time.Minute,
+		Usage:  "polling time for checking the balance of the signer",
+		EnvVar: "GAS_PRICE_ORACLE_BALANCE_CHECK_INTERVAL",
+	}
+	BalanceCheckIntervalSecondsFlag = cli.Uint64Flag{
+		Name:   "balance-check-interval-seconds",
+		Usage:  "deprecated, use --balance-check-interval",
+		EnvVar: "GAS_PRICE_ORACLE_BALANCE_CHECK_INTERVAL_SECONDS",
+	}
+	LowBalanceThresholdFlag = cli.StringFlag{
+		Name:   "low-balance-threshold",
+		Usage:  "warn when the balance of the signer falls below this value, such as 0.5ether",
+		EnvVar: "GAS_PRICE_ORACLE_LOW_BALANCE_THRESHOLD",
+	}
+	LowBalanceWebhookURLFlag = cli.StringFlag{
+		Name:   "low-balance-webhook-url",
+		Usage:  "URL that is sent a JSON payload when the balance of the signer is low",
+		EnvVar: "GAS_PRICE_ORACLE_LOW_BALANCE_WEBHOOK_URL",
+	}
+	FeeVaultAddressFlag = cli.StringFlag{
+		Name:   "fee-vault.address",
+		Usage:  "monitor the balance and the revenue of the sequencer fee vault at this address, such as 0x4200000000000000000000000000000000000011",
+		EnvVar: "GAS_PRICE_ORACLE_FEE_VAULT_ADDRESS",
+	}
+	FeeVaultIntervalFlag = cli.DurationFlag{
+		Name:   "fee-vault.interval",
+		Value:  time.Minute,
+		Usage:  "polling time for checking the balance of the sequencer fee vault",
+		EnvVar: "GAS_PRICE_ORACLE_FEE_VAULT_INTERVAL",
+	}
+	WebhookURLFlag = cli.StringSliceFlag{
+		Name:   "webhook-url",
+		Usage:  "URL that is sent a JSON payload when an update is sent, fails or is skipped due to caps, can be repeated",
+		EnvVar: "GAS_PRICE_ORACLE_WEBHOOK_URLS",
+	}
+	SlackWebhookURLFlag = cli.StringFlag{
+		Name:   "slack-webhook-url",
+		Usage:  "Slack incoming webhook URL that is posted significant events",
+		EnvVar: "GAS_PRICE_ORACLE_SLACK_WEBHOOK_URL",
+	}
+	DiscordWebhookURLFlag = cli.StringFlag{
+		Name:   "discord-webhook-url",
+		Usage:  "Discord webhook URL that is posted significant events",
+		EnvVar: "GAS_PRICE_ORACLE_DISCORD_WEBHOOK_URL",
+	}
+	ChatMinPriceChangeFlag = cli.Float64Flag{
+		Name:   "chat-min-price-change",
+		Value:  0.1,
+		Usage:  "post gas price updates to chat that change the price by more than this factor",
+		EnvVar: "GAS_PRICE_ORACLE_CHAT_MIN_PRICE_CHANGE",
+	}
+	ChatFailureThresholdFlag = cli.Uint64Flag{
+		Name:   "chat-failure-threshold",
+		Value:  3,
+		Usage:  "post to chat when this many consecutive epochs fail to update",
+		EnvVar: "GAS_PRICE_ORACLE_CHAT_FAILURE_THRESHOLD",
+	}
+	PagerDutyRoutingKeyFlag = cli.StringFlag{
+		Name:   "pagerduty-routing-key",
+		Usage:  "PagerDuty Events v2 integration key that is paged on critical failures",
+		EnvVar: "GAS_PRICE_ORACLE_PAGERDUTY_ROUTING_KEY",
+	}
+	PagerDutyFailureThresholdFlag = cli.Uint64Flag{
+		Name:   "pagerduty-failure-threshold",
+		Value:  5,
+		Usage:  "page when this many consecutive epochs fail to update",
+		EnvVar: "GAS_PRICE_ORACLE_PAGERDUTY_FAILURE_THRESHOLD",
+	}
+	PagerDutyURLFlag = cli.StringFlag{
+		Name:   "pagerduty-url",
+		Value:  "https://events.pagerduty.com/v2/enqueue",
+		Usage:  "PagerDuty Events v2 API endpoint",
+		EnvVar: "GAS_PRICE_ORACLE_PAGERDUTY_URL",
+	}
+	PublishURLFlag = cli.StringFlag{
+		Name:   "publish.url",
+		Usage:  "message bus that every decision and sent transaction is published to, a nats:// or tls:// NATS server or the http(s):// url of a Kafka REST proxy",
+		EnvVar: "GAS_PRICE_ORACLE_PUBLISH_URL",
+	}
+	PublishTopicFlag = cli.StringFlag{
+		Name:   "publish.topic",
+		Value:  "gas-oracle",
+		Usage:  "NATS subject or Kafka topic of the published messages",
+		EnvVar: "GAS_PRICE_ORACLE_PUBLISH_TOPIC",
+	}
+	PostgresURLFlag = cli.StringFlag{
+		Name:   "postgres.url",
+		Usage:  "postgres:// url of a database that records every epoch, sent transaction and receipt, the schema is migrated at startup",
+		EnvVar: "GAS_PRICE_ORACLE_POSTGRES_URL",
+	}
+	AuditLogFlag = cli.StringFlag{
+		Name:   "audit-log",
+		Usage:  "path of an append-only file that records every update transaction that is sent",
+		EnvVar: "GAS_PRICE_ORACLE_AUDIT_LOG",
+	}
+	StateDBFlag = cli.StringFlag{
+		Name:   "state-db",
+		Usage:  "directory of a LevelDB database that keeps the current epoch, the gas price and the smoothing samples across restarts",
+		EnvVar: "GAS_PRICE_ORACLE_STATE_DB",
+	}
+	PriceHistoryRetentionFlag = cli.DurationFlag{
+		Name:   "price-history.retention",
+		Value:  30 * 24 * time.Hour,
+		Usage:  "how long the gas prices and the decisions of the epochs are kept in the --state-db for the history of the admin API, 0 keeps them forever",
+		EnvVar: "GAS_PRICE_ORACLE_PRICE_HISTORY_RETENTION",
+	}
+	WarmStartBlocksFlag = cli.Uint64Flag{
+		Name:   "warm-start-blocks",
+		Usage:  "number of recent blocks that are scanned for gas price updates on startup to restore the rate limit, the daily change budget and the stale update alarm, disabled when 0",
+		EnvVar: "GAS_PRICE_ORACLE_WARM_START_BLOCKS",
+	}
+	GasTokenRateFeedFlag = cli.StringFlag{
+		Name:   "gas-token.rate-feed",
+		Usage:  "address of a Chainlink price feed on L1 that prices the custom gas token of L2 in ether, the floor and max gas prices are then in ether and converted to the gas token",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_TOKEN_RATE_FEED",
+	}
+	GasTokenMaxRateAgeFlag = cli.DurationFlag{
+		Name:   "gas-token.max-rate-age",
+		Value:  25 * time.Hour,
+		Usage:  "fail the epochs when the price feed of the gas token has not been updated for this long",
+		EnvVar: "GAS_PRICE_ORACLE_GAS_TOKEN_MAX_RATE_AGE",
+	}
+	ImplementationCheckIntervalFlag = cli.DurationFlag{
+		Name:   "implementation-check-interval",
+		Value:  5 * time.Minute,
+		Usage:  "how often the implementation of a gas price oracle behind an EIP-1967 proxy is checked for upgrades, disabled when 0",
+		EnvVar: "GAS_PRICE_ORACLE_IMPLEMENTATION_CHECK_INTERVAL",
+	}
+	OwnerCheckIntervalFlag = cli.DurationFlag{
+		Name:   "owner-check-interval",
+		Value:  time.Minute,
+		Usage:  "how often the owner of the updated contract is checked against the signer, disabled when 0",
+		EnvVar: "GAS_PRICE_ORACLE_OWNER_CHECK_INTERVAL",
+	}
+	WatchExternalUpdatesFlag = cli.BoolFlag{
+		Name:   "watch-external-updates",
+		Usage:  "detect gas prices set by other transactions and continue from them",
+		EnvVar: "GAS_PRICE_ORACLE_WATCH_EXTERNAL_UPDATES",
+	}
+	DriftToleranceFlag = cli.Float64Flag{
+		Name:   "drift-tolerance",
+		Usage:  "resynchronize the gas pricer from the chain when they differ by more than this factor, disabled when 0",
+		EnvVar: "GAS_PRICE_ORACLE_DRIFT_TOLERANCE",
+	}
+	HeartbeatURLFlag = cli.StringFlag{
+		Name:   "heartbeat-url",
+		Usage:  "URL that is pinged after each successful epoch, such as a healthchecks.io check",
+		EnvVar: "GAS_PRICE_ORACLE_HEARTBEAT_URL",
+	}
+	HALeaseURLFlag = cli.StringFlag{
+		Name:   "ha.lease-url",
+		Usage:  "postgres://, redis://, rediss://, etcd:// or etcds:// url of the lease that elects the replica that sends transactions, disabled when empty",
+		EnvVar: "GAS_PRICE_ORACLE_HA_LEASE_URL",
+	}
+	HALeaseNameFlag = cli.StringFlag{
+		Name:   "ha.lease-name",
+		Value:  "gas-oracle",
+		Usage:  "name of the lease that the replicas of a chain share",
+		EnvVar: "GAS_PRICE_ORACLE_HA_LEASE_NAME",
+	}
+	HALeaseTTLFlag = cli.DurationFlag{
+		Name:   "ha.lease-ttl",
+		Value:  15 * time.Second,
+		Usage:  "time after which the lease of a replica that stopped renewing it can be taken by another replica",
+		EnvVar: "GAS_PRICE_ORACLE_HA_LEASE_TTL",
+	}
+	HALeaseTLSCAFlag = cli.StringFlag{
+		Name:   "ha.lease-tls-ca",
+		Usage:  "path to the PEM CA bundle that verifies rediss:// and etcds:// lease servers instead of the system roots",
+		EnvVar: "GAS_PRICE_ORACLE_HA_LEASE_TLS_CA",
+	}
+	HAReplicaIDFlag = cli.StringFlag{
+		Name:   "ha.replica-id",
+		Usage:  "identifies the replica in the lease, defaults to the host name with a random suffix",
+		EnvVar: "GAS_PRICE_ORACLE_HA_REPLICA_ID",
+	}
+	EpochDeadlineFlag = cli.DurationFlag{
+		Name:   "epoch-deadline",
+		Usage:  "cancel and restart an epoch that takes longer than this, defaults to the epoch length",
+		EnvVar: "GAS_PRICE_ORACLE_EPOCH_DEADLINE",
+	}
+	StaleUpdateEpochsFlag = cli.Uint64Flag{
+		Name:   "stale-update-epochs",
+		Usage:  "raise an alarm when no update has landed for this many epochs while the demand changed, disabled when 0",
+		EnvVar: "GAS_PRICE_ORACLE_STALE_UPDATE_EPOCHS",
+	}
+	StaleUpdateDemandChangeFlag = cli.Float64Flag{
+		Name:   "stale-update-demand-change",
+		Value:  0.1,
+		Usage:  "relative range of the demand since the last update above which the demand changed",
+		EnvVar: "GAS_PRICE_ORACLE_STALE_UPDATE_DEMAND_CHANGE",
+	}
+	L2GasPriceIncreaseSignificanceFactorFlag = cli.Float64Flag{
+		Name:   "significant-factor-increase",
+		Usage:  "only increase the gas price when it changes by more than this factor, defaults to --significant-factor",
+		EnvVar: "GAS_PRICE_ORACLE_SIGNIFICANT_FACTOR_INCREASE",
+	}
+	L2GasPriceDecreaseSignificanceFactorFlag = cli.Float64Flag{
+		Name:   "significant-factor-decrease",
+		Usage:  "only decrease the gas price when it changes by more than this factor, defaults to --significant-factor",
+		EnvVar: "GAS_PRICE_ORACLE_SIGNIFICANT_FACTOR_DECREASE",
+	}
 	WaitForReceiptFlag = cli.BoolFlag{
 		Name:   "wait-for-receipt",
 		Usage:  "wait for receipts when sending transactions",
 		EnvVar: "GAS_PRICE_ORACLE_WAIT_FOR_RECEIPT",
 	}
+	DryRunFlag = cli.BoolFlag{
+		Name:   "dry-run",
+		Usage:  "compute updates without sending transactions",
+		EnvVar: "GAS_PRICE_ORACLE_DRY_RUN",
+	}
+	OneShotFlag = cli.BoolFlag{
+		Name:   "one-shot",
+		Usage:  "evaluate a single epoch, wait for the receipt of its update and exit",
+		EnvVar: "GAS_PRICE_ORACLE_ONE_SHOT",
+	}
+	AdminAddrFlag = cli.StringFlag{
+		Name:   "admin.addr",
+		Usage:  "listening address of the admin HTTP API, disabled when empty",
+		EnvVar: "GAS_PRICE_ORACLE_ADMIN_ADDR",
+	}
+	AdminGRPCAddrFlag = cli.StringFlag{
+		Name:   "admin.grpc-addr",
+		Usage:  "listening address of the admin gRPC API, disabled when empty",
+		EnvVar: "GAS_PRICE_ORACLE_ADMIN_GRPC_ADDR",
+	}
+	AdminTokenFlag = cli.StringFlag{
+		Name:   "admin.token",
+		Usage:  "bearer token accepted by the admin HTTP and gRPC APIs",
+		EnvVar: "GAS_PRICE_ORACLE_ADMIN_TOKEN",
+	}
+	AdminJWTSecretFileFlag = cli.StringFlag{
+		Name:   "admin.jwt-secret-file",
+		Usage:  "path to a file with the hex encoded secret of the HS256 JWTs accepted by the admin APIs",
+		EnvVar: "GAS_PRICE_ORACLE_ADMIN_JWT_SECRET_FILE",
+	}
+	AdminBindAllowlistFlag = cli.StringSliceFlag{
+		Name:   "admin.bind-allowlist",
+		Usage:  "hosts that the admin APIs may listen on, 0.0.0.0 for all interfaces or * for any, defaults to the loopback interface, can be repeated",
+		EnvVar: "GAS_PRICE_ORACLE_ADMIN_BIND_ALLOWLIST",
+	}
+	ApprovalTokenFlag = cli.StringFlag{
+		Name:   "approval.token",
+		Usage:  "bearer token of a second operator that approves every L2 gas price update on the admin HTTP API before it is sent, the updates are sent without approval when empty",
+		EnvVar: "GAS_PRICE_ORACLE_APPROVAL_TOKEN",
+	}
+	ApprovalTTLFlag = cli.DurationFlag{
+		Name:   "approval.ttl",
+		Value:  10 * time.Minute,
+		Usage:  "how long a staged update can be approved before it expires",
+		EnvVar: "GAS_PRICE_ORACLE_APPROVAL_TTL",
+	}
+	PriceAPIAddrFlag = cli.StringFlag{
+		Name:   "price-api.addr",
+		Usage:  "listening address of the read-only API of the recommended gas price, disabled when empty",
+		EnvVar: "GAS_PRICE_ORACLE_PRICE_API_ADDR",
+	}
 	MetricsEnabledFlag = cli.BoolFlag{
 		Name:   "metrics",
 		Usage:  "Enable metrics collection and reporting",
@@ -129,6 +766,56 @@ var (
 		Value:  6060,
 		EnvVar: "GAS_PRICE_ORACLE_METRICS_PORT",
 	}
+	MetricsBackendFlag = cli.StringFlag{
+		Name:   "metrics.backend",
+		Value:  "prometheus",
+		Usage:  "metrics backend, either prometheus to serve metrics over HTTP, statsd to send them to a StatsD server or cloudwatch to publish them to AWS CloudWatch",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_BACKEND",
+	}
+	MetricsStatsDAddressFlag = cli.StringFlag{
+		Name:   "metrics.statsd.address",
+		Value:  "127.0.0.1:8125",
+		Usage:  "address of the StatsD server, over UDP",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_STATSD_ADDRESS",
+	}
+	MetricsStatsDPrefixFlag = cli.StringFlag{
+		Name:   "metrics.statsd.prefix",
+		Value:  "gas_oracle.",
+		Usage:  "prefix of the names of the metrics sent to StatsD",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_STATSD_PREFIX",
+	}
+	MetricsStatsDTagsFlag = cli.StringSliceFlag{
+		Name:   "metrics.statsd.tags",
+		Usage:  "tags in the DogStatsD format such as env:prod that are sent with every metric, can be repeated",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_STATSD_TAGS",
+	}
+	MetricsCloudWatchNamespaceFlag = cli.StringFlag{
+		Name:   "metrics.cloudwatch.namespace",
+		Value:  "GasOracle",
+		Usage:  "namespace of the metrics published to CloudWatch",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_CLOUDWATCH_NAMESPACE",
+	}
+	MetricsCloudWatchRegionFlag = cli.StringFlag{
+		Name:   "metrics.cloudwatch.region",
+		Usage:  "region of CloudWatch, defaults to the region of the AWS environment",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_CLOUDWATCH_REGION",
+	}
+	MetricsCloudWatchDimensionsFlag = cli.StringSliceFlag{
+		Name:   "metrics.cloudwatch.dimensions",
+		Usage:  "dimensions such as Network=mainnet that are sent to CloudWatch with every metric, can be repeated",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_CLOUDWATCH_DIMENSIONS",
+	}
+	MetricsPushgatewayURLFlag = cli.StringFlag{
+		Name:   "metrics.pushgateway.url",
+		Usage:  "url of a Prometheus Pushgateway that the final metrics of a backtest are pushed to",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_PUSHGATEWAY_URL",
+	}
+	MetricsPushgatewayJobFlag = cli.StringFlag{
+		Name:   "metrics.pushgateway.job",
+		Value:  "gas-oracle",
+		Usage:  "job name of the metrics pushed to the Pushgateway",
+		EnvVar: "GAS_PRICE_ORACLE_METRICS_PUSHGATEWAY_JOB",
+	}
 	MetricsEnableInfluxDBFlag = cli.BoolFlag{
 		Name:   "metrics.influxdb",
 		Usage:  "Enable metrics export/push to an external InfluxDB database",
@@ -158,34 +845,417 @@ var (
 		Value:  "test",
 		EnvVar: "GAS_PRICE_ORACLE_METRICS_INFLUX_DB_PASSWORD",
 	}
+	TracingOTLPEndpointFlag = cli.StringFlag{
+		Name:   "tracing.otlp.endpoint",
+		Usage:  "endpoint of the OTLP HTTP collector that traces are exported to, tracing is disabled when unset",
+		EnvVar: "GAS_PRICE_ORACLE_TRACING_OTLP_ENDPOINT",
+	}
+	TracingOTLPInsecureFlag = cli.BoolFlag{
+		Name:   "tracing.otlp.insecure",
+		Usage:  "export traces over HTTP instead of HTTPS",
+		EnvVar: "GAS_PRICE_ORACLE_TRACING_OTLP_INSECURE",
+	}
+	TracingSampleRatioFlag = cli.Float64Flag{
+		Name:   "tracing.sample-ratio",
+		Value:  1,
+		Usage:  "fraction of epochs that are traced",
+		EnvVar: "GAS_PRICE_ORACLE_TRACING_SAMPLE_RATIO",
+	}
+	SentryDSNFlag = cli.StringFlag{
+		Name:   "sentry.dsn",
+		Usage:  "data source name of the Sentry project that panics and repeated errors are reported to",
+		EnvVar: "GAS_PRICE_ORACLE_SENTRY_DSN",
+	}
+	SentryEnvironmentFlag = cli.StringFlag{
+		Name:   "sentry.environment",
+		Usage:  "environment that is attached to Sentry reports",
+		EnvVar: "GAS_PRICE_ORACLE_SENTRY_ENVIRONMENT",
+	}
+	SentryFailureThresholdFlag = cli.Uint64Flag{
+		Name:   "sentry.failure-threshold",
+		Value:  3,
+		Usage:  "report to Sentry when this many consecutive epochs fail to update",
+		EnvVar: "GAS_PRICE_ORACLE_SENTRY_FAILURE_THRESHOLD",
+	}
 )
 
+// Flags used by the backtest command
+var (
+	BacktestStartBlockFlag = cli.Uint64Flag{
+		Name:  "start-block",
+		Usage: "First block of the range to replay",
+	}
+	BacktestEndBlockFlag = cli.Uint64Flag{
+		Name:  "end-block",
+		Usage: "Last block of the range to replay",
+	}
+	BacktestInitialGasPriceFlag = cli.StringFlag{
+		Name:  "initial-gas-price",
+		Usage: "L2 gas price at the start block such as 0.001gwei, read from the contract when unset",
+	}
+	BacktestUpdateTxGasFlag = cli.Uint64Flag{
+		Name:  "update-tx-gas",
+		Value: 35000,
+		Usage: "Estimated gas used by each gas price update transaction",
+	}
+	InitOutputFlag = cli.StringFlag{
+		Name:  "output",
+		Value: "gas-oracle.yaml",
+		Usage: "Path to write the sample config file to, - for stdout",
+	}
+	InitForceFlag = cli.BoolFlag{
+		Name:  "force",
+		Usage: "Overwrite the output file when it exists",
+	}
+	JSONFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the result as JSON",
+	}
+	HistoryStartBlockFlag = cli.Uint64Flag{
+		Name:  "start-block",
+		Usage: "First block to scan for updates",
+	}
+	HistoryEndBlockFlag = cli.Uint64Flag{
+		Name:  "end-block",
+		Usage: "Last block to scan for updates, the tip when unset",
+	}
+	HistoryFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Value: "csv",
+		Usage: "Format of the export, csv or json",
+	}
+	HistoryOutputFlag = cli.StringFlag{
+		Name:  "output",
+		Value: "-",
+		Usage: "Path to write the export to, - for stdout",
+	}
+	HistorySourceFlag = cli.StringFlag{
+		Name:  "source",
+		Value: "chain",
+		Usage: "Source of the updates, chain to scan the events of the contract or postgres to read the database of --postgres.url",
+	}
+	DecisionsFromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Time of the first decision to export, RFC 3339 or unix seconds, the oldest when unset",
+	}
+	DecisionsToFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "Time of the last decision to export, RFC 3339 or unix seconds, now when unset",
+	}
+	DecisionsFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Value: "csv",
+		Usage: "Format of the export, csv, json or signed for the signed records as JSON lines",
+	}
+	VerifyDecisionsInputFlag = cli.StringFlag{
+		Name:  "input",
+		Value: "-",
+		Usage: "Path to the signed decisions of export-decisions --format signed, - for stdin",
+	}
+	VerifyDecisionsSignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "Address that must have signed every decision, any signer when unset",
+	}
+	WatchStartBlockFlag = cli.Uint64Flag{
+		Name:  "start-block",
+		Usage: "First block to print the updates of, the next block when unset",
+	}
+	WatchPollIntervalFlag = cli.DurationFlag{
+		Name:  "poll-interval",
+		Value: 2 * time.Second,
+		Usage: "Interval at which new blocks are polled for updates",
+	}
+	FeeTxFlag = cli.StringFlag{
+		Name:  "tx",
+		Usage: "Hex encoded serialized transaction to estimate the fee of",
+	}
+	FeeCalldataSizeFlag = cli.Uint64Flag{
+		Name:  "calldata-size",
+		Usage: "Size in bytes of a transaction to estimate the fee of, instead of --tx",
+	}
+	FeeGasLimitFlag = cli.Uint64Flag{
+		Name:  "gas-limit",
+		Usage: "Gas limit of the transaction, read from --tx when unset",
+	}
+	SimulateTraceFlag = cli.StringFlag{
+		Name:  "trace",
+		Usage: "Path to the demand trace, such as the JSON output of a backtest, - for stdin",
+	}
+	SimulateTargetsFlag = cli.StringFlag{
+		Name:  "targets",
+		Usage: "Target gas per second values to sweep as a list such as 5000000,10000000 or a range such as 5000000:15000000:2500000",
+	}
+	SimulateMaxChangesFlag = cli.StringFlag{
+		Name:  "max-changes",
+		Usage: "Max percent change per epoch values to sweep as a list or a range such as 0.05:0.2:0.05",
+	}
+	SimulateSignificanceFactorsFlag = cli.StringFlag{
+		Name:  "significance-factors",
+		Usage: "L2 gas price significance factors to sweep as a list or a range such as 0.01:0.1:0.01",
+	}
+	SimulateInitialGasPriceFlag = cli.StringFlag{
+		Name:  "initial-gas-price",
+		Usage: "L2 gas price at the start of the trace such as 0.001gwei, the first gas price of the trace when unset",
+	}
+	CheckMinBalanceFlag = cli.StringFlag{
+		Name:  "min-balance",
+		Usage: "Minimum balance of the signer such as 1ether, the low balance threshold when unset",
+	}
+	ManualYesFlag = cli.BoolFlag{
+		Name:  "yes",
+		Usage: "Send the transaction without asking for confirmation",
+	}
+	ManualForceFlag = cli.BoolFlag{
+		Name:  "force",
+		Usage: "Send the transaction even when the value is outside of the configured bounds",
+	}
+)
+
+var ExportHistoryFlags = []cli.Flag{
+	HistoryStartBlockFlag,
+	HistoryEndBlockFlag,
+	HistoryFormatFlag,
+	HistoryOutputFlag,
+	HistorySourceFlag,
+}
+
+var ExportDecisionsFlags = []cli.Flag{
+	DecisionsFromFlag,
+	DecisionsToFlag,
+	DecisionsFormatFlag,
+	HistoryOutputFlag,
+}
+
+var VerifyDecisionsFlags = []cli.Flag{
+	VerifyDecisionsInputFlag,
+	VerifyDecisionsSignerFlag,
+}
+
+var WatchFlags = []cli.Flag{
+	WatchStartBlockFlag,
+	WatchPollIntervalFlag,
+	JSONFlag,
+}
+
+var EstimateFeeFlags = []cli.Flag{
+	FeeTxFlag,
+	FeeCalldataSizeFlag,
+	FeeGasLimitFlag,
+	JSONFlag,
+}
+
+var SimulateFlags = []cli.Flag{
+	SimulateTraceFlag,
+	SimulateTargetsFlag,
+	SimulateMaxChangesFlag,
+	SimulateSignificanceFactorsFlag,
+	SimulateInitialGasPriceFlag,
+	JSONFlag,
+}
+
+var CheckFlags = []cli.Flag{
+	CheckMinBalanceFlag,
+	JSONFlag,
+}
+
+var SetGasPriceFlags = []cli.Flag{
+	ManualYesFlag,
+	ManualForceFlag,
+}
+
+var SetL1BaseFeeFlags = []cli.Flag{
+	ManualYesFlag,
+	ManualForceFlag,
+}
+
+var OwnerFlags = []cli.Flag{
+	JSONFlag,
+}
+
+var TransferOwnershipFlags = []cli.Flag{
+	ManualYesFlag,
+}
+
+var AcceptOwnershipFlags = []cli.Flag{
+	ManualYesFlag,
+}
+
+var StatusFlags = []cli.Flag{
+	JSONFlag,
+}
+
+var InitFlags = []cli.Flag{
+	InitOutputFlag,
+	InitForceFlag,
+}
+
+var BacktestFlags = []cli.Flag{
+	BacktestStartBlockFlag,
+	BacktestEndBlockFlag,
+	BacktestInitialGasPriceFlag,
+	BacktestUpdateTxGasFlag,
+	JSONFlag,
+}
+
 var Flags = []cli.Flag{
+	ConfigFileFlag,
+	NetworkFlag,
+	StrictConfigFlag,
 	EthereumHttpUrlFlag,
 	LayerTwoHttpUrlFlag,
+	RPCTLSCertFlag,
+	RPCTLSKeyFlag,
+	RPCTLSCAFlag,
 	L1ChainIDFlag,
 	L2ChainIDFlag,
 	L1BaseFeeSignificanceFactorFlag,
 	GasPriceOracleAddressFlag,
 	PrivateKeyFlag,
+	PrivateKeyFileFlag,
 	TransactionGasPriceFlag,
 	LogLevelFlag,
+	LogFormatFlag,
+	PricerFlag,
+	CanaryIncumbentPricerFlag,
+	CanaryMaxDeltaFlag,
+	ShadowPricerFlag,
+	ShadowTargetGasPerSecondFlag,
+	ShadowMaxPercentChangePerEpochFlag,
 	FloorPriceFlag,
+	MaxGasPriceFlag,
+	SanityMinGasPriceFlag,
+	SanityMaxGasPriceFlag,
 	TargetGasPerSecondFlag,
 	MaxPercentChangePerEpochFlag,
+	DailyPriceChangeBudgetFlag,
 	AverageBlockGasLimitPerEpochFlag,
+	EpochLengthFlag,
 	EpochLengthSecondsFlag,
+	MinUpdateIntervalFlag,
+	MinUpdateIntervalSecondsFlag,
+	L1BaseFeeEpochLengthFlag,
 	L1BaseFeeEpochLengthSecondsFlag,
 	L2GasPriceSignificanceFactorFlag,
+	L2GasPriceIncreaseSignificanceFactorFlag,
+	L2GasPriceDecreaseSignificanceFactorFlag,
+	EnableAdaptiveSignificanceFlag,
+	AdaptiveSignificanceEpochsFlag,
+	AdaptiveSignificanceMinFactorFlag,
+	AdaptiveSignificanceMaxFactorFlag,
+	GasPriceRoundingFlag,
+	GasPriceRoundingIncrementFlag,
+	GasPriceRoundingDigitsFlag,
+	EnableSurgePricingFlag,
+	SurgeThresholdFlag,
+	SurgeEpochsFlag,
+	SurgeFactorFlag,
+	SurgeMaxMultiplierFlag,
+	SurgeDecayRateFlag,
+	EnableIdleDecayFlag,
+	IdleDecayThresholdFlag,
+	IdleDecayEpochsFlag,
+	IdleDecayRateFlag,
+	DemandGasWeightFlag,
+	DemandTxWeightFlag,
+	DemandCalldataWeightFlag,
+	DemandBlockTagFlag,
+	DemandConfirmationsFlag,
+	ReorgReuseDemandFlag,
+	EnableOutlierRejectionFlag,
+	OutlierMethodFlag,
+	OutlierWindowFlag,
+	OutlierMinSamplesFlag,
+	OutlierThresholdFlag,
+	SmoothingEpochsFlag,
+	SmoothingMethodFlag,
+	EnableTxPoolSignalFlag,
+	TxPoolSignalWeightFlag,
+	TxPoolQueuedWeightFlag,
+	TxPoolGasPerTxFlag,
+	BalanceCheckIntervalFlag,
+	BalanceCheckIntervalSecondsFlag,
+	LowBalanceThresholdFlag,
+	LowBalanceWebhookURLFlag,
+	FeeVaultAddressFlag,
+	FeeVaultIntervalFlag,
+	WebhookURLFlag,
+	SlackWebhookURLFlag,
+	DiscordWebhookURLFlag,
+	ChatMinPriceChangeFlag,
+	ChatFailureThresholdFlag,
+	PagerDutyRoutingKeyFlag,
+	PagerDutyFailureThresholdFlag,
+	PagerDutyURLFlag,
+	PublishURLFlag,
+	PublishTopicFlag,
+	PostgresURLFlag,
+	AuditLogFlag,
+	StateDBFlag,
+	PriceHistoryRetentionFlag,
+	WarmStartBlocksFlag,
+	ImplementationCheckIntervalFlag,
+	OwnerCheckIntervalFlag,
+	GasTokenRateFeedFlag,
+	GasTokenMaxRateAgeFlag,
+	WatchExternalUpdatesFlag,
+	DriftToleranceFlag,
+	HeartbeatURLFlag,
+	HALeaseURLFlag,
+	HALeaseNameFlag,
+	HALeaseTTLFlag,
+	HALeaseTLSCAFlag,
+	HAReplicaIDFlag,
+	EpochDeadlineFlag,
+	StaleUpdateEpochsFlag,
+	StaleUpdateDemandChangeFlag,
 	WaitForReceiptFlag,
+	DryRunFlag,
+	OneShotFlag,
+	AdminAddrFlag,
+	AdminGRPCAddrFlag,
+	AdminTokenFlag,
+	AdminJWTSecretFileFlag,
+	AdminBindAllowlistFlag,
+	ApprovalTokenFlag,
+	ApprovalTTLFlag,
+	PriceAPIAddrFlag,
 	EnableL1BaseFeeFlag,
 	EnableL2GasPriceFlag,
+	SystemConfigAddressFlag,
+	SystemConfigOverheadFlag,
+	SystemConfigScalarFlag,
+	SystemConfigEnableGasConfigFlag,
+	SystemConfigIntervalFlag,
+	SystemConfigOperatorFeeScalarFlag,
+	SystemConfigOperatorFeeConstantFlag,
+	SystemConfigMaxOperatorFeeScalarFlag,
+	SystemConfigMaxOperatorFeeConstantFlag,
+	SystemConfigOperatorFeeSignificanceFactorFlag,
+	L1TxResubmitTimeoutFlag,
+	L1TxMaxFeeFlag,
+	SafeAddressFlag,
+	SafeServiceURLFlag,
 	MetricsEnabledFlag,
 	MetricsHTTPFlag,
 	MetricsPortFlag,
+	MetricsBackendFlag,
+	MetricsStatsDAddressFlag,
+	MetricsStatsDPrefixFlag,
+	MetricsStatsDTagsFlag,
+	MetricsCloudWatchNamespaceFlag,
+	MetricsCloudWatchRegionFlag,
+	MetricsCloudWatchDimensionsFlag,
+	MetricsPushgatewayURLFlag,
+	MetricsPushgatewayJobFlag,
 	MetricsEnableInfluxDBFlag,
 	MetricsInfluxDBEndpointFlag,
 	MetricsInfluxDBDatabaseFlag,
 	MetricsInfluxDBUsernameFlag,
 	MetricsInfluxDBPasswordFlag,
+	TracingOTLPEndpointFlag,
+	TracingOTLPInsecureFlag,
+	TracingSampleRatioFlag,
+	SentryDSNFlag,
+	SentryEnvironmentFlag,
+	SentryFailureThresholdFlag,
 }
@@ -0,0 +1,83 @@
+package gasprices
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// DemandObserver is notified of the average gas per second of every epoch
+// that is completed by the GasPriceUpdater
+type DemandObserver interface {
+	ObserveDemand(avgGasPerSecond float64)
+}
+
+// AdaptiveSignificance scales the significance factor with the volatility of
+// recent demand. Volatility is measured as the coefficient of variation, the
+// standard deviation divided by the mean, of the average gas per second over
+// the last N epochs. Quiet chains use the max factor so that they do not
+// churn updates, while volatile chains approach the min factor so that they
+// still respond quickly.
+type AdaptiveSignificance struct {
+	mu        sync.RWMutex
+	epochs    int
+	minFactor float64
+	maxFactor float64
+	samples   []float64
+}
+
+// NewAdaptiveSignificance creates an AdaptiveSignificance that tracks the
+// last epochs number of epochs
+func NewAdaptiveSignificance(epochs uint64, minFactor, maxFactor float64) (*AdaptiveSignificance, error) {
+	if epochs < 2 {
+		return nil, errors.New("adaptive significance epochs must be greater than or equal to 2")
+	}
+	if minFactor < 0 || maxFactor > 1 || minFactor > maxFactor {
+		return nil, errors.New("adaptive significance factors must satisfy 0 <= min <= max <= 1")
+	}
+	return &AdaptiveSignificance{
+		epochs:    int(epochs),
+		minFactor: minFactor,
+		maxFactor: maxFactor,
+	}, nil
+}
+
+// ObserveDemand records the average gas per second of an epoch
+func (a *AdaptiveSignificance) ObserveDemand(avgGasPerSecond float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.samples = append(a.samples, avgGasPerSecond)
+	if len(a.samples) > a.epochs {
+		a.samples = a.samples[len(a.samples)-a.epochs:]
+	}
+}
+
+// Volatility returns the coefficient of variation of the recorded demand
+func (a *AdaptiveSignificance) Volatility() float64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return coefficientOfVariation(a.samples)
+}
+
+// Factor returns the significance factor for the current volatility. Until
+// enough epochs have been observed the max factor is used.
+func (a *AdaptiveSignificance) Factor() float64 {
+	a.mu.RLock()
+	n := len(a.samples)
+	a.mu.RUnlock()
+	if n < 2 {
+		return a.maxFactor
+	}
+	volatility := math.Min(1, a.Volatility())
+	return a.maxFactor - (a.maxFactor-a.minFactor)*volatility
+}
+
+// coefficientOfVariation returns the population standard deviation of the
+// samples divided by their mean
+func coefficientOfVariation(samples []float64) float64 {
+	mean, stdDev := meanAndStdDev(samples)
+	if mean == 0 {
+		return 0
+	}
+	return stdDev / mean
+}
@@ -0,0 +1,56 @@
+package gasprices
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAdaptiveSignificance(t *testing.T) {
+	a, err := NewAdaptiveSignificance(4, 0.01, 0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Not enough samples yet
+	if f := a.Factor(); f != 0.2 {
+		t.Fatalf("expected max factor, got %f", f)
+	}
+
+	// Constant demand has no volatility
+	for i := 0; i < 4; i++ {
+		a.ObserveDemand(100)
+	}
+	if f := a.Factor(); f != 0.2 {
+		t.Fatalf("expected max factor for quiet demand, got %f", f)
+	}
+
+	// Only the last 4 epochs are tracked. The mean is 100 and the
+	// standard deviation is 50.
+	a.ObserveDemand(50)
+	a.ObserveDemand(150)
+	a.ObserveDemand(50)
+	a.ObserveDemand(150)
+	if v := a.Volatility(); math.Abs(v-0.5) > 1e-9 {
+		t.Fatalf("expected volatility of 0.5, got %f", v)
+	}
+	if f := a.Factor(); math.Abs(f-0.105) > 1e-9 {
+		t.Fatalf("expected factor of 0.105, got %f", f)
+	}
+
+	// Very volatile demand is bounded by the min factor
+	a.ObserveDemand(0)
+	a.ObserveDemand(0)
+	a.ObserveDemand(0)
+	a.ObserveDemand(1000)
+	if f := a.Factor(); math.Abs(f-0.01) > 1e-9 {
+		t.Fatalf("expected min factor for volatile demand, got %f", f)
+	}
+}
+
+func TestNewAdaptiveSignificanceInvalid(t *testing.T) {
+	if _, err := NewAdaptiveSignificance(1, 0.01, 0.2); err == nil {
+		t.Fatal("expected error for a single epoch")
+	}
+	if _, err := NewAdaptiveSignificance(10, 0.3, 0.2); err == nil {
+		t.Fatal("expected error for min greater than max")
+	}
+}
@@ -0,0 +1,107 @@
+package gasprices
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// BlockUsage is the amount of each resource that a block used
+type BlockUsage struct {
+	GasUsed       uint64
+	TxCount       uint64
+	CalldataBytes uint64
+}
+
+type GetBlockUsageFn func(*big.Int) (*BlockUsage, error)
+
+// DemandWeights converts the resources used by a block into the gas
+// equivalent demand that is compared against the target gas per second.
+// This allows chains that are bound by calldata rather than execution gas
+// to be priced on the resource that is actually scarce.
+type DemandWeights struct {
+	// Gas is the weight of each unit of gas used
+	Gas float64
+	// Tx is the gas equivalent of each transaction
+	Tx float64
+	// CalldataByte is the gas equivalent of each byte of calldata
+	CalldataByte float64
+}
+
+// DefaultDemandWeights only count the gas used by blocks
+var DefaultDemandWeights = DemandWeights{Gas: 1}
+
+// Validate ensures that the weights are usable
+func (w DemandWeights) Validate() error {
+	if w.Gas < 0 || w.Tx < 0 || w.CalldataByte < 0 {
+		return errors.New("demand weights cannot be negative")
+	}
+	if w.Gas == 0 && w.Tx == 0 && w.CalldataByte == 0 {
+		return errors.New("at least one demand weight must be positive")
+	}
+	return nil
+}
+
+// IsDefault returns true when only the gas used by blocks is counted
+func (w DemandWeights) IsDefault() bool {
+	return w == DefaultDemandWeights
+}
+
+// Demand returns the gas equivalent demand of a block
+func (w DemandWeights) Demand(usage *BlockUsage) float64 {
+	return w.Gas*float64(usage.GasUsed) +
+		w.Tx*float64(usage.TxCount) +
+		w.CalldataByte*float64(usage.CalldataBytes)
+}
+
+// SetBlockUsage counts the weighted usage of each block towards the demand
+// of an epoch instead of only the gas used
+func (g *GasPriceUpdater) SetBlockUsage(getBlockUsageFn GetBlockUsageFn, weights DemandWeights) error {
+	if getBlockUsageFn == nil {
+		return errors.New("getBlockUsageFn cannot be nil")
+	}
+	if err := weights.Validate(); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.getBlockUsageFn = getBlockUsageFn
+	g.demandWeights = weights
+	return nil
+}
+
+// epochDemand accumulates the demand of the blocks in the epoch ending at
+// the latest block
+func (g *GasPriceUpdater) epochDemand(latestBlockNumber uint64) (float64, error) {
+	if g.getBlockUsageFn == nil {
+		// Accumulate the amount of gas that has been used in the epoch
+		totalGasUsed := uint64(0)
+		for i := g.epochStartBlockNumber + 1; i <= latestBlockNumber; i++ {
+			gasUsed, err := g.getGasUsedByBlockFn(new(big.Int).SetUint64(i))
+			log.Trace("fetching gas used", "height", i, "gas-used", gasUsed, "total-gas", totalGasUsed)
+			if err != nil {
+				return 0, err
+			}
+			totalGasUsed += gasUsed
+		}
+		return float64(totalGasUsed), nil
+	}
+
+	total := new(BlockUsage)
+	for i := g.epochStartBlockNumber + 1; i <= latestBlockNumber; i++ {
+		usage, err := g.getBlockUsageFn(new(big.Int).SetUint64(i))
+		if err != nil {
+			return 0, err
+		}
+		log.Trace("fetching block usage", "height", i, "gas-used", usage.GasUsed,
+			"txs", usage.TxCount, "calldata-bytes", usage.CalldataBytes)
+		total.GasUsed += usage.GasUsed
+		total.TxCount += usage.TxCount
+		total.CalldataBytes += usage.CalldataBytes
+	}
+	demand := g.demandWeights.Demand(total)
+	log.Debug("epoch usage", "gas-used", total.GasUsed, "txs", total.TxCount,
+		"calldata-bytes", total.CalldataBytes, "demand", demand)
+	return demand, nil
+}
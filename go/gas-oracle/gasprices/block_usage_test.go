@@ -0,0 +1,62 @@
+package gasprices
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestDemandWeights(t *testing.T) {
+	usage := &BlockUsage{GasUsed: 1000, TxCount: 2, CalldataBytes: 100}
+	if d := DefaultDemandWeights.Demand(usage); d != 1000 {
+		t.Fatalf("expected 1000, got %f", d)
+	}
+	weights := DemandWeights{Gas: 0.5, Tx: 100, CalldataByte: 16}
+	if d := weights.Demand(usage); d != 500+200+1600 {
+		t.Fatalf("expected 2300, got %f", d)
+	}
+
+	if err := weights.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (DemandWeights{}).Validate(); err == nil {
+		t.Fatal("expected error for all zero weights")
+	}
+	if err := (DemandWeights{Gas: 1, Tx: -1}).Validate(); err == nil {
+		t.Fatal("expected error for negative weights")
+	}
+	if !DefaultDemandWeights.IsDefault() || weights.IsDefault() {
+		t.Fatal("unexpected IsDefault")
+	}
+}
+
+func TestUpdateGasPriceUsesBlockUsage(t *testing.T) {
+	_, gasUpdater, incrementCurrentBlock, err := makeTestGasPricerAndUpdater(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := new(mockDemandObserver)
+	gasUpdater.AddDemandObserver(observer)
+
+	getBlockUsage := func(number *big.Int) (*BlockUsage, error) {
+		return &BlockUsage{GasUsed: 1000, TxCount: 10, CalldataBytes: 5000}, nil
+	}
+	// A calldata bound chain that only counts calldata
+	if err := gasUpdater.SetBlockUsage(getBlockUsage, DemandWeights{CalldataByte: 16}); err != nil {
+		t.Fatal(err)
+	}
+	incrementCurrentBlock(3)
+	if err := gasUpdater.UpdateGasPrice(); err != nil {
+		t.Fatal(err)
+	}
+	// 3 blocks of 5000 calldata bytes at 16 gas per byte over 10 seconds
+	if observer.observed[0] != 24000 {
+		t.Fatalf("unexpected demand observed: %f", observer.observed[0])
+	}
+
+	if err := gasUpdater.SetBlockUsage(nil, DefaultDemandWeights); err == nil {
+		t.Fatal("expected error for nil getBlockUsageFn")
+	}
+	if err := gasUpdater.SetBlockUsage(getBlockUsage, DemandWeights{}); err == nil {
+		t.Fatal("expected error for invalid weights")
+	}
+}
@@ -0,0 +1,121 @@
+package gasprices
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// CanaryPricer safely rolls out a new pricer. Every epoch both the candidate
+// and the incumbent pricer compute a gas price from the same demand. The
+// candidate gas price is used only when it is within MaxDelta of the
+// incumbent gas price, otherwise the incumbent gas price is used and the
+// fallback function is called so that the operator can be alerted.
+type CanaryPricer struct {
+	mu         sync.Mutex
+	candidate  Pricer
+	incumbent  Pricer
+	maxDelta   float64
+	onFallback func(candidate, incumbent *big.Int, divergence float64)
+	price      *big.Int
+}
+
+// NewCanaryPricer creates a CanaryPricer that starts at the incumbent gas
+// price. The onFallback function may be nil.
+func NewCanaryPricer(candidate, incumbent Pricer, maxDelta float64, onFallback func(candidate, incumbent *big.Int, divergence float64)) (*CanaryPricer, error) {
+	if maxDelta < 0 {
+		return nil, errors.New("canary max delta cannot be negative")
+	}
+	return &CanaryPricer{
+		candidate:  candidate,
+		incumbent:  incumbent,
+		maxDelta:   maxDelta,
+		onFallback: onFallback,
+		price:      incumbent.GetGasPrice(),
+	}, nil
+}
+
+// CompleteEpoch completes the epoch of both pricers and returns the
+// candidate gas price if it is within the max delta of the incumbent
+func (c *CanaryPricer) CompleteEpoch(avgGasPerSecondLastEpoch float64) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	candidate, err := c.candidate.CompleteEpoch(avgGasPerSecondLastEpoch)
+	if err != nil {
+		return nil, err
+	}
+	incumbent, err := c.incumbent.CompleteEpoch(avgGasPerSecondLastEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	divergence := Divergence(incumbent, candidate)
+	if math.Abs(divergence) > c.maxDelta {
+		log.Warn("canary pricer diverged, falling back to incumbent", "candidate", candidate,
+			"incumbent", incumbent, "divergence", divergence, "max-delta", c.maxDelta)
+		if c.onFallback != nil {
+			c.onFallback(candidate, incumbent, divergence)
+		}
+		c.price = incumbent
+	} else {
+		log.Debug("canary pricer accepted", "candidate", candidate,
+			"incumbent", incumbent, "divergence", divergence)
+		c.price = candidate
+	}
+	return new(big.Int).Set(c.price), nil
+}
+
+// GetGasPrice returns the gas price of the last epoch
+func (c *CanaryPricer) GetGasPrice() *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return new(big.Int).Set(c.price)
+}
+
+// SetFloor sets the floor price of both pricers
+func (c *CanaryPricer) SetFloor(floorPrice *big.Int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.candidate.SetFloor(floorPrice); err != nil {
+		return err
+	}
+	if err := c.incumbent.SetFloor(floorPrice); err != nil {
+		return err
+	}
+	c.price = maxBig(c.price, floorPrice)
+	return nil
+}
+
+// SetMaxPrice sets the max price of both pricers
+func (c *CanaryPricer) SetMaxPrice(maxPrice *big.Int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.candidate.SetMaxPrice(maxPrice); err != nil {
+		return err
+	}
+	if err := c.incumbent.SetMaxPrice(maxPrice); err != nil {
+		return err
+	}
+	if c.price.Cmp(maxPrice) > 0 {
+		c.price = new(big.Int).Set(maxPrice)
+	}
+	return nil
+}
+
+// SetGasPrice moves the gas price of both pricers
+func (c *CanaryPricer) SetGasPrice(price *big.Int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.candidate.SetGasPrice(price); err != nil {
+		return err
+	}
+	if err := c.incumbent.SetGasPrice(price); err != nil {
+		return err
+	}
+	c.price = c.incumbent.GetGasPrice()
+	return nil
+}
@@ -0,0 +1,68 @@
+package gasprices
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestCanaryPricer(t *testing.T) {
+	target := func() float64 { return 10 }
+	candidate, err := NewGasPricer(big.NewInt(100), big.NewInt(1), target, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	incumbent, err := NewFixedPricer(big.NewInt(100), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fallbacks := 0
+	canary, err := NewCanaryPricer(candidate, incumbent, 0.3, func(c, i *big.Int, divergence float64) {
+		fallbacks++
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		demand    float64
+		expect    int64
+		fallbacks int
+	}{
+		// The candidate moves to 120 which is within 30% of 100
+		{12, 120, 0},
+		// 180 is too far from 100 so the incumbent is used
+		{15, 100, 1},
+		// The candidate returns to 90 which is accepted again
+		{5, 90, 1},
+	}
+	for i, tt := range tests {
+		gp, err := canary.CompleteEpoch(tt.demand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gp.Cmp(big.NewInt(tt.expect)) != 0 {
+			t.Fatalf("epoch %d: expected %d, got %s", i, tt.expect, gp)
+		}
+		if canary.GetGasPrice().Cmp(gp) != 0 {
+			t.Fatalf("epoch %d: unexpected gas price %s", i, canary.GetGasPrice())
+		}
+		if fallbacks != tt.fallbacks {
+			t.Fatalf("epoch %d: expected %d fallbacks, got %d", i, tt.fallbacks, fallbacks)
+		}
+	}
+
+	if err := canary.SetMaxPrice(big.NewInt(50)); err != nil {
+		t.Fatal(err)
+	}
+	if canary.GetGasPrice().Cmp(big.NewInt(50)) != 0 {
+		t.Fatalf("expected price capped, got %s", canary.GetGasPrice())
+	}
+	if incumbent.GetGasPrice().Cmp(big.NewInt(50)) != 0 {
+		t.Fatalf("expected incumbent capped, got %s", incumbent.GetGasPrice())
+	}
+
+	if _, err := NewCanaryPricer(candidate, incumbent, -1, nil); err == nil {
+		t.Fatal("expected error for a negative max delta")
+	}
+}
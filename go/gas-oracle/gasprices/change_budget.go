@@ -0,0 +1,109 @@
+package gasprices
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// changeBudgetWindow is the window over which the cumulative price change
+// budget is tracked
+const changeBudgetWindow = 24 * time.Hour
+
+// pricePoint is a gas price at a point in time
+type pricePoint struct {
+	time  time.Time
+	price *big.Int
+}
+
+// changeBudget bounds how much the gas price can move within a window of
+// time. The price can never be more than (1 + budget) times the lowest price
+// in the window or less than (1 - budget) times the highest price in the
+// window, so a sequence of maximal per epoch moves cannot multiply the price
+// severalfold.
+type changeBudget struct {
+	budget  *big.Rat
+	window  time.Duration
+	history []pricePoint
+}
+
+func newChangeBudget(budget float64, window time.Duration) (*changeBudget, error) {
+	if budget <= 0 {
+		return nil, errors.New("price change budget must be greater than 0")
+	}
+	r, err := NewRatFromFloat(budget)
+	if err != nil {
+		return nil, err
+	}
+	return &changeBudget{
+		budget: r,
+		window: window,
+	}, nil
+}
+
+// prune removes prices that are no longer in the window
+func (c *changeBudget) prune(now time.Time) {
+	i := 0
+	for i < len(c.history) && now.Sub(c.history[i].time) >= c.window {
+		i++
+	}
+	c.history = c.history[i:]
+}
+
+// bound returns the price limited by the remaining budget
+func (c *changeBudget) bound(price *big.Int, now time.Time) *big.Int {
+	c.prune(now)
+	if len(c.history) == 0 {
+		return price
+	}
+	lowest, highest := c.history[0].price, c.history[0].price
+	for _, p := range c.history[1:] {
+		if p.price.Cmp(lowest) < 0 {
+			lowest = p.price
+		}
+		if p.price.Cmp(highest) > 0 {
+			highest = p.price
+		}
+	}
+	// Round the upper bound down and the lower bound up so that the
+	// budget is never exceeded
+	upper := new(big.Rat).Add(ratOne, c.budget)
+	upper.Mul(upper, new(big.Rat).SetInt(lowest))
+	upperBound := new(big.Int).Quo(upper.Num(), upper.Denom())
+	if price.Cmp(upperBound) > 0 {
+		log.Debug("Gas price bounded by change budget", "price", price, "bound", upperBound)
+		return upperBound
+	}
+	lower := new(big.Rat).Sub(ratOne, c.budget)
+	if lower.Sign() <= 0 {
+		return price
+	}
+	lowerBound := mulCeil(highest, lower)
+	if price.Cmp(lowerBound) < 0 {
+		log.Debug("Gas price bounded by change budget", "price", price, "bound", lowerBound)
+		return lowerBound
+	}
+	return price
+}
+
+// record adds a price to the history
+func (c *changeBudget) record(price *big.Int, now time.Time) {
+	c.history = append(c.history, pricePoint{time: now, price: new(big.Int).Set(price)})
+}
+
+// recordAt adds a price from the past to the history, keeping it in order of
+// time. Prices that already left the window are ignored.
+func (c *changeBudget) recordAt(price *big.Int, at, now time.Time) {
+	if now.Sub(at) >= c.window {
+		return
+	}
+	i := sort.Search(len(c.history), func(i int) bool {
+		return c.history[i].time.After(at)
+	})
+	c.history = append(c.history, pricePoint{})
+	copy(c.history[i+1:], c.history[i:])
+	c.history[i] = pricePoint{time: at, price: new(big.Int).Set(price)}
+}
@@ -0,0 +1,82 @@
+package gasprices
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDailyChangeBudget(t *testing.T) {
+	gp, err := NewGasPricer(big.NewInt(100), big.NewInt(1), returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	gp.now = func() time.Time { return now }
+	// The price may not move by more than 50% within 24 hours
+	if err := gp.SetDailyChangeBudget(0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := func(demand float64, expect uint64) {
+		t.Helper()
+		price, err := gp.CompleteEpoch(demand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price.Uint64() != expect {
+			t.Fatalf("mismatch. Got %d, expected %d", price, expect)
+		}
+		now = now.Add(time.Hour)
+	}
+
+	// Maximal increases are allowed until the budget is used up
+	complete(100, 150)
+	complete(100, 150)
+	// The lower bound is half of the highest price in the window
+	complete(0, 75)
+	complete(0, 75)
+
+	// Once the earliest prices have left the window the upper bound is
+	// based on the lowest remaining price and rounded down
+	now = now.Add(20 * time.Hour)
+	complete(100, 112)
+}
+
+func TestDailyChangeBudgetInvalid(t *testing.T) {
+	gp, err := NewGasPricer(big.NewInt(100), big.NewInt(1), returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gp.SetDailyChangeBudget(0); err == nil {
+		t.Fatal("expected zero budget to fail")
+	}
+}
+
+func TestDailyChangeBudgetPastPrices(t *testing.T) {
+	gp, err := NewGasPricer(big.NewInt(100), big.NewInt(1), returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	gp.now = func() time.Time { return now }
+	if err := gp.SetDailyChangeBudget(0.5); err != nil {
+		t.Fatal(err)
+	}
+	// A price that left the window does not count
+	gp.RecordPastPrice(big.NewInt(10), now.Add(-25*time.Hour))
+	// The price was doubled an hour ago, so it may only rise to 1.5 times
+	// the price before that
+	gp.RecordPastPrice(big.NewInt(50), now.Add(-time.Hour))
+
+	price, err := gp.CompleteEpoch(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Uint64() != 75 {
+		t.Fatalf("mismatch. Got %d, expected 75", price)
+	}
+	if len(gp.changeBudget.history) != 3 || gp.changeBudget.history[0].price.Uint64() != 50 {
+		t.Fatalf("unexpected history %+v", gp.changeBudget.history)
+	}
+}
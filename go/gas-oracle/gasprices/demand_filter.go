@@ -0,0 +1,8 @@
+package gasprices
+
+// DemandFilter transforms the average gas per second of a completed epoch
+// before it is used by the pricer. This allows additional demand signals to
+// be blended into the estimate from the gas used by blocks.
+type DemandFilter interface {
+	FilterDemand(avgGasPerSecond float64) (float64, error)
+}
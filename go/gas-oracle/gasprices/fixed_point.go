@@ -0,0 +1,113 @@
+package gasprices
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+var (
+	// bigOne is the constant 1 as a big.Int
+	bigOne = big.NewInt(1)
+	// ratOne is the constant 1 as a big.Rat
+	ratOne = big.NewRat(1, 1)
+	// maxWei is the largest amount that the uint256 of the GasPriceOracle holds
+	maxWei = new(big.Int).Sub(new(big.Int).Lsh(bigOne, 256), bigOne)
+)
+
+// maxExactFloat is the largest float64 below which every whole number is
+// exactly representable
+const maxExactFloat = 1 << 53
+
+var (
+	// ErrInvalidFloat represents the error when a float64 is NaN, infinite,
+	// negative or too large to be precise
+	ErrInvalidFloat = errors.New("invalid floating point value")
+	// ErrInvalidWei represents the error when a gas price cannot be sent to
+	// the GasPriceOracle
+	ErrInvalidWei = errors.New("invalid wei amount")
+)
+
+// NewRatFromFloat converts a float64 into a big.Rat using its shortest
+// decimal representation. This means that a configured value of 0.1 is
+// exactly 1/10 rather than the nearest binary float, which prevents rounding
+// the gas price up by an extra wei after multiplication.
+func NewRatFromFloat(f float64) (*big.Rat, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFloat, f)
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %v to a rational number", f)
+	}
+	return r, nil
+}
+
+// CheckQuantity makes sure that a float64 such as the demand of an epoch
+// can be priced. Above 2^53 a float64 skips whole numbers, so the value was
+// most likely produced by an overflow rather than measured.
+func CheckQuantity(f float64) error {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		return fmt.Errorf("%w: %v", ErrInvalidFloat, f)
+	case f < 0:
+		return fmt.Errorf("%w: %v is negative", ErrInvalidFloat, f)
+	case f > maxExactFloat:
+		return fmt.Errorf("%w: %v is above %d and not precise", ErrInvalidFloat, f, uint64(maxExactFloat))
+	}
+	return nil
+}
+
+// CheckWei makes sure that a gas price fits in the uint256 of the
+// GasPriceOracle, rather than wrapping around when it is encoded
+func CheckWei(amount *big.Int) error {
+	switch {
+	case amount == nil:
+		return fmt.Errorf("%w: no amount", ErrInvalidWei)
+	case amount.Sign() < 0:
+		return fmt.Errorf("%w: %s is negative", ErrInvalidWei, amount)
+	case amount.Cmp(maxWei) > 0:
+		return fmt.Errorf("%w: %s does not fit in 256 bits", ErrInvalidWei, amount)
+	}
+	return nil
+}
+
+// ceilRat rounds a non-negative big.Rat up to the nearest integer
+func ceilRat(r *big.Rat) *big.Int {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, bigOne)
+	}
+	return q
+}
+
+// mulCeil multiplies an integer by a big.Rat and rounds the result up
+func mulCeil(x *big.Int, r *big.Rat) *big.Int {
+	return ceilRat(new(big.Rat).Mul(new(big.Rat).SetInt(x), r))
+}
+
+// maxBig returns a copy of the larger of two big.Ints
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
+
+// minRat returns the smaller of two big.Rats
+func minRat(a, b *big.Rat) *big.Rat {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+// maxRat returns the larger of two big.Rats
+func maxRat(a, b *big.Rat) *big.Rat {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
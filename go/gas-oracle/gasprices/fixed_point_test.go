@@ -0,0 +1,37 @@
+package gasprices
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestCheckQuantity(t *testing.T) {
+	for _, f := range []float64{0, 0.5, 15000000, maxExactFloat} {
+		if err := CheckQuantity(f); err != nil {
+			t.Fatalf("unexpected error for %v: %v", f, err)
+		}
+	}
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), -1, maxExactFloat * 2} {
+		if err := CheckQuantity(f); !errors.Is(err, ErrInvalidFloat) {
+			t.Fatalf("expected %v for %v, got %v", ErrInvalidFloat, f, err)
+		}
+	}
+	if _, err := NewRatFromFloat(math.NaN()); !errors.Is(err, ErrInvalidFloat) {
+		t.Fatalf("expected %v, got %v", ErrInvalidFloat, err)
+	}
+}
+
+func TestCheckWei(t *testing.T) {
+	for _, amount := range []*big.Int{big.NewInt(0), big.NewInt(1), maxWei} {
+		if err := CheckWei(amount); err != nil {
+			t.Fatalf("unexpected error for %v: %v", amount, err)
+		}
+	}
+	for _, amount := range []*big.Int{nil, big.NewInt(-1), new(big.Int).Add(maxWei, bigOne)} {
+		if err := CheckWei(amount); !errors.Is(err, ErrInvalidWei) {
+			t.Fatalf("expected %v for %v, got %v", ErrInvalidWei, amount, err)
+		}
+	}
+}
@@ -4,30 +4,44 @@ import (
 	"errors"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 )
 
 type GetLatestBlockNumberFn func() (uint64, error)
-type UpdateL2GasPriceFn func(uint64) error
+type UpdateL2GasPriceFn func(*big.Int) error
 type GetGasUsedByBlockFn func(*big.Int) (uint64, error)
 
+// ReanchorFn is called when the latest block is below the start of the
+// epoch, such as after a reorg or a reset of the chain. The epoch is
+// re-anchored at the latest block and it is completed with the average gas
+// per second that is returned when ok, such as the demand of the last epoch
+// that was recorded. The demand is not filtered again. The epoch is skipped
+// otherwise.
+type ReanchorFn func(epochStartBlockNumber, latestBlockNumber uint64) (avgGasPerSecond float64, ok bool)
+
 type GasPriceUpdater struct {
 	mu                     *sync.RWMutex
-	gasPricer              *GasPricer
+	gasPricer              Pricer
 	epochStartBlockNumber  uint64
 	averageBlockGasLimit   uint64
-	epochLengthSeconds     uint64
+	epochLength            time.Duration
 	getLatestBlockNumberFn GetLatestBlockNumberFn
 	getGasUsedByBlockFn    GetGasUsedByBlockFn
 	updateL2GasPriceFn     UpdateL2GasPriceFn
+	demandObservers        []DemandObserver
+	demandFilters          []DemandFilter
+	getBlockUsageFn        GetBlockUsageFn
+	demandWeights          DemandWeights
+	reanchorFn             ReanchorFn
 }
 
 func NewGasPriceUpdater(
-	gasPricer *GasPricer,
+	gasPricer Pricer,
 	epochStartBlockNumber uint64,
 	averageBlockGasLimit uint64,
-	epochLengthSeconds uint64,
+	epochLength time.Duration,
 	getLatestBlockNumberFn GetLatestBlockNumberFn,
 	getGasUsedByBlockFn GetGasUsedByBlockFn,
 	updateL2GasPriceFn UpdateL2GasPriceFn,
@@ -35,14 +49,14 @@ func NewGasPriceUpdater(
 	if averageBlockGasLimit < 1 {
 		return nil, errors.New("averageBlockGasLimit cannot be less than 1 gas")
 	}
-	if epochLengthSeconds < 1 {
-		return nil, errors.New("epochLengthSeconds cannot be less than 1 second")
+	if epochLength < time.Second {
+		return nil, errors.New("epochLength cannot be less than 1 second")
 	}
 	return &GasPriceUpdater{
 		mu:                     new(sync.RWMutex),
 		gasPricer:              gasPricer,
 		epochStartBlockNumber:  epochStartBlockNumber,
-		epochLengthSeconds:     epochLengthSeconds,
+		epochLength:            epochLength,
 		averageBlockGasLimit:   averageBlockGasLimit,
 		getLatestBlockNumberFn: getLatestBlockNumberFn,
 		getGasUsedByBlockFn:    getGasUsedByBlockFn,
@@ -50,16 +64,69 @@ func NewGasPriceUpdater(
 	}, nil
 }
 
+// AddDemandObserver registers a DemandObserver that is notified of the
+// average gas per second of each completed epoch
+func (g *GasPriceUpdater) AddDemandObserver(o DemandObserver) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.demandObservers = append(g.demandObservers, o)
+}
+
+// AddDemandFilter registers a DemandFilter that is applied to the average
+// gas per second of each completed epoch. Filters are applied in the order
+// that they are added.
+func (g *GasPriceUpdater) AddDemandFilter(f DemandFilter) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.demandFilters = append(g.demandFilters, f)
+}
+
+// SetReanchorFn sets the function that is called when the epoch is
+// re-anchored
+func (g *GasPriceUpdater) SetReanchorFn(fn ReanchorFn) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.reanchorFn = fn
+}
+
 func (g *GasPriceUpdater) UpdateGasPrice() error {
+	return g.CloseEpoch(g.epochLength)
+}
+
+// CloseEpoch completes the current epoch after it ran for the elapsed time,
+// which is shorter than the epoch length when an epoch is closed early. The
+// demand is divided by the elapsed time in floating point, so the fraction
+// of a short epoch is kept.
+func (g *GasPriceUpdater) CloseEpoch(elapsed time.Duration) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	if elapsed > g.epochLength {
+		elapsed = g.epochLength
+	}
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+
 	latestBlockNumber, err := g.getLatestBlockNumberFn()
 	if err != nil {
 		return err
 	}
 	if latestBlockNumber < g.epochStartBlockNumber {
-		return errors.New("Latest block number less than the last epoch's block number")
+		// The blocks of the epoch are gone, so the epoch restarts at the
+		// latest block instead of waiting for the chain to catch up
+		log.Warn("Latest block number is below the epoch start, re-anchoring the epoch",
+			"epoch-start", g.epochStartBlockNumber, "latest", latestBlockNumber)
+		previous := g.epochStartBlockNumber
+		g.epochStartBlockNumber = latestBlockNumber
+		if g.reanchorFn == nil {
+			return nil
+		}
+		averageGasPerSecond, ok := g.reanchorFn(previous, latestBlockNumber)
+		if !ok {
+			return nil
+		}
+		return g.completeEpoch(averageGasPerSecond, latestBlockNumber)
 	}
 
 	if latestBlockNumber == g.epochStartBlockNumber {
@@ -67,34 +134,67 @@ func (g *GasPriceUpdater) UpdateGasPrice() error {
 		return nil
 	}
 
-	// Accumulate the amount of gas that has been used in the epoch
-	totalGasUsed := uint64(0)
-	for i := g.epochStartBlockNumber + 1; i <= latestBlockNumber; i++ {
-		gasUsed, err := g.getGasUsedByBlockFn(new(big.Int).SetUint64(i))
-		log.Trace("fetching gas used", "height", i, "gas-used", gasUsed, "total-gas", totalGasUsed)
+	totalDemand, err := g.epochDemand(latestBlockNumber)
+	if err != nil {
+		return err
+	}
+
+	averageGasPerSecond := totalDemand / elapsed.Seconds()
+	for _, f := range g.demandFilters {
+		averageGasPerSecond, err = f.FilterDemand(averageGasPerSecond)
 		if err != nil {
 			return err
 		}
-		totalGasUsed += gasUsed
 	}
 
-	averageGasPerSecond := float64(totalGasUsed) / float64(g.epochLengthSeconds)
+	return g.completeEpoch(averageGasPerSecond, latestBlockNumber)
+}
 
-	log.Debug("UpdateGasPrice", "average-gas-per-second", averageGasPerSecond, "current-price", g.gasPricer.curPrice)
-	_, err = g.gasPricer.CompleteEpoch(averageGasPerSecond)
+// completeEpoch prices the demand of the epoch that ends at the latest
+// block and sends the gas price
+func (g *GasPriceUpdater) completeEpoch(averageGasPerSecond float64, latestBlockNumber uint64) error {
+	log.Debug("UpdateGasPrice", "average-gas-per-second", averageGasPerSecond, "current-price", g.gasPricer.GetGasPrice())
+	gasPrice, err := g.gasPricer.CompleteEpoch(averageGasPerSecond)
 	if err != nil {
 		return err
 	}
 	g.epochStartBlockNumber = latestBlockNumber
-	err = g.updateL2GasPriceFn(g.gasPricer.curPrice)
+	for _, o := range g.demandObservers {
+		o.ObserveDemand(averageGasPerSecond)
+	}
+	err = g.updateL2GasPriceFn(new(big.Int).Set(gasPrice))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *GasPriceUpdater) GetGasPrice() uint64 {
+// EpochStartBlockNumber returns the last block of the previous epoch, the
+// demand of the current epoch is measured from the block after it
+func (g *GasPriceUpdater) EpochStartBlockNumber() uint64 {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	return g.gasPricer.curPrice
+	return g.epochStartBlockNumber
+}
+
+// SetEpochStartBlockNumber moves the start of the current epoch, so that an
+// epoch that was started elsewhere is continued from its first block
+func (g *GasPriceUpdater) SetEpochStartBlockNumber(number uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.epochStartBlockNumber = number
+}
+
+func (g *GasPriceUpdater) GetGasPrice() *big.Int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.gasPricer.GetGasPrice()
+}
+
+// SetGasPrice moves the gas price of the gas pricer, so that the next epoch
+// starts from a gas price that was set outside of the updater
+func (g *GasPriceUpdater) SetGasPrice(price *big.Int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.gasPricer.SetGasPrice(price)
 }
@@ -2,23 +2,25 @@ package gasprices
 
 import (
 	"math/big"
+	"sync"
 	"testing"
+	"time"
 )
 
 type MockEpoch struct {
 	numBlocks   uint64
 	repeatCount uint64
-	postHook    func(prevGasPrice uint64, gasPriceUpdater *GasPriceUpdater)
+	postHook    func(prevGasPrice *big.Int, gasPriceUpdater *GasPriceUpdater)
 }
 
 // Return a gas pricer that targets 3 blocks per epoch & 10% max change per epoch.
 func makeTestGasPricerAndUpdater(curPrice uint64) (*GasPricer, *GasPriceUpdater, func(uint64), error) {
 	gpsTarget := 990000.3
 	getGasTarget := func() float64 { return gpsTarget }
-	epochLengthSeconds := uint64(10)
+	epochLength := 10 * time.Second
 	averageBlockGasLimit := uint64(11000000)
 	// Based on our 10 second epoch, we are targetting 3 blocks per epoch.
-	gasPricer, err := NewGasPricer(curPrice, 1, getGasTarget, 10)
+	gasPricer, err := NewGasPricer(new(big.Int).SetUint64(curPrice), big.NewInt(1), getGasTarget, 10)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -26,14 +28,14 @@ func makeTestGasPricerAndUpdater(curPrice uint64) (*GasPricer, *GasPriceUpdater,
 	curBlock := uint64(10)
 	incrementCurrentBlock := func(newBlockNum uint64) { curBlock += newBlockNum }
 	getLatestBlockNumber := func() (uint64, error) { return curBlock, nil }
-	updateL2GasPrice := func(x uint64) error {
+	updateL2GasPrice := func(x *big.Int) error {
 		return nil
 	}
 
 	// This is paramaterized based on 3 blocks per epoch, where each uses
 	// the average block gas limit plus an additional bit of gas added
 	getGasUsedByBlockFn := func(number *big.Int) (uint64, error) {
-		return averageBlockGasLimit*3/epochLengthSeconds + 1, nil
+		return averageBlockGasLimit*3/uint64(epochLength.Seconds()) + 1, nil
 	}
 
 	startBlock, _ := getLatestBlockNumber()
@@ -41,7 +43,7 @@ func makeTestGasPricerAndUpdater(curPrice uint64) (*GasPricer, *GasPriceUpdater,
 		gasPricer,
 		startBlock,
 		averageBlockGasLimit,
-		epochLengthSeconds,
+		epochLength,
 		getLatestBlockNumber,
 		getGasUsedByBlockFn,
 		updateL2GasPrice,
@@ -58,7 +60,7 @@ func TestUpdateGasPriceCallsUpdateL2GasPriceFn(t *testing.T) {
 		t.Fatal(err)
 	}
 	wasCalled := false
-	gasUpdater.updateL2GasPriceFn = func(gasPrice uint64) error {
+	gasUpdater.updateL2GasPriceFn = func(gasPrice *big.Int) error {
 		wasCalled = true
 		return nil
 	}
@@ -71,6 +73,198 @@ func TestUpdateGasPriceCallsUpdateL2GasPriceFn(t *testing.T) {
 	}
 }
 
+type mockDemandObserver struct {
+	observed []float64
+}
+
+func (m *mockDemandObserver) ObserveDemand(avgGasPerSecond float64) {
+	m.observed = append(m.observed, avgGasPerSecond)
+}
+
+func TestUpdateGasPriceNotifiesDemandObservers(t *testing.T) {
+	_, gasUpdater, incrementCurrentBlock, err := makeTestGasPricerAndUpdater(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := new(mockDemandObserver)
+	gasUpdater.AddDemandObserver(observer)
+	incrementCurrentBlock(3)
+	if err := gasUpdater.UpdateGasPrice(); err != nil {
+		t.Fatal(err)
+	}
+	if len(observer.observed) != 1 {
+		t.Fatalf("expected 1 observation, got %d", len(observer.observed))
+	}
+	// 3 blocks of 3300001 gas over 10 seconds
+	if observer.observed[0] != 990000.3 {
+		t.Fatalf("unexpected demand observed: %f", observer.observed[0])
+	}
+}
+
+type mockDemandFilter struct {
+	add float64
+}
+
+func (m *mockDemandFilter) FilterDemand(avgGasPerSecond float64) (float64, error) {
+	return avgGasPerSecond + m.add, nil
+}
+
+func TestUpdateGasPriceAppliesDemandFilters(t *testing.T) {
+	_, gasUpdater, incrementCurrentBlock, err := makeTestGasPricerAndUpdater(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := new(mockDemandObserver)
+	gasUpdater.AddDemandObserver(observer)
+	gasUpdater.AddDemandFilter(&mockDemandFilter{add: 9.7})
+	gasUpdater.AddDemandFilter(&mockDemandFilter{add: 10})
+	incrementCurrentBlock(3)
+	if err := gasUpdater.UpdateGasPrice(); err != nil {
+		t.Fatal(err)
+	}
+	// The observers see the demand after all of the filters
+	if observer.observed[0] != 990020 {
+		t.Fatalf("unexpected demand observed: %f", observer.observed[0])
+	}
+}
+
+func TestCloseEpochAveragesOverTheElapsedTime(t *testing.T) {
+	_, gasUpdater, incrementCurrentBlock, err := makeTestGasPricerAndUpdater(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := new(mockDemandObserver)
+	gasUpdater.AddDemandObserver(observer)
+	incrementCurrentBlock(3)
+	// Closing halfway through the epoch doubles the demand per second
+	if err := gasUpdater.CloseEpoch(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	incrementCurrentBlock(3)
+	// The elapsed time is bounded by the epoch length
+	if err := gasUpdater.CloseEpoch(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if observer.observed[0] != 1980000.6 || observer.observed[1] != 990000.3 {
+		t.Fatalf("unexpected demand observed: %v", observer.observed)
+	}
+}
+
+func TestCloseEpochKeepsTheFractionOfShortEpochs(t *testing.T) {
+	// For any usage of a short epoch, the average is the exact quotient of
+	// the demand and the elapsed time rather than a truncated integer
+	for _, gasUsed := range []uint64{1, 7, 21001, 11000001} {
+		for blocks := uint64(1); blocks <= 3; blocks++ {
+			for _, elapsed := range []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second, 3 * time.Second, 7 * time.Second} {
+				gasPricer, err := NewGasPricer(big.NewInt(1), big.NewInt(1), returnConstFn(1), 10)
+				if err != nil {
+					t.Fatal(err)
+				}
+				latest := blocks
+				gasUpdater, err := NewGasPriceUpdater(gasPricer, 0, 11000000, 10*time.Second,
+					func() (uint64, error) { return latest, nil },
+					func(*big.Int) (uint64, error) { return gasUsed, nil },
+					func(*big.Int) error { return nil })
+				if err != nil {
+					t.Fatal(err)
+				}
+				observer := new(mockDemandObserver)
+				gasUpdater.AddDemandObserver(observer)
+				if err := gasUpdater.CloseEpoch(elapsed); err != nil {
+					t.Fatal(err)
+				}
+				expected := float64(gasUsed*blocks) / elapsed.Seconds()
+				if observer.observed[0] != expected {
+					t.Fatalf("%d gas in %d blocks over %s: expected %v, got %v",
+						gasUsed, blocks, elapsed, expected, observer.observed[0])
+				}
+			}
+		}
+	}
+
+	// An epoch that is closed within a second is averaged over a second
+	gasPricer, _ := NewGasPricer(big.NewInt(1), big.NewInt(1), returnConstFn(1), 10)
+	gasUpdater, err := NewGasPriceUpdater(gasPricer, 0, 11000000, 10*time.Second,
+		func() (uint64, error) { return 1, nil },
+		func(*big.Int) (uint64, error) { return 3, nil },
+		func(*big.Int) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := new(mockDemandObserver)
+	gasUpdater.AddDemandObserver(observer)
+	if err := gasUpdater.CloseEpoch(time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if observer.observed[0] != 3 {
+		t.Fatalf("expected the demand to be averaged over a second, got %v", observer.observed[0])
+	}
+}
+
+func TestSetEpochStartBlockNumber(t *testing.T) {
+	_, gasUpdater, incrementCurrentBlock, err := makeTestGasPricerAndUpdater(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := new(mockDemandObserver)
+	gasUpdater.AddDemandObserver(observer)
+	incrementCurrentBlock(3)
+	// The epoch continues from a later block, so only 2 blocks are measured
+	gasUpdater.SetEpochStartBlockNumber(11)
+	if err := gasUpdater.UpdateGasPrice(); err != nil {
+		t.Fatal(err)
+	}
+	if observer.observed[0] != 660000.2 || gasUpdater.EpochStartBlockNumber() != 13 {
+		t.Fatalf("unexpected demand observed: %v", observer.observed)
+	}
+}
+
+func TestGasPriceUpdaterConcurrentAccess(t *testing.T) {
+	_, gasUpdater, _, err := makeTestGasPricerAndUpdater(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var latest uint64 = 10
+	var latestMu sync.Mutex
+	gasUpdater.getLatestBlockNumberFn = func() (uint64, error) {
+		latestMu.Lock()
+		defer latestMu.Unlock()
+		latest++
+		return latest, nil
+	}
+
+	// The update loop, the queries of the APIs and the manual updates run
+	// on their own goroutines
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				switch i {
+				case 0:
+					if err := gasUpdater.UpdateGasPrice(); err != nil {
+						t.Error(err)
+					}
+				case 1:
+					gasUpdater.EpochStartBlockNumber()
+					gasUpdater.GetGasPrice()
+				case 2:
+					if err := gasUpdater.SetGasPrice(big.NewInt(int64(j + 1))); err != nil {
+						t.Error(err)
+					}
+				case 3:
+					gasUpdater.SetEpochStartBlockNumber(gasUpdater.EpochStartBlockNumber())
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if gasUpdater.GetGasPrice().Sign() <= 0 {
+		t.Fatalf("unexpected gas price %d", gasUpdater.GetGasPrice())
+	}
+}
+
 func TestUpdateGasPriceCorrectlyUpdatesAZeroBlockEpoch(t *testing.T) {
 	gasPricer, gasUpdater, _, err := makeTestGasPricerAndUpdater(100)
 	if err != nil {
@@ -78,28 +272,65 @@ func TestUpdateGasPriceCorrectlyUpdatesAZeroBlockEpoch(t *testing.T) {
 	}
 	gasPriceBefore := gasPricer.curPrice
 	gasPriceAfter := gasPricer.curPrice
-	gasUpdater.updateL2GasPriceFn = func(gasPrice uint64) error {
+	gasUpdater.updateL2GasPriceFn = func(gasPrice *big.Int) error {
 		gasPriceAfter = gasPrice
 		return nil
 	}
 	if err := gasUpdater.UpdateGasPrice(); err != nil {
 		t.Fatal(err)
 	}
-	if gasPriceBefore < gasPriceAfter {
+	if gasPriceBefore.Cmp(gasPriceAfter) < 0 {
 		t.Fatalf("Expected gasPrice to go down because we had fewer than 3 blocks in the epoch.")
 	}
 }
 
-func TestUpdateGasPriceFailsIfBlockNumberGoesBackwards(t *testing.T) {
+func TestUpdateGasPriceReanchorsIfBlockNumberGoesBackwards(t *testing.T) {
 	_, gasUpdater, _, err := makeTestGasPricerAndUpdater(1)
 	if err != nil {
 		t.Fatal(err)
 	}
-	gasUpdater.epochStartBlockNumber = 10
-	gasUpdater.getLatestBlockNumberFn = func() (uint64, error) { return 0, nil }
-	err = gasUpdater.UpdateGasPrice()
-	if err == nil {
-		t.Fatalf("Expected UpdateGasPrice to fail when block number goes backwards.")
+	updates := 0
+	gasUpdater.updateL2GasPriceFn = func(*big.Int) error {
+		updates++
+		return nil
+	}
+	observer := new(mockDemandObserver)
+	gasUpdater.AddDemandObserver(observer)
+	gasUpdater.AddDemandFilter(&mockDemandFilter{add: 10})
+	latest := uint64(4)
+	gasUpdater.getLatestBlockNumberFn = func() (uint64, error) { return latest, nil }
+
+	// Without a ReanchorFn the epoch is skipped and restarts at the latest
+	// block
+	if err := gasUpdater.UpdateGasPrice(); err != nil {
+		t.Fatal(err)
+	}
+	if gasUpdater.EpochStartBlockNumber() != 4 || updates != 0 {
+		t.Fatalf("expected the epoch to restart at block 4, got %d", gasUpdater.EpochStartBlockNumber())
+	}
+	// The next epoch is measured from the new start
+	latest = 7
+	if err := gasUpdater.UpdateGasPrice(); err != nil {
+		t.Fatal(err)
+	}
+	if updates != 1 || observer.observed[0] != 990010.3 {
+		t.Fatalf("expected the epoch to be measured, got %v", observer.observed)
+	}
+
+	// The ReanchorFn completes the epoch with the demand that it returns,
+	// which is not filtered again
+	gasUpdater.SetReanchorFn(func(epochStart, latestBlockNumber uint64) (float64, bool) {
+		if epochStart != 7 || latestBlockNumber != 2 {
+			t.Fatalf("unexpected re-anchor from %d to %d", epochStart, latestBlockNumber)
+		}
+		return 500000, true
+	})
+	latest = 2
+	if err := gasUpdater.UpdateGasPrice(); err != nil {
+		t.Fatal(err)
+	}
+	if updates != 2 || observer.observed[1] != 500000 || gasUpdater.EpochStartBlockNumber() != 2 {
+		t.Fatalf("expected the epoch to be completed with the returned demand, got %v", observer.observed)
 	}
 }
 
@@ -115,9 +346,9 @@ func TestUsageOfGasPriceUpdater(t *testing.T) {
 			numBlocks:   10,
 			repeatCount: 3,
 			// Make sure the gas price is increasing
-			postHook: func(prevGasPrice uint64, gasPriceUpdater *GasPriceUpdater) {
-				curPrice := gasPriceUpdater.gasPricer.curPrice
-				if prevGasPrice >= curPrice {
+			postHook: func(prevGasPrice *big.Int, gasPriceUpdater *GasPriceUpdater) {
+				curPrice := gasPriceUpdater.GetGasPrice()
+				if prevGasPrice.Cmp(curPrice) >= 0 {
 					t.Fatalf("Expected gas price to increase. Got %d, was %d", curPrice, prevGasPrice)
 				}
 			},
@@ -126,19 +357,20 @@ func TestUsageOfGasPriceUpdater(t *testing.T) {
 		MockEpoch{
 			numBlocks:   3,
 			repeatCount: 5,
-			postHook:    func(prevGasPrice uint64, gasPriceUpdater *GasPriceUpdater) {},
+			postHook:    func(prevGasPrice *big.Int, gasPriceUpdater *GasPriceUpdater) {},
 		},
 		MockEpoch{
 			numBlocks:   3,
 			repeatCount: 0,
-			postHook: func(prevGasPrice uint64, gasPriceUpdater *GasPriceUpdater) {
-				curPrice := gasPriceUpdater.gasPricer.curPrice
-				if prevGasPrice != curPrice {
+			postHook: func(prevGasPrice *big.Int, gasPriceUpdater *GasPriceUpdater) {
+				curPrice := gasPriceUpdater.GetGasPrice()
+				if prevGasPrice.Cmp(curPrice) != 0 {
 					t.Fatalf("Expected gas price to stablize. Got %d, was %d", curPrice, prevGasPrice)
 				}
 
-				targetGps := gasPriceUpdater.gasPricer.getTargetGasPerSecond()
-				averageGps := gasPriceUpdater.gasPricer.avgGasPerSecondLastEpoch
+				gasPricer := gasPriceUpdater.gasPricer.(*GasPricer)
+				targetGps := gasPricer.getTargetGasPerSecond()
+				averageGps := gasPricer.avgGasPerSecondLastEpoch
 				if targetGps != averageGps {
 					t.Fatalf("Average gas/second (%f) did not converge to target (%f)",
 						averageGps, targetGps)
@@ -149,16 +381,16 @@ func TestUsageOfGasPriceUpdater(t *testing.T) {
 		MockEpoch{
 			numBlocks:   1,
 			repeatCount: 5,
-			postHook: func(prevGasPrice uint64, gasPriceUpdater *GasPriceUpdater) {
-				curPrice := gasPriceUpdater.gasPricer.curPrice
-				if prevGasPrice <= curPrice && curPrice != gasPriceUpdater.gasPricer.floorPrice {
+			postHook: func(prevGasPrice *big.Int, gasPriceUpdater *GasPriceUpdater) {
+				curPrice := gasPriceUpdater.GetGasPrice()
+				if prevGasPrice.Cmp(curPrice) <= 0 && curPrice.Cmp(gasPriceUpdater.gasPricer.(*GasPricer).floorPrice) != 0 {
 					t.Fatalf("Expected gas price either reduce or be at the floor.")
 				}
 			},
 		},
 	}
 	loop := func(epoch MockEpoch) {
-		prevGasPrice := gasUpdater.gasPricer.curPrice
+		prevGasPrice := gasUpdater.GetGasPrice()
 		incrementCurrentBlock(epoch.numBlocks)
 		err = gasUpdater.UpdateGasPrice()
 		if err != nil {
@@ -0,0 +1,79 @@
+package gasprices
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// IdleDecayConfig configures the optional decay of the gas price toward the
+// floor price. Once the chain has been idle for a number of consecutive
+// epochs, the gas price moves toward the floor price by a fraction of its
+// distance from the floor each epoch so that it does not stay elevated after
+// a spike in traffic ends.
+type IdleDecayConfig struct {
+	// Threshold is the average gas per second at or below which an epoch
+	// is considered idle
+	Threshold float64
+	// Epochs is the number of consecutive idle epochs before the gas price
+	// starts decaying
+	Epochs uint64
+	// Rate is the fraction of the distance to the floor price that is
+	// removed each idle epoch
+	Rate float64
+}
+
+// idleDecayParams is the IdleDecayConfig converted into exact ratios
+type idleDecayParams struct {
+	threshold  float64
+	epochs     uint64
+	retainRate *big.Rat
+}
+
+// EnableIdleDecay turns on idle decay with the given config
+func (p *GasPricer) EnableIdleDecay(cfg IdleDecayConfig) error {
+	if cfg.Threshold < 0 {
+		return errors.New("idle decay threshold cannot be negative")
+	}
+	if cfg.Epochs < 1 {
+		return errors.New("idle decay epochs must be greater than or equal to 1")
+	}
+	if cfg.Rate <= 0 || cfg.Rate > 1 {
+		return errors.New("idle decay rate must be between (0,1]")
+	}
+	rate, err := NewRatFromFloat(cfg.Rate)
+	if err != nil {
+		return err
+	}
+	p.idleDecay = &idleDecayParams{
+		threshold:  cfg.Threshold,
+		epochs:     cfg.Epochs,
+		retainRate: new(big.Rat).Sub(ratOne, rate),
+	}
+	p.idleEpochs = 0
+	return nil
+}
+
+// applyIdleDecay lowers the next gas price to the decayed current gas price
+// once enough consecutive idle epochs have been observed. The decayed price
+// is rounded down so that it eventually reaches the floor price.
+func (p *GasPricer) applyIdleDecay(gp *big.Int, avgGasPerSecondLastEpoch float64) *big.Int {
+	if avgGasPerSecondLastEpoch > p.idleDecay.threshold {
+		p.idleEpochs = 0
+		return gp
+	}
+	p.idleEpochs++
+	if p.idleEpochs < p.idleDecay.epochs || p.curPrice.Cmp(p.floorPrice) <= 0 {
+		return gp
+	}
+	excess := new(big.Int).Sub(p.curPrice, p.floorPrice)
+	excess.Mul(excess, p.idleDecay.retainRate.Num())
+	excess.Quo(excess, p.idleDecay.retainRate.Denom())
+	decayed := excess.Add(excess, p.floorPrice)
+	if decayed.Cmp(gp) >= 0 {
+		return gp
+	}
+	log.Debug("Applied idle decay", "idle-epochs", p.idleEpochs, "result", decayed)
+	return decayed
+}
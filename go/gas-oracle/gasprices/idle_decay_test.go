@@ -0,0 +1,82 @@
+package gasprices
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGasPricerIdleDecay(t *testing.T) {
+	pricer, err := NewGasPricer(big.NewInt(1000), big.NewInt(100), func() float64 { return 10 }, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pricer.EnableIdleDecay(IdleDecayConfig{
+		Threshold: 0.5,
+		Epochs:    2,
+		Rate:      0.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		avgGasPerSecond float64
+		expect          int64
+	}{
+		// The first idle epoch only applies the max change
+		{0, 990},
+		// Then the distance to the floor is halved each epoch
+		{0.5, 545},
+		{0, 322},
+		// Demand at the target resets the idle epochs
+		{10, 322},
+		{0, 319},
+		{0, 209},
+	}
+	for i, tt := range tests {
+		gp, err := pricer.CompleteEpoch(tt.avgGasPerSecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gp.Cmp(big.NewInt(tt.expect)) != 0 {
+			t.Fatalf("epoch %d: expected %d, got %s", i, tt.expect, gp)
+		}
+	}
+}
+
+func TestGasPricerIdleDecayReachesFloor(t *testing.T) {
+	pricer, err := NewGasPricer(big.NewInt(103), big.NewInt(100), func() float64 { return 10 }, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pricer.EnableIdleDecay(IdleDecayConfig{Epochs: 1, Rate: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []int64{101, 100, 100} {
+		gp, err := pricer.CompleteEpoch(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gp.Cmp(big.NewInt(expect)) != 0 {
+			t.Fatalf("expected %d, got %s", expect, gp)
+		}
+	}
+}
+
+func TestGasPricerIdleDecayInvalidConfig(t *testing.T) {
+	pricer, err := NewGasPricer(big.NewInt(100), big.NewInt(1), func() float64 { return 10 }, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configs := []IdleDecayConfig{
+		{Threshold: -1, Epochs: 1, Rate: 0.1},
+		{Threshold: 0, Epochs: 0, Rate: 0.1},
+		{Threshold: 0, Epochs: 1, Rate: 0},
+		{Threshold: 0, Epochs: 1, Rate: 1.1},
+	}
+	for i, cfg := range configs {
+		if err := pricer.EnableIdleDecay(cfg); err == nil {
+			t.Fatalf("config %d: expected error", i)
+		}
+	}
+}
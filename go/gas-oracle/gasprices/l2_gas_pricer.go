@@ -3,21 +3,67 @@ package gasprices
 import (
 	"errors"
 	"fmt"
-	"math"
+	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 )
 
 type GetTargetGasPerSecond func() float64
 
+// GasPricer computes the L2 gas price. Prices are represented as big.Int and
+// all of the ratios applied to them are exact big.Rat values so that no
+// precision is lost at high wei values.
 type GasPricer struct {
-	curPrice                 uint64
+	curPrice                 *big.Int
 	avgGasPerSecondLastEpoch float64
-	floorPrice               uint64
+	floorPrice               *big.Int
+	maxPrice                 *big.Int
 	getTargetGasPerSecond    GetTargetGasPerSecond
-	maxChangePerEpoch        float64
+	maxChangePerEpoch        *big.Rat
+	surge                    *surgeParams
+	surgeMultiplier          *big.Rat
+	surgeEpochs              uint64
+	changeBudget             *changeBudget
+	idleDecay                *idleDecayParams
+	idleEpochs               uint64
+	now                      func() time.Time
 }
 
+// SurgeConfig configures the optional surge pricing mode. When demand stays
+// above the target for a sustained number of epochs, the gas price is
+// multiplied by a surge multiplier that grows each epoch. Once demand drops
+// the multiplier decays exponentially back towards 1.
+type SurgeConfig struct {
+	// Threshold is the proportion of the target gas per second that an
+	// epoch must meet or exceed to count towards a surge
+	Threshold float64
+	// Epochs is the number of consecutive epochs at or above the threshold
+	// required before the surge multiplier starts growing
+	Epochs uint64
+	// Factor is what the surge multiplier is multiplied by for each epoch
+	// that the surge is sustained
+	Factor float64
+	// MaxMultiplier bounds the surge multiplier
+	MaxMultiplier float64
+	// DecayRate is the fraction of the excess surge multiplier that is
+	// removed each epoch once demand drops below the threshold
+	DecayRate float64
+}
+
+// surgeParams is the SurgeConfig converted into exact ratios
+type surgeParams struct {
+	threshold     *big.Rat
+	epochs        uint64
+	factor        *big.Rat
+	maxMultiplier *big.Rat
+	retainRate    *big.Rat
+}
+
+// surgeSnapThreshold is the distance from 1 at which a decaying surge
+// multiplier is considered to be fully decayed
+var surgeSnapThreshold = big.NewRat(1, 1_000_000_000)
+
 // LinearInterpolation can be used to dynamically update target gas per second
 func GetLinearInterpolationFn(getX func() float64, x1 float64, x2 float64, y1 float64, y2 float64) func() float64 {
 	return func() float64 {
@@ -25,69 +71,276 @@ func GetLinearInterpolationFn(getX func() float64, x1 float64, x2 float64, y1 fl
 	}
 }
 
-// NewGasPricer creates a GasPricer and checks its config beforehand
-func NewGasPricer(curPrice, floorPrice uint64, getTargetGasPerSecond GetTargetGasPerSecond, maxPercentChangePerEpoch float64) (*GasPricer, error) {
-	if floorPrice < 1 {
+// NewGasPricer creates a GasPricer and checks its config beforehand. The
+// maxPercentChangePerEpoch is interpreted by its decimal representation, so
+// 0.1 is exactly a 10% change.
+func NewGasPricer(curPrice, floorPrice *big.Int, getTargetGasPerSecond GetTargetGasPerSecond, maxPercentChangePerEpoch float64) (*GasPricer, error) {
+	if floorPrice == nil || floorPrice.Cmp(bigOne) < 0 {
 		return nil, errors.New("floorPrice must be greater than or equal to 1")
 	}
+	if curPrice == nil {
+		return nil, errors.New("curPrice cannot be nil")
+	}
 	if maxPercentChangePerEpoch <= 0 {
 		return nil, errors.New("maxPercentChangePerEpoch must be between (0,100]")
 	}
+	maxChangePerEpoch, err := NewRatFromFloat(maxPercentChangePerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maxPercentChangePerEpoch: %w", err)
+	}
 	return &GasPricer{
-		curPrice:              max(curPrice, floorPrice),
-		floorPrice:            floorPrice,
+		curPrice:              maxBig(curPrice, floorPrice),
+		floorPrice:            new(big.Int).Set(floorPrice),
 		getTargetGasPerSecond: getTargetGasPerSecond,
-		maxChangePerEpoch:     maxPercentChangePerEpoch,
+		maxChangePerEpoch:     maxChangePerEpoch,
 	}, nil
 }
 
-// CalcNextEpochGasPrice calculates the next gas price given some average
-// gas per second over the last epoch
-func (p *GasPricer) CalcNextEpochGasPrice(avgGasPerSecondLastEpoch float64) (uint64, error) {
+// SetMaxPrice sets a hard cap that the gas price will never exceed
+// regardless of demand
+func (p *GasPricer) SetMaxPrice(maxPrice *big.Int) error {
+	if maxPrice == nil {
+		return errors.New("maxPrice cannot be nil")
+	}
+	if maxPrice.Cmp(p.floorPrice) < 0 {
+		return fmt.Errorf("maxPrice %s cannot be less than floorPrice %s", maxPrice, p.floorPrice)
+	}
+	p.maxPrice = new(big.Int).Set(maxPrice)
+	p.curPrice = p.capPrice(p.curPrice)
+	return nil
+}
+
+// SetFloor sets the price that the gas price will never go below and raises
+// the current gas price to it if necessary
+func (p *GasPricer) SetFloor(floorPrice *big.Int) error {
+	if err := validateFloor(floorPrice, p.maxPrice); err != nil {
+		return err
+	}
+	p.floorPrice = new(big.Int).Set(floorPrice)
+	p.curPrice = maxBig(p.curPrice, floorPrice)
+	return nil
+}
+
+// GetGasPrice returns the current gas price
+func (p *GasPricer) GetGasPrice() *big.Int {
+	return new(big.Int).Set(p.curPrice)
+}
+
+// SetGasPrice moves the current gas price that the next epoch starts from
+func (p *GasPricer) SetGasPrice(price *big.Int) error {
+	if price == nil {
+		return errors.New("price cannot be nil")
+	}
+	p.curPrice = p.capPrice(maxBig(price, p.floorPrice))
+	return nil
+}
+
+// SetDailyChangeBudget limits the cumulative change of the gas price within
+// any 24 hour window to the budget, where 1 is a 100% change
+func (p *GasPricer) SetDailyChangeBudget(budget float64) error {
+	c, err := newChangeBudget(budget, changeBudgetWindow)
+	if err != nil {
+		return err
+	}
+	p.changeBudget = c
+	p.changeBudget.record(p.curPrice, p.timeNow())
+	return nil
+}
+
+// RecordPastPrice adds a gas price that was in effect at a time in the past
+// to the window of the daily change budget, so that the budget accounts for
+// the changes made before the gas pricer was created. It does nothing
+// without a daily change budget.
+func (p *GasPricer) RecordPastPrice(price *big.Int, at time.Time) {
+	if p.changeBudget == nil || price == nil {
+		return
+	}
+	p.changeBudget.recordAt(price, at, p.timeNow())
+}
+
+// SetClock sets the clock used by time based features of the gas pricer,
+// which allows historical data to be replayed
+func (p *GasPricer) SetClock(now func() time.Time) {
+	p.now = now
+}
+
+// timeNow returns the current time
+func (p *GasPricer) timeNow() time.Time {
+	if p.now == nil {
+		return time.Now()
+	}
+	return p.now()
+}
+
+// capPrice bounds the price by the max price if one is set
+func (p *GasPricer) capPrice(price *big.Int) *big.Int {
+	if p.maxPrice != nil && price.Cmp(p.maxPrice) > 0 {
+		log.Debug("Gas price capped by max price", "price", price, "max-price", p.maxPrice)
+		return new(big.Int).Set(p.maxPrice)
+	}
+	return price
+}
+
+// EnableSurge turns on surge pricing with the given config
+func (p *GasPricer) EnableSurge(cfg SurgeConfig) error {
+	if cfg.Threshold < 1 {
+		return errors.New("surge threshold must be greater than or equal to 1")
+	}
+	if cfg.Epochs < 1 {
+		return errors.New("surge epochs must be greater than or equal to 1")
+	}
+	if cfg.Factor < 1 {
+		return errors.New("surge factor must be greater than or equal to 1")
+	}
+	if cfg.MaxMultiplier < 1 {
+		return errors.New("surge max multiplier must be greater than or equal to 1")
+	}
+	if cfg.DecayRate <= 0 || cfg.DecayRate > 1 {
+		return errors.New("surge decay rate must be between (0,1]")
+	}
+	decayRate, err := NewRatFromFloat(cfg.DecayRate)
+	if err != nil {
+		return err
+	}
+	threshold, err := NewRatFromFloat(cfg.Threshold)
+	if err != nil {
+		return err
+	}
+	factor, err := NewRatFromFloat(cfg.Factor)
+	if err != nil {
+		return err
+	}
+	maxMultiplier, err := NewRatFromFloat(cfg.MaxMultiplier)
+	if err != nil {
+		return err
+	}
+	p.surge = &surgeParams{
+		threshold:     threshold,
+		epochs:        cfg.Epochs,
+		factor:        factor,
+		maxMultiplier: maxMultiplier,
+		retainRate:    new(big.Rat).Sub(ratOne, decayRate),
+	}
+	p.surgeMultiplier = new(big.Rat).Set(ratOne)
+	p.surgeEpochs = 0
+	return nil
+}
+
+// proportionOfTarget returns the ratio of the average gas per second to the
+// target gas per second
+func (p *GasPricer) proportionOfTarget(avgGasPerSecondLastEpoch float64) (*big.Rat, error) {
 	targetGasPerSecond := p.getTargetGasPerSecond()
-	if avgGasPerSecondLastEpoch < 0 {
-		return 0.0, fmt.Errorf("avgGasPerSecondLastEpoch cannot be negative, got %f", avgGasPerSecondLastEpoch)
+	if err := CheckQuantity(avgGasPerSecondLastEpoch); err != nil {
+		return nil, fmt.Errorf("invalid avgGasPerSecondLastEpoch: %w", err)
+	}
+	if err := CheckQuantity(targetGasPerSecond); err != nil {
+		return nil, fmt.Errorf("invalid targetGasPerSecond: %w", err)
 	}
 	if targetGasPerSecond < 1 {
-		return 0.0, fmt.Errorf("gasPerSecond cannot be less than 1, got %f", targetGasPerSecond)
+		return nil, fmt.Errorf("gasPerSecond cannot be less than 1, got %f", targetGasPerSecond)
+	}
+	avg, err := NewRatFromFloat(avgGasPerSecondLastEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avgGasPerSecondLastEpoch: %w", err)
+	}
+	target, err := NewRatFromFloat(targetGasPerSecond)
+	if err != nil {
+		return nil, fmt.Errorf("invalid targetGasPerSecond: %w", err)
 	}
+	return new(big.Rat).Quo(avg, target), nil
+}
+
+// CalcNextEpochGasPrice calculates the next gas price given some average
+// gas per second over the last epoch
+func (p *GasPricer) CalcNextEpochGasPrice(avgGasPerSecondLastEpoch float64) (*big.Int, error) {
 	// The percent difference between our current average gas & our target gas
-	proportionOfTarget := avgGasPerSecondLastEpoch / targetGasPerSecond
+	proportionOfTarget, err := p.proportionOfTarget(avgGasPerSecondLastEpoch)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Trace("Calculating next epoch gas price", "proportionOfTarget", proportionOfTarget,
-		"avgGasPerSecondLastEpoch", avgGasPerSecondLastEpoch, "targetGasPerSecond", targetGasPerSecond)
+	log.Trace("Calculating next epoch gas price", "proportionOfTarget", proportionOfTarget.FloatString(6),
+		"avgGasPerSecondLastEpoch", avgGasPerSecondLastEpoch, "targetGasPerSecond", p.getTargetGasPerSecond())
 
 	// The percent that we should adjust the gas price to reach our target gas
-	proportionToChangeBy := 0.0
-	if proportionOfTarget >= 1 { // If average avgGasPerSecondLastEpoch is GREATER than our target
-		proportionToChangeBy = math.Min(proportionOfTarget, 1+p.maxChangePerEpoch)
+	var proportionToChangeBy *big.Rat
+	if proportionOfTarget.Cmp(ratOne) >= 0 { // If average avgGasPerSecondLastEpoch is GREATER than our target
+		upper := new(big.Rat).Add(ratOne, p.maxChangePerEpoch)
+		proportionToChangeBy = minRat(proportionOfTarget, upper)
 	} else {
-		proportionToChangeBy = math.Max(proportionOfTarget, 1-p.maxChangePerEpoch)
+		lower := new(big.Rat).Sub(ratOne, p.maxChangePerEpoch)
+		proportionToChangeBy = maxRat(proportionOfTarget, lower)
 	}
 
-	updated := float64(max(1, p.curPrice)) * proportionToChangeBy
-	result := max(p.floorPrice, uint64(math.Ceil(updated)))
+	updated := mulCeil(maxBig(bigOne, p.curPrice), proportionToChangeBy)
+	result := p.capPrice(maxBig(p.floorPrice, updated))
 
-	log.Debug("Calculated next epoch gas price", "proportionToChangeBy", proportionToChangeBy,
-		"proportionOfTarget", proportionOfTarget, "result", result)
+	log.Debug("Calculated next epoch gas price", "proportionToChangeBy", proportionToChangeBy.FloatString(6),
+		"proportionOfTarget", proportionOfTarget.FloatString(6), "result", result)
 
 	return result, nil
 }
 
+// nextSurgeMultiplier calculates the surge multiplier for the next epoch
+// along with the updated count of consecutive surging epochs
+func (p *GasPricer) nextSurgeMultiplier(avgGasPerSecondLastEpoch float64) (*big.Rat, uint64, error) {
+	proportionOfTarget, err := p.proportionOfTarget(avgGasPerSecondLastEpoch)
+	if err != nil {
+		return nil, 0, err
+	}
+	if proportionOfTarget.Cmp(p.surge.threshold) >= 0 {
+		epochs := p.surgeEpochs + 1
+		if epochs < p.surge.epochs {
+			return p.surgeMultiplier, epochs, nil
+		}
+		grown := new(big.Rat).Mul(p.surgeMultiplier, p.surge.factor)
+		return minRat(grown, p.surge.maxMultiplier), epochs, nil
+	}
+	// Exponentially decay the excess multiplier back towards 1
+	excess := new(big.Rat).Sub(p.surgeMultiplier, ratOne)
+	excess.Mul(excess, p.surge.retainRate)
+	if excess.Cmp(surgeSnapThreshold) < 0 {
+		return new(big.Rat).Set(ratOne), 0, nil
+	}
+	return excess.Add(excess, ratOne), 0, nil
+}
+
 // CompleteEpoch ends the current epoch and updates the current gas price for the next epoch
-func (p *GasPricer) CompleteEpoch(avgGasPerSecondLastEpoch float64) (uint64, error) {
+func (p *GasPricer) CompleteEpoch(avgGasPerSecondLastEpoch float64) (*big.Int, error) {
 	gp, err := p.CalcNextEpochGasPrice(avgGasPerSecondLastEpoch)
 	if err != nil {
 		return gp, err
 	}
+	if p.surge != nil {
+		// The current price already includes the previous multiplier, so
+		// only apply the change in the multiplier
+		multiplier, epochs, err := p.nextSurgeMultiplier(avgGasPerSecondLastEpoch)
+		if err != nil {
+			return nil, err
+		}
+		if multiplier.Cmp(p.surgeMultiplier) != 0 {
+			change := new(big.Rat).Quo(multiplier, p.surgeMultiplier)
+			gp = p.capPrice(maxBig(p.floorPrice, mulCeil(gp, change)))
+			log.Debug("Applied surge multiplier", "multiplier", multiplier.FloatString(6),
+				"surge-epochs", epochs, "result", gp)
+		}
+		p.surgeMultiplier = multiplier
+		p.surgeEpochs = epochs
+	}
+	if p.idleDecay != nil {
+		gp = p.applyIdleDecay(gp, avgGasPerSecondLastEpoch)
+	}
+	// Without a max gas price nothing bounds the growth of the gas price, so
+	// a price that cannot be sent is rejected before it is recorded
+	if err := CheckWei(gp); err != nil {
+		return nil, err
+	}
+	if p.changeBudget != nil {
+		now := p.timeNow()
+		gp = p.capPrice(maxBig(p.floorPrice, p.changeBudget.bound(gp, now)))
+		p.changeBudget.record(gp, now)
+	}
 	p.curPrice = gp
 	p.avgGasPerSecondLastEpoch = avgGasPerSecondLastEpoch
-	return gp, nil
-}
-
-func max(a, b uint64) uint64 {
-	if a >= b {
-		return a
-	}
-	return b
+	return new(big.Int).Set(gp), nil
 }
@@ -1,7 +1,9 @@
 package gasprices
 
 import (
+	"errors"
 	"math"
+	"math/big"
 	"testing"
 )
 
@@ -18,7 +20,7 @@ func returnConstFn(retVal uint64) func() float64 {
 func runCalcGasPriceTests(gp GasPricer, tcs []CalcGasPriceTestCase, t *testing.T) {
 	for _, tc := range tcs {
 		nextEpochGasPrice, err := gp.CalcNextEpochGasPrice(tc.avgGasPerSecondLastEpoch)
-		if tc.expectedNextGasPrice != nextEpochGasPrice || err != nil {
+		if err != nil || tc.expectedNextGasPrice != nextEpochGasPrice.Uint64() {
 			t.Fatalf("failed on test: %s", tc.name)
 		}
 	}
@@ -26,10 +28,10 @@ func runCalcGasPriceTests(gp GasPricer, tcs []CalcGasPriceTestCase, t *testing.T
 
 func TestCalcGasPriceFarFromFloor(t *testing.T) {
 	gp := GasPricer{
-		curPrice:              100,
-		floorPrice:            1,
+		curPrice:              big.NewInt(100),
+		floorPrice:            big.NewInt(1),
 		getTargetGasPerSecond: returnConstFn(10),
-		maxChangePerEpoch:     0.5,
+		maxChangePerEpoch:     big.NewRat(1, 2),
 	}
 	tcs := []CalcGasPriceTestCase{
 		// No change
@@ -72,10 +74,10 @@ func TestCalcGasPriceFarFromFloor(t *testing.T) {
 
 func TestCalcGasPriceAtFloor(t *testing.T) {
 	gp := GasPricer{
-		curPrice:              100,
-		floorPrice:            100,
+		curPrice:              big.NewInt(100),
+		floorPrice:            big.NewInt(100),
 		getTargetGasPerSecond: returnConstFn(10),
-		maxChangePerEpoch:     0.5,
+		maxChangePerEpoch:     big.NewRat(1, 2),
 	}
 	tcs := []CalcGasPriceTestCase{
 		// No change
@@ -102,16 +104,16 @@ func TestCalcGasPriceAtFloor(t *testing.T) {
 
 func TestGasPricerUpdates(t *testing.T) {
 	gp := GasPricer{
-		curPrice:              100,
-		floorPrice:            100,
+		curPrice:              big.NewInt(100),
+		floorPrice:            big.NewInt(100),
 		getTargetGasPerSecond: returnConstFn(10),
-		maxChangePerEpoch:     0.5,
+		maxChangePerEpoch:     big.NewRat(1, 2),
 	}
 	_, err := gp.CompleteEpoch(12.5)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if gp.curPrice != 125 {
+	if gp.curPrice.Uint64() != 125 {
 		t.Fatalf("gp.curPrice not updated correctly. Got: %v, expected: %v", gp.curPrice, 125)
 	}
 }
@@ -151,22 +153,205 @@ func TestGasPricerDynamicTarget(t *testing.T) {
 	dynamicGetTarget := GetLinearInterpolationFn(mockTimeNow, startTimestamp, endTimestamp, startGasPerSecond, endGasPerSecond)
 
 	gp := GasPricer{
-		curPrice:              100,
-		floorPrice:            1,
+		curPrice:              big.NewInt(100),
+		floorPrice:            big.NewInt(1),
 		getTargetGasPerSecond: dynamicGetTarget,
-		maxChangePerEpoch:     0.5,
+		maxChangePerEpoch:     big.NewRat(1, 2),
 	}
 	gasPerSecondDemanded := returnConstFn(15)
 	for i := 0; i < 10; i++ {
 		mockTimestamp = float64(i * 10)
-		expectedPrice := math.Ceil(float64(gp.curPrice) * math.Max(0.5, gasPerSecondDemanded()/dynamicGetTarget()))
+		expectedPrice := math.Ceil(float64(gp.curPrice.Uint64()) * math.Max(0.5, gasPerSecondDemanded()/dynamicGetTarget()))
 
 		_, err := gp.CompleteEpoch(gasPerSecondDemanded())
 		if err != nil {
 			t.Fatal(err)
 		}
-		if gp.curPrice != uint64(expectedPrice) {
+		if gp.curPrice.Uint64() != uint64(expectedPrice) {
 			t.Fatalf("gp.curPrice not updated correctly. Got: %v expected: %v", gp.curPrice, expectedPrice)
 		}
 	}
 }
+
+func TestGasPricerSurge(t *testing.T) {
+	gp := GasPricer{
+		curPrice:              big.NewInt(100),
+		floorPrice:            big.NewInt(1),
+		getTargetGasPerSecond: returnConstFn(10),
+		maxChangePerEpoch:     big.NewRat(1, 2),
+	}
+	err := gp.EnableSurge(SurgeConfig{
+		Threshold:     1.5,
+		Epochs:        2,
+		Factor:        2,
+		MaxMultiplier: 4,
+		DecayRate:     0.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcs := []struct {
+		name       string
+		demand     float64
+		price      uint64
+		multiplier *big.Rat
+	}{
+		// The first surging epoch only counts towards the surge
+		{"first surging epoch", 15, 150, big.NewRat(1, 1)},
+		{"surge starts", 15, 450, big.NewRat(2, 1)},
+		{"surge grows", 15, 1350, big.NewRat(4, 1)},
+		{"surge bounded by max multiplier", 15, 2025, big.NewRat(4, 1)},
+		// Demand is at the target, so only the multiplier changes
+		{"surge decays", 10, 1266, big.NewRat(5, 2)},
+		{"surge keeps decaying", 10, 887, big.NewRat(7, 4)},
+	}
+	for _, tc := range tcs {
+		price, err := gp.CompleteEpoch(tc.demand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price.Uint64() != tc.price {
+			t.Fatalf("%s: price mismatch. Got %d, expected %d", tc.name, price, tc.price)
+		}
+		if gp.surgeMultiplier.Cmp(tc.multiplier) != 0 {
+			t.Fatalf("%s: multiplier mismatch. Got %s, expected %s", tc.name, gp.surgeMultiplier, tc.multiplier)
+		}
+	}
+}
+
+func TestGasPricerSurgeInvalidConfig(t *testing.T) {
+	gp, err := NewGasPricer(big.NewInt(100), big.NewInt(1), returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := SurgeConfig{Threshold: 1.5, Epochs: 1, Factor: 2, MaxMultiplier: 4, DecayRate: 0.5}
+	if err := gp.EnableSurge(valid); err != nil {
+		t.Fatal(err)
+	}
+	invalid := valid
+	invalid.DecayRate = 0
+	if err := gp.EnableSurge(invalid); err == nil {
+		t.Fatal("expected error for zero decay rate")
+	}
+	invalid = valid
+	invalid.Threshold = 0.5
+	if err := gp.EnableSurge(invalid); err == nil {
+		t.Fatal("expected error for threshold below target")
+	}
+}
+
+func TestCalcGasPriceHighPrecision(t *testing.T) {
+	// A price well above what a float64 can represent exactly
+	curPrice, _ := new(big.Int).SetString("123456789012345678901234567", 10)
+	gp, err := NewGasPricer(curPrice, big.NewInt(1), returnConstFn(10), 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Demand is far above the target so the price increases by exactly 10%
+	next, err := gp.CalcNextEpochGasPrice(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, _ := new(big.Int).SetString("135802467913580246791358024", 10)
+	if next.Cmp(expect) != 0 {
+		t.Fatalf("mismatch. Got %s, expected %s", next, expect)
+	}
+
+	// Demand is at 75% of the target, so the price is reduced by
+	// exactly 10% and rounded up
+	next, err = gp.CalcNextEpochGasPrice(7.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, _ = new(big.Int).SetString("111111110111111111011111111", 10)
+	if next.Cmp(expect) != 0 {
+		t.Fatalf("mismatch. Got %s, expected %s", next, expect)
+	}
+}
+
+func TestCalcGasPriceMaxPrice(t *testing.T) {
+	gp, err := NewGasPricer(big.NewInt(100), big.NewInt(1), returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gp.SetMaxPrice(big.NewInt(120)); err != nil {
+		t.Fatal(err)
+	}
+	tcs := []CalcGasPriceTestCase{
+		{
+			name:                     "Increase bounded by the max price",
+			avgGasPerSecondLastEpoch: 100,
+			expectedNextGasPrice:     120,
+		},
+		{
+			name:                     "Increase below the max price",
+			avgGasPerSecondLastEpoch: 11,
+			expectedNextGasPrice:     110,
+		},
+		{
+			name:                     "Reduction unaffected by the max price",
+			avgGasPerSecondLastEpoch: 5,
+			expectedNextGasPrice:     50,
+		},
+	}
+	runCalcGasPriceTests(*gp, tcs, t)
+
+	// Surging cannot push the price past the max price either
+	err = gp.EnableSurge(SurgeConfig{Threshold: 1, Epochs: 1, Factor: 2, MaxMultiplier: 4, DecayRate: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err := gp.CompleteEpoch(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Uint64() != 120 {
+		t.Fatalf("surge exceeded the max price, got %d", price)
+	}
+}
+
+func TestSetMaxPrice(t *testing.T) {
+	gp, err := NewGasPricer(big.NewInt(200), big.NewInt(10), returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gp.SetMaxPrice(big.NewInt(5)); err == nil {
+		t.Fatal("expected max price below the floor price to fail")
+	}
+	// The current price is capped immediately
+	if err := gp.SetMaxPrice(big.NewInt(150)); err != nil {
+		t.Fatal(err)
+	}
+	if gp.curPrice.Uint64() != 150 {
+		t.Fatalf("current price not capped, got %d", gp.curPrice)
+	}
+}
+
+func TestGasPricerInvalidDemand(t *testing.T) {
+	gp, err := NewGasPricer(big.NewInt(100), big.NewInt(1), returnConstFn(10), 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, demand := range []float64{math.NaN(), math.Inf(1), -1, 1e300} {
+		if _, err := gp.CompleteEpoch(demand); !errors.Is(err, ErrInvalidFloat) {
+			t.Fatalf("expected %v for %v, got %v", ErrInvalidFloat, demand, err)
+		}
+	}
+	if gp.GetGasPrice().Uint64() != 100 {
+		t.Fatalf("expected the gas price to be kept, got %d", gp.GetGasPrice())
+	}
+
+	// Without a max gas price the gas price cannot grow past a uint256
+	gp, err = NewGasPricer(maxWei, big.NewInt(1), returnConstFn(10), 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gp.CompleteEpoch(100); !errors.Is(err, ErrInvalidWei) {
+		t.Fatalf("expected %v, got %v", ErrInvalidWei, err)
+	}
+	if gp.GetGasPrice().Cmp(maxWei) != 0 {
+		t.Fatalf("expected the gas price to be kept, got %d", gp.GetGasPrice())
+	}
+}
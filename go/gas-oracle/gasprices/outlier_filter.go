@@ -0,0 +1,145 @@
+package gasprices
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// OutlierMethod is how the spread of recent demand is measured
+type OutlierMethod string
+
+const (
+	// OutlierMethodMAD uses the median absolute deviation around the median,
+	// which is not affected by the outliers themselves
+	OutlierMethodMAD OutlierMethod = "mad"
+	// OutlierMethodStdDev uses the standard deviation around the mean
+	OutlierMethodStdDev OutlierMethod = "stddev"
+)
+
+// madScale makes the median absolute deviation comparable to the standard
+// deviation of normally distributed samples
+const madScale = 1.4826
+
+// OutlierFilter is a DemandFilter that rejects anomalous epochs, such as a
+// burst of gas from a replayed batch or an epoch stretched by a stalled
+// clock. An epoch whose demand is further than Threshold times the spread
+// from the center of the last Window epochs is clamped to that bound, so
+// that a single sample cannot cause a maximal price move. The unfiltered
+// demand is kept in the window so that a sustained change in demand is
+// accepted once it makes up enough of the window.
+type OutlierFilter struct {
+	mu         sync.Mutex
+	method     OutlierMethod
+	window     int
+	minSamples int
+	threshold  float64
+	samples    []float64
+}
+
+// NewOutlierFilter creates an OutlierFilter. No epochs are rejected until
+// minSamples epochs have been observed.
+func NewOutlierFilter(method OutlierMethod, window, minSamples uint64, threshold float64) (*OutlierFilter, error) {
+	switch method {
+	case OutlierMethodMAD, OutlierMethodStdDev:
+	default:
+		return nil, fmt.Errorf("unknown outlier method %q", method)
+	}
+	if minSamples < 3 {
+		return nil, errors.New("outlier min samples must be greater than or equal to 3")
+	}
+	if window < minSamples {
+		return nil, errors.New("outlier window cannot be less than the min samples")
+	}
+	if threshold <= 0 {
+		return nil, errors.New("outlier threshold must be greater than 0")
+	}
+	return &OutlierFilter{
+		method:     method,
+		window:     int(window),
+		minSamples: int(minSamples),
+		threshold:  threshold,
+	}, nil
+}
+
+// FilterDemand clamps the demand if it is an outlier
+func (f *OutlierFilter) FilterDemand(avgGasPerSecond float64) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	filtered := avgGasPerSecond
+	if len(f.samples) >= f.minSamples {
+		center, spread := f.centerAndSpread()
+		// Without any spread there is nothing to compare against
+		if spread > 0 {
+			bound := f.threshold * spread
+			lower, upper := center-bound, center+bound
+			if avgGasPerSecond > upper {
+				filtered = upper
+			} else if avgGasPerSecond < lower {
+				filtered = math.Max(0, lower)
+			}
+			if filtered != avgGasPerSecond {
+				log.Info("Rejected outlier demand", "average-gas-per-second", avgGasPerSecond,
+					"filtered", filtered, "center", center, "spread", spread)
+			}
+		}
+	}
+
+	f.samples = append(f.samples, avgGasPerSecond)
+	if len(f.samples) > f.window {
+		f.samples = f.samples[len(f.samples)-f.window:]
+	}
+	return filtered, nil
+}
+
+// centerAndSpread measures the recorded samples with the configured method
+func (f *OutlierFilter) centerAndSpread() (float64, float64) {
+	if f.method == OutlierMethodStdDev {
+		return meanAndStdDev(f.samples)
+	}
+	center := median(f.samples)
+	deviations := make([]float64, len(f.samples))
+	for i, s := range f.samples {
+		deviations[i] = math.Abs(s - center)
+	}
+	return center, madScale * median(deviations)
+}
+
+// median returns the median of the samples without modifying them
+func median(samples []float64) float64 {
+	sorted := make([]float64, len(samples))
+	copy(sorted, samples)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// meanAndStdDev returns the mean and the population standard deviation of
+// the samples
+func meanAndStdDev(samples []float64) (float64, float64) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	mean := 0.0
+	for _, s := range samples {
+		mean += s
+	}
+	mean /= float64(len(samples))
+	variance := 0.0
+	for _, s := range samples {
+		variance += (s - mean) * (s - mean)
+	}
+	variance /= float64(len(samples))
+	return mean, math.Sqrt(variance)
+}
@@ -0,0 +1,110 @@
+package gasprices
+
+import (
+	"testing"
+)
+
+func TestOutlierFilterMAD(t *testing.T) {
+	f, err := NewOutlierFilter(OutlierMethodMAD, 5, 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		demand float64
+		expect float64
+	}{
+		// Not enough samples to reject anything yet
+		{100, 100},
+		{110, 110},
+		{90, 90},
+		{105, 105},
+		// median 102.5 and MAD 5 so the bound is 102.5 +/- 3 * 1.4826 * 5
+		{1000, 124.739},
+		// median 105 and MAD 5, the earlier outlier does not widen the bound
+		{0, 82.761},
+	}
+	for i, tt := range tests {
+		demand, err := f.FilterDemand(tt.demand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := demand - tt.expect; diff > 1e-9 || diff < -1e-9 {
+			t.Fatalf("epoch %d: expected %f, got %f", i, tt.expect, demand)
+		}
+	}
+}
+
+func TestOutlierFilterSustainedChange(t *testing.T) {
+	f, err := NewOutlierFilter(OutlierMethodMAD, 5, 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []float64{100, 101, 99, 100, 101} {
+		if _, err := f.FilterDemand(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A sustained increase is accepted once it is the majority of the window
+	var demand float64
+	for i := 0; i < 4; i++ {
+		demand, err = f.FilterDemand(500)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if demand != 500 {
+		t.Fatalf("expected sustained demand to be accepted, got %f", demand)
+	}
+}
+
+func TestOutlierFilterNoSpread(t *testing.T) {
+	f, err := NewOutlierFilter(OutlierMethodStdDev, 5, 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []float64{0, 0, 0, 1000} {
+		demand, err := f.FilterDemand(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if demand != d {
+			t.Fatalf("expected %f, got %f", d, demand)
+		}
+	}
+}
+
+func TestOutlierFilterStdDev(t *testing.T) {
+	f, err := NewOutlierFilter(OutlierMethodStdDev, 4, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// mean 100, standard deviation 10
+	for _, d := range []float64{90, 110, 90, 110} {
+		if _, err := f.FilterDemand(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	demand, err := f.FilterDemand(200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if demand != 120 {
+		t.Fatalf("expected 120, got %f", demand)
+	}
+}
+
+func TestNewOutlierFilterInvalid(t *testing.T) {
+	if _, err := NewOutlierFilter("iqr", 5, 3, 3); err == nil {
+		t.Fatal("expected error for an unknown method")
+	}
+	if _, err := NewOutlierFilter(OutlierMethodMAD, 5, 2, 3); err == nil {
+		t.Fatal("expected error for too few min samples")
+	}
+	if _, err := NewOutlierFilter(OutlierMethodMAD, 3, 4, 3); err == nil {
+		t.Fatal("expected error for a window smaller than the min samples")
+	}
+	if _, err := NewOutlierFilter(OutlierMethodMAD, 5, 3, 0); err == nil {
+		t.Fatal("expected error for a zero threshold")
+	}
+}
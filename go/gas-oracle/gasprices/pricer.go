@@ -0,0 +1,208 @@
+package gasprices
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Pricer computes the L2 gas price from the demand observed in each epoch.
+// Alternative pricing algorithms implement this interface and register
+// themselves with RegisterPricer so that they can be selected by name.
+type Pricer interface {
+	// CompleteEpoch ends the current epoch and returns the gas price for
+	// the next epoch
+	CompleteEpoch(avgGasPerSecondLastEpoch float64) (*big.Int, error)
+	// GetGasPrice returns the current gas price
+	GetGasPrice() *big.Int
+	// SetFloor sets the price that the gas price will never go below
+	SetFloor(floorPrice *big.Int) error
+	// SetMaxPrice sets the price that the gas price will never exceed
+	SetMaxPrice(maxPrice *big.Int) error
+	// SetGasPrice moves the current gas price, for example when it was
+	// changed on chain outside of the pricer. The price is kept between the
+	// floor and the max price.
+	SetGasPrice(price *big.Int) error
+}
+
+// PricerConfig holds the parameters that are shared by all pricers
+type PricerConfig struct {
+	CurrentPrice             *big.Int
+	FloorPrice               *big.Int
+	GetTargetGasPerSecond    GetTargetGasPerSecond
+	MaxPercentChangePerEpoch float64
+	// Now is the clock used by time based features, defaults to time.Now
+	Now func() time.Time
+}
+
+// PricerFactory creates a Pricer from a PricerConfig
+type PricerFactory func(cfg PricerConfig) (Pricer, error)
+
+// DefaultPricer is the name of the pricer that is used when none is selected
+const DefaultPricer = "proportional"
+
+var (
+	pricersMu sync.RWMutex
+	pricers   = make(map[string]PricerFactory)
+)
+
+func init() {
+	if err := RegisterPricer(DefaultPricer, newProportionalPricer); err != nil {
+		panic(err)
+	}
+	if err := RegisterPricer("fixed", newFixedPricer); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterPricer makes a pricer available by name
+func RegisterPricer(name string, factory PricerFactory) error {
+	if name == "" {
+		return errors.New("pricer name cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("pricer %q: factory cannot be nil", name)
+	}
+	pricersMu.Lock()
+	defer pricersMu.Unlock()
+	if _, ok := pricers[name]; ok {
+		return fmt.Errorf("pricer %q is already registered", name)
+	}
+	pricers[name] = factory
+	return nil
+}
+
+// NewPricer creates the pricer registered under name
+func NewPricer(name string, cfg PricerConfig) (Pricer, error) {
+	pricersMu.RLock()
+	factory, ok := pricers[name]
+	pricersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown pricer %q, must be one of %v", name, Pricers())
+	}
+	return factory(cfg)
+}
+
+// Pricers returns the sorted names of the registered pricers
+func Pricers() []string {
+	pricersMu.RLock()
+	defer pricersMu.RUnlock()
+	names := make([]string, 0, len(pricers))
+	for name := range pricers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newProportionalPricer creates a GasPricer, which moves the gas price in
+// proportion to how far demand is from the target
+func newProportionalPricer(cfg PricerConfig) (Pricer, error) {
+	p, err := NewGasPricer(cfg.CurrentPrice, cfg.FloorPrice, cfg.GetTargetGasPerSecond, cfg.MaxPercentChangePerEpoch)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Now != nil {
+		p.SetClock(cfg.Now)
+	}
+	return p, nil
+}
+
+// FixedPricer keeps the gas price constant regardless of demand. The gas
+// price only moves when it must stay between the floor and the max price.
+type FixedPricer struct {
+	mu         sync.Mutex
+	price      *big.Int
+	floorPrice *big.Int
+	maxPrice   *big.Int
+}
+
+func newFixedPricer(cfg PricerConfig) (Pricer, error) {
+	return NewFixedPricer(cfg.CurrentPrice, cfg.FloorPrice)
+}
+
+// NewFixedPricer creates a FixedPricer at price
+func NewFixedPricer(price, floorPrice *big.Int) (*FixedPricer, error) {
+	if floorPrice == nil || floorPrice.Cmp(bigOne) < 0 {
+		return nil, errors.New("floorPrice must be greater than or equal to 1")
+	}
+	if price == nil {
+		return nil, errors.New("price cannot be nil")
+	}
+	return &FixedPricer{
+		price:      maxBig(price, floorPrice),
+		floorPrice: new(big.Int).Set(floorPrice),
+	}, nil
+}
+
+// CompleteEpoch returns the fixed gas price
+func (p *FixedPricer) CompleteEpoch(avgGasPerSecondLastEpoch float64) (*big.Int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return new(big.Int).Set(p.price), nil
+}
+
+// GetGasPrice returns the fixed gas price
+func (p *FixedPricer) GetGasPrice() *big.Int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return new(big.Int).Set(p.price)
+}
+
+// SetFloor raises the gas price to the floor if it is below it
+func (p *FixedPricer) SetFloor(floorPrice *big.Int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := validateFloor(floorPrice, p.maxPrice); err != nil {
+		return err
+	}
+	p.floorPrice = new(big.Int).Set(floorPrice)
+	p.price = maxBig(p.price, floorPrice)
+	return nil
+}
+
+// SetMaxPrice lowers the gas price to the max price if it is above it
+func (p *FixedPricer) SetMaxPrice(maxPrice *big.Int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if maxPrice == nil {
+		return errors.New("maxPrice cannot be nil")
+	}
+	if maxPrice.Cmp(p.floorPrice) < 0 {
+		return fmt.Errorf("maxPrice %s cannot be less than floorPrice %s", maxPrice, p.floorPrice)
+	}
+	p.maxPrice = new(big.Int).Set(maxPrice)
+	if p.price.Cmp(maxPrice) > 0 {
+		p.price = new(big.Int).Set(maxPrice)
+	}
+	return nil
+}
+
+// SetGasPrice moves the fixed gas price
+func (p *FixedPricer) SetGasPrice(price *big.Int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if price == nil {
+		return errors.New("price cannot be nil")
+	}
+	p.price = maxBig(price, p.floorPrice)
+	if p.maxPrice != nil && p.price.Cmp(p.maxPrice) > 0 {
+		p.price = new(big.Int).Set(p.maxPrice)
+	}
+	return nil
+}
+
+// validateFloor ensures that a floor price is at least 1 and not above the
+// max price when one is set
+func validateFloor(floorPrice, maxPrice *big.Int) error {
+	if floorPrice == nil || floorPrice.Cmp(bigOne) < 0 {
+		return errors.New("floorPrice must be greater than or equal to 1")
+	}
+	if maxPrice != nil && floorPrice.Cmp(maxPrice) > 0 {
+		return fmt.Errorf("floorPrice %s cannot be greater than maxPrice %s", floorPrice, maxPrice)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package gasprices
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPricerRegistry(t *testing.T) {
+	names := Pricers()
+	if len(names) != 2 || names[0] != "fixed" || names[1] != DefaultPricer {
+		t.Fatalf("unexpected pricers: %v", names)
+	}
+
+	if err := RegisterPricer(DefaultPricer, newFixedPricer); err == nil {
+		t.Fatal("expected error registering a duplicate pricer")
+	}
+	if err := RegisterPricer("", newFixedPricer); err == nil {
+		t.Fatal("expected error registering an unnamed pricer")
+	}
+	if err := RegisterPricer("nil", nil); err == nil {
+		t.Fatal("expected error registering a nil factory")
+	}
+
+	cfg := PricerConfig{
+		CurrentPrice:             big.NewInt(100),
+		FloorPrice:               big.NewInt(1),
+		GetTargetGasPerSecond:    func() float64 { return 10 },
+		MaxPercentChangePerEpoch: 0.5,
+	}
+	if _, err := NewPricer("unknown", cfg); err == nil {
+		t.Fatal("expected error for an unknown pricer")
+	}
+
+	pricer, err := NewPricer(DefaultPricer, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pricer.(*GasPricer); !ok {
+		t.Fatalf("expected a GasPricer, got %T", pricer)
+	}
+	// Demand at twice the target raises the price by the max change
+	price, err := pricer.CompleteEpoch(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(150)) != 0 {
+		t.Fatalf("expected 150, got %s", price)
+	}
+}
+
+func TestFixedPricer(t *testing.T) {
+	pricer, err := NewPricer("fixed", PricerConfig{
+		CurrentPrice: big.NewInt(100),
+		FloorPrice:   big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, demand := range []float64{0, 1e9, 0} {
+		price, err := pricer.CompleteEpoch(demand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price.Cmp(big.NewInt(100)) != 0 {
+			t.Fatalf("expected 100, got %s", price)
+		}
+	}
+
+	if err := pricer.SetFloor(big.NewInt(200)); err != nil {
+		t.Fatal(err)
+	}
+	if pricer.GetGasPrice().Cmp(big.NewInt(200)) != 0 {
+		t.Fatalf("expected price raised to the floor, got %s", pricer.GetGasPrice())
+	}
+	if err := pricer.SetMaxPrice(big.NewInt(100)); err == nil {
+		t.Fatal("expected error for max price below the floor")
+	}
+	if err := pricer.SetFloor(big.NewInt(50)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pricer.SetMaxPrice(big.NewInt(150)); err != nil {
+		t.Fatal(err)
+	}
+	if pricer.GetGasPrice().Cmp(big.NewInt(150)) != 0 {
+		t.Fatalf("expected price lowered to the max price, got %s", pricer.GetGasPrice())
+	}
+	if err := pricer.SetFloor(big.NewInt(151)); err == nil {
+		t.Fatal("expected error for floor above the max price")
+	}
+	if err := pricer.SetFloor(big.NewInt(0)); err == nil {
+		t.Fatal("expected error for a zero floor")
+	}
+}
+
+func TestGasPricerSetFloor(t *testing.T) {
+	pricer, err := NewGasPricer(big.NewInt(100), big.NewInt(1), func() float64 { return 10 }, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pricer.SetFloor(big.NewInt(120)); err != nil {
+		t.Fatal(err)
+	}
+	if pricer.GetGasPrice().Cmp(big.NewInt(120)) != 0 {
+		t.Fatalf("expected price raised to the floor, got %s", pricer.GetGasPrice())
+	}
+	// No demand would halve the price but the floor holds it
+	price, err := pricer.CompleteEpoch(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(120)) != 0 {
+		t.Fatalf("expected floor price, got %s", price)
+	}
+}
+
+func TestSetGasPrice(t *testing.T) {
+	gasPricer, err := NewGasPricer(big.NewInt(100), big.NewInt(10), func() float64 { return 10 }, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gasPricer.SetMaxPrice(big.NewInt(1000)); err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := NewFixedPricer(big.NewInt(100), big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fixed.SetMaxPrice(big.NewInt(1000)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pricer := range []Pricer{gasPricer, fixed} {
+		tests := []struct {
+			price  int64
+			expect int64
+		}{
+			{500, 500},
+			{1, 10},
+			{5000, 1000},
+		}
+		for _, tt := range tests {
+			if err := pricer.SetGasPrice(big.NewInt(tt.price)); err != nil {
+				t.Fatal(err)
+			}
+			if pricer.GetGasPrice().Int64() != tt.expect {
+				t.Fatalf("%T: expected %d, got %s", pricer, tt.expect, pricer.GetGasPrice())
+			}
+		}
+		if err := pricer.SetGasPrice(nil); err == nil {
+			t.Fatalf("%T: expected error for a nil price", pricer)
+		}
+	}
+
+	// The next epoch starts from the new gas price
+	if err := gasPricer.SetGasPrice(big.NewInt(500)); err != nil {
+		t.Fatal(err)
+	}
+	price, err := gasPricer.CompleteEpoch(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Int64() != 500 {
+		t.Fatalf("expected 500, got %s", price)
+	}
+}
@@ -0,0 +1,81 @@
+package gasprices
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// RoundingMode determines how computed gas prices are rounded before they
+// are compared against the current gas price and submitted
+type RoundingMode string
+
+const (
+	// RoundingNone leaves gas prices as they are
+	RoundingNone RoundingMode = "none"
+	// RoundingIncrement rounds gas prices up to the nearest multiple of an
+	// increment in wei
+	RoundingIncrement RoundingMode = "increment"
+	// RoundingSignificantDigits rounds gas prices up so that they have at
+	// most a number of significant digits
+	RoundingSignificantDigits RoundingMode = "significant-digits"
+)
+
+// Rounding rounds gas prices up to human friendly values so that wallets
+// display stable prices and noise does not trigger significance checks
+type Rounding struct {
+	mode      RoundingMode
+	increment *big.Int
+	digits    uint64
+}
+
+// NewRounding creates a Rounding for the given mode. The increment is only
+// used by RoundingIncrement and the digits are only used by
+// RoundingSignificantDigits.
+func NewRounding(mode RoundingMode, increment *big.Int, digits uint64) (*Rounding, error) {
+	switch mode {
+	case RoundingNone:
+	case RoundingIncrement:
+		if increment == nil || increment.Sign() <= 0 {
+			return nil, errors.New("rounding increment must be greater than 0")
+		}
+	case RoundingSignificantDigits:
+		if digits < 1 {
+			return nil, errors.New("rounding significant digits must be greater than 0")
+		}
+	default:
+		return nil, fmt.Errorf("unknown rounding mode %q", mode)
+	}
+	r := &Rounding{mode: mode, digits: digits}
+	if increment != nil {
+		r.increment = new(big.Int).Set(increment)
+	}
+	return r, nil
+}
+
+// Round rounds the price up according to the rounding mode. A nil Rounding
+// does not round.
+func (r *Rounding) Round(price *big.Int) *big.Int {
+	if r == nil {
+		return new(big.Int).Set(price)
+	}
+	switch r.mode {
+	case RoundingIncrement:
+		return roundUpToMultiple(price, r.increment)
+	case RoundingSignificantDigits:
+		numDigits := uint64(len(new(big.Int).Abs(price).String()))
+		if numDigits <= r.digits {
+			return new(big.Int).Set(price)
+		}
+		exp := new(big.Int).SetUint64(numDigits - r.digits)
+		return roundUpToMultiple(price, new(big.Int).Exp(big.NewInt(10), exp, nil))
+	default:
+		return new(big.Int).Set(price)
+	}
+}
+
+// roundUpToMultiple rounds x up to the nearest multiple of m
+func roundUpToMultiple(x, m *big.Int) *big.Int {
+	q := ceilRat(new(big.Rat).SetFrac(x, m))
+	return q.Mul(q, m)
+}
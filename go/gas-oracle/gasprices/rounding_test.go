@@ -0,0 +1,58 @@
+package gasprices
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      RoundingMode
+		increment int64
+		digits    uint64
+		price     int64
+		expect    int64
+	}{
+		{name: "none", mode: RoundingNone, price: 1_234_567, expect: 1_234_567},
+		{name: "increment rounds up", mode: RoundingIncrement, increment: 1_000_000, price: 1_234_567, expect: 2_000_000},
+		{name: "increment exact", mode: RoundingIncrement, increment: 1_000_000, price: 3_000_000, expect: 3_000_000},
+		{name: "increment below", mode: RoundingIncrement, increment: 1_000_000, price: 1, expect: 1_000_000},
+		{name: "digits rounds up", mode: RoundingSignificantDigits, digits: 3, price: 1_234_567, expect: 1_240_000},
+		{name: "digits exact", mode: RoundingSignificantDigits, digits: 3, price: 1_230_000, expect: 1_230_000},
+		{name: "digits carry", mode: RoundingSignificantDigits, digits: 2, price: 995, expect: 1000},
+		{name: "digits short", mode: RoundingSignificantDigits, digits: 3, price: 15, expect: 15},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRounding(tc.mode, big.NewInt(tc.increment), tc.digits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := r.Round(big.NewInt(tc.price))
+			if got.Int64() != tc.expect {
+				t.Fatalf("mismatch. Got %d, expected %d", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestRoundingInvalid(t *testing.T) {
+	if _, err := NewRounding(RoundingIncrement, big.NewInt(0), 0); err == nil {
+		t.Fatal("expected error for zero increment")
+	}
+	if _, err := NewRounding(RoundingSignificantDigits, nil, 0); err == nil {
+		t.Fatal("expected error for zero digits")
+	}
+	if _, err := NewRounding("nearest", nil, 0); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
+
+func TestRoundingNil(t *testing.T) {
+	var r *Rounding
+	if got := r.Round(big.NewInt(12345)); got.Int64() != 12345 {
+		t.Fatalf("nil rounding should not round, got %d", got)
+	}
+}
@@ -0,0 +1,70 @@
+package gasprices
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ShadowPricer runs a second pricer alongside the live pricer. It is a
+// DemandObserver so that it sees the same demand as the live pricer each
+// epoch, but its gas price is never sent. The divergence between the two
+// can be used to evaluate a pricer before switching to it.
+type ShadowPricer struct {
+	mu     sync.RWMutex
+	live   Pricer
+	shadow Pricer
+	// observe is called with the prices after each epoch
+	observe func(live, shadow *big.Int, divergence float64)
+}
+
+// NewShadowPricer creates a ShadowPricer. The observe function is called
+// with the live and shadow gas prices and their divergence after each epoch
+// and may be nil.
+func NewShadowPricer(live, shadow Pricer, observe func(live, shadow *big.Int, divergence float64)) *ShadowPricer {
+	return &ShadowPricer{
+		live:    live,
+		shadow:  shadow,
+		observe: observe,
+	}
+}
+
+// ObserveDemand completes the epoch of the shadow pricer. It is called after
+// the live pricer has completed the same epoch.
+func (s *ShadowPricer) ObserveDemand(avgGasPerSecond float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shadow, err := s.shadow.CompleteEpoch(avgGasPerSecond)
+	if err != nil {
+		log.Warn("shadow pricer cannot complete epoch", "message", err)
+		return
+	}
+	live := s.live.GetGasPrice()
+	divergence := Divergence(live, shadow)
+	log.Debug("shadow pricer", "live", live, "shadow", shadow, "divergence", divergence)
+	if s.observe != nil {
+		s.observe(live, shadow, divergence)
+	}
+}
+
+// GetGasPrice returns the gas price of the shadow pricer
+func (s *ShadowPricer) GetGasPrice() *big.Int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.shadow.GetGasPrice()
+}
+
+// Divergence returns the signed relative difference of a gas price from a
+// reference gas price, (price - reference) / reference
+func Divergence(reference, price *big.Int) float64 {
+	if reference.Sign() == 0 {
+		if price.Sign() == 0 {
+			return 0
+		}
+		return 1
+	}
+	d := new(big.Rat).SetFrac(new(big.Int).Sub(price, reference), reference)
+	f, _ := d.Float64()
+	return f
+}
@@ -0,0 +1,59 @@
+package gasprices
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestShadowPricer(t *testing.T) {
+	target := func() float64 { return 10 }
+	live, err := NewGasPricer(big.NewInt(100), big.NewInt(1), target, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shadow, err := NewGasPricer(big.NewInt(100), big.NewInt(1), target, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var observed []float64
+	s := NewShadowPricer(live, shadow, func(l, sh *big.Int, divergence float64) {
+		observed = append(observed, divergence)
+	})
+
+	// The GasPriceUpdater completes the live epoch before notifying
+	// the observers
+	if _, err := live.CompleteEpoch(20); err != nil {
+		t.Fatal(err)
+	}
+	s.ObserveDemand(20)
+
+	if live.GetGasPrice().Cmp(big.NewInt(110)) != 0 {
+		t.Fatalf("unexpected live price %s", live.GetGasPrice())
+	}
+	if s.GetGasPrice().Cmp(big.NewInt(150)) != 0 {
+		t.Fatalf("unexpected shadow price %s", s.GetGasPrice())
+	}
+	if len(observed) != 1 || observed[0] != 40.0/110 {
+		t.Fatalf("unexpected divergence %v", observed)
+	}
+}
+
+func TestDivergence(t *testing.T) {
+	tests := []struct {
+		reference, price int64
+		expect           float64
+	}{
+		{100, 100, 0},
+		{100, 150, 0.5},
+		{100, 50, -0.5},
+		{0, 0, 0},
+		{0, 10, 1},
+	}
+	for _, tt := range tests {
+		d := Divergence(big.NewInt(tt.reference), big.NewInt(tt.price))
+		if d != tt.expect {
+			t.Fatalf("divergence of %d from %d: expected %f, got %f", tt.price, tt.reference, tt.expect, d)
+		}
+	}
+}
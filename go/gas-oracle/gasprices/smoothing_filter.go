@@ -0,0 +1,76 @@
+package gasprices
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SmoothingMethod is how the demand of recent epochs is combined
+type SmoothingMethod string
+
+const (
+	SmoothingMethodMean   SmoothingMethod = "mean"
+	SmoothingMethodMedian SmoothingMethod = "median"
+)
+
+// SmoothingFilter is a DemandFilter that replaces the demand of an epoch
+// with the mean or the median of the last Epochs epochs. This trades
+// responsiveness for stability.
+type SmoothingFilter struct {
+	mu      sync.Mutex
+	method  SmoothingMethod
+	epochs  int
+	samples []float64
+}
+
+// NewSmoothingFilter creates a SmoothingFilter over the last epochs epochs
+func NewSmoothingFilter(method SmoothingMethod, epochs uint64) (*SmoothingFilter, error) {
+	switch method {
+	case SmoothingMethodMean, SmoothingMethodMedian:
+	default:
+		return nil, fmt.Errorf("unknown smoothing method %q", method)
+	}
+	if epochs < 1 {
+		return nil, errors.New("smoothing epochs must be greater than or equal to 1")
+	}
+	return &SmoothingFilter{
+		method: method,
+		epochs: int(epochs),
+	}, nil
+}
+
+// FilterDemand returns the smoothed demand including this epoch. Until
+// enough epochs have been observed all of the observed epochs are used.
+func (f *SmoothingFilter) FilterDemand(avgGasPerSecond float64) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.samples = append(f.samples, avgGasPerSecond)
+	if len(f.samples) > f.epochs {
+		f.samples = f.samples[len(f.samples)-f.epochs:]
+	}
+	if f.method == SmoothingMethodMedian {
+		return median(f.samples), nil
+	}
+	mean, _ := meanAndStdDev(f.samples)
+	return mean, nil
+}
+
+// Samples returns the demands of the epochs that are smoothed, oldest first
+func (f *SmoothingFilter) Samples() []float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]float64(nil), f.samples...)
+}
+
+// SetSamples replaces the demands of the epochs that are smoothed, for
+// example with the samples saved before a restart
+func (f *SmoothingFilter) SetSamples(samples []float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(samples) > f.epochs {
+		samples = samples[len(samples)-f.epochs:]
+	}
+	f.samples = append([]float64(nil), samples...)
+}
@@ -0,0 +1,76 @@
+package gasprices
+
+import (
+	"testing"
+)
+
+func TestSmoothingFilter(t *testing.T) {
+	tests := []struct {
+		method SmoothingMethod
+		expect []float64
+	}{
+		{SmoothingMethodMean, []float64{100, 150, 100, 100, 300}},
+		// A single spike does not move the median
+		{SmoothingMethodMedian, []float64{100, 150, 100, 100, 100}},
+	}
+	demand := []float64{100, 200, 0, 100, 800}
+	for _, tt := range tests {
+		f, err := NewSmoothingFilter(tt.method, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, d := range demand {
+			smoothed, err := f.FilterDemand(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if smoothed != tt.expect[i] {
+				t.Fatalf("%s epoch %d: expected %f, got %f", tt.method, i, tt.expect[i], smoothed)
+			}
+		}
+	}
+}
+
+func TestSmoothingFilterSingleEpoch(t *testing.T) {
+	f, err := NewSmoothingFilter(SmoothingMethodMean, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []float64{100, 0, 50} {
+		smoothed, err := f.FilterDemand(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if smoothed != d {
+			t.Fatalf("expected %f, got %f", d, smoothed)
+		}
+	}
+}
+
+func TestSmoothingFilterSetSamples(t *testing.T) {
+	f, err := NewSmoothingFilter(SmoothingMethodMean, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the most recent epochs are kept
+	f.SetSamples([]float64{1000, 100, 200, 300})
+	if samples := f.Samples(); len(samples) != 3 || samples[0] != 100 {
+		t.Fatalf("unexpected samples %v", samples)
+	}
+	smoothed, err := f.FilterDemand(400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smoothed != 300 {
+		t.Fatalf("expected 300, got %f", smoothed)
+	}
+}
+
+func TestNewSmoothingFilterInvalid(t *testing.T) {
+	if _, err := NewSmoothingFilter("ewma", 3); err == nil {
+		t.Fatal("expected error for an unknown method")
+	}
+	if _, err := NewSmoothingFilter(SmoothingMethodMean, 0); err == nil {
+		t.Fatal("expected error for zero epochs")
+	}
+}
@@ -0,0 +1,67 @@
+package gasprices
+
+import (
+	"fmt"
+	"time"
+)
+
+// TargetGasWindow is a time of day window in UTC during which a specific
+// target gas per second is used. Start is inclusive and End is exclusive,
+// both are offsets from midnight. A window where End is before Start wraps
+// around midnight.
+type TargetGasWindow struct {
+	Start              time.Duration
+	End                time.Duration
+	TargetGasPerSecond uint64
+}
+
+// contains returns true if the offset from midnight is within the window
+func (w TargetGasWindow) contains(offset time.Duration) bool {
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// ParseTimeOfDay parses a time of day in the form of HH:MM into an offset
+// from midnight
+func ParseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ValidateTargetGasSchedule ensures that each window in the schedule is
+// well formed
+func ValidateTargetGasSchedule(schedule []TargetGasWindow) error {
+	for i, w := range schedule {
+		if w.Start == w.End {
+			return fmt.Errorf("target gas window %d: start and end cannot be equal", i)
+		}
+		if w.Start < 0 || w.Start >= 24*time.Hour || w.End < 0 || w.End >= 24*time.Hour {
+			return fmt.Errorf("target gas window %d: must be within a single day", i)
+		}
+		if w.TargetGasPerSecond < 1 {
+			return fmt.Errorf("target gas window %d: target gas per second cannot be less than 1", i)
+		}
+	}
+	return nil
+}
+
+// GetScheduledTargetGasPerSecondFn returns the target gas per second of the
+// first window in the schedule that contains the current time of day in UTC.
+// If no window matches, the fallback target gas per second is used.
+func GetScheduledTargetGasPerSecondFn(now func() time.Time, schedule []TargetGasWindow, fallback uint64) GetTargetGasPerSecond {
+	return func() float64 {
+		t := now().UTC()
+		offset := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+		for _, w := range schedule {
+			if w.contains(offset) {
+				return float64(w.TargetGasPerSecond)
+			}
+		}
+		return float64(fallback)
+	}
+}
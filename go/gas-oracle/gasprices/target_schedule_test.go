@@ -0,0 +1,90 @@
+package gasprices
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect time.Duration
+		err    bool
+	}{
+		{input: "00:00", expect: 0},
+		{input: "08:30", expect: 8*time.Hour + 30*time.Minute},
+		{input: "23:59", expect: 23*time.Hour + 59*time.Minute},
+		{input: "24:00", err: true},
+		{input: "8am", err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseTimeOfDay(tc.input)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.expect {
+				t.Fatalf("mismatch. Got %s, expected %s", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestGetScheduledTargetGasPerSecondFn(t *testing.T) {
+	schedule := []TargetGasWindow{
+		{Start: 8 * time.Hour, End: 18 * time.Hour, TargetGasPerSecond: 5_000_000},
+		// Wraps around midnight
+		{Start: 22 * time.Hour, End: 2 * time.Hour, TargetGasPerSecond: 20_000_000},
+	}
+	if err := ValidateTargetGasSchedule(schedule); err != nil {
+		t.Fatal(err)
+	}
+
+	var now time.Time
+	getTarget := GetScheduledTargetGasPerSecondFn(func() time.Time { return now }, schedule, 11_000_000)
+
+	tests := []struct {
+		hour   int
+		expect float64
+	}{
+		{hour: 7, expect: 11_000_000},
+		{hour: 8, expect: 5_000_000},
+		{hour: 17, expect: 5_000_000},
+		{hour: 18, expect: 11_000_000},
+		{hour: 22, expect: 20_000_000},
+		{hour: 1, expect: 20_000_000},
+		{hour: 2, expect: 11_000_000},
+	}
+	for _, tc := range tests {
+		now = time.Date(2022, 3, 1, tc.hour, 15, 0, 0, time.UTC)
+		if got := getTarget(); got != tc.expect {
+			t.Fatalf("hour %d: mismatch. Got %f, expected %f", tc.hour, got, tc.expect)
+		}
+	}
+
+	// The schedule is evaluated in UTC regardless of the local timezone
+	now = time.Date(2022, 3, 1, 8, 15, 0, 0, time.FixedZone("UTC+8", 8*60*60))
+	if got := getTarget(); got != 20_000_000 {
+		t.Fatalf("timezone not respected. Got %f", got)
+	}
+}
+
+func TestValidateTargetGasSchedule(t *testing.T) {
+	invalid := [][]TargetGasWindow{
+		{{Start: time.Hour, End: time.Hour, TargetGasPerSecond: 1}},
+		{{Start: time.Hour, End: 25 * time.Hour, TargetGasPerSecond: 1}},
+		{{Start: time.Hour, End: 2 * time.Hour, TargetGasPerSecond: 0}},
+	}
+	for i, schedule := range invalid {
+		if err := ValidateTargetGasSchedule(schedule); err == nil {
+			t.Fatalf("case %d: expected error", i)
+		}
+	}
+}
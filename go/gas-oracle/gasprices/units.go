@@ -0,0 +1,48 @@
+package gasprices
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// units are the suffixes that ParseWei accepts, longest first so that gwei
+// is not mistaken for wei
+var units = []struct {
+	suffix string
+	wei    int64
+}{
+	{"ether", params.Ether},
+	{"gwei", params.GWei},
+	{"wei", params.Wei},
+}
+
+// ParseWei parses an amount such as 0.001gwei, 10 gwei or 1ether into wei.
+// An amount without a unit is in wei. Fractions of a wei are rejected, so
+// that an amount meant in gwei but given without the unit fails instead of
+// being off by 10^9.
+func ParseWei(s string) (*big.Int, error) {
+	amount := strings.ToLower(strings.TrimSpace(s))
+	multiplier := int64(params.Wei)
+	for _, unit := range units {
+		if strings.HasSuffix(amount, unit.suffix) {
+			amount = strings.TrimSpace(strings.TrimSuffix(amount, unit.suffix))
+			multiplier = unit.wei
+			break
+		}
+	}
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q, expected a number with an optional unit of wei, gwei or ether", s)
+	}
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("amount %q cannot be negative", s)
+	}
+	value.Mul(value, new(big.Rat).SetInt64(multiplier))
+	if !value.IsInt() {
+		return nil, fmt.Errorf("amount %q is not a whole number of wei", s)
+	}
+	return new(big.Int).Set(value.Num()), nil
+}
@@ -0,0 +1,38 @@
+package gasprices
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseWei(t *testing.T) {
+	tests := []struct {
+		amount   string
+		expected string
+	}{
+		{"1", "1"},
+		{"1000000", "1000000"},
+		{"15wei", "15"},
+		{"0.001gwei", "1000000"},
+		{"10gwei", "10000000000"},
+		{"10 GWei", "10000000000"},
+		{"1.5ether", "1500000000000000000"},
+		{"1e9", "1000000000"},
+	}
+	for _, tt := range tests {
+		got, err := ParseWei(tt.amount)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.amount, err)
+		}
+		expected, _ := new(big.Int).SetString(tt.expected, 10)
+		if got.Cmp(expected) != 0 {
+			t.Fatalf("%s: expected %s, got %s", tt.amount, expected, got)
+		}
+	}
+
+	for _, amount := range []string{"", "gwei", "ten", "-1", "0.5", "0.0000000001gwei", "1kwei"} {
+		if _, err := ParseWei(amount); err == nil {
+			t.Fatalf("%q: expected error", amount)
+		}
+	}
+}
@@ -3,6 +3,23 @@ module github.com/ethereum-optimism/optimism/go/gas-oracle
 go 1.16
 
 require (
+	github.com/alicebob/miniredis/v2 v2.16.0
+	github.com/aws/aws-sdk-go v1.42.0
 	github.com/ethereum/go-ethereum v1.10.16
+	github.com/getsentry/sentry-go v0.12.0
+	github.com/go-redis/redis/v8 v8.11.4
+	github.com/gorilla/websocket v1.4.2
+	github.com/lib/pq v1.10.9
+	github.com/nats-io/nats.go v1.13.0
+	github.com/syndtr/goleveldb v1.0.1-0.20210819022825-2ae1ddf74ef7
 	github.com/urfave/cli v1.20.0
+	go.etcd.io/etcd/api/v3 v3.5.1
+	go.etcd.io/etcd/client/v3 v3.5.1
+	go.opentelemetry.io/otel v1.3.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.3.0
+	go.opentelemetry.io/otel/sdk v1.3.0
+	go.opentelemetry.io/otel/trace v1.3.0
+	google.golang.org/grpc v1.42.0
+	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v2 v2.4.0
 )
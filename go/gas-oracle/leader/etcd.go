@@ -0,0 +1,194 @@
+package leader
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// etcdDefaultPort is the client port of an etcd cluster
+const etcdDefaultPort = "2379"
+
+// etcdDialTimeout bounds the time to connect to an etcd cluster
+const etcdDialTimeout = 5 * time.Second
+
+// Etcd is a lease in an election of an etcd cluster. The holder campaigns
+// with a session, an etcd lease with the ttl that the client keeps alive, and
+// is elected once the keys of the sessions that campaigned before it are
+// deleted. The key of a session is deleted when the holder resigns or stops
+// keeping its session alive. The state is kept in the key with a /state
+// suffix, which is not attached to a session.
+type Etcd struct {
+	client   *clientv3.Client
+	prefix   string
+	stateKey string
+
+	mu       sync.Mutex
+	holder   string
+	session  *concurrency.Session
+	election *concurrency.Election
+	// cancel stops the campaign, which sends its result to campaign
+	cancel   context.CancelFunc
+	campaign chan error
+	elected  bool
+}
+
+// NewEtcd creates the lease in the election of the name in the cluster at u,
+// an etcds:// url uses TLS, verified by the TLS configuration or the system
+// roots when it is nil. The user info of u authenticates.
+func NewEtcd(u *url.URL, name string, tlsConfig *tls.Config) (*Etcd, error) {
+	endpoint := u.Host
+	if u.Port() == "" {
+		endpoint = net.JoinHostPort(u.Hostname(), etcdDefaultPort)
+	}
+	config := clientv3.Config{
+		Endpoints:   []string{endpoint},
+		DialTimeout: etcdDialTimeout,
+	}
+	if u.Scheme == "etcds" {
+		config.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+		if tlsConfig != nil {
+			config.TLS = tlsConfig.Clone()
+		}
+		config.TLS.ServerName = u.Hostname()
+	}
+	if u.User != nil {
+		config.Username = u.User.Username()
+		config.Password, _ = u.User.Password()
+	}
+	client, err := clientv3.New(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Etcd{client: client, prefix: name + "/election", stateKey: name + "/state"}, nil
+}
+
+// Acquire campaigns for the holder with a session of the ttl, and returns
+// true once the holder is elected and its key is still there
+func (e *Etcd) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.session != nil && e.holder != holder {
+		e.stopLocked(ctx)
+	}
+	if e.elected {
+		resp, err := e.client.Get(ctx, e.election.Key())
+		if err != nil {
+			return false, err
+		}
+		if len(resp.Kvs) == 1 && resp.Kvs[0].CreateRevision == e.election.Rev() {
+			return true, nil
+		}
+		// The session expired, the holder campaigns again
+		e.stopLocked(ctx)
+	}
+	if e.session == nil {
+		// The lease is granted with the context of the call, the session
+		// keeps it alive with the context of the client
+		seconds := int(math.Ceil(ttl.Seconds()))
+		lease, err := e.client.Grant(ctx, int64(seconds))
+		if err != nil {
+			return false, fmt.Errorf("cannot grant etcd lease: %w", err)
+		}
+		session, err := concurrency.NewSession(e.client, concurrency.WithTTL(seconds), concurrency.WithLease(lease.ID))
+		if err != nil {
+			e.client.Revoke(ctx, lease.ID)
+			return false, fmt.Errorf("cannot create etcd session: %w", err)
+		}
+		campaignCtx, cancel := context.WithCancel(context.Background())
+		e.holder, e.session, e.cancel = holder, session, cancel
+		e.election = concurrency.NewElection(session, e.prefix)
+		e.campaign = make(chan error, 1)
+		go func(election *concurrency.Election, result chan<- error) {
+			result <- election.Campaign(campaignCtx, holder)
+		}(e.election, e.campaign)
+	}
+	select {
+	case err := <-e.campaign:
+		e.campaign = nil
+		if err != nil {
+			e.stopLocked(ctx)
+			return false, fmt.Errorf("cannot campaign in etcd: %w", err)
+		}
+		e.elected = true
+	default:
+	}
+	return e.elected, nil
+}
+
+// stopLocked stops the campaign and closes the session, which deletes the
+// key of the holder
+func (e *Etcd) stopLocked(ctx context.Context) error {
+	if e.session == nil {
+		return nil
+	}
+	e.cancel()
+	var err error
+	if e.campaign != nil {
+		// A cancelled campaign resigns by itself
+		<-e.campaign
+	} else if e.elected {
+		err = e.election.Resign(ctx)
+	}
+	if closeErr := e.session.Close(); err == nil {
+		err = closeErr
+	}
+	e.session, e.election, e.campaign, e.elected = nil, nil, nil, false
+	return err
+}
+
+// Release resigns from the election, or stops campaigning, when the holder
+// campaigns
+func (e *Etcd) Release(ctx context.Context, holder string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.holder != holder {
+		return nil
+	}
+	return e.stopLocked(ctx)
+}
+
+// SaveState puts the state when the key of the holder is still the key of
+// the leader
+func (e *Etcd) SaveState(ctx context.Context, holder string, state []byte) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.elected || e.holder != holder {
+		return false, nil
+	}
+	resp, err := e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(e.election.Key()), "=", e.election.Rev())).
+		Then(clientv3.OpPut(e.stateKey, string(state))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+// LoadState gets the key of the state
+func (e *Etcd) LoadState(ctx context.Context) ([]byte, error) {
+	resp, err := e.client.Get(ctx, e.stateKey)
+	if err != nil || len(resp.Kvs) == 0 {
+		return nil, err
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+// Close stops the campaign and closes the client
+func (e *Etcd) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), etcdDialTimeout)
+	defer cancel()
+	e.stopLocked(ctx)
+	return e.client.Close()
+}
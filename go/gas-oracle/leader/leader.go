@@ -0,0 +1,76 @@
+// Package leader elects the replica that sends the transactions when several
+// replicas of the oracle run for high availability. The replicas compete for
+// a lease with a ttl in a store that they share.
+package leader
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+)
+
+// Lease is held by at most one holder at a time until it expires. The lease
+// keeps the state of its holder, so that the next holder continues from it.
+type Lease interface {
+	// Acquire takes the lease for the holder when it is free or expired, or
+	// renews it when the holder has it, and returns true when the holder
+	// has the lease for the ttl
+	Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error)
+	// Release gives up the lease when the holder has it. The state is kept.
+	Release(ctx context.Context, holder string) error
+	// SaveState replaces the state when the holder has the lease, and
+	// returns false when it does not, so that a holder that lost the lease
+	// does not overwrite the state of the next one
+	SaveState(ctx context.Context, holder string, state []byte) (bool, error)
+	// LoadState returns the state that was saved last, it is nil when no
+	// state was saved
+	LoadState(ctx context.Context) ([]byte, error)
+	Close() error
+}
+
+// CheckURL returns an error when the url is not the url of a supported store
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		return store.CheckURL(rawURL)
+	case "redis", "rediss", "etcd", "etcds":
+		if u.Host == "" {
+			return fmt.Errorf("no host in %s url", u.Scheme)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported scheme %q, expected postgres, redis, rediss, etcd or etcds", u.Scheme)
+	}
+}
+
+// New creates the lease of the name in the store of the url. A postgres://
+// url keeps the lease in a table of the database, which is migrated first, a
+// redis:// or rediss:// url in a key of a Redis server and an etcd:// or
+// etcds:// url in an election of an etcd cluster. The TLS configuration
+// verifies the rediss:// and etcds:// servers, the system roots do when it is
+// nil.
+func New(ctx context.Context, rawURL, name string, tlsConfig *tls.Config) (Lease, error) {
+	if err := CheckURL(rawURL); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		return store.OpenPostgresLease(ctx, rawURL, name)
+	case "redis", "rediss":
+		return NewRedis(u, name, tlsConfig)
+	default:
+		return NewEtcd(u, name, tlsConfig)
+	}
+}
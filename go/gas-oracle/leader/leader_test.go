@@ -0,0 +1,390 @@
+package leader
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/url"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"google.golang.org/grpc"
+)
+
+func TestCheckURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"postgres://oracle@localhost/gas_oracle",
+		"redis://:secret@localhost:6379/1",
+		"rediss://redis.internal",
+		"etcd://localhost:2379",
+		"etcds://etcd.internal:2379",
+	} {
+		if err := CheckURL(rawURL); err != nil {
+			t.Fatalf("expected %s to be valid, got %v", rawURL, err)
+		}
+	}
+	for _, rawURL := range []string{"http://localhost", "redis://", "zookeeper://localhost:2181"} {
+		if err := CheckURL(rawURL); err == nil {
+			t.Fatalf("expected %s to be invalid", rawURL)
+		}
+	}
+}
+
+// testLease runs the election of two holders on the lease
+func testLease(t *testing.T, lease Lease) {
+	ctx := context.Background()
+	for _, step := range []struct {
+		holder string
+		held   bool
+	}{
+		{"a", true},
+		// The holder renews the lease that the other holder cannot take
+		{"b", false},
+		{"a", true},
+	} {
+		held, err := lease.Acquire(ctx, step.holder, 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held != step.held {
+			t.Fatalf("expected %s to hold the lease: %t, got %t", step.holder, step.held, held)
+		}
+	}
+	if err := lease.Release(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if held, err := lease.Acquire(ctx, "b", 10*time.Second); err != nil || !held {
+		t.Fatalf("expected the released lease to be taken, got %t %v", held, err)
+	}
+	testLeaseState(t, lease, "b", "a")
+}
+
+// testLeaseState saves the state of the holder of the lease
+func testLeaseState(t *testing.T, lease Lease, holder, other string) {
+	ctx := context.Background()
+	if state, err := lease.LoadState(ctx); err != nil || state != nil {
+		t.Fatalf("expected no state, got %q %v", state, err)
+	}
+	if saved, err := lease.SaveState(ctx, other, []byte(`{"epoch":1}`)); err != nil || saved {
+		t.Fatalf("expected the state of %s to be rejected, got %t %v", other, saved, err)
+	}
+	if saved, err := lease.SaveState(ctx, holder, []byte(`{"epoch":2}`)); err != nil || !saved {
+		t.Fatalf("expected the state of %s to be saved, got %t %v", holder, saved, err)
+	}
+	// The state is kept for the next holder
+	if err := lease.Release(ctx, holder); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := lease.LoadState(ctx); err != nil || string(state) != `{"epoch":2}` {
+		t.Fatalf("unexpected state %q %v", state, err)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	server.RequireAuth("secret")
+	u, _ := url.Parse("redis://:secret@" + server.Addr() + "/2")
+	lease, err := NewRedis(u, "gas-oracle", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease.Close()
+	testLease(t, lease)
+	if state, err := server.DB(2).Get("gas-oracle/state"); err != nil || state != `{"epoch":2}` {
+		t.Fatalf("expected the state in the database of the url, got %q %v", state, err)
+	}
+}
+
+// fakeEtcd serves the kv, lease and watch services of etcd in memory. Leases
+// do not expire, expire deletes the keys of a lease like an expiry.
+type fakeEtcd struct {
+	pb.UnimplementedKVServer
+	pb.UnimplementedLeaseServer
+	pb.UnimplementedWatchServer
+
+	mu       sync.Mutex
+	revision int64
+	nextID   int64
+	leases   map[int64]bool
+	kvs      map[string]*mvccpb.KeyValue
+	// changed is closed and replaced when a key is deleted
+	changed chan struct{}
+}
+
+func newFakeEtcd(t *testing.T) (*fakeEtcd, *url.URL) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeEtcd{
+		revision: 1,
+		leases:   make(map[int64]bool),
+		kvs:      make(map[string]*mvccpb.KeyValue),
+		changed:  make(chan struct{}),
+	}
+	server := grpc.NewServer()
+	pb.RegisterKVServer(server, f)
+	pb.RegisterLeaseServer(server, f)
+	pb.RegisterWatchServer(server, f)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	u, _ := url.Parse("etcd://" + listener.Addr().String())
+	return f, u
+}
+
+func (f *fakeEtcd) header() *pb.ResponseHeader {
+	return &pb.ResponseHeader{Revision: f.revision}
+}
+
+func (f *fakeEtcd) rangeLocked(r *pb.RangeRequest) *pb.RangeResponse {
+	var kvs []*mvccpb.KeyValue
+	for key, kv := range f.kvs {
+		inRange := key == string(r.Key)
+		if len(r.RangeEnd) > 0 {
+			inRange = key >= string(r.Key) && key < string(r.RangeEnd)
+		}
+		if inRange && (r.MaxCreateRevision == 0 || kv.CreateRevision <= r.MaxCreateRevision) {
+			kvs = append(kvs, kv)
+		}
+	}
+	// The election only sorts by descending creation
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].CreateRevision > kvs[j].CreateRevision })
+	if r.Limit > 0 && int64(len(kvs)) > r.Limit {
+		kvs = kvs[:r.Limit]
+	}
+	return &pb.RangeResponse{Header: f.header(), Kvs: kvs, Count: int64(len(kvs))}
+}
+
+func (f *fakeEtcd) putLocked(r *pb.PutRequest) *pb.PutResponse {
+	f.revision++
+	kv := &mvccpb.KeyValue{Key: r.Key, Value: r.Value, Lease: r.Lease, CreateRevision: f.revision, ModRevision: f.revision}
+	if previous, ok := f.kvs[string(r.Key)]; ok {
+		kv.CreateRevision = previous.CreateRevision
+	}
+	f.kvs[string(r.Key)] = kv
+	return &pb.PutResponse{Header: f.header()}
+}
+
+func (f *fakeEtcd) deleteLocked(key string) {
+	if _, ok := f.kvs[key]; !ok {
+		return
+	}
+	f.revision++
+	delete(f.kvs, key)
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeEtcd) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rangeLocked(r), nil
+}
+
+func (f *fakeEtcd) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.putLocked(r), nil
+}
+
+// Txn compares the creation revisions and values, which the election and
+// the lease use
+func (f *fakeEtcd) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	succeeded := true
+	for _, compare := range r.Compare {
+		kv, ok := f.kvs[string(compare.Key)]
+		var equal bool
+		switch compare.Target {
+		case pb.Compare_CREATE:
+			equal = (ok && kv.CreateRevision == compare.GetCreateRevision()) || (!ok && compare.GetCreateRevision() == 0)
+		case pb.Compare_VALUE:
+			equal = ok && bytes.Equal(kv.Value, compare.GetValue())
+		}
+		succeeded = succeeded && equal == (compare.Result == pb.Compare_EQUAL)
+	}
+	ops := r.Success
+	if !succeeded {
+		ops = r.Failure
+	}
+	resp := &pb.TxnResponse{Succeeded: succeeded}
+	for _, op := range ops {
+		switch {
+		case op.GetRequestRange() != nil:
+			resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponseRange{
+				ResponseRange: f.rangeLocked(op.GetRequestRange()),
+			}})
+		case op.GetRequestPut() != nil:
+			resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponsePut{
+				ResponsePut: f.putLocked(op.GetRequestPut()),
+			}})
+		case op.GetRequestDeleteRange() != nil:
+			f.deleteLocked(string(op.GetRequestDeleteRange().Key))
+			resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponseDeleteRange{
+				ResponseDeleteRange: &pb.DeleteRangeResponse{Header: f.header()},
+			}})
+		}
+	}
+	resp.Header = f.header()
+	return resp, nil
+}
+
+func (f *fakeEtcd) LeaseGrant(ctx context.Context, r *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	f.leases[f.nextID] = true
+	return &pb.LeaseGrantResponse{Header: f.header(), ID: f.nextID, TTL: r.TTL}, nil
+}
+
+func (f *fakeEtcd) LeaseRevoke(ctx context.Context, r *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.expireLocked(r.ID)
+	return &pb.LeaseRevokeResponse{Header: f.header()}, nil
+}
+
+func (f *fakeEtcd) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) error {
+	for {
+		r, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		f.mu.Lock()
+		resp := &pb.LeaseKeepAliveResponse{Header: f.header(), ID: r.ID}
+		if f.leases[r.ID] {
+			resp.TTL = 10
+		}
+		f.mu.Unlock()
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// Watch sends the deletion of the watched keys, which the election waits for
+func (f *fakeEtcd) Watch(stream pb.Watch_WatchServer) error {
+	var send sync.Mutex
+	var id int64
+	for {
+		r, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		create := r.GetCreateRequest()
+		if create == nil {
+			continue
+		}
+		id++
+		f.mu.Lock()
+		header := f.header()
+		f.mu.Unlock()
+		send.Lock()
+		stream.Send(&pb.WatchResponse{Header: header, WatchId: id, Created: true})
+		send.Unlock()
+		go func(id int64, key []byte) {
+			for {
+				f.mu.Lock()
+				_, ok := f.kvs[string(key)]
+				header, changed := f.header(), f.changed
+				f.mu.Unlock()
+				if !ok {
+					send.Lock()
+					defer send.Unlock()
+					stream.Send(&pb.WatchResponse{Header: header, WatchId: id, Events: []*mvccpb.Event{
+						{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: key, ModRevision: header.Revision}},
+					}})
+					return
+				}
+				select {
+				case <-changed:
+				case <-stream.Context().Done():
+					return
+				}
+			}
+		}(id, create.Key)
+	}
+}
+
+// expire deletes the keys of the lease of the holder, as if it expired
+func (f *fakeEtcd) expire(holder string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, kv := range f.kvs {
+		if string(kv.Value) == holder && kv.Lease != 0 {
+			f.expireLocked(kv.Lease)
+		}
+	}
+}
+
+func (f *fakeEtcd) expireLocked(id int64) {
+	delete(f.leases, id)
+	for key, kv := range f.kvs {
+		if kv.Lease == id {
+			f.deleteLocked(key)
+		}
+	}
+}
+
+// waitAcquire acquires the lease until the holder is elected, since the
+// campaign runs in the background
+func waitAcquire(t *testing.T, lease Lease, holder string) {
+	ctx := context.Background()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		held, err := lease.Acquire(ctx, holder, 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s to be elected", holder)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEtcd(t *testing.T) {
+	f, u := newFakeEtcd(t)
+	// Each replica has its own client
+	a, err := NewEtcd(u, "gas-oracle", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := NewEtcd(u, "gas-oracle", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	ctx := context.Background()
+	waitAcquire(t, a, "a")
+	if held, err := b.Acquire(ctx, "b", 10*time.Second); err != nil || held {
+		t.Fatalf("expected b to wait for the lease, got %t %v", held, err)
+	}
+	if held, err := a.Acquire(ctx, "a", 10*time.Second); err != nil || !held {
+		t.Fatalf("expected a to renew the lease, got %t %v", held, err)
+	}
+
+	// An expired lease of a is taken by b
+	f.expire("a")
+	waitAcquire(t, b, "b")
+	if held, err := a.Acquire(ctx, "a", 10*time.Second); err != nil || held {
+		t.Fatalf("expected a to lose the expired lease, got %t %v", held, err)
+	}
+	testLeaseState(t, b, "b", "a")
+	// a campaigned again after it lost the lease
+	waitAcquire(t, a, "a")
+}
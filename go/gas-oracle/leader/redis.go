@@ -0,0 +1,95 @@
+package leader
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisAcquireScript sets the key to the holder with the ttl in milliseconds
+// when it is free or already set to the holder
+var redisAcquireScript = redis.NewScript(`local holder = redis.call('GET', KEYS[1])
+if holder == false or holder == ARGV[1] then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+return 0`)
+
+// redisReleaseScript deletes the key when it is set to the holder
+var redisReleaseScript = redis.NewScript(`if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`)
+
+// redisSaveScript sets the key of the state when the key of the lease is set
+// to the holder
+var redisSaveScript = redis.NewScript(`if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('SET', KEYS[2], ARGV[2])
+	return 1
+end
+return 0`)
+
+// Redis is a lease in a key of a Redis server that expires with the ttl of
+// the key. The scripts are run atomically by the server.
+type Redis struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedis creates the lease in the key of the server at u, the state is kept
+// in the key with a /state suffix, which does not expire. The user info of
+// u authenticates and the path selects the database. The TLS configuration
+// verifies a rediss:// server, the system roots do when it is nil.
+func NewRedis(u *url.URL, key string, tlsConfig *tls.Config) (*Redis, error) {
+	options, err := redis.ParseURL(u.String())
+	if err != nil {
+		return nil, err
+	}
+	if options.TLSConfig != nil && tlsConfig != nil {
+		config := tlsConfig.Clone()
+		config.ServerName = options.TLSConfig.ServerName
+		options.TLSConfig = config
+	}
+	return &Redis{client: redis.NewClient(options), key: key}, nil
+}
+
+// Acquire sets the key to the holder, see Lease
+func (r *Redis) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	reply, err := redisAcquireScript.Run(ctx, r.client, []string{r.key}, holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return reply == 1, nil
+}
+
+// Release deletes the key when it is set to the holder
+func (r *Redis) Release(ctx context.Context, holder string) error {
+	return redisReleaseScript.Run(ctx, r.client, []string{r.key}, holder).Err()
+}
+
+// SaveState sets the key of the state when the holder has the lease
+func (r *Redis) SaveState(ctx context.Context, holder string, state []byte) (bool, error) {
+	reply, err := redisSaveScript.Run(ctx, r.client, []string{r.key, r.key + "/state"}, holder, state).Int()
+	if err != nil {
+		return false, err
+	}
+	return reply == 1, nil
+}
+
+// LoadState gets the key of the state
+func (r *Redis) LoadState(ctx context.Context) ([]byte, error) {
+	state, err := r.client.Get(ctx, r.key+"/state").Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return state, err
+}
+
+// Close closes the connections
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
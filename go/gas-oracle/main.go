@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/commands"
 	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
 	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
 	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/reporting"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics/influxdb"
 	"github.com/ethereum/go-ethereum/params"
@@ -18,6 +22,7 @@ var (
 	GitVersion = ""
 	GitCommit  = ""
 	GitDate    = ""
+	BuildDate  = ""
 )
 
 func main() {
@@ -30,10 +35,40 @@ func main() {
 	app.Description = "Configure with a private key and an Optimism HTTP endpoint " +
 		"to send transactions that update the L2 gas price."
 
+	info := commands.BuildInfo{
+		Version:    GitVersion,
+		Commit:     GitCommit,
+		CommitDate: GitDate,
+		BuildDate:  BuildDate,
+	}
+	app.Commands = []cli.Command{
+		commands.BacktestCommand,
+		commands.SimulateCommand,
+		commands.InitCommand,
+		commands.StatusCommand,
+		commands.CheckCommand,
+		commands.ExportHistoryCommand,
+		commands.ExportDecisionsCommand,
+		commands.VerifyDecisionsCommand,
+		commands.WatchCommand,
+		commands.EstimateFeeCommand,
+		commands.SetGasPriceCommand,
+		commands.SetL1BaseFeeCommand,
+		commands.OwnerCommand,
+		commands.TransferOwnershipCommand,
+		commands.AcceptOwnershipCommand,
+		commands.NewVersionCommand(info),
+	}
+
 	// Configure the logging
 	app.Before = func(ctx *cli.Context) error {
 		loglevel := ctx.GlobalUint64(flags.LogLevelFlag.Name)
-		log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(loglevel), log.StreamHandler(os.Stdout, log.TerminalFormat(true))))
+		format, err := logFormat(ctx.GlobalString(flags.LogFormatFlag.Name))
+		if err != nil {
+			return err
+		}
+		log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(loglevel), log.StreamHandler(os.Stdout, format)))
+		ometrics.SetBuildInfo(info.Labels())
 		return nil
 	}
 
@@ -43,20 +78,83 @@ func main() {
 			return fmt.Errorf("invalid command: %q", args[0])
 		}
 
-		config := oracle.NewConfig(ctx)
-		gpo, err := oracle.NewGasPriceOracle(config)
+		config, err := oracle.NewConfig(ctx)
 		if err != nil {
 			return err
 		}
+		if err := config.Validate(); err != nil {
+			return err
+		}
+		log.Info("Starting gas oracle", "version", GitVersion, "commit", GitCommit)
 
-		if err := gpo.Start(); err != nil {
+		if config.SentryDSN != "" {
+			log.Info("Enabling error reporting to Sentry", "environment", config.SentryEnvironment)
+			flush, err := reporting.Setup(config.SentryDSN, config.SentryEnvironment, GitVersion,
+				config.ReportingTags())
+			if err != nil {
+				return err
+			}
+			defer flush()
+			defer reporting.Recover()
+		}
+
+		if config.TracingOTLPEndpoint != "" {
+			log.Info("Enabling trace export to OTLP", "endpoint", config.TracingOTLPEndpoint)
+			shutdown, err := tracing.Setup(config.TracingOTLPEndpoint, config.TracingOTLPInsecure,
+				config.TracingSampleRatio, GitVersion)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := shutdown(context.Background()); err != nil {
+					log.Error("cannot flush traces", "message", err)
+				}
+			}()
+		}
+
+		// The chains of the config file run in this process, each with its
+		// own updater
+		chains, err := oracle.NewChainSet(config)
+		if err != nil {
+			return err
+		}
+
+		// A one-shot run exits once its updates are mined, which suits cron
+		// jobs and manual runs during incidents
+		if config.OneShot() {
+			if config.MetricsPushgatewayURL != "" {
+				defer commands.PushMetrics(config)
+			}
+			log.Info("Running a single epoch")
+			return chains.RunOnce()
+		}
+
+		if err := chains.Start(); err != nil {
 			return err
 		}
+		handlePauseSignal(chains)
 
 		if config.MetricsEnabled {
-			address := fmt.Sprintf("%s:%d", config.MetricsHTTP, config.MetricsPort)
-			log.Info("Enabling stand-alone metrics HTTP endpoint", "address", address)
-			ometrics.Setup(address)
+			switch config.MetricsBackend {
+			case "prometheus":
+				address := fmt.Sprintf("%s:%d", config.MetricsHTTP, config.MetricsPort)
+				log.Info("Enabling stand-alone metrics HTTP endpoint", "address", address)
+				ometrics.Setup(address)
+			case "statsd":
+				err := ometrics.StatsD(ometrics.DefaultRegistry, 10*time.Second, config.MetricsStatsDAddress,
+					config.MetricsStatsDPrefix, config.MetricsStatsDTags)
+				if err != nil {
+					return err
+				}
+			case "cloudwatch":
+				err := ometrics.CloudWatch(ometrics.DefaultRegistry, time.Minute, config.MetricsCloudWatchNamespace,
+					config.MetricsCloudWatchRegion, config.MetricsCloudWatchDimensions)
+				if err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("invalid metrics backend: %q", config.MetricsBackend)
+			}
 		}
 
 		if config.MetricsEnableInfluxDB {
@@ -68,7 +166,7 @@ func main() {
 			go influxdb.InfluxDBWithTags(ometrics.DefaultRegistry, 10*time.Second, endpoint, database, username, password, "geth.", make(map[string]string))
 		}
 
-		gpo.Wait()
+		chains.Wait()
 
 		return nil
 	}
@@ -78,3 +176,15 @@ func main() {
 		log.Crit("application failed", "message", err)
 	}
 }
+
+// logFormat returns the log format with the given name
+func logFormat(name string) (log.Format, error) {
+	switch name {
+	case "terminal":
+		return log.TerminalFormat(true), nil
+	case "json":
+		return log.JSONFormat(), nil
+	default:
+		return nil, fmt.Errorf("invalid log format: %q", name)
+	}
+}
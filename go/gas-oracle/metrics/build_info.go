@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	buildInfoLock sync.RWMutex
+	buildInfo     map[string]string
+)
+
+// SetBuildInfo sets the labels of the build_info metric, which identifies
+// the build that is running. The go-ethereum registry has no labels, so the
+// metric is only served by PrometheusHandler.
+func SetBuildInfo(labels map[string]string) {
+	buildInfoLock.Lock()
+	defer buildInfoLock.Unlock()
+	buildInfo = labels
+}
+
+// PrometheusHandler serves the metrics of the registry in the Prometheus
+// text format, see writePrometheus
+func PrometheusHandler(r metrics.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body bytes.Buffer
+		writePrometheus(&body, r)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+		w.Write(body.Bytes())
+	})
+}
+
+// writePrometheus writes the metrics of the registry in the Prometheus text
+// format, followed by the build_info metric. The metrics of the chains are
+// written with a chain label.
+func writePrometheus(buf *bytes.Buffer, r metrics.Registry) {
+	base, chains := splitChains(r)
+	if len(chains) == 0 {
+		buf.Write(prometheusText(r))
+	} else {
+		buf.Write(chainsText(base, chains))
+	}
+	buf.WriteString(buildInfoText())
+}
+
+// buildInfoText renders the build_info metric
+func buildInfoText() string {
+	buildInfoLock.RLock()
+	defer buildInfoLock.RUnlock()
+	if len(buildInfo) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(buildInfo))
+	for name := range buildInfo {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = fmt.Sprintf("%s=%q", name, buildInfo[name])
+	}
+	return fmt.Sprintf("# TYPE build_info gauge\nbuild_info{%s} 1\n", strings.Join(labels, ","))
+}
+
+type bodyRecorder struct {
+	header http.Header
+	body   []byte
+}
+
+func (b *bodyRecorder) Header() http.Header { return b.header }
+func (b *bodyRecorder) WriteHeader(int)     {}
+
+func (b *bodyRecorder) Write(p []byte) (int, error) {
+	b.body = append(b.body, p...)
+	return len(p), nil
+}
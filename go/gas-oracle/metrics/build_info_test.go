@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestPrometheusHandlerBuildInfo(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	r := metrics.NewRegistry()
+	metrics.NewRegisteredGauge("gas_price", r).Update(1000)
+
+	SetBuildInfo(map[string]string{"version": "0.1.11", "commit": "abc"})
+	defer SetBuildInfo(nil)
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "gas_price 1000") {
+		t.Fatalf("expected the registry metrics, got %q", body)
+	}
+	if !strings.Contains(body, `build_info{commit="abc",version="0.1.11"} 1`) {
+		t.Fatalf("expected the build_info metric, got %q", body)
+	}
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+)
+
+// chainPrefix is the prefix of the metrics of a chain in the registry
+const chainPrefix = "chain/"
+
+// ChainRegistry returns the registry of the metrics of a chain when one
+// process runs several chains. The metrics are kept in the DefaultRegistry
+// under chain/<name>/, which is how the backends without labels export
+// them, and PrometheusHandler serves them with a chain label.
+func ChainRegistry(name string) metrics.Registry {
+	return metrics.NewPrefixedChildRegistry(DefaultRegistry, chainPrefix+name+"/")
+}
+
+// UnregisterChain removes the metrics of a chain that is no longer run, so
+// that they are not exported anymore
+func UnregisterChain(name string) {
+	prefix := chainPrefix + name + "/"
+	var names []string
+	DefaultRegistry.Each(func(name string, _ interface{}) {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	})
+	for _, name := range names {
+		DefaultRegistry.Unregister(name)
+	}
+}
+
+// splitChains separates the metrics of the chains from the other metrics of
+// the registry. The registries of the chains are keyed by the chain name and
+// hold the metrics without the prefix.
+func splitChains(r metrics.Registry) (metrics.Registry, map[string]metrics.Registry) {
+	base := metrics.NewRegistry()
+	chains := make(map[string]metrics.Registry)
+	r.Each(func(name string, metric interface{}) {
+		rest := strings.TrimPrefix(name, chainPrefix)
+		i := strings.Index(rest, "/")
+		if rest == name || i <= 0 {
+			base.Register(name, metric)
+			return
+		}
+		chain, ok := chains[rest[:i]]
+		if !ok {
+			chain = metrics.NewRegistry()
+			chains[rest[:i]] = chain
+		}
+		chain.Register(rest[i+1:], metric)
+	})
+	return base, chains
+}
+
+// prometheusText renders the registry in the Prometheus text format
+func prometheusText(r metrics.Registry) []byte {
+	rec := &bodyRecorder{header: make(http.Header)}
+	// The handler of the registry does not read the request
+	prometheus.Handler(r).ServeHTTP(rec, new(http.Request))
+	return rec.body
+}
+
+// families groups the samples of the rendered registries by metric, since
+// a metric may only have one TYPE line
+type families struct {
+	order   []string
+	types   map[string]string
+	samples map[string][]string
+}
+
+// add adds the samples of the text, with the chain label unless chain is
+// empty
+func (f *families) add(text []byte, chain string) {
+	family := ""
+	for _, line := range strings.Split(string(text), "\n") {
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "# TYPE "):
+			family = strings.Fields(line)[2]
+			if _, ok := f.types[family]; !ok {
+				f.order = append(f.order, family)
+				f.types[family] = line
+			}
+		default:
+			if chain != "" {
+				line = withChainLabel(line, chain)
+			}
+			f.samples[family] = append(f.samples[family], line)
+		}
+	}
+}
+
+func (f *families) bytes() []byte {
+	var b strings.Builder
+	for _, family := range f.order {
+		b.WriteString(f.types[family] + "\n")
+		for _, sample := range f.samples[family] {
+			b.WriteString(sample + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+// withChainLabel adds the chain label to a sample, which is either a name
+// and a value or a name, the labels and a value
+func withChainLabel(sample, chain string) string {
+	i := strings.IndexByte(sample, ' ')
+	if i < 0 {
+		return sample
+	}
+	name, rest := sample[:i], sample[i+1:]
+	if strings.HasPrefix(rest, "{") {
+		return fmt.Sprintf("%s{chain=%q,%s", name, chain, rest[1:])
+	}
+	return fmt.Sprintf("%s{chain=%q} %s", name, chain, rest)
+}
+
+// chainsText renders the metrics of the registry with the metrics of the
+// chains labelled with their name
+func chainsText(base metrics.Registry, chains map[string]metrics.Registry) []byte {
+	f := &families{types: make(map[string]string), samples: make(map[string][]string)}
+	f.add(prometheusText(base), "")
+	names := make([]string, 0, len(chains))
+	for name := range chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f.add(prometheusText(chains[name]), name)
+	}
+	return f.bytes()
+}
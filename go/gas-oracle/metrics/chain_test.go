@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestPrometheusHandlerChains(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	r := metrics.NewRegistry()
+	metrics.NewRegisteredGauge("gas_price", r).Update(1000)
+	for name, price := range map[string]int64{"op-mainnet": 1, "op-goerli": 2} {
+		chain := metrics.NewPrefixedChildRegistry(r, chainPrefix+name+"/")
+		metrics.NewRegisteredGauge("gas_price", chain).Update(price)
+		metrics.NewRegisteredTimer("tx/send", chain)
+	}
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	for _, sample := range []string{
+		"gas_price 1000",
+		`gas_price{chain="op-goerli"} 2`,
+		`gas_price{chain="op-mainnet"} 1`,
+		`tx_send{chain="op-mainnet",quantile="0.5"} 0`,
+	} {
+		if !strings.Contains(body, sample) {
+			t.Fatalf("expected %s, got %q", sample, body)
+		}
+	}
+	if n := strings.Count(body, "# TYPE gas_price gauge"); n != 1 {
+		t.Fatalf("expected one TYPE line for the gas price, got %d", n)
+	}
+	if strings.Contains(body, "chain_op") {
+		t.Fatalf("expected no prefixed metrics, got %q", body)
+	}
+}
+
+func TestUnregisterChain(t *testing.T) {
+	metrics.NewRegisteredGauge("gas_price", ChainRegistry("op-sepolia"))
+	metrics.NewRegisteredGauge("gas_price", ChainRegistry("op-sepolia-2"))
+	UnregisterChain("op-sepolia")
+	if DefaultRegistry.Get(chainPrefix+"op-sepolia/gas_price") != nil {
+		t.Fatal("expected the metrics of the chain to be unregistered")
+	}
+	if DefaultRegistry.Get(chainPrefix+"op-sepolia-2/gas_price") == nil {
+		t.Fatal("expected the metrics of the other chain to be kept")
+	}
+	UnregisterChain("op-sepolia-2")
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// maxCloudWatchData is the number of metrics sent in each PutMetricData call
+const maxCloudWatchData = 20
+
+// CloudWatchMetrics are the core metrics of the oracle that are published to
+// CloudWatch. Each metric is billed separately, so the rest of the registry
+// is not published.
+var CloudWatchMetrics = []string{
+	"gas_price",
+	"epoch/count",
+	"epoch/avg_gas_per_second",
+	"epoch/computed_price",
+	"epoch/gas_price",
+	"epoch/change",
+	"epoch/sent",
+	"tx/send",
+	"signer/balance",
+	"signer/low_balance",
+	"drift/ratio",
+}
+
+// CloudWatch periodically publishes the core metrics of the registry to
+// CloudWatch under the namespace. Credentials are resolved by the default
+// AWS credential chain and the region of the environment is used when the
+// region is empty. Dimensions are given as Name=Value.
+func CloudWatch(r metrics.Registry, interval time.Duration, namespace, region string, dimensions []string) error {
+	dims, err := parseDimensions(dimensions)
+	if err != nil {
+		return err
+	}
+	opts := session.Options{SharedConfigState: session.SharedConfigEnable}
+	if region != "" {
+		opts.Config.Region = aws.String(region)
+	}
+	sess, err := session.NewSessionWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	c := newCloudWatchReporter(r, cloudwatch.New(sess), namespace, dims, CloudWatchMetrics)
+	log.Info("Starting CloudWatch metrics export", "namespace", namespace,
+		"region", aws.StringValue(sess.Config.Region), "interval", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := c.flush(context.Background()); err != nil {
+				log.Error("Failure in sending metrics to CloudWatch", "err", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// parseDimensions parses dimensions given as Name=Value
+func parseDimensions(dimensions []string) ([]*cloudwatch.Dimension, error) {
+	var dims []*cloudwatch.Dimension
+	for _, d := range dimensions {
+		parts := strings.SplitN(d, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid CloudWatch dimension: %q", d)
+		}
+		dims = append(dims, &cloudwatch.Dimension{
+			Name:  aws.String(parts[0]),
+			Value: aws.String(parts[1]),
+		})
+	}
+	return dims, nil
+}
+
+type cloudWatchReporter struct {
+	registry   metrics.Registry
+	client     cloudwatchiface.CloudWatchAPI
+	namespace  string
+	dimensions []*cloudwatch.Dimension
+	names      []string
+	// counts holds the counts of the previous flush so that counters are
+	// sent as increments
+	counts map[string]int64
+}
+
+func newCloudWatchReporter(r metrics.Registry, client cloudwatchiface.CloudWatchAPI, namespace string, dimensions []*cloudwatch.Dimension, names []string) *cloudWatchReporter {
+	return &cloudWatchReporter{
+		registry:   r,
+		client:     client,
+		namespace:  namespace,
+		dimensions: dimensions,
+		names:      names,
+		counts:     make(map[string]int64),
+	}
+}
+
+// flush publishes the current value of every core metric. Metrics that
+// have not been registered yet are skipped.
+func (c *cloudWatchReporter) flush(ctx context.Context) error {
+	now := time.Now()
+	var data []*cloudwatch.MetricDatum
+	add := func(name string, value float64, unit string) {
+		data = append(data, &cloudwatch.MetricDatum{
+			MetricName: aws.String(strings.ReplaceAll(name, "/", ".")),
+			Dimensions: c.dimensions,
+			Timestamp:  aws.Time(now),
+			Value:      aws.Float64(value),
+			Unit:       aws.String(unit),
+		})
+	}
+	for _, name := range c.names {
+		switch metric := c.registry.Get(name).(type) {
+		case metrics.Counter:
+			count := metric.Count()
+			add(name, float64(count-c.counts[name]), cloudwatch.StandardUnitCount)
+			c.counts[name] = count
+		case metrics.Gauge:
+			add(name, float64(metric.Value()), cloudwatch.StandardUnitNone)
+		case metrics.GaugeFloat64:
+			add(name, metric.Value(), cloudwatch.StandardUnitNone)
+		}
+	}
+
+	for len(data) > 0 {
+		n := len(data)
+		if n > maxCloudWatchData {
+			n = maxCloudWatchData
+		}
+		_, err := c.client.PutMetricDataWithContext(ctx, &cloudwatch.PutMetricDataInput{
+			Namespace:  aws.String(c.namespace),
+			MetricData: data[:n],
+		})
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
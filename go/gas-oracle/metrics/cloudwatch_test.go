@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+type mockCloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+	inputs []*cloudwatch.PutMetricDataInput
+}
+
+func (m *mockCloudWatch) PutMetricDataWithContext(ctx aws.Context, input *cloudwatch.PutMetricDataInput, opts ...request.Option) (*cloudwatch.PutMetricDataOutput, error) {
+	m.inputs = append(m.inputs, input)
+	return &cloudwatch.PutMetricDataOutput{}, nil
+}
+
+func TestCloudWatchReporter(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	r := metrics.NewRegistry()
+	counter := metrics.NewRegisteredCounter("tx/send", r)
+	metrics.NewRegisteredGauge("gas_price", r).Update(1000)
+	metrics.NewRegisteredGaugeFloat64("drift/ratio", r).Update(0.25)
+	metrics.NewRegisteredGauge("txpool/pending", r).Update(3)
+	counter.Inc(3)
+
+	dims, err := parseDimensions([]string{"Network=test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseDimensions([]string{"Network"}); err == nil {
+		t.Fatal("expected error for a dimension without a value")
+	}
+
+	client := new(mockCloudWatch)
+	c := newCloudWatchReporter(r, client, "GasOracle", dims, CloudWatchMetrics)
+	values := func() map[string]float64 {
+		if err := c.flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		input := client.inputs[len(client.inputs)-1]
+		if aws.StringValue(input.Namespace) != "GasOracle" {
+			t.Fatalf("unexpected namespace %s", aws.StringValue(input.Namespace))
+		}
+		got := make(map[string]float64)
+		for _, datum := range input.MetricData {
+			if len(datum.Dimensions) != 1 || aws.StringValue(datum.Dimensions[0].Value) != "test" {
+				t.Fatal("expected the Network dimension")
+			}
+			got[aws.StringValue(datum.MetricName)] = aws.Float64Value(datum.Value)
+		}
+		return got
+	}
+
+	// Only the core metrics that are registered are published
+	got := values()
+	expect := map[string]float64{"gas_price": 1000, "tx.send": 3, "drift.ratio": 0.25}
+	if len(got) != len(expect) {
+		t.Fatalf("expected %v, got %v", expect, got)
+	}
+	for name, value := range expect {
+		if got[name] != value {
+			t.Fatalf("%s: expected %f, got %f", name, value, got[name])
+		}
+	}
+
+	// Counters are published as increments
+	counter.Inc(2)
+	if got := values(); got["tx.send"] != 2 {
+		t.Fatalf("expected an increment of 2, got %f", got["tx.send"])
+	}
+}
@@ -60,7 +60,7 @@ func ExpHandler(r metrics.Registry) http.Handler {
 func Setup(address string) {
 	m := http.NewServeMux()
 	m.Handle("/debug/metrics", ExpHandler(DefaultRegistry))
-	m.Handle("/debug/metrics/prometheus", prometheus.Handler(DefaultRegistry))
+	m.Handle("/debug/metrics/prometheus", PrometheusHandler(DefaultRegistry))
 	log.Info("Starting metrics server", "addr", fmt.Sprintf("http://%s/debug/metrics", address))
 	go func() {
 		if err := http.ListenAndServe(address, m); err != nil {
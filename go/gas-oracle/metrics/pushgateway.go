@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Push sends the metrics of the registry to a Prometheus Pushgateway under
+// the job, replacing the metrics of the previous push of the job. It is
+// meant for short-lived runs that cannot be scraped.
+func Push(r metrics.Registry, gatewayURL, job string) error {
+	var body bytes.Buffer
+	writePrometheus(&body, r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	endpoint := strings.TrimRight(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(job)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("pushgateway returned status %d", resp.StatusCode)
+	}
+	return nil
+}
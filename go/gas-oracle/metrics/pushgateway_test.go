@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestPush(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	r := metrics.NewRegistry()
+	metrics.NewRegisteredGauge("backtest/updates", r).Update(4)
+
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := ioutil.ReadAll(req.Body)
+		method, path, body = req.Method, req.URL.Path, string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	if err := Push(r, server.URL+"/", "gas-oracle"); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/gas-oracle" {
+		t.Fatalf("unexpected request %s %s", method, path)
+	}
+	if !strings.Contains(body, "backtest_updates 4") {
+		t.Fatalf("expected the gauge in the body, got %q", body)
+	}
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	if err := Push(r, server.URL, "gas-oracle"); err == nil {
+		t.Fatal("expected error for a failed push")
+	}
+}
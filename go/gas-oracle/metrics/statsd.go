@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// maxPacketSize keeps StatsD packets below the MTU of most networks
+const maxPacketSize = 1432
+
+// StatsD periodically sends the metrics of the registry to a StatsD server
+// over UDP. Tags are appended in the DogStatsD format so that they can be
+// ingested by the Datadog agent.
+func StatsD(r metrics.Registry, interval time.Duration, address, prefix string, tags []string) error {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return err
+	}
+	s := newStatsDReporter(r, conn, prefix, tags)
+	log.Info("Starting StatsD metrics export", "address", address, "interval", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.flush(); err != nil {
+				log.Error("Failure in sending metrics to StatsD", "err", err)
+			}
+		}
+	}()
+	return nil
+}
+
+type statsDReporter struct {
+	registry metrics.Registry
+	conn     net.Conn
+	prefix   string
+	tags     string
+	// counts holds the counts of the previous flush so that counters are
+	// sent as increments
+	counts map[string]int64
+}
+
+func newStatsDReporter(r metrics.Registry, conn net.Conn, prefix string, tags []string) *statsDReporter {
+	s := &statsDReporter{
+		registry: r,
+		conn:     conn,
+		prefix:   prefix,
+		counts:   make(map[string]int64),
+	}
+	if len(tags) > 0 {
+		s.tags = "|#" + strings.Join(tags, ",")
+	}
+	return s
+}
+
+// flush sends the current value of every metric
+func (s *statsDReporter) flush() error {
+	var lines []string
+	add := func(name, value, kind string) {
+		name = s.prefix + strings.ReplaceAll(name, "/", ".")
+		lines = append(lines, fmt.Sprintf("%s:%s|%s%s", name, value, kind, s.tags))
+	}
+	incr := func(name string, count int64) {
+		delta := count - s.counts[name]
+		s.counts[name] = count
+		if delta != 0 {
+			add(name, fmt.Sprint(delta), "c")
+		}
+	}
+
+	s.registry.Each(func(name string, i interface{}) {
+		switch metric := i.(type) {
+		case metrics.Counter:
+			incr(name, metric.Count())
+		case metrics.Gauge:
+			add(name, fmt.Sprint(metric.Value()), "g")
+		case metrics.GaugeFloat64:
+			add(name, fmt.Sprint(metric.Value()), "g")
+		case metrics.Meter:
+			m := metric.Snapshot()
+			incr(name+".count", m.Count())
+			add(name+".rate1", fmt.Sprint(m.Rate1()), "g")
+		case metrics.Histogram:
+			h := metric.Snapshot()
+			ps := h.Percentiles([]float64{0.5, 0.95, 0.99})
+			incr(name+".count", h.Count())
+			add(name+".mean", fmt.Sprint(h.Mean()), "g")
+			add(name+".p50", fmt.Sprint(ps[0]), "g")
+			add(name+".p95", fmt.Sprint(ps[1]), "g")
+			add(name+".p99", fmt.Sprint(ps[2]), "g")
+		case metrics.Timer:
+			// Durations are sent in milliseconds
+			t := metric.Snapshot()
+			ps := t.Percentiles([]float64{0.5, 0.95, 0.99})
+			ms := float64(time.Millisecond)
+			incr(name+".count", t.Count())
+			add(name+".mean", fmt.Sprint(t.Mean()/ms), "g")
+			add(name+".p50", fmt.Sprint(ps[0]/ms), "g")
+			add(name+".p95", fmt.Sprint(ps[1]/ms), "g")
+			add(name+".p99", fmt.Sprint(ps[2]/ms), "g")
+		}
+	})
+
+	// Send as many lines as fit in each packet
+	var packet bytes.Buffer
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+len(line)+1 > maxPacketSize {
+			if _, err := s.conn.Write(packet.Bytes()); err != nil {
+				return err
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	if packet.Len() > 0 {
+		if _, err := s.conn.Write(packet.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestStatsDReporter(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	r := metrics.NewRegistry()
+	counter := metrics.NewRegisteredCounter("tx/send", r)
+	metrics.NewRegisteredGauge("gas_price", r).Update(1000)
+	metrics.NewRegisteredGaugeFloat64("shadow/divergence", r).Update(0.25)
+	counter.Inc(3)
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	conn, err := net.Dial("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	read := func() []string {
+		buf := make([]byte, maxPacketSize)
+		if err := server.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		n, _, err := server.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	s := newStatsDReporter(r, conn, "gas_oracle.", []string{"env:test"})
+	if err := s.flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines := read()
+	expect := map[string]bool{
+		"gas_oracle.tx.send:3|c|#env:test":              true,
+		"gas_oracle.gas_price:1000|g|#env:test":         true,
+		"gas_oracle.shadow.divergence:0.25|g|#env:test": true,
+	}
+	if len(lines) != len(expect) {
+		t.Fatalf("expected %d lines, got %v", len(expect), lines)
+	}
+	for _, line := range lines {
+		if !expect[line] {
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+
+	// Counters are sent as increments
+	counter.Inc(2)
+	if err := s.flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines = read()
+	found := false
+	for _, line := range lines {
+		if line == "gas_oracle.tx.send:2|c|#env:test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected counter increment, got %v", lines)
+	}
+}
@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+)
+
+// ChatFormat is the chat service that messages are formatted for
+type ChatFormat string
+
+const (
+	ChatFormatSlack   ChatFormat = "slack"
+	ChatFormatDiscord ChatFormat = "discord"
+)
+
+// Chat posts messages about significant events to a Slack or Discord
+// incoming webhook. Unlike the Webhook, most events are not worth a message:
+// only large price changes, repeated failures, an ownership mismatch and
+// external updates are posted.
+type Chat struct {
+	format ChatFormat
+	url    string
+	client *http.Client
+	// minPriceChange is the relative change of the gas price that is
+	// posted, 0.1 posts changes of more than 10%
+	minPriceChange float64
+	// failureThreshold is the number of consecutive failed epochs that is
+	// posted
+	failureThreshold uint64
+}
+
+// NewChat creates a Chat that posts to the incoming webhook url
+func NewChat(format ChatFormat, url string, minPriceChange float64, failureThreshold uint64) (*Chat, error) {
+	switch format {
+	case ChatFormatSlack, ChatFormatDiscord:
+	default:
+		return nil, fmt.Errorf("unknown chat format: %q", format)
+	}
+	if minPriceChange < 0 {
+		return nil, fmt.Errorf("minimum price change cannot be negative: %f", minPriceChange)
+	}
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &Chat{
+		format:           format,
+		url:              url,
+		client:           new(http.Client),
+		minPriceChange:   minPriceChange,
+		failureThreshold: failureThreshold,
+	}, nil
+}
+
+// Notify posts the event when it is significant
+func (c *Chat) Notify(ctx context.Context, event *Event) error {
+	text, ok := c.message(event)
+	if !ok {
+		return nil
+	}
+	var payload interface{}
+	switch c.format {
+	case ChatFormatDiscord:
+		payload = map[string]string{"content": text}
+	default:
+		payload = map[string]string{"text": text}
+	}
+	return PostJSON(ctx, c.client, c.url, payload)
+}
+
+// message formats the event, returning false when it is not significant
+func (c *Chat) message(event *Event) (string, bool) {
+	chain := "L2"
+	if event.ChainID != nil {
+		chain = fmt.Sprintf("chain %d", event.ChainID)
+	}
+	switch event.Type {
+	case EventUpdateSent:
+		change, ok := priceChange(event.CurrentPrice, event.GasPrice)
+		if !ok || math.Abs(change) <= c.minPriceChange {
+			return "", false
+		}
+		return fmt.Sprintf("Gas price on %s changed by %+.1f%% from %s to %s wei (tx %s)",
+			chain, change*100, event.CurrentPrice, event.GasPrice, event.TxHash), true
+	case EventUpdateFailed:
+		// Only the epoch that reaches the threshold is posted so that an
+		// outage does not flood the channel
+		if event.ConsecutiveFailures != c.failureThreshold {
+			return "", false
+		}
+		return fmt.Sprintf("Gas price oracle on %s failed to update for %d consecutive epochs: %s",
+			chain, event.ConsecutiveFailures, event.Error), true
+	case EventUpdateRefused:
+		return fmt.Sprintf("Gas price oracle on %s refused to send a gas price of %s wei: %s",
+			chain, event.GasPrice, event.Error), true
+	case EventApprovalRequired:
+		return fmt.Sprintf("Gas price update on %s from %s to %s wei waits for approval (id %s)",
+			chain, event.CurrentPrice, event.GasPrice, event.ApprovalID), true
+	case EventOwnerMismatch:
+		return fmt.Sprintf("Gas price oracle signer %s is not the owner %s of the GasPriceOracle on %s",
+			event.Signer, event.Owner, chain), true
+	case EventExternalUpdate:
+		return fmt.Sprintf("Gas price on %s was set to %s wei outside of the oracle (tx %s)",
+			chain, event.GasPrice, event.TxHash), true
+	case EventStaleUpdate:
+		return fmt.Sprintf("Gas price on %s has been stuck at %s wei for %d epochs while the demand changed",
+			chain, event.GasPrice, event.EpochsSinceUpdate), true
+	case EventImplementationChanged:
+		if event.Error != "" {
+			return fmt.Sprintf("GasPriceOracle on %s was upgraded from %s to %s, which is not compatible, updates are paused: %s",
+				chain, event.PreviousImplementation, event.Implementation, event.Error), true
+		}
+		return fmt.Sprintf("GasPriceOracle on %s was upgraded from %s to %s",
+			chain, event.PreviousImplementation, event.Implementation), true
+	default:
+		return "", false
+	}
+}
+
+// priceChange returns the change from current to next relative to current
+func priceChange(current, next *big.Int) (float64, bool) {
+	if current == nil || next == nil || current.Sign() == 0 {
+		return 0, false
+	}
+	diff := new(big.Rat).SetFrac(new(big.Int).Sub(next, current), current)
+	change, _ := diff.Float64()
+	return change, true
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChatMessage(t *testing.T) {
+	chat, err := NewChat(ChatFormatSlack, "", 0.1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		event   *Event
+		message string
+	}{
+		{
+			name: "small price change",
+			event: &Event{Type: EventUpdateSent, ChainID: big.NewInt(10),
+				CurrentPrice: big.NewInt(1000), GasPrice: big.NewInt(1100)},
+		},
+		{
+			name: "large price change",
+			event: &Event{Type: EventUpdateSent, ChainID: big.NewInt(10),
+				CurrentPrice: big.NewInt(1000), GasPrice: big.NewInt(800), TxHash: "0x01"},
+			message: "Gas price on chain 10 changed by -20.0% from 1000 to 800 wei (tx 0x01)",
+		},
+		{
+			name:  "failure below threshold",
+			event: &Event{Type: EventUpdateFailed, ConsecutiveFailures: 2, Error: "timeout"},
+		},
+		{
+			name:    "failure at threshold",
+			event:   &Event{Type: EventUpdateFailed, ConsecutiveFailures: 3, Error: "timeout"},
+			message: "Gas price oracle on L2 failed to update for 3 consecutive epochs: timeout",
+		},
+		{
+			name:  "failure above threshold",
+			event: &Event{Type: EventUpdateFailed, ConsecutiveFailures: 4, Error: "timeout"},
+		},
+		{
+			name:    "owner mismatch",
+			event:   &Event{Type: EventOwnerMismatch, ChainID: big.NewInt(10), Signer: "0xaa", Owner: "0xbb"},
+			message: "Gas price oracle signer 0xaa is not the owner 0xbb of the GasPriceOracle on chain 10",
+		},
+		{
+			name: "approval required",
+			event: &Event{Type: EventApprovalRequired, ChainID: big.NewInt(10), CurrentPrice: big.NewInt(100),
+				GasPrice: big.NewInt(120), ApprovalID: "0011223344556677"},
+			message: "Gas price update on chain 10 from 100 to 120 wei waits for approval (id 0011223344556677)",
+		},
+		{
+			name: "refused update",
+			event: &Event{Type: EventUpdateRefused, ChainID: big.NewInt(10), GasPrice: big.NewInt(0),
+				Error: "0 is below the sanity min gas price of 1000"},
+			message: "Gas price oracle on chain 10 refused to send a gas price of 0 wei: 0 is below the sanity min gas price of 1000",
+		},
+		{
+			name: "incompatible implementation",
+			event: &Event{Type: EventImplementationChanged, ChainID: big.NewInt(10),
+				PreviousImplementation: "0xaa", Implementation: "0xbb", Error: "no gasPrice()"},
+			message: "GasPriceOracle on chain 10 was upgraded from 0xaa to 0xbb, which is not compatible, updates are paused: no gasPrice()",
+		},
+		{
+			name:  "skipped update",
+			event: &Event{Type: EventUpdateSkipped},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, ok := chat.message(tt.event)
+			if ok != (tt.message != "") {
+				t.Fatalf("unexpected significance %t", ok)
+			}
+			if message != tt.message {
+				t.Fatalf("expected %q, got %q", tt.message, message)
+			}
+		})
+	}
+}
+
+func TestChatFormats(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer server.Close()
+
+	event := &Event{Type: EventOwnerMismatch, Signer: "0xaa", Owner: "0xbb"}
+	for format, key := range map[ChatFormat]string{ChatFormatSlack: "text", ChatFormatDiscord: "content"} {
+		chat, err := NewChat(format, server.URL, 0.1, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := chat.Notify(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(payload[key], "is not the owner") {
+			t.Fatalf("%s: unexpected payload %v", format, payload)
+		}
+	}
+
+	if _, err := NewChat("irc", server.URL, 0.1, 3); err == nil {
+		t.Fatal("expected error for an unknown format")
+	}
+}
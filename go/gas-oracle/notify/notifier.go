@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/queue"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// EventType is the kind of event that a notification is sent for
+type EventType string
+
+const (
+	// EventUpdateSent means that a gas price update transaction was sent
+	EventUpdateSent EventType = "update_sent"
+	// EventUpdateFailed means that the gas price could not be updated
+	EventUpdateFailed EventType = "update_failed"
+	// EventUpdateSkipped means that an update was held back by the rate
+	// limit or the maximum gas price
+	EventUpdateSkipped EventType = "update_skipped"
+	// EventUpdateRefused means that an update was outside of the sanity
+	// bounds and was not sent, Error describes the violated bound
+	EventUpdateRefused EventType = "update_refused"
+	// EventOwnerMismatch means that the signer is not the owner of the
+	// GasPriceOracle and cannot update the gas price
+	EventOwnerMismatch EventType = "owner_mismatch"
+	// EventUpdateRecovered means that an epoch succeeded after failures
+	EventUpdateRecovered EventType = "update_recovered"
+	// EventLowBalance means that the balance of the signer fell below the
+	// threshold
+	EventLowBalance EventType = "low_balance"
+	// EventBalanceRecovered means that the balance of the signer is back
+	// above the threshold
+	EventBalanceRecovered EventType = "balance_recovered"
+	// EventExternalUpdate means that the gas price was set by a transaction
+	// that the oracle did not send
+	EventExternalUpdate EventType = "external_update"
+	// EventStaleUpdate means that no update has landed on chain for a
+	// number of epochs while the demand changed
+	EventStaleUpdate EventType = "stale_update"
+	// EventStaleUpdateRecovered means that an update landed after the gas
+	// price was stale
+	EventStaleUpdateRecovered EventType = "stale_update_recovered"
+	// EventImplementationChanged means that the proxy of the GasPriceOracle
+	// was upgraded to another implementation. Error is set when the new
+	// implementation is not compatible and the updates were paused.
+	EventImplementationChanged EventType = "implementation_changed"
+	// EventApprovalRequired means that an update was staged and waits for
+	// the approval of a second operator, ApprovalID identifies it
+	EventApprovalRequired EventType = "approval_required"
+)
+
+// Event is the payload of a notification
+type Event struct {
+	Type         EventType `json:"type"`
+	Time         time.Time `json:"time"`
+	ChainID      *big.Int  `json:"chainId,omitempty"`
+	CurrentPrice *big.Int  `json:"currentPrice,omitempty"`
+	GasPrice     *big.Int  `json:"gasPrice,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Bound        string    `json:"bound,omitempty"`
+	TxHash       string    `json:"txHash,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	// ConsecutiveFailures is the number of epochs in a row that failed,
+	// including the epoch of a failed update
+	ConsecutiveFailures uint64 `json:"consecutiveFailures,omitempty"`
+	Signer              string `json:"signer,omitempty"`
+	Owner               string `json:"owner,omitempty"`
+	// Balance is the balance of the signer and Threshold is the balance
+	// below which it is low
+	Balance   *big.Int `json:"balance,omitempty"`
+	Threshold *big.Int `json:"threshold,omitempty"`
+	// EpochsSinceUpdate is the number of epochs since an update landed on
+	// chain at LastUpdateBlock
+	EpochsSinceUpdate uint64 `json:"epochsSinceUpdate,omitempty"`
+	LastUpdateBlock   uint64 `json:"lastUpdateBlock,omitempty"`
+	// Implementation is the implementation of the proxy of the
+	// GasPriceOracle that replaced PreviousImplementation
+	Implementation         string `json:"implementation,omitempty"`
+	PreviousImplementation string `json:"previousImplementation,omitempty"`
+	ApprovalID             string `json:"approvalId,omitempty"`
+}
+
+// Notifier delivers events to an external system
+type Notifier interface {
+	Notify(ctx context.Context, event *Event) error
+}
+
+// queueSize is the number of events that can wait for delivery before new
+// events are dropped
+const queueSize = 64
+
+// Dispatcher delivers events to notifiers in the background so that a slow
+// receiver does not delay the oracle
+type Dispatcher struct {
+	notifiers []Notifier
+	queue     *queue.Queue
+}
+
+// NewDispatcher creates a Dispatcher and starts delivering events to the
+// notifiers
+func NewDispatcher(notifiers ...Notifier) *Dispatcher {
+	d := &Dispatcher{notifiers: notifiers}
+	d.queue = queue.New(queueSize, func(item interface{}) { d.deliver(item.(*Event)) })
+	return d
+}
+
+// Notify queues an event for delivery. The event is dropped when the queue
+// is full. A nil Dispatcher drops all events.
+func (d *Dispatcher) Notify(event *Event) {
+	if d == nil {
+		return
+	}
+	if !d.queue.Push(event) {
+		log.Warn("Dropping notification", "type", event.Type)
+	}
+}
+
+// Close stops accepting events and waits for the queued events to be
+// delivered
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	d.queue.Close()
+}
+
+func (d *Dispatcher) deliver(event *Event) {
+	for _, n := range d.notifiers {
+		if err := n.Notify(context.Background(), event); err != nil {
+			log.Error("cannot deliver notification", "type", event.Type, "message", err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type chanNotifier chan *Event
+
+func (c chanNotifier) Notify(ctx context.Context, event *Event) error {
+	c <- event
+	return nil
+}
+
+func TestDispatcher(t *testing.T) {
+	events := make(chanNotifier, 1)
+	d := NewDispatcher(events)
+	d.Notify(&Event{Type: EventUpdateSent})
+
+	select {
+	case event := <-events:
+		if event.Type != EventUpdateSent {
+			t.Fatalf("unexpected event %s", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	// A nil dispatcher drops events
+	var nilDispatcher *Dispatcher
+	nilDispatcher.Notify(&Event{Type: EventUpdateSent})
+}
+
+func TestWebhook(t *testing.T) {
+	// The webhook posts to each url before Notify returns
+	received := make(chan *Event, 2)
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %s", r.Header.Get("Content-Type"))
+		}
+		event := new(Event)
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			t.Error(err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- event
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	webhook := NewWebhook([]string{failing.URL, ok.URL})
+	err := webhook.Notify(context.Background(), &Event{
+		Type:     EventUpdateSkipped,
+		GasPrice: big.NewInt(100),
+		Reason:   "RATE_LIMITED",
+	})
+	// The failure of one url does not stop delivery to the others
+	if err == nil {
+		t.Fatal("expected an error for the failing url")
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(received))
+	}
+	event := <-received
+	if event.Type != EventUpdateSkipped || event.GasPrice.Uint64() != 100 ||
+		event.Reason != "RATE_LIMITED" {
+		t.Fatal("unexpected event payload")
+	}
+}
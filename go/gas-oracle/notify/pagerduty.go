@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// PagerDuty triggers incidents with the PagerDuty Events v2 API for
+// conditions that stop the gas price from being updated, and resolves them
+// once the condition clears
+type PagerDuty struct {
+	url        string
+	routingKey string
+	client     *http.Client
+	// failureThreshold is the number of consecutive failed epochs that
+	// triggers an incident
+	failureThreshold uint64
+}
+
+// NewPagerDuty creates a PagerDuty that sends events to the url with the
+// routing key of an Events v2 integration
+func NewPagerDuty(url, routingKey string, failureThreshold uint64) *PagerDuty {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &PagerDuty{
+		url:              url,
+		routingKey:       routingKey,
+		client:           new(http.Client),
+		failureThreshold: failureThreshold,
+	}
+}
+
+// pagerDutyEvent is the body of an Events v2 request
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string    `json:"summary"`
+	Source        string    `json:"source"`
+	Severity      string    `json:"severity"`
+	Timestamp     time.Time `json:"timestamp"`
+	Component     string    `json:"component"`
+	CustomDetails *Event    `json:"custom_details"`
+}
+
+// Notify triggers or resolves the incident of the event, other events are
+// ignored
+func (p *PagerDuty) Notify(ctx context.Context, event *Event) error {
+	body, ok := p.event(event)
+	if !ok {
+		return nil
+	}
+	return PostJSON(ctx, p.client, p.url, body)
+}
+
+// event returns the Events v2 request for the event, returning false when
+// the event does not trigger or resolve an incident
+func (p *PagerDuty) event(event *Event) (*pagerDutyEvent, bool) {
+	chain, chainID := "L2", "l2"
+	if event.ChainID != nil {
+		chain, chainID = fmt.Sprintf("chain %d", event.ChainID), event.ChainID.String()
+	}
+
+	var condition, summary string
+	action := "trigger"
+	switch event.Type {
+	case EventUpdateFailed:
+		if event.ConsecutiveFailures != p.failureThreshold {
+			return nil, false
+		}
+		condition = "update_failed"
+		summary = fmt.Sprintf("Gas price oracle on %s failed to update for %d consecutive epochs: %s",
+			chain, event.ConsecutiveFailures, event.Error)
+	case EventUpdateRecovered:
+		condition, action = "update_failed", "resolve"
+	case EventUpdateRefused:
+		condition = "update_refused"
+		summary = fmt.Sprintf("Gas price oracle on %s refused to send a gas price of %s wei: %s",
+			chain, event.GasPrice, event.Error)
+	case EventOwnerMismatch:
+		condition = "owner_mismatch"
+		summary = fmt.Sprintf("Gas price oracle signer %s is not the owner %s of the GasPriceOracle on %s",
+			event.Signer, event.Owner, chain)
+	case EventLowBalance:
+		condition = "low_balance"
+		summary = fmt.Sprintf("Gas price oracle signer %s on %s has a balance of %s wei, below %s wei",
+			event.Signer, chain, event.Balance, event.Threshold)
+	case EventBalanceRecovered:
+		condition, action = "low_balance", "resolve"
+	case EventStaleUpdate:
+		condition = "stale_update"
+		summary = fmt.Sprintf("Gas price on %s has been stuck at %s wei for %d epochs while the demand changed",
+			chain, event.GasPrice, event.EpochsSinceUpdate)
+	case EventStaleUpdateRecovered:
+		condition, action = "stale_update", "resolve"
+	case EventImplementationChanged:
+		// A compatible upgrade does not need anyone to act on it
+		if event.Error == "" {
+			return nil, false
+		}
+		condition = "implementation_changed"
+		summary = fmt.Sprintf("GasPriceOracle on %s was upgraded from %s to %s, which is not compatible, updates are paused: %s",
+			chain, event.PreviousImplementation, event.Implementation, event.Error)
+	default:
+		return nil, false
+	}
+
+	// Incidents are deduplicated per chain and condition so that a
+	// resolve closes the incident that the same condition triggered
+	body := &pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: action,
+		DedupKey:    fmt.Sprintf("gas-oracle/%s/%s", chainID, condition),
+	}
+	if action == "trigger" {
+		body.Payload = &pagerDutyPayload{
+			Summary:       summary,
+			Source:        "gas-oracle",
+			Severity:      "critical",
+			Timestamp:     event.Time,
+			Component:     condition,
+			CustomDetails: event,
+		}
+	}
+	return body, true
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPagerDuty(t *testing.T) {
+	var received []*pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := new(pagerDutyEvent)
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pd := NewPagerDuty(server.URL, "key", 3)
+	chainID := big.NewInt(10)
+	events := []*Event{
+		{Type: EventUpdateFailed, ChainID: chainID, ConsecutiveFailures: 2},
+		{Type: EventUpdateFailed, ChainID: chainID, ConsecutiveFailures: 3, Error: "timeout"},
+		{Type: EventUpdateFailed, ChainID: chainID, ConsecutiveFailures: 4},
+		{Type: EventUpdateSent, ChainID: chainID},
+		{Type: EventUpdateRecovered, ChainID: chainID},
+		{Type: EventLowBalance, ChainID: chainID, Balance: big.NewInt(1), Threshold: big.NewInt(2)},
+	}
+	for _, event := range events {
+		if err := pd.Notify(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(received) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(received))
+	}
+	trigger, resolve, balance := received[0], received[1], received[2]
+	if trigger.RoutingKey != "key" || trigger.EventAction != "trigger" {
+		t.Fatalf("unexpected trigger %+v", trigger)
+	}
+	if trigger.Payload.Severity != "critical" || trigger.Payload.CustomDetails.Error != "timeout" {
+		t.Fatalf("unexpected payload %+v", trigger.Payload)
+	}
+	// The recovery resolves the incident of the failures
+	if resolve.EventAction != "resolve" || resolve.DedupKey != trigger.DedupKey || resolve.Payload != nil {
+		t.Fatalf("unexpected resolve %+v", resolve)
+	}
+	if balance.DedupKey != "gas-oracle/10/low_balance" {
+		t.Fatalf("unexpected dedup key %s", balance.DedupKey)
+	}
+}
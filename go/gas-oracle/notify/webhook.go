@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds the time spent delivering a single webhook so that
+// a slow receiver cannot stall the oracle
+const webhookTimeout = 10 * time.Second
+
+// Webhook POSTs each event as JSON to a set of URLs
+type Webhook struct {
+	urls   []string
+	client *http.Client
+}
+
+// NewWebhook creates a Webhook that notifies the urls
+func NewWebhook(urls []string) *Webhook {
+	return &Webhook{
+		urls:   urls,
+		client: new(http.Client),
+	}
+}
+
+// Notify POSTs the event to every url, an error for one url does not stop
+// the event from being delivered to the others
+func (w *Webhook) Notify(ctx context.Context, event *Event) error {
+	var failed error
+	for _, url := range w.urls {
+		if err := PostJSON(ctx, w.client, url, event); err != nil {
+			failed = fmt.Errorf("%s: %w", url, err)
+		}
+	}
+	return failed
+}
+
+// PostJSON POSTs the payload encoded as JSON to the url
+func PostJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// adminRequestTimeout bounds the RPC calls made for a request to the admin
+// API
+const adminRequestTimeout = 10 * time.Second
+
+// decisionStreamKeepAlive is how often a comment is sent on an idle decision
+// stream, so that proxies do not close it between epochs
+const decisionStreamKeepAlive = 15 * time.Second
+
+// adminHandler serves the admin API of a running oracle. Every request must
+// carry the token or a JWT of the configuration as a bearer token.
+//
+//	GET  /state             the runtime state
+//	GET  /decisions         a stream of server-sent events of the epoch decisions
+//	GET  /history           the recorded gas prices, ?from=&to=&resolution=
+//	GET  /decision-history  the recorded decisions, ?from=&to=&format=csv
+//	POST /pause             stop sending transactions
+//	POST /resume            send transactions again
+//	POST /floor             set the floor price, {"floorPrice": "1gwei"}
+//	POST /target            set the target gas per second, {"targetGasPerSecond": 11000000}
+//	POST /force             close the current epoch and send its gas price now
+//	GET  /approvals         the update that waits for approval
+//	POST /approvals/approve send the staged update, {"id": "..."}
+//	POST /approvals/reject  drop the staged update, {"id": "..."}
+//	POST /                  the JSON-RPC methods of the gasoracle namespace
+//
+// In dual control the staged update is approved or rejected with the
+// approval token only, which is also accepted to read it.
+type adminHandler struct {
+	gpo  *GasPriceOracle
+	auth *adminAuth
+	// approver is nil when the updates are sent without approval
+	approver *adminAuth
+	mux      *http.ServeMux
+}
+
+func newAdminHandler(gpo *GasPriceOracle, auth *adminAuth) (*adminHandler, error) {
+	rpcServer, err := newAdminRPCServer(gpo)
+	if err != nil {
+		return nil, err
+	}
+	h := &adminHandler{gpo: gpo, auth: auth, mux: http.NewServeMux()}
+	if gpo.config.approvalToken != "" {
+		h.approver = &adminAuth{token: gpo.config.approvalToken, now: time.Now}
+	}
+	h.mux.Handle("/", rpcServer)
+	h.mux.HandleFunc("/state", h.state)
+	h.mux.HandleFunc("/decisions", h.decisions)
+	h.mux.HandleFunc("/history", h.priceHistory)
+	h.mux.HandleFunc("/decision-history", h.decisionHistory)
+	h.mux.HandleFunc("/pause", h.post(func(*http.Request) error {
+		gpo.Pause()
+		return nil
+	}))
+	h.mux.HandleFunc("/resume", h.post(func(*http.Request) error {
+		gpo.Resume()
+		return nil
+	}))
+	h.mux.HandleFunc("/floor", h.post(h.setFloor))
+	h.mux.HandleFunc("/target", h.post(h.setTarget))
+	h.mux.HandleFunc("/force", h.post(func(r *http.Request) error {
+		_, err := gpo.ForceUpdate(r.Context())
+		return err
+	}))
+	h.mux.HandleFunc("/approvals", h.stagedUpdate)
+	h.mux.HandleFunc("/approvals/approve", h.post(func(r *http.Request) error {
+		id, err := approvalID(r)
+		if err != nil {
+			return err
+		}
+		return gpo.ApproveUpdate(r.Context(), id)
+	}))
+	h.mux.HandleFunc("/approvals/reject", h.post(func(r *http.Request) error {
+		id, err := approvalID(r)
+		if err != nil {
+			return err
+		}
+		return gpo.RejectUpdate(id)
+	}))
+	return h, nil
+}
+
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.authorize(r); err != nil {
+		log.Warn("Unauthorized admin request", "path", r.URL.Path, "remote", r.RemoteAddr, "message", err)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeAdminError(w, http.StatusUnauthorized, err)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorize checks the credential of the request, the approvals are decided
+// by the approver rather than by the operator
+func (h *adminHandler) authorize(r *http.Request) error {
+	header := r.Header.Get("Authorization")
+	if h.approver == nil || !isApprovalPath(r.URL.Path) {
+		return h.auth.authorize(header)
+	}
+	if err := h.approver.authorize(header); err == nil || r.URL.Path != "/approvals" {
+		return err
+	}
+	return h.auth.authorize(header)
+}
+
+// isApprovalPath returns true for the paths that accept the approval token.
+// The paths must match exactly, any other path falls through to the
+// JSON-RPC server of the operator.
+func isApprovalPath(path string) bool {
+	switch path {
+	case "/approvals", "/approvals/approve", "/approvals/reject":
+		return true
+	}
+	return false
+}
+
+func (h *adminHandler) state(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	h.writeState(w, r)
+}
+
+func (h *adminHandler) writeState(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), adminRequestTimeout)
+	defer cancel()
+	state, err := h.gpo.State(ctx)
+	if err != nil {
+		writeAdminError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, state)
+}
+
+// decisions streams the decision of every epoch as a server-sent event until
+// the client disconnects
+func (h *adminHandler) decisions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAdminError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+	decisions, unsubscribe := h.gpo.SubscribeDecisions()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	log.Info("Streaming decisions", "remote", r.RemoteAddr)
+
+	keepAlive := time.NewTicker(decisionStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case d, ok := <-decisions:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(d)
+			if err != nil {
+				log.Warn("cannot encode decision", "message", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: decision\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// post returns a handler of a control action that responds with the state
+// once the action is done
+func (h *adminHandler) post(action func(*http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		if err := action(r); err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+		log.Info("Admin action", "path", r.URL.Path, "remote", r.RemoteAddr)
+		h.writeState(w, r)
+	}
+}
+
+func (h *adminHandler) stagedUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	staged, err := h.gpo.StagedUpdate()
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, struct {
+		Staged *StagedUpdate `json:"staged"`
+	}{staged})
+}
+
+// approvalID reads the id of the staged update that is approved or rejected
+func approvalID(r *http.Request) (string, error) {
+	var body struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.ID == "" {
+		return "", errors.New("no id of the staged update")
+	}
+	return body.ID, nil
+}
+
+func (h *adminHandler) setFloor(r *http.Request) error {
+	var body struct {
+		FloorPrice string `json:"floorPrice"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return err
+	}
+	floorPrice, err := gasprices.ParseWei(body.FloorPrice)
+	if err != nil {
+		return err
+	}
+	return h.gpo.SetFloorPrice(floorPrice)
+}
+
+func (h *adminHandler) setTarget(r *http.Request) error {
+	var body struct {
+		TargetGasPerSecond uint64 `json:"targetGasPerSecond"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return err
+	}
+	return h.gpo.SetTargetGasPerSecond(body.TargetGasPerSecond)
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warn("cannot write admin response", "message", err)
+	}
+}
+
+func writeAdminError(w http.ResponseWriter, status int, err error) {
+	writeAdminJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// startAdminServer serves the admin API at the configured address. The
+// address is bound before returning so that a busy port fails the startup.
+func (g *GasPriceOracle) startAdminServer() error {
+	handler, err := newAdminHandler(g, newAdminAuth(g.config))
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", g.config.adminAddr)
+	if err != nil {
+		return fmt.Errorf("cannot start admin server: %w", err)
+	}
+	server := &http.Server{Handler: handler}
+	log.Info("Starting admin server", "addr", listener.Addr().String())
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("Failure in running admin server", "message", err)
+		}
+	}()
+	return nil
+}
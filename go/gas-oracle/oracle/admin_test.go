@@ -0,0 +1,240 @@
+package oracle
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newControlledOracle returns an oracle of a deployed gas price oracle that
+// can serve the admin APIs
+func newControlledOracle(t *testing.T) (*GasPriceOracle, *gasprices.GasPricer, *backends.SimulatedBackend) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:                   key,
+		l2ChainID:                    big.NewInt(1337),
+		gasPriceOracleAddress:        addr,
+		gasPrice:                     big.NewInt(2_000_000_000),
+		floorPrice:                   big.NewInt(1),
+		maxGasPrice:                  big.NewInt(1000),
+		targetGasPerSecond:           11_000_000,
+		l2GasPriceSignificanceFactor: 0.05,
+		enableL2GasPrice:             true,
+	}
+	cfg.controls = newControls(cfg)
+	updateL2GasPrice, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := func() float64 { return float64(cfg.targetGasPerSecond) }
+	pricer, err := gasprices.NewGasPricer(big.NewInt(10), cfg.floorPrice, target, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop := func() (uint64, error) { return 0, nil }
+	updater, err := gasprices.NewGasPriceUpdater(pricer, 0, 11_000_000, time.Second, noop,
+		func(*big.Int) (uint64, error) { return 0, nil }, updateL2GasPrice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract, err := bindings.NewGasPriceOracle(addr, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpo := &GasPriceOracle{
+		ctx:              context.Background(),
+		contract:         contract,
+		config:           cfg,
+		l2Backend:        sim,
+		gasPriceUpdater:  updater,
+		pricer:           pricer,
+		epoch:            new(epochState),
+		updateL2GasPrice: updateL2GasPrice,
+		force:            make(chan chan error),
+		handoff:          make(chan time.Time, 1),
+		stop:             make(chan struct{}),
+	}
+	return gpo, pricer, sim
+}
+
+func TestAdminHandler(t *testing.T) {
+	gpo, pricer, _ := newControlledOracle(t)
+	cfg := gpo.config
+	handler, err := newAdminHandler(gpo, &adminAuth{token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	do := func(method, path, token, body string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, out
+	}
+
+	if status, _ := do(http.MethodGet, "/state", "", ""); status != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized without a token, got %d", status)
+	}
+	if status, _ := do(http.MethodGet, "/state", "wrong", ""); status != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized with a wrong token, got %d", status)
+	}
+	status, state := do(http.MethodGet, "/state", "secret", "")
+	if status != http.StatusOK || state["paused"] != false || state["localGasPrice"] != float64(10) {
+		t.Fatalf("unexpected state %d %v", status, state)
+	}
+	if status, _ := do(http.MethodPost, "/state", "secret", ""); status != http.StatusMethodNotAllowed {
+		t.Fatalf("expected method not allowed, got %d", status)
+	}
+
+	if _, state := do(http.MethodPost, "/pause", "secret", ""); state["paused"] != true {
+		t.Fatalf("expected paused, got %v", state)
+	}
+	if !cfg.controls.isPaused() {
+		t.Fatal("expected the controls to be paused")
+	}
+	if _, state := do(http.MethodPost, "/resume", "secret", ""); state["paused"] != false {
+		t.Fatalf("expected resumed, got %v", state)
+	}
+
+	if status, _ := do(http.MethodPost, "/floor", "secret", `{"floorPrice": "2000"}`); status != http.StatusBadRequest {
+		t.Fatalf("expected a floor above the max gas price to be rejected, got %d", status)
+	}
+	if status, _ := do(http.MethodPost, "/target", "secret", `{"targetGasPerSecond": 0}`); status != http.StatusBadRequest {
+		t.Fatalf("expected a zero target to be rejected, got %d", status)
+	}
+	status, state = do(http.MethodPost, "/floor", "secret", `{"floorPrice": "100"}`)
+	if status != http.StatusOK || state["floorPrice"] != float64(100) {
+		t.Fatalf("unexpected state %d %v", status, state)
+	}
+	status, state = do(http.MethodPost, "/target", "secret", `{"targetGasPerSecond": 5000000}`)
+	if status != http.StatusOK || state["targetGasPerSecond"] != float64(5_000_000) {
+		t.Fatalf("unexpected state %d %v", status, state)
+	}
+
+	// The changes are applied by the update loop
+	if cfg.floorPrice.Uint64() != 1 || cfg.targetGasPerSecond != 11_000_000 {
+		t.Fatal("expected the changes to be applied at the next epoch")
+	}
+	if err := cfg.controls.apply(cfg, pricer); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.floorPrice.Uint64() != 100 || cfg.targetGasPerSecond != 5_000_000 {
+		t.Fatalf("unexpected config after apply, floor %d target %d", cfg.floorPrice, cfg.targetGasPerSecond)
+	}
+	if pricer.GetGasPrice().Uint64() != 100 {
+		t.Fatalf("expected the pricer to be raised to the floor, got %d", pricer.GetGasPrice())
+	}
+}
+
+func TestAdminApproverPaths(t *testing.T) {
+	gpo, _, _ := newControlledOracle(t)
+	gpo.config.approvalToken = "approver"
+	gpo.config.approvals = newApprovals(gpo.config)
+	handler, err := newAdminHandler(gpo, &adminAuth{token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// The paths that are not the approvals reach the JSON-RPC server, which
+	// only accepts the admin token
+	for _, path := range []string{"/", "/approvals/x", "/approvalsX"} {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+path,
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"gasoracle_pause"}`))
+		req.Header.Set("Authorization", "Bearer approver")
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected the approval token to be refused on %s, got %d", path, resp.StatusCode)
+		}
+	}
+	if gpo.config.controls.isPaused() {
+		t.Fatal("expected the approval token not to pause the oracle")
+	}
+}
+
+func TestAdminDecisionStream(t *testing.T) {
+	gpo, _, _ := newControlledOracle(t)
+	handler, err := newAdminHandler(gpo, &adminAuth{token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/decisions", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected response %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// The headers are sent once the stream is subscribed
+	gpo.config.controls.observeDecision(&Decision{GasPrice: big.NewInt(42), Send: true, Reason: ReasonUpdated})
+	reader := bufio.NewReader(resp.Body)
+	event, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event != "event: decision\n" {
+		t.Fatalf("unexpected event %q", event)
+	}
+	data, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decision Decision
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &decision); err != nil {
+		t.Fatal(err)
+	}
+	if decision.GasPrice.Uint64() != 42 || decision.Reason != ReasonUpdated {
+		t.Fatalf("unexpected decision %+v", decision)
+	}
+}
@@ -0,0 +1,213 @@
+package oracle
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	approvalStagedCounter   = metrics.NewRegisteredCounter("approval/staged", ometrics.DefaultRegistry)
+	approvalApprovedCounter = metrics.NewRegisteredCounter("approval/approved", ometrics.DefaultRegistry)
+	approvalRejectedCounter = metrics.NewRegisteredCounter("approval/rejected", ometrics.DefaultRegistry)
+	approvalExpiredCounter  = metrics.NewRegisteredCounter("approval/expired", ometrics.DefaultRegistry)
+)
+
+var (
+	// errApprovalsDisabled represents the error when an update is approved
+	// without the dual control
+	errApprovalsDisabled = errors.New("the updates are not staged for approval")
+	// errNoStagedUpdate represents the error when the approved update is not
+	// staged, because it expired or it was replaced by another one
+	errNoStagedUpdate = errors.New("no staged update")
+)
+
+// StagedUpdate is an L2 gas price that waits for the approval of a second
+// operator before it is sent
+type StagedUpdate struct {
+	// ID identifies the update that is approved, so that an update that
+	// replaced the reviewed one is not approved by mistake
+	ID        string    `json:"id"`
+	GasPrice  *big.Int  `json:"gasPrice"`
+	Decision  *Decision `json:"decision"`
+	StagedAt  time.Time `json:"stagedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// approvals holds the update that waits for approval in dual control, where
+// the oracle decides the updates and another operator with a separate
+// credential approves each of them before it is sent
+type approvals struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu     sync.Mutex
+	staged *StagedUpdate
+	// send sends the staged update, it must be called from the update loop
+	send func(context.Context) error
+}
+
+// newApprovals returns nil when the updates are sent without approval
+func newApprovals(cfg *Config) *approvals {
+	if cfg.approvalToken == "" {
+		return nil
+	}
+	log.Info("Staging the updates for approval", "ttl", cfg.approvalTTL)
+	return &approvals{ttl: cfg.approvalTTL, now: time.Now}
+}
+
+// stage holds the update until it is approved. The staged update is kept
+// when the decision stages the same gas price again, otherwise it is
+// replaced. It returns true when the update was newly staged.
+func (a *approvals) stage(d *Decision, send func(context.Context) error) (*StagedUpdate, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+	if a.staged != nil && a.staged.GasPrice.Cmp(d.GasPrice) == 0 {
+		return a.staged, false
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		panic(err)
+	}
+	now := a.now()
+	a.staged = &StagedUpdate{
+		ID:        hex.EncodeToString(id),
+		GasPrice:  new(big.Int).Set(d.GasPrice),
+		Decision:  d,
+		StagedAt:  now,
+		ExpiresAt: now.Add(a.ttl),
+	}
+	a.send = send
+	approvalStagedCounter.Inc(1)
+	log.Info("Staged update for approval", "id", a.staged.ID, "gas-price", d.GasPrice,
+		"expires", a.staged.ExpiresAt)
+	return a.staged, true
+}
+
+// current returns the staged update, nil when there is none
+func (a *approvals) current() *StagedUpdate {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+	return a.staged
+}
+
+// take removes the staged update with the id and returns the function that
+// sends it
+func (a *approvals) take(id string) (func(context.Context) error, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+	if a.staged == nil || a.staged.ID != id {
+		return nil, fmt.Errorf("%w with id %q", errNoStagedUpdate, id)
+	}
+	send := a.send
+	a.staged, a.send = nil, nil
+	return send, nil
+}
+
+// reject drops the staged update with the id
+func (a *approvals) reject(id string) error {
+	if _, err := a.take(id); err != nil {
+		return err
+	}
+	approvalRejectedCounter.Inc(1)
+	log.Warn("Rejected staged update", "id", id)
+	return nil
+}
+
+// expire drops the staged update once its ttl passed, it must be called
+// with the lock held
+func (a *approvals) expire() {
+	if a.staged == nil || a.now().Before(a.staged.ExpiresAt) {
+		return
+	}
+	approvalExpiredCounter.Inc(1)
+	log.Warn("Staged update expired without approval", "id", a.staged.ID, "gas-price", a.staged.GasPrice)
+	a.staged, a.send = nil, nil
+}
+
+// stageUpdate stages the decided update and notifies that it waits for
+// approval
+func stageUpdate(cfg *Config, d *Decision, send func(context.Context) error) {
+	staged, created := cfg.approvals.stage(d, send)
+	if !created {
+		return
+	}
+	event := d.Event(notify.EventApprovalRequired, cfg.l2ChainID)
+	event.ApprovalID = staged.ID
+	cfg.notifier.Notify(event)
+}
+
+// approvalRequest is an approval that the update loop sends
+type approvalRequest struct {
+	id    string
+	reply chan error
+}
+
+// StagedUpdate returns the update that waits for approval, nil when there is
+// none
+func (g *GasPriceOracle) StagedUpdate() (*StagedUpdate, error) {
+	if g.config.approvals == nil {
+		return nil, errApprovalsDisabled
+	}
+	return g.config.approvals.current(), nil
+}
+
+// ApproveUpdate sends the staged update with the id from the update loop and
+// returns once it was sent
+func (g *GasPriceOracle) ApproveUpdate(ctx context.Context, id string) error {
+	if g.config.approvals == nil {
+		return errApprovalsDisabled
+	}
+	req := approvalRequest{id: id, reply: make(chan error, 1)}
+	select {
+	case g.approve <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RejectUpdate drops the staged update with the id
+func (g *GasPriceOracle) RejectUpdate(id string) error {
+	if g.config.approvals == nil {
+		return errApprovalsDisabled
+	}
+	return g.config.approvals.reject(id)
+}
+
+// approveUpdate sends the approved update, it runs in the update loop. The
+// update stays staged while the updates are paused or the replica is on
+// standby.
+func (g *GasPriceOracle) approveUpdate(id string) error {
+	if g.config.controls.isPaused() {
+		return errors.New("the updates are paused")
+	}
+	if !g.config.leader.isLeader() {
+		return errors.New("the replica is on standby")
+	}
+	send, err := g.config.approvals.take(id)
+	if err != nil {
+		return err
+	}
+	approvalApprovedCounter.Inc(1)
+	log.Info("Sending approved update", "id", id)
+	return send(g.ctx)
+}
@@ -0,0 +1,139 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+func TestApprovals(t *testing.T) {
+	gpo, _, sim := newControlledOracle(t)
+	cfg := gpo.config
+	cfg.approvalToken = "approver"
+	cfg.approvalTTL = time.Minute
+	cfg.approvals = newApprovals(cfg)
+	gpo.approve = make(chan approvalRequest)
+	go func() {
+		for req := range gpo.approve {
+			req.reply <- gpo.approveUpdate(req.id)
+		}
+	}()
+	defer close(gpo.approve)
+
+	gasPrice := func() uint64 {
+		price, err := gpo.contract.GasPrice(&bind.CallOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return price.Uint64()
+	}
+	before := gasPrice()
+
+	// The update is staged rather than sent, and staging the same gas price
+	// again keeps the reviewed update
+	if err := gpo.updateL2GasPrice(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	staged, _ := gpo.StagedUpdate()
+	if staged == nil || staged.GasPrice.Uint64() != 100 {
+		t.Fatalf("expected a staged update, got %+v", staged)
+	}
+	if err := gpo.updateL2GasPrice(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := gpo.StagedUpdate(); again.ID != staged.ID {
+		t.Fatal("expected the staged update to be kept")
+	}
+	sim.Commit()
+	if gasPrice() != before {
+		t.Fatal("expected the staged update not to be sent")
+	}
+
+	handler, err := newAdminHandler(gpo, &adminAuth{token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	do := func(method, path, token, body string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	// Both operators see the staged update, only the approver approves it
+	for _, token := range []string{"secret", "approver"} {
+		status, out := do(http.MethodGet, "/approvals", token, "")
+		got, _ := out["staged"].(map[string]interface{})
+		if status != http.StatusOK || got["id"] != staged.ID {
+			t.Fatalf("unexpected approvals %d %v", status, out)
+		}
+	}
+	body := `{"id": "` + staged.ID + `"}`
+	if status, _ := do(http.MethodPost, "/approvals/approve", "secret", body); status != http.StatusUnauthorized {
+		t.Fatalf("expected the admin token not to approve, got %d", status)
+	}
+	if status, _ := do(http.MethodPost, "/pause", "approver", ""); status != http.StatusUnauthorized {
+		t.Fatalf("expected the approver token not to pause, got %d", status)
+	}
+	if status, _ := do(http.MethodPost, "/approvals/approve", "approver", `{"id": "other"}`); status != http.StatusBadRequest {
+		t.Fatalf("expected another id to be rejected, got %d", status)
+	}
+	if status, out := do(http.MethodPost, "/approvals/approve", "approver", body); status != http.StatusOK {
+		t.Fatalf("unexpected approval %d %v", status, out)
+	}
+	sim.Commit()
+	if gasPrice() != 100 {
+		t.Fatalf("expected the approved update to be sent, got %d", gasPrice())
+	}
+	if staged, _ := gpo.StagedUpdate(); staged != nil {
+		t.Fatal("expected the approved update to be removed")
+	}
+
+	// A rejected update is not sent
+	if err := gpo.updateL2GasPrice(big.NewInt(200)); err != nil {
+		t.Fatal(err)
+	}
+	staged, _ = gpo.StagedUpdate()
+	if status, _ := do(http.MethodPost, "/approvals/reject", "approver", `{"id": "`+staged.ID+`"}`); status != http.StatusOK {
+		t.Fatalf("unexpected rejection %d", status)
+	}
+	if err := gpo.ApproveUpdate(context.Background(), staged.ID); !errors.Is(err, errNoStagedUpdate) {
+		t.Fatalf("expected the rejected update not to be approved, got %v", err)
+	}
+
+	// An update that was not approved in time expires
+	if err := gpo.updateL2GasPrice(big.NewInt(300)); err != nil {
+		t.Fatal(err)
+	}
+	staged, _ = gpo.StagedUpdate()
+	cfg.approvals.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if current, _ := gpo.StagedUpdate(); current != nil {
+		t.Fatal("expected the staged update to expire")
+	}
+	if err := gpo.ApproveUpdate(context.Background(), staged.ID); !errors.Is(err, errNoStagedUpdate) {
+		t.Fatalf("expected the expired update not to be approved, got %v", err)
+	}
+	sim.Commit()
+	if gasPrice() != 100 {
+		t.Fatalf("expected the gas price to stay, got %d", gasPrice())
+	}
+}
@@ -0,0 +1,262 @@
+package oracle
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var auditErrorCounter = metrics.NewRegisteredCounter("audit/error", ometrics.DefaultRegistry)
+
+// AuditKind is the kind of update that an audit record is written for
+type AuditKind string
+
+const (
+	AuditKindL2GasPrice AuditKind = "l2_gas_price"
+	AuditKindL1BaseFee  AuditKind = "l1_base_fee"
+	AuditKindOwnership  AuditKind = "ownership"
+	// The runtime changes of the admin API are recorded without a
+	// transaction
+	AuditKindTargetGasPerSecond AuditKind = "target_gas_per_second"
+	AuditKindFloorPrice         AuditKind = "floor_price"
+)
+
+// AuditRecord is an update transaction that was sent or a runtime change of
+// a pricing parameter by the admin API. Each record includes the hash of the
+// previous record and is signed by the signer of the oracle, so that records
+// cannot be removed, reordered or altered without breaking the chain.
+type AuditRecord struct {
+	Time       time.Time      `json:"time"`
+	Kind       AuditKind      `json:"kind"`
+	Signer     common.Address `json:"signer"`
+	Nonce      uint64         `json:"nonce"`
+	TxHash     common.Hash    `json:"txHash"`
+	TxGasPrice *big.Int       `json:"txGasPrice"`
+	Value      *big.Int       `json:"value"`
+	// NewOwner is set when the ownership of the gas price oracle was
+	// transferred
+	NewOwner *common.Address `json:"newOwner,omitempty"`
+	// Previous is set for a runtime change to the value that it replaced
+	Previous     *big.Int      `json:"previous,omitempty"`
+	ConfigDigest common.Hash   `json:"configDigest"`
+	PrevHash     common.Hash   `json:"prevHash"`
+	Signature    hexutil.Bytes `json:"signature,omitempty"`
+}
+
+// hash is the hash of the record without its signature, which is what the
+// signature signs and what the next record refers to
+func (r *AuditRecord) hash() (common.Hash, error) {
+	unsigned := *r
+	unsigned.Signature = nil
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// auditLog appends a record of every update transaction to a file
+type auditLog struct {
+	mu           sync.Mutex
+	file         *os.File
+	key          *ecdsa.PrivateKey
+	configDigest common.Hash
+	prev         common.Hash
+}
+
+// openAuditLog opens the audit log at path for appending, continuing the
+// chain of the records that it already holds
+func openAuditLog(path string, key *ecdsa.PrivateKey, cfg *Config) (*auditLog, error) {
+	if key == nil {
+		return nil, errNoPrivateKey
+	}
+	var prev common.Hash
+	if existing, err := os.Open(path); err == nil {
+		_, prev, err = verifyAuditLog(existing, nil)
+		existing.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit log %s: %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := configDigest(cfg)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &auditLog{
+		file:         file,
+		key:          key,
+		configDigest: digest,
+		prev:         prev,
+	}, nil
+}
+
+// record appends a signed record of the transaction. The transaction has
+// already been sent, so errors are logged and counted rather than failing
+// the update.
+func (a *auditLog) record(kind AuditKind, tx *types.Transaction, value *big.Int) {
+	a.write(&AuditRecord{Kind: kind, Value: value}, tx)
+}
+
+// recordChange appends a signed record of a runtime change of a parameter,
+// which has no transaction
+func (a *auditLog) recordChange(kind AuditKind, previous, value *big.Int) {
+	a.write(&AuditRecord{Kind: kind, Previous: previous, Value: value}, nil)
+}
+
+// write appends a signed record of the transaction to the fields of the
+// entry that describe the update
+func (a *auditLog) write(entry *AuditRecord, tx *types.Transaction) {
+	if a == nil {
+		return
+	}
+	if err := a.append(entry, tx); err != nil {
+		var hash common.Hash
+		if tx != nil {
+			hash = tx.Hash()
+		}
+		log.Error("cannot write audit record", "kind", entry.Kind, "hash", hash.Hex(), "message", err)
+		auditErrorCounter.Inc(1)
+	}
+}
+
+// close closes the file of the audit log
+func (a *auditLog) close() error {
+	if a == nil {
+		return nil
+	}
+	return a.file.Close()
+}
+
+func (a *auditLog) append(entry *AuditRecord, tx *types.Transaction) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	record := &AuditRecord{
+		Time:         time.Now().UTC(),
+		Kind:         entry.Kind,
+		Signer:       crypto.PubkeyToAddress(a.key.PublicKey),
+		Value:        entry.Value,
+		NewOwner:     entry.NewOwner,
+		Previous:     entry.Previous,
+		ConfigDigest: a.configDigest,
+		PrevHash:     a.prev,
+	}
+	if tx != nil {
+		record.Nonce = tx.Nonce()
+		record.TxHash = tx.Hash()
+		record.TxGasPrice = tx.GasPrice()
+	}
+	hash, err := record.hash()
+	if err != nil {
+		return err
+	}
+	record.Signature, err = crypto.Sign(hash.Bytes(), a.key)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := a.file.Sync(); err != nil {
+		return err
+	}
+	a.prev = hash
+	return nil
+}
+
+// VerifyAuditLog checks the chain and the signatures of the records of an
+// audit log and returns the number of records. When signer is set every
+// record must be signed by it.
+func VerifyAuditLog(r io.Reader, signer *common.Address) (int, error) {
+	n, _, err := verifyAuditLog(r, signer)
+	return n, err
+}
+
+func verifyAuditLog(r io.Reader, signer *common.Address) (int, common.Hash, error) {
+	var prev common.Hash
+	n := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		n++
+		record := new(AuditRecord)
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return n, prev, fmt.Errorf("record %d: %w", n, err)
+		}
+		if record.PrevHash != prev {
+			return n, prev, fmt.Errorf("record %d: does not follow the previous record", n)
+		}
+		hash, err := record.hash()
+		if err != nil {
+			return n, prev, fmt.Errorf("record %d: %w", n, err)
+		}
+		pub, err := crypto.SigToPub(hash.Bytes(), record.Signature)
+		if err != nil {
+			return n, prev, fmt.Errorf("record %d: %w", n, err)
+		}
+		if crypto.PubkeyToAddress(*pub) != record.Signer {
+			return n, prev, fmt.Errorf("record %d: invalid signature", n)
+		}
+		if signer != nil && record.Signer != *signer {
+			return n, prev, fmt.Errorf("record %d: signed by %s", n, record.Signer.Hex())
+		}
+		prev = hash
+	}
+	return n, prev, scanner.Err()
+}
+
+// configDigest is the hash of the options that determine the updates that
+// the oracle sends
+func configDigest(cfg *Config) (common.Hash, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"l2ChainID":                    cfg.l2ChainID,
+		"gasPriceOracleAddress":        cfg.gasPriceOracleAddress,
+		"pricer":                       cfg.pricer,
+		"floorPrice":                   cfg.floorPrice,
+		"maxGasPrice":                  cfg.maxGasPrice,
+		"targetGasPerSecond":           cfg.targetGasPerSecond,
+		"targetGasSchedule":            cfg.targetGasSchedule,
+		"maxPercentChangePerEpoch":     cfg.maxPercentChangePerEpoch,
+		"dailyPriceChangeBudget":       cfg.dailyPriceChangeBudget,
+		"averageBlockGasLimitPerEpoch": cfg.averageBlockGasLimitPerEpoch,
+		"epochLength":                  cfg.epochLength,
+		"minUpdateInterval":            cfg.minUpdateInterval,
+		"l2GasPriceSignificanceFactor": cfg.l2GasPriceSignificanceFactor,
+		"l1BaseFeeSignificanceFactor":  cfg.l1BaseFeeSignificanceFactor,
+		"gasPrice":                     cfg.gasPrice,
+		"demandWeights":                cfg.demandWeights,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
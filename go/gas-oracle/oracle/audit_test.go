@@ -0,0 +1,160 @@
+package oracle
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestAuditLog(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	path := filepath.Join(t.TempDir(), "audit.log")
+	cfg := &Config{l2ChainID: big.NewInt(1337)}
+
+	audit, err := openAuditLog(path, key, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit.record(AuditKindL2GasPrice, types.NewTx(&types.LegacyTx{Nonce: 0}), big.NewInt(100))
+	audit.record(AuditKindL1BaseFee, types.NewTx(&types.LegacyTx{Nonce: 1}), big.NewInt(200))
+	audit.file.Close()
+
+	// Reopening the log continues its chain
+	audit, err = openAuditLog(path, key, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit.record(AuditKindL2GasPrice, types.NewTx(&types.LegacyTx{Nonce: 2}), big.NewInt(300))
+	audit.file.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := VerifyAuditLog(bytes.NewReader(data), &signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 records, got %d", n)
+	}
+
+	otherKey, _ := crypto.GenerateKey()
+	other := crypto.PubkeyToAddress(otherKey.PublicKey)
+	if _, err := VerifyAuditLog(bytes.NewReader(data), &other); err == nil {
+		t.Fatal("expected error for another signer")
+	}
+
+	// Altering a record breaks its signature
+	tampered := bytes.Replace(data, []byte(`"value":300`), []byte(`"value":301`), 1)
+	if _, err := VerifyAuditLog(bytes.NewReader(tampered), nil); err == nil {
+		t.Fatal("expected error for an altered record")
+	}
+	// Removing a record breaks the chain
+	lines := bytes.SplitN(data, []byte("\n"), 2)
+	if _, err := VerifyAuditLog(bytes.NewReader(lines[1]), nil); err == nil {
+		t.Fatal("expected error for a removed record")
+	}
+	if err := ioutil.WriteFile(path, tampered, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openAuditLog(path, key, cfg); err == nil {
+		t.Fatal("expected error when opening an altered log")
+	}
+}
+
+func TestWrapUpdateL2GasPriceFnAudit(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+	}
+	path := filepath.Join(t.TempDir(), "audit.log")
+	cfg.auditLog, err = openAuditLog(path, key, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	n, err := VerifyAuditLog(file, &opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 record, got %d", n)
+	}
+}
+
+func TestAuditLogRuntimeChanges(t *testing.T) {
+	gpo, _, _ := newControlledOracle(t)
+	cfg := gpo.config
+	path := filepath.Join(t.TempDir(), "audit.log")
+	var err error
+	cfg.auditLog, err = openAuditLog(path, cfg.privateKey, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gpo.SetTargetGasPerSecond(5_000_000); err != nil {
+		t.Fatal(err)
+	}
+	if err := gpo.SetFloorPrice(big.NewInt(2)); err != nil {
+		t.Fatal(err)
+	}
+	// Rejected changes are not recorded
+	if err := gpo.SetTargetGasPerSecond(0); err == nil {
+		t.Fatal("expected a zero target to be rejected")
+	}
+	cfg.auditLog.close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(cfg.privateKey.PublicKey)
+	if n, err := VerifyAuditLog(bytes.NewReader(data), &signer); err != nil || n != 2 {
+		t.Fatalf("expected 2 valid records, got %d: %v", n, err)
+	}
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	var target AuditRecord
+	if err := json.Unmarshal(lines[0], &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Kind != AuditKindTargetGasPerSecond || target.Previous.Uint64() != 11_000_000 ||
+		target.Value.Uint64() != 5_000_000 || target.TxHash != (common.Hash{}) {
+		t.Fatalf("unexpected record %+v", target)
+	}
+}
@@ -0,0 +1,164 @@
+package oracle
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// jwtMaxIssuedAtSkew bounds how old a JWT without an expiry can be, like the
+// authenticated RPC of the execution clients
+const jwtMaxIssuedAtSkew = 60 * time.Second
+
+// minJWTSecretLength is the minimum length of a JWT secret in bytes
+const minJWTSecretLength = 32
+
+// defaultAdminBindAllowlist only allows the admin APIs on the loopback
+// interface, so that they cannot be reached from the pod network
+var defaultAdminBindAllowlist = []string{"127.0.0.1", "::1", "localhost"}
+
+var (
+	errUnauthorized     = errors.New("unauthorized")
+	errInvalidJWT       = errors.New("invalid JWT")
+	errExpiredJWT       = errors.New("expired JWT")
+	errJWTWithoutExpiry = errors.New("JWT has no expiry or issued at time")
+)
+
+// adminAuth authenticates the requests of the admin APIs. A request carries
+// either the static token or an HS256 JWT signed with the secret as a
+// bearer token.
+type adminAuth struct {
+	token     string
+	jwtSecret []byte
+	now       func() time.Time
+}
+
+func newAdminAuth(cfg *Config) *adminAuth {
+	return &adminAuth{
+		token:     cfg.adminToken,
+		jwtSecret: cfg.adminJWTSecret,
+		now:       time.Now,
+	}
+}
+
+// authorize returns nil when the authorization header carries a valid token
+func (a *adminAuth) authorize(header string) error {
+	if !strings.HasPrefix(header, "Bearer ") {
+		return errUnauthorized
+	}
+	token := strings.TrimPrefix(header, "Bearer ")
+	if a.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1 {
+		return nil
+	}
+	if len(a.jwtSecret) > 0 && strings.Count(token, ".") == 2 {
+		return a.verifyJWT(token)
+	}
+	return errUnauthorized
+}
+
+// verifyJWT verifies the signature and the time claims of an HS256 JWT. A
+// token must either expire or have been issued recently.
+func (a *adminAuth) verifyJWT(token string) error {
+	parts := strings.Split(token, ".")
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return err
+	}
+	if header.Alg != "HS256" {
+		return fmt.Errorf("%w: unsupported algorithm %q", errInvalidJWT, header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errInvalidJWT
+	}
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return fmt.Errorf("%w: invalid signature", errInvalidJWT)
+	}
+
+	var claims struct {
+		ExpiresAt *int64 `json:"exp"`
+		NotBefore *int64 `json:"nbf"`
+		IssuedAt  *int64 `json:"iat"`
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return err
+	}
+	now := a.now()
+	if claims.NotBefore != nil && now.Before(time.Unix(*claims.NotBefore, 0)) {
+		return fmt.Errorf("%w: not valid yet", errInvalidJWT)
+	}
+	switch {
+	case claims.ExpiresAt != nil:
+		if !now.Before(time.Unix(*claims.ExpiresAt, 0)) {
+			return errExpiredJWT
+		}
+	case claims.IssuedAt != nil:
+		skew := now.Sub(time.Unix(*claims.IssuedAt, 0))
+		if skew > jwtMaxIssuedAtSkew || skew < -jwtMaxIssuedAtSkew {
+			return errExpiredJWT
+		}
+	default:
+		return errJWTWithoutExpiry
+	}
+	return nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errInvalidJWT
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errInvalidJWT
+	}
+	return nil
+}
+
+// loadJWTSecret reads a hex encoded JWT secret from a file
+func loadJWTSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil {
+		// The contents of the file are not included since they are secret
+		return nil, fmt.Errorf("invalid hex JWT secret in %s", path)
+	}
+	if len(secret) < minJWTSecretLength {
+		return nil, fmt.Errorf("JWT secret in %s is shorter than %d bytes", path, minJWTSecretLength)
+	}
+	return secret, nil
+}
+
+// checkBindAddress returns an error when the host of the listening address
+// is not in the allowlist. An empty host listens on all interfaces, which
+// must be allowed as 0.0.0.0. A "*" allows any address.
+func checkBindAddress(addr string, allowlist []string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	for _, allowed := range allowlist {
+		if allowed == "*" || allowed == host {
+			return nil
+		}
+	}
+	return fmt.Errorf("host %s of %s is not in the bind allowlist %s", host, addr, strings.Join(allowlist, ","))
+}
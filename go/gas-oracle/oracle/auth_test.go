@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signJWT returns an HS256 JWT of the claims
+func signJWT(secret []byte, claims string) string {
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestAdminAuth(t *testing.T) {
+	secret := []byte(strings.Repeat("s", 32))
+	now := time.Unix(1_000_000, 0)
+	auth := &adminAuth{token: "token", jwtSecret: secret, now: func() time.Time { return now }}
+
+	tests := []struct {
+		name   string
+		header string
+		err    error
+	}{
+		{"token", "Bearer token", nil},
+		{"wrong token", "Bearer other", errUnauthorized},
+		{"no bearer", "token", errUnauthorized},
+		{"empty", "", errUnauthorized},
+		{"expiry", "Bearer " + signJWT(secret, `{"exp":1000060}`), nil},
+		{"issued at", "Bearer " + signJWT(secret, `{"iat":999990}`), nil},
+		{"expired", "Bearer " + signJWT(secret, `{"exp":999999}`), errExpiredJWT},
+		{"stale", "Bearer " + signJWT(secret, `{"iat":999000}`), errExpiredJWT},
+		{"no expiry", "Bearer " + signJWT(secret, `{}`), errJWTWithoutExpiry},
+		{"not before", "Bearer " + signJWT(secret, `{"exp":1000060,"nbf":1000030}`), errInvalidJWT},
+		{"wrong secret", "Bearer " + signJWT([]byte(strings.Repeat("x", 32)), `{"exp":1000060}`), errInvalidJWT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := auth.authorize(tt.header)
+			if tt.err == nil && err != nil || tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	// JWTs are rejected without a secret
+	auth.jwtSecret = nil
+	if err := auth.authorize("Bearer " + signJWT(secret, `{"exp":1000060}`)); !errors.Is(err, errUnauthorized) {
+		t.Fatalf("expected a JWT to be rejected without a secret, got %v", err)
+	}
+}
+
+func TestLoadJWTSecret(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "jwt.hex")
+	if err := os.WriteFile(path, []byte("0x"+strings.Repeat("ab", 32)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := loadJWTSecret(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 || secret[0] != 0xab {
+		t.Fatalf("unexpected secret %x", secret)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Repeat("ab", 16)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadJWTSecret(path); err == nil {
+		t.Fatal("expected a short secret to be rejected")
+	}
+	if err := os.WriteFile(path, []byte("not hex"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadJWTSecret(path); err == nil || strings.Contains(err.Error(), "not hex") {
+		t.Fatalf("expected an error without the secret, got %v", err)
+	}
+}
+
+func TestCheckBindAddress(t *testing.T) {
+	tests := []struct {
+		addr      string
+		allowlist []string
+		ok        bool
+	}{
+		{"127.0.0.1:7301", defaultAdminBindAllowlist, true},
+		{"[::1]:7301", defaultAdminBindAllowlist, true},
+		{"localhost:7301", defaultAdminBindAllowlist, true},
+		{"0.0.0.0:7301", defaultAdminBindAllowlist, false},
+		{":7301", defaultAdminBindAllowlist, false},
+		{":7301", []string{"0.0.0.0"}, true},
+		{"10.0.0.1:7301", []string{"10.0.0.1"}, true},
+		{"10.0.0.1:7301", []string{"*"}, true},
+		{"7301", defaultAdminBindAllowlist, false},
+	}
+	for _, tt := range tests {
+		if err := checkBindAddress(tt.addr, tt.allowlist); (err == nil) != tt.ok {
+			t.Errorf("%s with %v: unexpected result %v", tt.addr, tt.allowlist, err)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Metrics of the most recent backtest, which are pushed to a Pushgateway
+// because a backtest exits before it can be scraped
+var (
+	backtestEpochsGauge        = metrics.NewRegisteredGauge("backtest/epochs", ometrics.DefaultRegistry)
+	backtestUpdatesGauge       = metrics.NewRegisteredGauge("backtest/updates", ometrics.DefaultRegistry)
+	backtestOwnerGasSpendGauge = metrics.NewRegisteredGaugeFloat64("backtest/owner_gas_spend", ometrics.DefaultRegistry)
+	backtestFinalPriceGauge    = metrics.NewRegisteredGauge("backtest/final_gas_price", ometrics.DefaultRegistry)
+	backtestMinPriceGauge      = metrics.NewRegisteredGauge("backtest/min_gas_price", ometrics.DefaultRegistry)
+	backtestMaxPriceGauge      = metrics.NewRegisteredGauge("backtest/max_gas_price", ometrics.DefaultRegistry)
+)
+
+// BacktestConfig represents the options for replaying historical blocks
+// through the gas pricer
+type BacktestConfig struct {
+	StartBlock uint64
+	EndBlock   uint64
+	// InitialGasPrice overrides the L2 gas price at the start block, which
+	// otherwise is read from the contract and requires an archive node
+	InitialGasPrice *big.Int
+	// UpdateTxGas is the estimated gas used by each update transaction
+	UpdateTxGas uint64
+}
+
+// BacktestEpoch is the outcome of a single replayed epoch
+type BacktestEpoch struct {
+	BlockNumber     uint64     `json:"blockNumber"`
+	Time            time.Time  `json:"time"`
+	AvgGasPerSecond float64    `json:"avgGasPerSecond"`
+	ComputedPrice   *big.Int   `json:"computedPrice"`
+	GasPrice        *big.Int   `json:"gasPrice"`
+	Updated         bool       `json:"updated"`
+	Reason          ReasonCode `json:"reason"`
+	Bound           ReasonCode `json:"bound,omitempty"`
+}
+
+// BacktestResult is the price trajectory and the cost of a backtest
+type BacktestResult struct {
+	Epochs          []BacktestEpoch `json:"epochs"`
+	Updates         int             `json:"updates"`
+	OwnerGasSpend   *big.Int        `json:"ownerGasSpend"`
+	InitialGasPrice *big.Int        `json:"initialGasPrice"`
+	FinalGasPrice   *big.Int        `json:"finalGasPrice"`
+	MinGasPrice     *big.Int        `json:"minGasPrice"`
+	MaxGasPrice     *big.Int        `json:"maxGasPrice"`
+}
+
+// Backtest replays a range of blocks through the gas pricer using the
+// configured pricing parameters. Blocks are grouped into epochs using their
+// timestamps and every decision that the oracle would make is evaluated
+// against a simulated on-chain gas price.
+func Backtest(cfg *Config, backend bind.ContractBackend, btCfg *BacktestConfig) (*BacktestResult, error) {
+	if btCfg.EndBlock <= btCfg.StartBlock {
+		return nil, errors.New("end block must be greater than start block")
+	}
+	if cfg.epochLength < time.Second {
+		return nil, errors.New("epoch length cannot be less than 1 second")
+	}
+
+	onChainPrice := btCfg.InitialGasPrice
+	if onChainPrice == nil {
+		contract, err := bindings.NewGasPriceOracle(cfg.gasPriceOracleAddress, backend)
+		if err != nil {
+			return nil, err
+		}
+		onChainPrice, err = contract.GasPrice(&bind.CallOpts{
+			Context:     context.Background(),
+			BlockNumber: new(big.Int).SetUint64(btCfg.StartBlock),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch gas price at block %d: %w", btCfg.StartBlock, err)
+		}
+	}
+
+	start, err := backend.HeaderByNumber(context.Background(), new(big.Int).SetUint64(btCfg.StartBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	// The clock of the gas pricer follows the timestamps of the blocks
+	now := time.Unix(int64(start.Time), 0)
+	clock := func() time.Time { return now }
+
+	gasPricer, err := newLivePricer(cfg, onChainPrice, clock)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BacktestResult{
+		OwnerGasSpend:   new(big.Int),
+		InitialGasPrice: new(big.Int).Set(onChainPrice),
+		MinGasPrice:     new(big.Int).Set(onChainPrice),
+		MaxGasPrice:     new(big.Int).Set(onChainPrice),
+	}
+
+	// latest is the last block of the epoch that is being replayed
+	latest := btCfg.StartBlock
+	usages := make(map[uint64]*gasprices.BlockUsage)
+	// replayedUsage returns the usage of a block that has been replayed
+	replayedUsage := func(number *big.Int) (*gasprices.BlockUsage, error) {
+		usage, ok := usages[number.Uint64()]
+		if !ok {
+			return nil, fmt.Errorf("block %d not replayed", number)
+		}
+		delete(usages, number.Uint64())
+		return usage, nil
+	}
+	// Full blocks are only fetched when their transactions are needed
+	blocks, _ := backend.(BlockBackend)
+	state := new(epochState)
+	limiter := &rateLimiter{interval: cfg.minUpdateInterval}
+
+	updateL2GasPriceFn := func(computed *big.Int) error {
+		decision := decideL2GasPrice(cfg, limiter, onChainPrice, computed, state.get(), now)
+		epoch := BacktestEpoch{
+			BlockNumber:     latest,
+			Time:            now,
+			AvgGasPerSecond: decision.AvgGasPerSecond,
+			ComputedPrice:   computed,
+			Reason:          decision.Reason,
+			Bound:           decision.Bound,
+		}
+		if decision.Send {
+			// The owner pays for the update at the configured
+			// transaction gas price or the current L2 gas price
+			txGasPrice := cfg.gasPrice
+			if txGasPrice == nil {
+				txGasPrice = onChainPrice
+			}
+			spend := new(big.Int).SetUint64(btCfg.UpdateTxGas)
+			result.OwnerGasSpend.Add(result.OwnerGasSpend, spend.Mul(spend, txGasPrice))
+			result.Updates++
+			limiter.record(now)
+			onChainPrice = decision.GasPrice
+			epoch.Updated = true
+		}
+		epoch.GasPrice = new(big.Int).Set(onChainPrice)
+		if onChainPrice.Cmp(result.MinGasPrice) < 0 {
+			result.MinGasPrice = new(big.Int).Set(onChainPrice)
+		}
+		if onChainPrice.Cmp(result.MaxGasPrice) > 0 {
+			result.MaxGasPrice = new(big.Int).Set(onChainPrice)
+		}
+		result.Epochs = append(result.Epochs, epoch)
+		return nil
+	}
+
+	updater, err := gasprices.NewGasPriceUpdater(
+		gasPricer,
+		btCfg.StartBlock,
+		cfg.averageBlockGasLimitPerEpoch,
+		cfg.epochLength,
+		func() (uint64, error) { return latest, nil },
+		func(number *big.Int) (uint64, error) {
+			usage, err := replayedUsage(number)
+			if err != nil {
+				return 0, err
+			}
+			return usage.GasUsed, nil
+		},
+		updateL2GasPriceFn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.usesBlockUsage() {
+		if blocks == nil {
+			return nil, errors.New("backend cannot fetch blocks for multi-signal demand")
+		}
+		if err := updater.SetBlockUsage(replayedUsage, cfg.demandWeights); err != nil {
+			return nil, err
+		}
+	}
+	demandFilters, err := newDemandFilters(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range demandFilters {
+		updater.AddDemandFilter(f)
+	}
+	updater.AddDemandObserver(state)
+	if cfg.adaptiveSignificance != nil {
+		updater.AddDemandObserver(cfg.adaptiveSignificance)
+	}
+
+	epochLength := cfg.epochLength
+	epochEnd := now.Add(epochLength)
+	for number := btCfg.StartBlock + 1; number <= btCfg.EndBlock; number++ {
+		blockTime, usage, err := replayBlock(backend, blocks, cfg.usesBlockUsage(), number)
+		if err != nil {
+			return nil, err
+		}
+		// Complete every epoch that ended before this block
+		for blockTime.After(epochEnd) {
+			now = epochEnd
+			if err := updater.UpdateGasPrice(); err != nil {
+				return nil, err
+			}
+			epochEnd = epochEnd.Add(epochLength)
+		}
+		usages[number] = usage
+		latest = number
+		log.Trace("Replayed block", "number", number, "gas-used", usage.GasUsed)
+	}
+	// Complete the final epoch
+	now = epochEnd
+	if err := updater.UpdateGasPrice(); err != nil {
+		return nil, err
+	}
+
+	result.FinalGasPrice = new(big.Int).Set(onChainPrice)
+	result.updateMetrics()
+	return result, nil
+}
+
+// updateMetrics exports the outcome of the backtest. The owner gas spend is
+// exported in ether.
+func (r *BacktestResult) updateMetrics() {
+	backtestEpochsGauge.Update(int64(len(r.Epochs)))
+	backtestUpdatesGauge.Update(int64(r.Updates))
+	spend, _ := new(big.Float).Quo(new(big.Float).SetInt(r.OwnerGasSpend), big.NewFloat(params.Ether)).Float64()
+	backtestOwnerGasSpendGauge.Update(spend)
+	backtestFinalPriceGauge.Update(r.FinalGasPrice.Int64())
+	backtestMinPriceGauge.Update(r.MinGasPrice.Int64())
+	backtestMaxPriceGauge.Update(r.MaxGasPrice.Int64())
+}
+
+// replayBlock fetches the time and the usage of a block. The header is
+// enough unless the full block is needed to count its transactions.
+func replayBlock(backend bind.ContractBackend, blocks BlockBackend, full bool, number uint64) (time.Time, *gasprices.BlockUsage, error) {
+	n := new(big.Int).SetUint64(number)
+	if full {
+		block, err := blocks.BlockByNumber(context.Background(), n)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		return time.Unix(int64(block.Time()), 0), blockUsage(block), nil
+	}
+	header, err := backend.HeaderByNumber(context.Background(), n)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return time.Unix(int64(header.Time), 0), &gasprices.BlockUsage{GasUsed: header.GasUsed}, nil
+}
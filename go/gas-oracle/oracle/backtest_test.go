@@ -0,0 +1,201 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBacktest(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	// Empty blocks are 10 seconds apart
+	for i := 0; i < 6; i++ {
+		sim.Commit()
+	}
+
+	cfg := &Config{
+		floorPrice:                   big.NewInt(1),
+		targetGasPerSecond:           11_000_000,
+		maxPercentChangePerEpoch:     0.5,
+		averageBlockGasLimitPerEpoch: 11_000_000,
+		epochLength:                  10 * time.Second,
+		l2GasPriceSignificanceFactor: 0.05,
+	}
+
+	result, err := Backtest(cfg, sim, &BacktestConfig{
+		StartBlock:      2,
+		EndBlock:        6,
+		InitialGasPrice: big.NewInt(1000),
+		UpdateTxGas:     35000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []uint64{500, 250, 125, 63}
+	if len(result.Epochs) != len(expect) {
+		t.Fatalf("expected %d epochs, got %d", len(expect), len(result.Epochs))
+	}
+	for i, epoch := range result.Epochs {
+		if epoch.GasPrice.Uint64() != expect[i] {
+			t.Fatalf("epoch %d: expected %d, got %d", i, expect[i], epoch.GasPrice)
+		}
+		if !epoch.Updated {
+			t.Fatalf("epoch %d: expected update", i)
+		}
+		if epoch.BlockNumber != uint64(i+3) {
+			t.Fatalf("epoch %d: expected block %d, got %d", i, i+3, epoch.BlockNumber)
+		}
+	}
+	if result.Updates != 4 {
+		t.Fatalf("expected 4 updates, got %d", result.Updates)
+	}
+	// Each update is paid for at the L2 gas price before the update
+	spend := big.NewInt(35000 * (1000 + 500 + 250 + 125))
+	if result.OwnerGasSpend.Cmp(spend) != 0 {
+		t.Fatalf("expected spend %d, got %d", spend, result.OwnerGasSpend)
+	}
+	if result.InitialGasPrice.Uint64() != 1000 || result.FinalGasPrice.Uint64() != 63 {
+		t.Fatal("unexpected initial or final gas price")
+	}
+	if result.MinGasPrice.Uint64() != 63 || result.MaxGasPrice.Uint64() != 1000 {
+		t.Fatal("unexpected min or max gas price")
+	}
+}
+
+func TestBacktestNotSignificant(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	for i := 0; i < 3; i++ {
+		sim.Commit()
+	}
+
+	cfg := &Config{
+		floorPrice:                   big.NewInt(1),
+		targetGasPerSecond:           11_000_000,
+		maxPercentChangePerEpoch:     0.5,
+		averageBlockGasLimitPerEpoch: 11_000_000,
+		epochLength:                  10 * time.Second,
+		l2GasPriceSignificanceFactor: 0.9,
+		gasPrice:                     big.NewInt(2),
+	}
+
+	result, err := Backtest(cfg, sim, &BacktestConfig{
+		StartBlock:      0,
+		EndBlock:        3,
+		InitialGasPrice: big.NewInt(1000),
+		UpdateTxGas:     35000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Epochs) != 3 {
+		t.Fatalf("expected 3 epochs, got %d", len(result.Epochs))
+	}
+	// A halving is never significant enough to be sent
+	if result.Updates != 0 || result.OwnerGasSpend.Sign() != 0 {
+		t.Fatal("expected no updates")
+	}
+	if result.FinalGasPrice.Uint64() != 1000 {
+		t.Fatalf("expected unchanged gas price, got %d", result.FinalGasPrice)
+	}
+	if result.Epochs[2].ComputedPrice.Uint64() != 125 {
+		t.Fatalf("expected computed price 125, got %d", result.Epochs[2].ComputedPrice)
+	}
+
+	if _, err := Backtest(cfg, sim, &BacktestConfig{StartBlock: 3, EndBlock: 3}); err == nil {
+		t.Fatal("expected error for empty range")
+	}
+}
+
+func TestBacktestSmoothing(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	for i := 0; i < 3; i++ {
+		sim.Commit()
+	}
+
+	cfg := &Config{
+		floorPrice:                   big.NewInt(1),
+		targetGasPerSecond:           11_000_000,
+		maxPercentChangePerEpoch:     0.5,
+		averageBlockGasLimitPerEpoch: 11_000_000,
+		epochLength:                  10 * time.Second,
+		smoothingEpochs:              3,
+		smoothingMethod:              "ewma",
+	}
+	btCfg := &BacktestConfig{
+		StartBlock:      0,
+		EndBlock:        3,
+		InitialGasPrice: big.NewInt(1000),
+	}
+	if _, err := Backtest(cfg, sim, btCfg); err == nil {
+		t.Fatal("expected error for an unknown smoothing method")
+	}
+
+	cfg.smoothingMethod = gasprices.SmoothingMethodMedian
+	result, err := Backtest(cfg, sim, btCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Epochs) != 3 {
+		t.Fatalf("expected 3 epochs, got %d", len(result.Epochs))
+	}
+}
+
+func TestBacktestCanary(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	for i := 0; i < 3; i++ {
+		sim.Commit()
+	}
+
+	cfg := &Config{
+		pricer:                       gasprices.DefaultPricer,
+		canaryIncumbentPricer:        "fixed",
+		floorPrice:                   big.NewInt(1),
+		targetGasPerSecond:           11_000_000,
+		averageBlockGasLimitPerEpoch: 11_000_000,
+		epochLength:                  10 * time.Second,
+		l2GasPriceSignificanceFactor: 0.05,
+	}
+	btCfg := &BacktestConfig{
+		StartBlock:      0,
+		EndBlock:        3,
+		InitialGasPrice: big.NewInt(1000),
+	}
+
+	// Halving the gas price diverges too far from the fixed incumbent
+	cfg.maxPercentChangePerEpoch = 0.5
+	cfg.canaryMaxDelta = 0.3
+	result, err := Backtest(cfg, sim, btCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updates != 0 || result.FinalGasPrice.Uint64() != 1000 {
+		t.Fatalf("expected the incumbent gas price, got %d updates to %d",
+			result.Updates, result.FinalGasPrice)
+	}
+
+	// Small changes are accepted until they drift too far from the incumbent
+	cfg.maxPercentChangePerEpoch = 0.1
+	cfg.canaryMaxDelta = 0.25
+	result, err = Backtest(cfg, sim, btCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []uint64{900, 810, 1000}
+	if len(result.Epochs) != len(expect) {
+		t.Fatalf("expected %d epochs, got %d", len(expect), len(result.Epochs))
+	}
+	for i, epoch := range result.Epochs {
+		if epoch.ComputedPrice.Uint64() != expect[i] {
+			t.Fatalf("epoch %d: expected %d, got %d", i, expect[i], epoch.ComputedPrice)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	signerBalanceGauge    = metrics.NewRegisteredGaugeFloat64("signer/balance", ometrics.DefaultRegistry)
+	signerLowBalanceGauge = metrics.NewRegisteredGauge("signer/low_balance", ometrics.DefaultRegistry)
+)
+
+// BalanceBackend can fetch the balance of an account
+type BalanceBackend interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// lowBalanceEvent is the webhook payload sent when the balance of the
+// signer falls below the threshold
+type lowBalanceEvent struct {
+	Event     string         `json:"event"`
+	Address   common.Address `json:"address"`
+	Balance   *big.Int       `json:"balance"`
+	Threshold *big.Int       `json:"threshold"`
+}
+
+// balanceMonitor exports the balance of the signer and alerts when it falls
+// below a threshold. A drained signer cannot pay for updates and the gas
+// price stops moving.
+type balanceMonitor struct {
+	backend    BalanceBackend
+	address    common.Address
+	threshold  *big.Int
+	webhookURL string
+	client     *http.Client
+	notifier   *notify.Dispatcher
+	chainID    *big.Int
+	metrics    *chainMetrics
+	low        bool
+}
+
+func newBalanceMonitor(backend BalanceBackend, address common.Address, cfg *Config) *balanceMonitor {
+	return &balanceMonitor{
+		backend:    backend,
+		address:    address,
+		threshold:  cfg.lowBalanceThreshold,
+		webhookURL: cfg.lowBalanceWebhookURL,
+		client:     new(http.Client),
+		notifier:   cfg.notifier,
+		chainID:    cfg.l2ChainID,
+		metrics:    cfg.chainMetrics,
+	}
+}
+
+// check fetches the balance of the signer. A warning is logged at every
+// check while the balance is low, but the webhook only fires when the
+// balance first falls below the threshold.
+func (b *balanceMonitor) check(ctx context.Context) error {
+	balance, err := b.backend.BalanceAt(ctx, b.address, nil)
+	if err != nil {
+		return err
+	}
+	ether, _ := new(big.Float).Quo(new(big.Float).SetInt(balance), big.NewFloat(params.Ether)).Float64()
+	signerBalanceGauge.Update(ether)
+	b.metrics.updateSignerBalance(ether)
+	log.Debug("Fetched signer balance", "address", b.address.Hex(), "balance", balance)
+
+	if b.threshold == nil {
+		return nil
+	}
+	if balance.Cmp(b.threshold) >= 0 {
+		if b.low {
+			log.Info("Signer balance recovered", "address", b.address.Hex(), "balance", balance)
+			b.notifier.Notify(b.event(notify.EventBalanceRecovered, balance))
+		}
+		b.low = false
+		signerLowBalanceGauge.Update(0)
+		return nil
+	}
+
+	log.Warn("Signer balance is low", "address", b.address.Hex(), "balance", balance,
+		"threshold", b.threshold)
+	signerLowBalanceGauge.Update(1)
+	if b.low {
+		return nil
+	}
+	if b.webhookURL == "" {
+		b.notifier.Notify(b.event(notify.EventLowBalance, balance))
+		b.low = true
+		return nil
+	}
+	// The webhook is retried at the next check when it cannot be delivered
+	err = notify.PostJSON(ctx, b.client, b.webhookURL, &lowBalanceEvent{
+		Event:     "low_balance",
+		Address:   b.address,
+		Balance:   balance,
+		Threshold: b.threshold,
+	})
+	if err != nil {
+		return err
+	}
+	b.notifier.Notify(b.event(notify.EventLowBalance, balance))
+	b.low = true
+	return nil
+}
+
+func (b *balanceMonitor) event(t notify.EventType, balance *big.Int) *notify.Event {
+	return &notify.Event{
+		Type:      t,
+		Time:      time.Now(),
+		ChainID:   b.chainID,
+		Signer:    b.address.Hex(),
+		Balance:   balance,
+		Threshold: b.threshold,
+	}
+}
@@ -0,0 +1,96 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBalanceMonitor(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	// The webhook is posted before check returns
+	events := make(chan lowBalanceEvent, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event lowBalanceEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Error(err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		events <- event
+	}))
+	defer server.Close()
+
+	// The simulated account holds 9223372036854775807 wei
+	monitor := newBalanceMonitor(sim, address, &Config{
+		lowBalanceThreshold:  big.NewInt(1),
+		lowBalanceWebhookURL: server.URL,
+	})
+	if err := monitor.check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 || monitor.low {
+		t.Fatal("expected the balance to be above the threshold")
+	}
+
+	// The webhook fires once while the balance stays low
+	monitor.threshold = new(big.Int).Lsh(big.NewInt(1), 64)
+	for i := 0; i < 2; i++ {
+		if err := monitor.check(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 webhook, got %d", len(events))
+	}
+	event := <-events
+	if event.Event != "low_balance" || event.Address != address {
+		t.Fatal("unexpected webhook payload")
+	}
+	if event.Threshold.Cmp(monitor.threshold) != 0 {
+		t.Fatalf("unexpected threshold %d", event.Threshold)
+	}
+
+	// The webhook fires again after the balance recovers
+	monitor.threshold = big.NewInt(1)
+	if err := monitor.check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	monitor.threshold = new(big.Int).Lsh(big.NewInt(1), 64)
+	if err := monitor.check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected another webhook, got %d", len(events))
+	}
+}
+
+func TestBalanceMonitorWebhookFailure(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	monitor := newBalanceMonitor(sim, crypto.PubkeyToAddress(key.PublicKey), &Config{
+		lowBalanceThreshold:  new(big.Int).Lsh(big.NewInt(1), 64),
+		lowBalanceWebhookURL: server.URL,
+	})
+	if err := monitor.check(context.Background()); err == nil {
+		t.Fatal("expected the webhook to fail")
+	}
+	// The webhook is retried at the next check
+	if monitor.low {
+		t.Fatal("expected the alert to be retried")
+	}
+}
@@ -5,31 +5,25 @@ import (
 	"fmt"
 
 	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 )
 
-func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContractBackend, cfg *Config) (func() error, error) {
-	if cfg.privateKey == nil {
-		return nil, errNoPrivateKey
-	}
-	if cfg.l2ChainID == nil {
-		return nil, errNoChainID
-	}
+var dryRunBaseFeeGauge = metrics.NewRegisteredGauge("dry_run/base_fee", ometrics.DefaultRegistry)
 
-	opts, err := bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
-	if err != nil {
-		return nil, err
-	}
-	// Once https://github.com/ethereum/go-ethereum/pull/23062 is released
-	// then we can remove setting the context here
-	if opts.Context == nil {
-		opts.Context = context.Background()
+func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContractBackend, cfg *Config) (func() error, error) {
+	// A transactor is only needed when transactions are sent
+	var opts *bind.TransactOpts
+	if !cfg.dryRun {
+		var err error
+		opts, err = newTransactOpts(cfg)
+		if err != nil {
+			return nil, err
+		}
 	}
-	// Don't send the transaction using the `contract` so that we can inspect
-	// it beforehand
-	opts.NoSend = true
 
 	// Create a new contract bindings in scope of the updateL2GasPriceFn
 	// that is returned from this function
@@ -52,11 +46,33 @@ func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContra
 		if tip.BaseFee == nil {
 			return errNoBaseFee
 		}
-		if !isDifferenceSignificant(baseFee.Uint64(), tip.BaseFee.Uint64(), cfg.l1BaseFeeSignificanceFactor) {
+		if !isDifferenceSignificant(baseFee, tip.BaseFee, cfg.l1BaseFeeSignificanceFactor) {
 			log.Debug("non significant base fee update", "tip", tip.BaseFee, "current", baseFee)
 			return nil
 		}
 
+		if cfg.dryRun {
+			log.Info("dry run: would update L1 base fee", "current", baseFee, "base-fee", tip.BaseFee)
+			dryRunBaseFeeGauge.Update(int64(tip.BaseFee.Uint64()))
+			return nil
+		}
+		if cfg.controls.isPaused() {
+			log.Warn("Updates are paused, not sending the L1 base fee", "current", baseFee, "base-fee", tip.BaseFee)
+			return nil
+		}
+		if !cfg.leader.isLeader() {
+			log.Info("Replica is on standby, not sending the L1 base fee", "current", baseFee, "base-fee", tip.BaseFee)
+			return nil
+		}
+
+		// The L1 base fee is only sent when it was read from the expected L1
+		if err := cfg.l1ChainIDGuard.verify(context.Background(), cfg.l1ChainID); err != nil {
+			return err
+		}
+		if err := cfg.l2ChainIDGuard.verify(context.Background(), cfg.l2ChainID); err != nil {
+			return err
+		}
+
 		// Use the configured gas price if it is set,
 		// otherwise use gas estimation
 		if cfg.gasPrice != nil {
@@ -79,13 +95,17 @@ func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContra
 			return fmt.Errorf("cannot update base fee: %w", err)
 		}
 		log.Info("L1 base fee transaction sent", "hash", tx.Hash().Hex())
+		cfg.auditLog.record(AuditKindL1BaseFee, tx, tip.BaseFee)
+		publishTransaction(cfg, AuditKindL1BaseFee, tx, tip.BaseFee)
+		cfg.controls.observeSent(tx.Hash())
 
 		if cfg.waitForReceipt {
 			// Wait for the receipt
-			receipt, err := waitForReceipt(l2Backend, tx)
+			receipt, err := waitForReceipt(context.Background(), l2Backend, tx)
 			if err != nil {
 				return err
 			}
+			publishReceipt(cfg, receipt)
 
 			log.Info("base-fee transaction confirmed", "hash", tx.Hash().Hex(),
 				"gas-used", receipt.GasUsed, "blocknumber", receipt.BlockNumber)
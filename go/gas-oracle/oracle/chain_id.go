@@ -0,0 +1,93 @@
+package oracle
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	chainIDReconnectCounter = metrics.NewRegisteredCounter("chain_id/reconnect", ometrics.DefaultRegistry)
+	chainIDMismatchCounter  = metrics.NewRegisteredCounter("chain_id/mismatch", ometrics.DefaultRegistry)
+)
+
+// chainIDGuard verifies the chain ID of an HTTP endpoint again after the
+// client opened a new connection to it, since the connection may reach
+// another node than the one that was verified at startup, such as when a
+// load balancer or the DNS of the URL is pointed elsewhere. The connections
+// are opened lazily by the HTTP client, so the chain ID is verified before
+// the next transaction is sent rather than when the connection is opened.
+// A nil chainIDGuard does not verify.
+type chainIDGuard struct {
+	layer  string
+	client chainIDReader
+	// unverified is 1 when a connection was opened since the last
+	// verification
+	unverified int32
+}
+
+// dialChainIDGuarded connects to the endpoint at rawURL and guards its chain
+// ID. Only the HTTP endpoints are guarded, the other transports keep their
+// connection and are verified once at startup. The TLS configuration is nil
+// without a client certificate.
+func dialChainIDGuarded(rawURL, layer string, tlsConfig *tls.Config) (*rpc.Client, *chainIDGuard, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		client, err := dialRPC(rawURL, tlsConfig)
+		return client, nil, err
+	}
+	g := &chainIDGuard{layer: layer, unverified: 1}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig.Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err == nil && atomic.SwapInt32(&g.unverified, 1) == 0 {
+			chainIDReconnectCounter.Inc(1)
+			log.Debug("Reconnected, verifying the chain id before the next transaction", "layer", layer)
+		}
+		return conn, err
+	}
+	client, err := rpc.DialHTTPWithClient(rawURL, &http.Client{Transport: transport})
+	if err != nil {
+		return nil, nil, err
+	}
+	g.client = ethclient.NewClient(client)
+	return client, g, nil
+}
+
+// verify returns errWrongChainID when the endpoint does not serve the
+// expected chain since it reconnected. The endpoint stays unverified until
+// the verification succeeds, so that every transaction is refused until it
+// serves the expected chain again.
+func (g *chainIDGuard) verify(ctx context.Context, expected *big.Int) error {
+	if g == nil || expected == nil || atomic.LoadInt32(&g.unverified) == 0 {
+		return nil
+	}
+	chainID, err := g.client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot verify %s chain id: %w", g.layer, err)
+	}
+	if chainID.Cmp(expected) != 0 {
+		chainIDMismatchCounter.Inc(1)
+		log.Error("Endpoint serves another chain, refusing to send", "layer", g.layer,
+			"expected", expected, "chain-id", chainID)
+		return fmt.Errorf("%w: %s: configured with %d and got %d after reconnecting",
+			errWrongChainID, g.layer, expected, chainID)
+	}
+	// The verification may have opened the connection itself
+	atomic.StoreInt32(&g.unverified, 0)
+	return nil
+}
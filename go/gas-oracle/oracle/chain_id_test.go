@@ -0,0 +1,69 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestChainIDGuard(t *testing.T) {
+	var chainID, calls int64 = 10, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		atomic.AddInt64(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x%x"}`, req.ID, atomic.LoadInt64(&chainID))
+	}))
+	defer server.Close()
+
+	client, guard, err := dialChainIDGuarded(server.URL, "L2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+	expected := big.NewInt(10)
+	if err := guard.verify(ctx, expected); err != nil {
+		t.Fatal(err)
+	}
+	// The connection is kept, so the chain id is not verified again
+	before := atomic.LoadInt64(&calls)
+	if err := guard.verify(ctx, expected); err != nil || atomic.LoadInt64(&calls) != before {
+		t.Fatalf("expected no verification without a reconnect, got %v", err)
+	}
+
+	// The endpoint is pointed to another chain and the client reconnects
+	atomic.StoreInt64(&chainID, 420)
+	server.CloseClientConnections()
+	// A request on the closed connection fails before the client dials
+	var result string
+	for i := 0; client.CallContext(ctx, &result, "eth_chainId") != nil; i++ {
+		if i == 3 {
+			t.Fatal("cannot reconnect")
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := guard.verify(ctx, expected); !errors.Is(err, errWrongChainID) {
+			t.Fatalf("expected %v, got %v", errWrongChainID, err)
+		}
+	}
+	atomic.StoreInt64(&chainID, 10)
+	if err := guard.verify(ctx, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	// Other transports are not guarded
+	var nilGuard *chainIDGuard
+	if err := nilGuard.verify(ctx, expected); err != nil {
+		t.Fatal(err)
+	}
+}
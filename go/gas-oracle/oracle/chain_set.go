@@ -0,0 +1,429 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/yaml.v2"
+)
+
+// maxChainDefinitionSize bounds the body of a request that registers a chain
+const maxChainDefinitionSize = 1 << 20
+
+// ChainSet runs the oracles of a process, which are the chains of the config
+// file or the single chain of the command line. With chains, the admin API
+// of the process registers and deregisters chains at runtime, and the chains
+// of the config file are rewritten so that they are kept across restarts.
+type ChainSet struct {
+	base *Config
+	// mu serializes the changes of the chains, which start and stop oracles
+	mu     sync.Mutex
+	chains []*runningChain
+}
+
+// runningChain is a chain of the set with its definition in the config file
+type runningChain struct {
+	name       string
+	definition yaml.MapSlice
+	gpo        *GasPriceOracle
+	admin      *adminHandler
+}
+
+// NewChainSet creates the oracles of the chains of the config
+func NewChainSet(cfg *Config) (*ChainSet, error) {
+	s := &ChainSet{base: cfg}
+	configs, err := cfg.Chains()
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		gpo, err := NewGasPriceOracle(cfg)
+		if err != nil {
+			return nil, err
+		}
+		s.chains = []*runningChain{{gpo: gpo}}
+		return s, nil
+	}
+
+	definitions, err := readChainDefinitions(cfg.configFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(definitions) != len(configs) {
+		return nil, fmt.Errorf("config file %s changed while loading its chains", cfg.configFile)
+	}
+	for i, chain := range configs {
+		log.Info("Creating gas oracle of chain", "chain", chain.ChainName())
+		c, err := newRunningChain(chain, definitions[i])
+		if err != nil {
+			return nil, err
+		}
+		s.chains = append(s.chains, c)
+	}
+	return s, nil
+}
+
+func newRunningChain(cfg *Config, definition yaml.MapSlice) (*runningChain, error) {
+	gpo, err := NewGasPriceOracle(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("chain %q: %w", cfg.ChainName(), err)
+	}
+	admin, err := newAdminHandler(gpo, newAdminAuth(cfg))
+	if err != nil {
+		return nil, err
+	}
+	return &runningChain{name: cfg.ChainName(), definition: definition, gpo: gpo, admin: admin}, nil
+}
+
+// multiChain returns true when the set runs the chains of the config file
+func (s *ChainSet) multiChain() bool {
+	return len(s.base.chains) > 0
+}
+
+// Oracles returns the oracles of the chains that are running
+func (s *ChainSet) Oracles() []*GasPriceOracle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gpos := make([]*GasPriceOracle, len(s.chains))
+	for i, c := range s.chains {
+		gpos[i] = c.gpo
+	}
+	return gpos
+}
+
+// RunOnce runs a single iteration of each chain in turn
+func (s *ChainSet) RunOnce() error {
+	for _, gpo := range s.Oracles() {
+		if err := gpo.RunOnce(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start runs the chains and serves the admin API of the chains
+func (s *ChainSet) Start() error {
+	for _, gpo := range s.Oracles() {
+		if err := gpo.Start(); err != nil {
+			return err
+		}
+	}
+	if s.multiChain() && s.base.adminAddr != "" {
+		return s.startAdminServer()
+	}
+	return nil
+}
+
+// Wait blocks until the chains have stopped, including the chains that were
+// registered while waiting
+func (s *ChainSet) Wait() {
+	for {
+		running := false
+		for _, gpo := range s.Oracles() {
+			select {
+			case <-gpo.stop:
+			default:
+				running = true
+				gpo.Wait()
+			}
+		}
+		if !running {
+			return
+		}
+	}
+}
+
+// Register starts the chain of the YAML or JSON definition, which has the
+// form of a chain of the config file, and adds it to the config file
+func (s *ChainSet) Register(data []byte) (string, error) {
+	if !s.multiChain() {
+		return "", errors.New("chains can only be registered with the chains of a config file")
+	}
+	var chain chainConfig
+	if err := yaml.UnmarshalStrict(data, &chain); err != nil {
+		return "", fmt.Errorf("invalid chain: %w", err)
+	}
+	var definition yaml.MapSlice
+	if err := yaml.Unmarshal(data, &definition); err != nil {
+		return "", fmt.Errorf("invalid chain: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.find(chain.Name) >= 0 {
+		return "", fmt.Errorf("chain %q is already registered", chain.Name)
+	}
+	cfg, err := s.base.chainConfig(&chain)
+	if err != nil {
+		return "", err
+	}
+	log.Info("Registering chain", "chain", chain.Name)
+	c, err := newRunningChain(cfg, definition)
+	if err != nil {
+		ometrics.UnregisterChain(chain.Name)
+		return "", err
+	}
+	chains := append(append([]*runningChain(nil), s.chains...), c)
+	if err := writeChainDefinitions(s.base.configFile, chains); err != nil {
+		c.gpo.Stop()
+		ometrics.UnregisterChain(chain.Name)
+		return "", fmt.Errorf("cannot update config file: %w", err)
+	}
+	if err := c.gpo.Start(); err != nil {
+		c.gpo.Stop()
+		ometrics.UnregisterChain(chain.Name)
+		if err := writeChainDefinitions(s.base.configFile, s.chains); err != nil {
+			log.Error("cannot restore config file", "message", err)
+		}
+		return "", fmt.Errorf("chain %q: %w", chain.Name, err)
+	}
+	s.chains = chains
+	return chain.Name, nil
+}
+
+// Deregister stops the chain and removes it from the config file. The last
+// chain cannot be deregistered, since the process would then run the chain
+// of the command line after a restart.
+func (s *ChainSet) Deregister(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.find(name)
+	if i < 0 {
+		return fmt.Errorf("unknown chain %q", name)
+	}
+	if len(s.chains) == 1 {
+		return fmt.Errorf("chain %q is the last chain", name)
+	}
+	chains := append(append([]*runningChain(nil), s.chains[:i]...), s.chains[i+1:]...)
+	if err := writeChainDefinitions(s.base.configFile, chains); err != nil {
+		return fmt.Errorf("cannot update config file: %w", err)
+	}
+	log.Info("Deregistering chain", "chain", name)
+	s.chains[i].gpo.Stop()
+	ometrics.UnregisterChain(name)
+	s.chains = chains
+	return nil
+}
+
+// find returns the index of the chain, or -1
+func (s *ChainSet) find(name string) int {
+	for i, c := range s.chains {
+		if c.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// chain returns the running chain of the name, or nil
+func (s *ChainSet) chain(name string) *runningChain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.find(name); i >= 0 {
+		return s.chains[i]
+	}
+	return nil
+}
+
+// readChainDefinitions returns the chains of the config file as they are
+// written, so that they are written back unchanged
+func readChainDefinitions(path string) ([]yaml.MapSlice, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Chains []yaml.MapSlice `yaml:"chains"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse config file %s: %w", path, err)
+	}
+	return file.Chains, nil
+}
+
+// writeChainDefinitions replaces the chains of the config file and keeps its
+// other options. The file is replaced as a whole, so that a crash leaves
+// either the previous or the next file. Comments are not kept.
+func writeChainDefinitions(path string, chains []*runningChain) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("cannot parse config file %s: %w", path, err)
+	}
+	definitions := make([]yaml.MapSlice, len(chains))
+	for i, c := range chains {
+		definitions[i] = c.definition
+	}
+	replaced := false
+	for i := range doc {
+		if doc[i].Key == "chains" {
+			doc[i].Value = definitions
+			replaced = true
+		}
+	}
+	if !replaced {
+		doc = append(doc, yaml.MapItem{Key: "chains", Value: definitions})
+	}
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, out, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// chainsAdminHandler serves the admin API of a process that runs the chains
+// of the config file. Every request must carry the token or a JWT of the
+// configuration as a bearer token, except for the approvals of a chain in
+// dual control, which carry the approval token.
+//
+//	GET    /chains          the names of the chains
+//	POST   /chains          register a chain, with its YAML or JSON definition
+//	DELETE /chains/<name>   deregister a chain
+//	       /chains/<name>/  the admin API of the chain, such as /chains/<name>/state
+type chainsAdminHandler struct {
+	set  *ChainSet
+	auth *adminAuth
+}
+
+func (h *chainsAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.authorize(r); err != nil {
+		log.Warn("Unauthorized admin request", "path", r.URL.Path, "remote", r.RemoteAddr, "message", err)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeAdminError(w, http.StatusUnauthorized, err)
+		return
+	}
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path == "/chains" {
+		h.chains(w, r)
+		return
+	}
+	name, sub, ok := chainPath(r.URL.Path)
+	if !ok {
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("unknown path %s", r.URL.Path))
+		return
+	}
+	if sub == "" && r.Method == http.MethodDelete {
+		if err := h.set.Deregister(name); err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+		log.Info("Admin action", "path", r.URL.Path, "remote", r.RemoteAddr)
+		writeAdminJSON(w, http.StatusOK, h.names())
+		return
+	}
+	c := h.set.chain(name)
+	if c == nil {
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("unknown chain %q", name))
+		return
+	}
+	if sub == "" {
+		sub = "/state"
+	}
+	c.admin.ServeHTTP(w, withPath(r, sub))
+}
+
+// authorize checks the credential of the request. In dual control the
+// approvals of a chain are passed to the admin API of the chain, which
+// decides them with the approval token.
+func (h *chainsAdminHandler) authorize(r *http.Request) error {
+	err := h.auth.authorize(r.Header.Get("Authorization"))
+	if err == nil {
+		return nil
+	}
+	if name, sub, ok := chainPath(r.URL.Path); ok && isApprovalPath(sub) {
+		if c := h.set.chain(name); c != nil && c.admin.approver != nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// chainPath splits a path of the admin API of a chain into the name of the
+// chain and the path below it
+func chainPath(path string) (name, sub string, ok bool) {
+	rest := strings.TrimPrefix(path, "/chains/")
+	if rest == path || rest == "" {
+		return "", "", false
+	}
+	name = rest
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		name, sub = rest[:i], rest[i:]
+	}
+	return name, sub, true
+}
+
+func (h *chainsAdminHandler) chains(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeAdminJSON(w, http.StatusOK, h.names())
+	case http.MethodPost:
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxChainDefinitionSize))
+		if err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+		if _, err := h.set.Register(data); err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+		log.Info("Admin action", "path", r.URL.Path, "remote", r.RemoteAddr)
+		writeAdminJSON(w, http.StatusCreated, h.names())
+	default:
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// names returns the names of the chains that are running
+func (h *chainsAdminHandler) names() map[string][]string {
+	h.set.mu.Lock()
+	defer h.set.mu.Unlock()
+	names := make([]string, len(h.set.chains))
+	for i, c := range h.set.chains {
+		names[i] = c.name
+	}
+	return map[string][]string{"chains": names}
+}
+
+// withPath returns a copy of the request with the path
+func withPath(r *http.Request, path string) *http.Request {
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+	return r2
+}
+
+// startAdminServer serves the admin API of the chains at the configured
+// address, which is bound before returning
+func (s *ChainSet) startAdminServer() error {
+	listener, err := net.Listen("tcp", s.base.adminAddr)
+	if err != nil {
+		return fmt.Errorf("cannot start admin server: %w", err)
+	}
+	server := &http.Server{Handler: &chainsAdminHandler{set: s, auth: newAdminAuth(s.base)}}
+	log.Info("Starting admin server of the chains", "addr", listener.Addr().String())
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("Failure in running admin server", "message", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,187 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+func TestChainSetAdmin(t *testing.T) {
+	path := writeConfigFile(t, `
+target-gas-schedule:
+  - start: "00:00"
+    end: "06:00"
+    target-gas-per-second: 5000000
+chains:
+  - name: op-mainnet
+    l2-chain-id: 10
+  - name: op-goerli
+    l2-chain-id: 420
+`)
+	fileCfg, err := loadConfigFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	definitions, err := readChainDefinitions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &adminAuth{token: "secret"}
+	set := &ChainSet{base: &Config{configFile: path, chains: fileCfg.Chains}}
+	for i, name := range []string{"op-mainnet", "op-goerli"} {
+		gpo, _, _ := newControlledOracle(t)
+		admin, err := newAdminHandler(gpo, auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set.chains = append(set.chains, &runningChain{name: name, definition: definitions[i], gpo: gpo, admin: admin})
+	}
+	goerli := set.chains[1].gpo
+	server := httptest.NewServer(&chainsAdminHandler{set: set, auth: auth})
+	defer server.Close()
+
+	do := func(method, path, token, body string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var out map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, out
+	}
+
+	if status, _ := do(http.MethodGet, "/chains", "", ""); status != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized without a token, got %d", status)
+	}
+	if status, out := do(http.MethodGet, "/chains", "secret", ""); status != http.StatusOK ||
+		len(out["chains"].([]interface{})) != 2 {
+		t.Fatalf("unexpected chains %d %v", status, out)
+	}
+	// The admin API of a chain is served under its name
+	if status, state := do(http.MethodGet, "/chains/op-goerli/state", "secret", ""); status != http.StatusOK ||
+		state["localGasPrice"] != float64(10) {
+		t.Fatalf("unexpected state %d %v", status, state)
+	}
+	if status, _ := do(http.MethodGet, "/chains/op-sepolia/state", "secret", ""); status != http.StatusNotFound {
+		t.Fatalf("expected an unknown chain, got %d", status)
+	}
+
+	// A chain that is invalid or already registered is rejected before an
+	// oracle is created
+	for _, body := range []string{
+		`{"name": "op-goerli", "l2-chain-id": 420}`,
+		`{"name": "OP Sepolia"}`,
+		"name: op-sepolia\nl2-chain: 11155420\n",
+	} {
+		if status, out := do(http.MethodPost, "/chains", "secret", body); status != http.StatusBadRequest {
+			t.Fatalf("expected %s to be rejected, got %d %v", body, status, out)
+		}
+	}
+
+	if status, out := do(http.MethodDelete, "/chains/op-goerli", "secret", ""); status != http.StatusOK ||
+		len(out["chains"].([]interface{})) != 1 {
+		t.Fatalf("unexpected chains %d %v", status, out)
+	}
+	select {
+	case <-goerli.stop:
+	default:
+		t.Fatal("expected the deregistered chain to stop")
+	}
+	// The config file keeps its other options
+	fileCfg, err = loadConfigFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fileCfg.Chains) != 1 || fileCfg.Chains[0].Name != "op-mainnet" || *fileCfg.Chains[0].L2ChainID != 10 ||
+		len(fileCfg.TargetGasSchedule) != 1 {
+		t.Fatalf("unexpected config file %+v", fileCfg)
+	}
+	if status, _ := do(http.MethodDelete, "/chains/op-mainnet", "secret", ""); status != http.StatusBadRequest {
+		t.Fatalf("expected the last chain to be kept, got %d", status)
+	}
+}
+
+func TestChainSetApprovals(t *testing.T) {
+	gpo, _, sim := newControlledOracle(t)
+	cfg := gpo.config
+	cfg.approvalToken = "approver"
+	cfg.approvalTTL = time.Minute
+	cfg.approvals = newApprovals(cfg)
+	gpo.approve = make(chan approvalRequest)
+	go func() {
+		for req := range gpo.approve {
+			req.reply <- gpo.approveUpdate(req.id)
+		}
+	}()
+	defer close(gpo.approve)
+
+	auth := &adminAuth{token: "secret"}
+	admin, err := newAdminHandler(gpo, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := &ChainSet{base: &Config{}, chains: []*runningChain{{name: "op-goerli", gpo: gpo, admin: admin}}}
+	server := httptest.NewServer(&chainsAdminHandler{set: set, auth: auth})
+	defer server.Close()
+
+	do := func(path, token, body string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if err := gpo.updateL2GasPrice(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	staged, _ := gpo.StagedUpdate()
+	if staged == nil {
+		t.Fatal("expected a staged update")
+	}
+	body := `{"id": "` + staged.ID + `"}`
+
+	// The approval token only reaches the approvals of the chain
+	if status := do("/chains/op-goerli/pause", "approver", ""); status != http.StatusUnauthorized {
+		t.Fatalf("expected the approval token not to pause, got %d", status)
+	}
+	if status := do("/chains/op-goerli/approvals/x", "approver", `{"method":"gasoracle_pause"}`); status != http.StatusUnauthorized {
+		t.Fatalf("expected the approval token not to reach the JSON-RPC server, got %d", status)
+	}
+	if status := do("/chains/op-goerli/approvals/approve", "secret", body); status != http.StatusUnauthorized {
+		t.Fatalf("expected the admin token not to approve, got %d", status)
+	}
+	if status := do("/chains/op-goerli/approvals/approve", "approver", body); status != http.StatusOK {
+		t.Fatalf("unexpected approval %d", status)
+	}
+	sim.Commit()
+	price, err := gpo.contract.GasPrice(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Uint64() != 100 {
+		t.Fatalf("expected the approved update to be sent, got %d", price)
+	}
+}
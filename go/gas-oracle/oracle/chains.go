@@ -0,0 +1,163 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// chainNamePattern restricts the names of the chains to what can be used in
+// the name of a metric
+var chainNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Chains returns a config for each chain of the config file, which are run
+// by independent updaters in one process. A chain starts from the options of
+// the command line and the options of the chain take precedence. It returns
+// nil when the config file defines no chains.
+func (c *Config) Chains() ([]*Config, error) {
+	if len(c.chains) == 0 {
+		return nil, nil
+	}
+	// The chains would listen on the same addresses and write the same
+	// files. The admin API of the process serves the chains instead.
+	for _, shared := range []struct {
+		flag  string
+		value string
+	}{
+		{flags.AdminGRPCAddrFlag.Name, c.adminGRPCAddr},
+		{flags.PriceAPIAddrFlag.Name, c.priceAPIAddr},
+		{flags.StateDBFlag.Name, c.stateDBPath},
+		{flags.AuditLogFlag.Name, c.auditLogPath},
+	} {
+		if shared.value != "" {
+			return nil, fmt.Errorf("option %q cannot be used with the chains of the config file", shared.flag)
+		}
+	}
+	if c.shadow != nil {
+		return nil, fmt.Errorf("option %q cannot be used with the chains of the config file",
+			flags.ShadowPricerFlag.Name)
+	}
+
+	names := make(map[string]bool, len(c.chains))
+	configs := make([]*Config, 0, len(c.chains))
+	for i, chain := range c.chains {
+		if names[chain.Name] {
+			return nil, fmt.Errorf("chain %q is defined more than once", chain.Name)
+		}
+		names[chain.Name] = true
+		cfg, err := c.chainConfig(chain)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d: %w", i, err)
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// chainConfig returns the config of a chain, which starts from the options
+// of the command line
+func (c *Config) chainConfig(chain *chainConfig) (*Config, error) {
+	if !chainNamePattern.MatchString(chain.Name) {
+		return nil, fmt.Errorf("invalid chain name %q, use lower case letters, digits, - and _", chain.Name)
+	}
+	cfg := *c
+	cfg.chains = nil
+	cfg.chainName = chain.Name
+	cfg.adminAddr = ""
+	never := func(name string) bool { return false }
+	if err := chain.networkConfig.apply(&cfg, never); err != nil {
+		return nil, fmt.Errorf("chain %q: %w", chain.Name, err)
+	}
+	if chain.PrivateKeyFile != nil {
+		key, err := loadPrivateKeyFile(*chain.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("chain %q: %w", chain.Name, err)
+		}
+		cfg.privateKey = key
+	}
+	cfg.chainMetrics = newChainMetrics(chain.Name)
+	return &cfg, nil
+}
+
+// ChainName returns the name of the chain of the config file, empty when the
+// process runs a single chain
+func (c *Config) ChainName() string {
+	return c.chainName
+}
+
+// chainMetrics are the main metrics of a chain, labelled with its name, when
+// one process runs several chains. The package metrics are shared by the
+// chains, so their gauges hold the value of the chain that was updated last.
+// A nil chainMetrics records nothing.
+type chainMetrics struct {
+	gasPrice         metrics.Gauge
+	txSend           metrics.Counter
+	txNotSignificant metrics.Counter
+	epochs           metrics.Counter
+	epochDemand      metrics.GaugeFloat64
+	epochGasPrice    metrics.Gauge
+	paused           metrics.Gauge
+	signerBalance    metrics.GaugeFloat64
+}
+
+func newChainMetrics(name string) *chainMetrics {
+	r := ometrics.ChainRegistry(name)
+	return &chainMetrics{
+		gasPrice:         metrics.GetOrRegisterGauge("gas_price", r),
+		txSend:           metrics.GetOrRegisterCounter("tx/send", r),
+		txNotSignificant: metrics.GetOrRegisterCounter("tx/not_significant", r),
+		epochs:           metrics.GetOrRegisterCounter("epoch/count", r),
+		epochDemand:      metrics.GetOrRegisterGaugeFloat64("epoch/avg_gas_per_second", r),
+		epochGasPrice:    metrics.GetOrRegisterGauge("epoch/gas_price", r),
+		paused:           metrics.GetOrRegisterGauge("paused", r),
+		signerBalance:    metrics.GetOrRegisterGaugeFloat64("signer/balance", r),
+	}
+}
+
+// observeDecision exports the decision of an epoch
+func (m *chainMetrics) observeDecision(d *Decision) {
+	if m == nil {
+		return
+	}
+	m.epochs.Inc(1)
+	m.epochDemand.Update(d.AvgGasPerSecond)
+	m.epochGasPrice.Update(int64(d.GasPrice.Uint64()))
+	if d.Reason == ReasonUnchanged || d.Reason == ReasonBelowSignificance {
+		m.txNotSignificant.Inc(1)
+	}
+}
+
+// recordSent exports the gas price of a transaction that was sent
+func (m *chainMetrics) recordSent(gasPrice *big.Int) {
+	if m == nil {
+		return
+	}
+	m.txSend.Inc(1)
+	m.updateGasPrice(gasPrice)
+}
+
+// updateGasPrice exports the gas price of the contract
+func (m *chainMetrics) updateGasPrice(gasPrice *big.Int) {
+	if m == nil {
+		return
+	}
+	m.gasPrice.Update(int64(gasPrice.Uint64()))
+}
+
+func (m *chainMetrics) updatePaused(paused bool) {
+	if m == nil {
+		return
+	}
+	m.paused.Update(boolGauge(paused))
+}
+
+func (m *chainMetrics) updateSignerBalance(ether float64) {
+	if m == nil {
+		return
+	}
+	m.signerBalance.Update(ether)
+}
@@ -0,0 +1,170 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// transactionReader is implemented by backends that can look up a
+// transaction that is not mined yet
+type transactionReader interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+}
+
+// checkpoint keeps the state that is saved in the state database. Every
+// change is written as a whole state, so that a crash leaves either the
+// previous or the next checkpoint. The update transaction is checkpointed
+// after it is signed and before it is sent. In high availability mode the
+// leader also saves it with the lease, for the next leader.
+type checkpoint struct {
+	mu     sync.Mutex
+	db     *store.StateDB
+	leader *leaderElection
+	state  store.State
+}
+
+// newCheckpoint creates the checkpoint of the database and of the lease,
+// which starts from the saved state of the same chain and contract
+func newCheckpoint(cfg *Config, saved *store.State) *checkpoint {
+	if cfg.stateDB == nil && cfg.leader == nil {
+		return nil
+	}
+	c := &checkpoint{db: cfg.stateDB, leader: cfg.leader}
+	if saved != nil && sameDeployment(cfg, saved) {
+		c.state = *saved
+	}
+	return c
+}
+
+// update changes the state and saves it. An error is logged, since the
+// change has already happened.
+func (c *checkpoint) update(fn func(*store.State)) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(&c.state)
+	if c.db != nil {
+		if err := c.db.Save(&c.state); err != nil {
+			log.Error("cannot save checkpoint", "message", err)
+		}
+	}
+	c.leader.saveState(&c.state)
+}
+
+// adopt replaces the state with the checkpoint of the previous leader
+func (c *checkpoint) adopt(saved *store.State) {
+	c.update(func(s *store.State) {
+		*s = *saved
+	})
+}
+
+// pendingTransaction returns the hash of the update transaction that is not
+// confirmed yet, if any
+func (c *checkpoint) pendingTransaction() (common.Hash, bool) {
+	if c == nil {
+		return common.Hash{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state.PendingTransaction == nil {
+		return common.Hash{}, false
+	}
+	return common.HexToHash(c.state.PendingTransaction.Hash), true
+}
+
+// recordSigned checkpoints an update transaction before it is sent
+func (c *checkpoint) recordSigned(tx *types.Transaction, value *big.Int) {
+	c.update(func(s *store.State) {
+		s.PendingTransaction = &store.PendingTransaction{
+			Hash:     tx.Hash().Hex(),
+			Nonce:    tx.Nonce(),
+			GasPrice: value,
+			SignedAt: time.Now(),
+		}
+	})
+}
+
+// recordReceipt checkpoints the receipt of the pending update transaction
+func (c *checkpoint) recordReceipt(receipt *types.Receipt) {
+	c.update(func(s *store.State) {
+		settlePendingTransaction(s, receipt)
+	})
+}
+
+// settlePendingTransaction clears the pending transaction of the receipt,
+// which is the last sent gas price when it succeeded
+func settlePendingTransaction(s *store.State, receipt *types.Receipt) {
+	pending := s.PendingTransaction
+	if pending == nil || receipt == nil || common.HexToHash(pending.Hash) != receipt.TxHash {
+		return
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		s.LastSentGasPrice = pending.GasPrice
+	}
+	s.PendingTransaction = nil
+}
+
+// reconcileCheckpoint settles the pending transaction of a saved state
+// against the chain. The process may have died after signing the
+// transaction and before it was confirmed, so the transaction was either
+// mined, is still in the pool or never reached the node. A transaction that
+// was mined moves the gas price that is expected on chain, so that the
+// saved gas price of the gas pricer is restored.
+func reconcileCheckpoint(ctx context.Context, cfg *Config, backend DeployContractBackend, saved *store.State) error {
+	if saved == nil || saved.PendingTransaction == nil || !sameDeployment(cfg, saved) {
+		return nil
+	}
+	pending := saved.PendingTransaction
+	hash := common.HexToHash(pending.Hash)
+	receipt, err := backend.TransactionReceipt(ctx, hash)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return err
+	}
+	if receipt != nil {
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			log.Info("The checkpointed update transaction was confirmed", "hash", pending.Hash,
+				"gas-price", pending.GasPrice, "blocknumber", receipt.BlockNumber)
+			saved.OnChainGasPrice = pending.GasPrice
+		} else {
+			log.Warn("The checkpointed update transaction failed", "hash", pending.Hash,
+				"gas-price", pending.GasPrice, "blocknumber", receipt.BlockNumber)
+		}
+		settlePendingTransaction(saved, receipt)
+		return nil
+	}
+
+	if reader, ok := backend.(transactionReader); ok {
+		_, isPending, err := reader.TransactionByHash(ctx, hash)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		if err == nil && isPending {
+			log.Info("The checkpointed update transaction is still pending", "hash", pending.Hash,
+				"gas-price", pending.GasPrice, "nonce", pending.Nonce)
+			return nil
+		}
+	}
+	// The next epoch decides again from the restored gas pricer
+	log.Warn("The checkpointed update transaction never reached the node", "hash", pending.Hash,
+		"gas-price", pending.GasPrice, "nonce", pending.Nonce, "signed-at", pending.SignedAt)
+	saved.PendingTransaction = nil
+	return nil
+}
+
+// sameDeployment returns true when the state was saved for the chain and
+// the contract of the configuration
+func sameDeployment(cfg *Config, saved *store.State) bool {
+	return saved.ChainID != nil && cfg.l2ChainID != nil && saved.ChainID.Cmp(cfg.l2ChainID) == 0 &&
+		saved.Address == cfg.gasPriceOracleAddress.Hex()
+}
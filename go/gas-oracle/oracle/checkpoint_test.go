@@ -0,0 +1,108 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCheckpointPendingTransaction(t *testing.T) {
+	gpo, _, sim := newControlledOracle(t)
+	cfg := gpo.config
+	db, err := store.OpenStateDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.stateDB = db
+	cfg.checkpoint = newCheckpoint(cfg, nil)
+	defer cfg.closeStateDB()
+
+	// The transaction is checkpointed before it is sent
+	opts, _ := bind.NewKeyedTransactorWithChainID(cfg.privateKey, big.NewInt(1337))
+	tx, err := gpo.contract.SetGasPrice(opts, big.NewInt(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.checkpoint.recordSigned(tx, big.NewInt(20))
+	saved, err := db.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.PendingTransaction == nil || saved.PendingTransaction.Hash != tx.Hash().Hex() ||
+		saved.PendingTransaction.GasPrice.Uint64() != 20 {
+		t.Fatalf("unexpected checkpoint %+v", saved)
+	}
+
+	// A transaction that is not mined stays pending at the end of the epoch
+	gpo.saveState(context.Background(), big.NewInt(10))
+	if _, ok := cfg.checkpoint.pendingTransaction(); !ok {
+		t.Fatal("expected a pending transaction")
+	}
+	sim.Commit()
+	gpo.saveState(context.Background(), big.NewInt(20))
+	saved, _ = db.Load()
+	if saved.PendingTransaction != nil || saved.LastSentGasPrice.Uint64() != 20 || saved.OnChainGasPrice.Uint64() != 20 {
+		t.Fatalf("unexpected checkpoint %+v", saved)
+	}
+}
+
+func TestReconcileCheckpoint(t *testing.T) {
+	gpo, _, sim := newControlledOracle(t)
+	cfg := gpo.config
+	opts, _ := bind.NewKeyedTransactorWithChainID(cfg.privateKey, big.NewInt(1337))
+	checkpointOf := func(hash common.Hash) *store.State {
+		return &store.State{
+			ChainID:         big.NewInt(1337),
+			Address:         cfg.gasPriceOracleAddress.Hex(),
+			GasPrice:        big.NewInt(25),
+			OnChainGasPrice: big.NewInt(1),
+			PendingTransaction: &store.PendingTransaction{
+				Hash:     hash.Hex(),
+				GasPrice: big.NewInt(25),
+				SignedAt: time.Now(),
+			},
+		}
+	}
+
+	// The process died before the transaction was mined
+	tx, err := gpo.contract.SetGasPrice(opts, big.NewInt(25))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := checkpointOf(tx.Hash())
+	if err := reconcileCheckpoint(context.Background(), cfg, sim, saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.PendingTransaction == nil {
+		t.Fatal("expected the transaction in the pool to stay pending")
+	}
+
+	// The transaction was mined after the process died, so the gas price on
+	// chain is the gas price of the transaction
+	sim.Commit()
+	if err := reconcileCheckpoint(context.Background(), cfg, sim, saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.PendingTransaction != nil || saved.LastSentGasPrice.Uint64() != 25 || saved.OnChainGasPrice.Uint64() != 25 {
+		t.Fatalf("unexpected checkpoint %+v", saved)
+	}
+	restored := restoreState(&Config{l2ChainID: big.NewInt(1337), gasPriceOracleAddress: cfg.gasPriceOracleAddress},
+		saved, 10, big.NewInt(25), time.Now())
+	if restored.gasPrice == nil || restored.gasPrice.Uint64() != 25 {
+		t.Fatalf("expected the gas price to be restored, got %+v", restored)
+	}
+
+	// The process died after signing and before sending
+	saved = checkpointOf(common.HexToHash("0x01"))
+	if err := reconcileCheckpoint(context.Background(), cfg, sim, saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.PendingTransaction != nil || saved.LastSentGasPrice != nil {
+		t.Fatalf("unexpected checkpoint %+v", saved)
+	}
+}
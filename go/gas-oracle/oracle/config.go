@@ -2,78 +2,432 @@ package oracle
 
 import (
 	"crypto/ecdsa"
+	"crypto/tls"
 	"fmt"
+	"math"
 	"math/big"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/leader"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/publish"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli"
 )
 
 // Config represents the configuration options for the gas oracle
 type Config struct {
-	l1ChainID                    *big.Int
-	l2ChainID                    *big.Int
-	ethereumHttpUrl              string
-	layerTwoHttpUrl              string
-	gasPriceOracleAddress        common.Address
-	privateKey                   *ecdsa.PrivateKey
-	gasPrice                     *big.Int
-	waitForReceipt               bool
-	floorPrice                   uint64
+	l1ChainID       *big.Int
+	l2ChainID       *big.Int
+	ethereumHttpUrl string
+	layerTwoHttpUrl string
+	// rpcTLS has the client certificate of the endpoints, it is nil without
+	// mutual TLS
+	rpcTLS                *tls.Config
+	gasPriceOracleAddress common.Address
+	privateKey            *ecdsa.PrivateKey
+	gasPrice              *big.Int
+	waitForReceipt        bool
+	dryRun                bool
+	oneShot               bool
+	adminAddr             string
+	adminGRPCAddr         string
+	adminToken            string
+	adminJWTSecret        []byte
+	adminBindAllowlist    []string
+	// A second operator approves the staged updates with the approval token
+	approvalToken string
+	approvalTTL   time.Duration
+	approvals     *approvals
+	priceAPIAddr  string
+	controls      *controls
+	floorPrice    *big.Int
+	maxGasPrice   *big.Int
+	// The sanity bounds are checked before an update is sent, independently
+	// of the pricer, and refuse the update rather than clamp it
+	sanityMinGasPrice            *big.Int
+	sanityMaxGasPrice            *big.Int
 	targetGasPerSecond           uint64
+	targetGasSchedule            []gasprices.TargetGasWindow
 	maxPercentChangePerEpoch     float64
+	dailyPriceChangeBudget       float64
 	averageBlockGasLimitPerEpoch uint64
-	epochLengthSeconds           uint64
-	l1BaseFeeEpochLengthSeconds  uint64
+	epochLength                  time.Duration
+	minUpdateInterval            time.Duration
+	l1BaseFeeEpochLength         time.Duration
 	l2GasPriceSignificanceFactor float64
 	l1BaseFeeSignificanceFactor  float64
+	gasPriceRounding             *gasprices.Rounding
+	pricer                       string
 	enableL1BaseFee              bool
 	enableL2GasPrice             bool
+	enableSurgePricing           bool
+	surgeThreshold               float64
+	surgeEpochs                  uint64
+	surgeFactor                  float64
+	surgeMaxMultiplier           float64
+	surgeDecayRate               float64
+	enableIdleDecay              bool
+	idleDecayThreshold           float64
+	idleDecayEpochs              uint64
+	idleDecayRate                float64
+
+	// Optional directional significance factors that take precedence over
+	// the l2GasPriceSignificanceFactor
+	l2GasPriceIncreaseSignificanceFactor *float64
+	l2GasPriceDecreaseSignificanceFactor *float64
+	// Scales the l2GasPriceSignificanceFactor with demand volatility
+	adaptiveSignificance *gasprices.AdaptiveSignificance
+
+	// Weights of the resources used by blocks in the demand
+	demandWeights gasprices.DemandWeights
+	// The demand is measured up to the block of the tag minus the
+	// confirmations rather than up to the tip
+	demandBlockTag      string
+	demandConfirmations uint64
+	// Completes an epoch whose blocks were reorged away with the demand of
+	// the last epoch
+	reorgReuseDemand bool
+	// Clamps the demand of anomalous epochs
+	enableOutlierRejection bool
+	outlierMethod          gasprices.OutlierMethod
+	outlierWindow          uint64
+	outlierMinSamples      uint64
+	outlierThreshold       float64
+	// Combines the demand of recent epochs
+	smoothingEpochs uint64
+	smoothingMethod gasprices.SmoothingMethod
+	// Blends the sequencer's transaction pool into the demand
+	enableTxPoolSignal bool
+	txPoolSignalWeight float64
+	txPoolQueuedWeight float64
+	txPoolGasPerTx     uint64
+	// Monitors the balance of the signer
+	balanceCheckInterval time.Duration
+	lowBalanceThreshold  *big.Int
+	lowBalanceWebhookURL string
+	// Monitors the revenue of the sequencer fee vault
+	feeVaultAddress  common.Address
+	feeVaultInterval time.Duration
+	// Notifies external systems of updates
+	webhookURLs          []string
+	slackWebhookURL      string
+	discordWebhookURL    string
+	chatMinPriceChange   float64
+	chatFailureThreshold uint64
+	// Pages on critical failures
+	pagerDutyURL              string
+	pagerDutyRoutingKey       string
+	pagerDutyFailureThreshold uint64
+	notifier                  *notify.Dispatcher
+	// Publishes the decisions and the sent transactions to a message bus
+	publishURL   string
+	publishTopic string
+	publisher    *publish.Dispatcher
+	// Records the epochs, transactions and receipts in a database
+	postgresURL string
+	// Records every update transaction that is sent
+	auditLogPath string
+	auditLog     *auditLog
+	// Keeps the epoch and the gas pricer across restarts
+	stateDBPath string
+	stateDB     *store.StateDB
+	checkpoint  *checkpoint
+	// Signs the decisions that are recorded in the state database
+	decisionSigner *decisionSigner
+	// How many recent blocks are scanned for updates on startup
+	warmStartBlocks uint64
+	warmStart       *warmStart
+	// Converts the gas price to a custom gas token
+	gasTokenRateFeed   common.Address
+	gasTokenMaxRateAge time.Duration
+	gasToken           *gasTokenRate
+	// How often the implementation of a proxied gas price oracle is checked
+	implementationCheckInterval time.Duration
+	// How often the owner of the updated contract is checked
+	ownerCheckInterval time.Duration
+	// How long the price history is kept in the state database
+	priceHistoryRetention time.Duration
+	// Detects gas prices set outside of the oracle
+	watchExternalUpdates bool
+	updateWatcher        *updateWatcher
+	// Resynchronizes the gas pricer when it drifts from the chain
+	driftTolerance float64
+	// Pings a dead man's switch after every successful epoch
+	heartbeatURL string
+	heartbeat    *heartbeat
+	// Elects the replica that sends transactions in high availability mode
+	leaseURL  string
+	leaseName string
+	leaseTTL  time.Duration
+	leaseTLS  *tls.Config
+	replicaID string
+	leader    *leaderElection
+	// Cancels and restarts epochs that exceed the deadline
+	epochDeadline time.Duration
+	// Raises an alarm when no update lands while the demand changes
+	staleUpdateEpochs       uint64
+	staleUpdateDemandChange float64
+	staleUpdates            *staleUpdateMonitor
+	// Verify the chain IDs of the endpoints after they reconnect, they are
+	// nil when the backends were not dialed from the URLs
+	l1ChainIDGuard *chainIDGuard
+	l2ChainIDGuard *chainIDGuard
+
+	// Sets the gas config in the L1 SystemConfig of a Bedrock chain
+	systemConfigAddress  common.Address
+	systemConfigOverhead uint64
+	systemConfigScalar   uint64
+	systemConfigInterval time.Duration
+	// The overhead and the scalar are not set when they are managed manually
+	enableGasConfig bool
+	// The operator fee of the SystemConfig, which is only set when one of
+	// its parameters is configured
+	enableOperatorFee             bool
+	operatorFeeScalar             uint64
+	operatorFeeConstant           uint64
+	maxOperatorFeeScalar          uint64
+	maxOperatorFeeConstant        uint64
+	operatorFeeSignificanceFactor float64
+	l1TxResubmitTimeout           time.Duration
+	l1TxMaxFee                    *big.Int
+
+	// The Safe that owns the gas price oracle, the updates are signed by
+	// one of its owners
+	safeAddress    common.Address
+	safeServiceURL string
+
+	// The pricer that bounds the pricer during a canary rollout
+	canaryIncumbentPricer string
+	canaryMaxDelta        float64
+	// A second pricer configuration that runs in shadow mode
+	shadow *Config
+	// A signer that is used instead of the private key, such as a remote
+	// signer of a program that embeds the oracle
+	signerAddress common.Address
+	signerFn      bind.SignerFn
+	// The chains of the config file that run in one process, and the name
+	// and the labelled metrics of a chain in its own config. The chains that
+	// are registered at runtime are written to the config file.
+	configFile   string
+	chains       []*chainConfig
+	chainName    string
+	chainMetrics *chainMetrics
+
 	// Metrics config
-	MetricsEnabled          bool
-	MetricsHTTP             string
-	MetricsPort             int
-	MetricsEnableInfluxDB   bool
-	MetricsInfluxDBEndpoint string
-	MetricsInfluxDBDatabase string
-	MetricsInfluxDBUsername string
-	MetricsInfluxDBPassword string
-}
-
-// NewConfig creates a new Config
-func NewConfig(ctx *cli.Context) *Config {
+	MetricsEnabled              bool
+	MetricsHTTP                 string
+	MetricsPort                 int
+	MetricsBackend              string
+	MetricsStatsDAddress        string
+	MetricsStatsDPrefix         string
+	MetricsStatsDTags           []string
+	MetricsCloudWatchNamespace  string
+	MetricsCloudWatchRegion     string
+	MetricsCloudWatchDimensions []string
+	MetricsPushgatewayURL       string
+	MetricsPushgatewayJob       string
+	MetricsEnableInfluxDB       bool
+	MetricsInfluxDBEndpoint     string
+	MetricsInfluxDBDatabase     string
+	MetricsInfluxDBUsername     string
+	MetricsInfluxDBPassword     string
+	// Tracing config
+	TracingOTLPEndpoint string
+	TracingOTLPInsecure bool
+	TracingSampleRatio  float64
+	// Error reporting config
+	SentryDSN              string
+	SentryEnvironment      string
+	sentryFailureThreshold uint64
+}
+
+// NewConfig creates a new Config from the command line options. Options
+// that cannot be parsed are returned as errors, the configuration as a whole
+// is checked by Validate.
+func NewConfig(ctx *cli.Context) (*Config, error) {
 	cfg := Config{}
+	var warnings configWarnings
 	cfg.ethereumHttpUrl = ctx.GlobalString(flags.EthereumHttpUrlFlag.Name)
 	cfg.layerTwoHttpUrl = ctx.GlobalString(flags.LayerTwoHttpUrlFlag.Name)
 	addr := ctx.GlobalString(flags.GasPriceOracleAddressFlag.Name)
 	cfg.gasPriceOracleAddress = common.HexToAddress(addr)
 	cfg.targetGasPerSecond = ctx.GlobalUint64(flags.TargetGasPerSecondFlag.Name)
 	cfg.maxPercentChangePerEpoch = ctx.GlobalFloat64(flags.MaxPercentChangePerEpochFlag.Name)
+	cfg.dailyPriceChangeBudget = ctx.GlobalFloat64(flags.DailyPriceChangeBudgetFlag.Name)
 	cfg.averageBlockGasLimitPerEpoch = ctx.GlobalUint64(flags.AverageBlockGasLimitPerEpochFlag.Name)
-	cfg.epochLengthSeconds = ctx.GlobalUint64(flags.EpochLengthSecondsFlag.Name)
-	cfg.l1BaseFeeEpochLengthSeconds = ctx.GlobalUint64(flags.L1BaseFeeEpochLengthSecondsFlag.Name)
+	cfg.epochLength = durationFlag(ctx, &warnings, flags.EpochLengthFlag, flags.EpochLengthSecondsFlag)
+	cfg.minUpdateInterval = durationFlag(ctx, &warnings, flags.MinUpdateIntervalFlag, flags.MinUpdateIntervalSecondsFlag)
+	cfg.l1BaseFeeEpochLength = durationFlag(ctx, &warnings, flags.L1BaseFeeEpochLengthFlag, flags.L1BaseFeeEpochLengthSecondsFlag)
 	cfg.l2GasPriceSignificanceFactor = ctx.GlobalFloat64(flags.L2GasPriceSignificanceFactorFlag.Name)
-	cfg.floorPrice = ctx.GlobalUint64(flags.FloorPriceFlag.Name)
+	if ctx.GlobalIsSet(flags.L2GasPriceIncreaseSignificanceFactorFlag.Name) {
+		factor := ctx.GlobalFloat64(flags.L2GasPriceIncreaseSignificanceFactorFlag.Name)
+		cfg.l2GasPriceIncreaseSignificanceFactor = &factor
+	}
+	if ctx.GlobalIsSet(flags.L2GasPriceDecreaseSignificanceFactorFlag.Name) {
+		factor := ctx.GlobalFloat64(flags.L2GasPriceDecreaseSignificanceFactorFlag.Name)
+		cfg.l2GasPriceDecreaseSignificanceFactor = &factor
+	}
+	if ctx.GlobalBool(flags.EnableAdaptiveSignificanceFlag.Name) {
+		adaptive, err := gasprices.NewAdaptiveSignificance(
+			ctx.GlobalUint64(flags.AdaptiveSignificanceEpochsFlag.Name),
+			ctx.GlobalFloat64(flags.AdaptiveSignificanceMinFactorFlag.Name),
+			ctx.GlobalFloat64(flags.AdaptiveSignificanceMaxFactorFlag.Name),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", flags.EnableAdaptiveSignificanceFlag.Name, err)
+		}
+		cfg.adaptiveSignificance = adaptive
+	}
+	floorPrice, err := weiFlag(ctx, flags.FloorPriceFlag)
+	if err != nil {
+		return nil, err
+	}
+	cfg.floorPrice = floorPrice
 	cfg.l1BaseFeeSignificanceFactor = ctx.GlobalFloat64(flags.L1BaseFeeSignificanceFactorFlag.Name)
 	cfg.enableL1BaseFee = ctx.GlobalBool(flags.EnableL1BaseFeeFlag.Name)
 	cfg.enableL2GasPrice = ctx.GlobalBool(flags.EnableL2GasPriceFlag.Name)
+	cfg.enableSurgePricing = ctx.GlobalBool(flags.EnableSurgePricingFlag.Name)
+	cfg.surgeThreshold = ctx.GlobalFloat64(flags.SurgeThresholdFlag.Name)
+	cfg.surgeEpochs = ctx.GlobalUint64(flags.SurgeEpochsFlag.Name)
+	cfg.surgeFactor = ctx.GlobalFloat64(flags.SurgeFactorFlag.Name)
+	cfg.surgeMaxMultiplier = ctx.GlobalFloat64(flags.SurgeMaxMultiplierFlag.Name)
+	cfg.surgeDecayRate = ctx.GlobalFloat64(flags.SurgeDecayRateFlag.Name)
+	cfg.enableIdleDecay = ctx.GlobalBool(flags.EnableIdleDecayFlag.Name)
+	cfg.idleDecayThreshold = ctx.GlobalFloat64(flags.IdleDecayThresholdFlag.Name)
+	cfg.idleDecayEpochs = ctx.GlobalUint64(flags.IdleDecayEpochsFlag.Name)
+	cfg.idleDecayRate = ctx.GlobalFloat64(flags.IdleDecayRateFlag.Name)
+	cfg.demandWeights = gasprices.DemandWeights{
+		Gas:          ctx.GlobalFloat64(flags.DemandGasWeightFlag.Name),
+		Tx:           ctx.GlobalFloat64(flags.DemandTxWeightFlag.Name),
+		CalldataByte: ctx.GlobalFloat64(flags.DemandCalldataWeightFlag.Name),
+	}
+	if err := cfg.demandWeights.Validate(); err != nil {
+		return nil, fmt.Errorf("option %q: %w", flags.DemandGasWeightFlag.Name, err)
+	}
+	cfg.demandBlockTag = ctx.GlobalString(flags.DemandBlockTagFlag.Name)
+	cfg.demandConfirmations = ctx.GlobalUint64(flags.DemandConfirmationsFlag.Name)
+	cfg.reorgReuseDemand = ctx.GlobalBool(flags.ReorgReuseDemandFlag.Name)
+	cfg.enableOutlierRejection = ctx.GlobalBool(flags.EnableOutlierRejectionFlag.Name)
+	cfg.outlierMethod = gasprices.OutlierMethod(ctx.GlobalString(flags.OutlierMethodFlag.Name))
+	cfg.outlierWindow = ctx.GlobalUint64(flags.OutlierWindowFlag.Name)
+	cfg.outlierMinSamples = ctx.GlobalUint64(flags.OutlierMinSamplesFlag.Name)
+	cfg.outlierThreshold = ctx.GlobalFloat64(flags.OutlierThresholdFlag.Name)
+	cfg.smoothingEpochs = ctx.GlobalUint64(flags.SmoothingEpochsFlag.Name)
+	cfg.smoothingMethod = gasprices.SmoothingMethod(ctx.GlobalString(flags.SmoothingMethodFlag.Name))
+	cfg.enableTxPoolSignal = ctx.GlobalBool(flags.EnableTxPoolSignalFlag.Name)
+	cfg.txPoolSignalWeight = ctx.GlobalFloat64(flags.TxPoolSignalWeightFlag.Name)
+	cfg.txPoolQueuedWeight = ctx.GlobalFloat64(flags.TxPoolQueuedWeightFlag.Name)
+	cfg.txPoolGasPerTx = ctx.GlobalUint64(flags.TxPoolGasPerTxFlag.Name)
+	cfg.balanceCheckInterval = durationFlag(ctx, &warnings, flags.BalanceCheckIntervalFlag, flags.BalanceCheckIntervalSecondsFlag)
+	if ctx.GlobalIsSet(flags.LowBalanceThresholdFlag.Name) {
+		threshold, err := weiFlag(ctx, flags.LowBalanceThresholdFlag)
+		if err != nil {
+			return nil, err
+		}
+		cfg.lowBalanceThreshold = threshold
+	}
+	cfg.lowBalanceWebhookURL = ctx.GlobalString(flags.LowBalanceWebhookURLFlag.Name)
+	cfg.webhookURLs = ctx.GlobalStringSlice(flags.WebhookURLFlag.Name)
+	cfg.slackWebhookURL = ctx.GlobalString(flags.SlackWebhookURLFlag.Name)
+	cfg.discordWebhookURL = ctx.GlobalString(flags.DiscordWebhookURLFlag.Name)
+	cfg.chatMinPriceChange = ctx.GlobalFloat64(flags.ChatMinPriceChangeFlag.Name)
+	cfg.chatFailureThreshold = ctx.GlobalUint64(flags.ChatFailureThresholdFlag.Name)
+	cfg.pagerDutyURL = ctx.GlobalString(flags.PagerDutyURLFlag.Name)
+	cfg.pagerDutyRoutingKey = ctx.GlobalString(flags.PagerDutyRoutingKeyFlag.Name)
+	cfg.pagerDutyFailureThreshold = ctx.GlobalUint64(flags.PagerDutyFailureThresholdFlag.Name)
+	cfg.publishURL = ctx.GlobalString(flags.PublishURLFlag.Name)
+	cfg.publishTopic = ctx.GlobalString(flags.PublishTopicFlag.Name)
+	cfg.postgresURL = ctx.GlobalString(flags.PostgresURLFlag.Name)
+	cfg.auditLogPath = ctx.GlobalString(flags.AuditLogFlag.Name)
+	cfg.stateDBPath = ctx.GlobalString(flags.StateDBFlag.Name)
+	cfg.priceHistoryRetention = ctx.GlobalDuration(flags.PriceHistoryRetentionFlag.Name)
+	cfg.warmStartBlocks = ctx.GlobalUint64(flags.WarmStartBlocksFlag.Name)
+	cfg.implementationCheckInterval = ctx.GlobalDuration(flags.ImplementationCheckIntervalFlag.Name)
+	cfg.ownerCheckInterval = ctx.GlobalDuration(flags.OwnerCheckIntervalFlag.Name)
+	cfg.watchExternalUpdates = ctx.GlobalBool(flags.WatchExternalUpdatesFlag.Name)
+	cfg.driftTolerance = ctx.GlobalFloat64(flags.DriftToleranceFlag.Name)
+	cfg.heartbeatURL = ctx.GlobalString(flags.HeartbeatURLFlag.Name)
+	cfg.leaseURL = ctx.GlobalString(flags.HALeaseURLFlag.Name)
+	cfg.leaseName = ctx.GlobalString(flags.HALeaseNameFlag.Name)
+	cfg.leaseTTL = ctx.GlobalDuration(flags.HALeaseTTLFlag.Name)
+	if caFile := ctx.GlobalString(flags.HALeaseTLSCAFlag.Name); caFile != "" {
+		leaseTLS, err := loadRPCTLSConfig("", "", caFile)
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", flags.HALeaseTLSCAFlag.Name, err)
+		}
+		cfg.leaseTLS = leaseTLS
+	}
+	cfg.replicaID = ctx.GlobalString(flags.HAReplicaIDFlag.Name)
+	cfg.epochDeadline = ctx.GlobalDuration(flags.EpochDeadlineFlag.Name)
+	cfg.staleUpdateEpochs = ctx.GlobalUint64(flags.StaleUpdateEpochsFlag.Name)
+	cfg.staleUpdateDemandChange = ctx.GlobalFloat64(flags.StaleUpdateDemandChangeFlag.Name)
 
+	increment, err := weiFlag(ctx, flags.GasPriceRoundingIncrementFlag)
+	if err != nil {
+		return nil, err
+	}
+	rounding, err := gasprices.NewRounding(
+		gasprices.RoundingMode(ctx.GlobalString(flags.GasPriceRoundingFlag.Name)),
+		increment,
+		ctx.GlobalUint64(flags.GasPriceRoundingDigitsFlag.Name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("option %q: %w", flags.GasPriceRoundingFlag.Name, err)
+	}
+	cfg.gasPriceRounding = rounding
+
+	var fileCfg *fileConfig
+	if ctx.GlobalIsSet(flags.ConfigFileFlag.Name) {
+		path := ctx.GlobalString(flags.ConfigFileFlag.Name)
+		fileCfg, err = loadConfigFile(path, &warnings)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load config file %s: %w", path, err)
+		}
+		schedule, err := fileCfg.targetGasSchedule()
+		if err != nil {
+			return nil, fmt.Errorf("invalid target gas schedule in %s: %w", path, err)
+		}
+		cfg.targetGasSchedule = schedule
+		cfg.configFile = path
+		cfg.chains = fileCfg.Chains
+	}
+
+	if ctx.GlobalIsSet(flags.PrivateKeyFlag.Name) && ctx.GlobalIsSet(flags.PrivateKeyFileFlag.Name) {
+		return nil, fmt.Errorf("options %q and %q cannot be used together", flags.PrivateKeyFlag.Name,
+			flags.PrivateKeyFileFlag.Name)
+	}
 	if ctx.GlobalIsSet(flags.PrivateKeyFlag.Name) {
-		hex := ctx.GlobalString(flags.PrivateKeyFlag.Name)
-		hex = strings.TrimPrefix(hex, "0x")
-		key, err := crypto.HexToECDSA(hex)
+		key, err := parsePrivateKey(ctx.GlobalString(flags.PrivateKeyFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", flags.PrivateKeyFlag.Name, err)
+		}
+		cfg.privateKey = key
+	}
+	if ctx.GlobalIsSet(flags.PrivateKeyFileFlag.Name) {
+		key, err := loadPrivateKeyFile(ctx.GlobalString(flags.PrivateKeyFileFlag.Name))
 		if err != nil {
-			log.Error(fmt.Sprintf("Option %q: %v", flags.PrivateKeyFlag.Name, err))
+			return nil, fmt.Errorf("option %q: %w", flags.PrivateKeyFileFlag.Name, err)
 		}
 		cfg.privateKey = key
-	} else {
-		log.Crit("No private key configured")
 	}
 
+	rpcTLS, err := loadRPCTLSConfig(ctx.GlobalString(flags.RPCTLSCertFlag.Name),
+		ctx.GlobalString(flags.RPCTLSKeyFlag.Name), ctx.GlobalString(flags.RPCTLSCAFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("option %q: %w", flags.RPCTLSCertFlag.Name, err)
+	}
+	cfg.rpcTLS = rpcTLS
+
 	if ctx.GlobalIsSet(flags.L1ChainIDFlag.Name) {
 		chainID := ctx.GlobalUint64(flags.L1ChainIDFlag.Name)
 		cfg.l1ChainID = new(big.Int).SetUint64(chainID)
@@ -84,22 +438,507 @@ func NewConfig(ctx *cli.Context) *Config {
 	}
 
 	if ctx.GlobalIsSet(flags.TransactionGasPriceFlag.Name) {
-		gasPrice := ctx.GlobalUint64(flags.TransactionGasPriceFlag.Name)
-		cfg.gasPrice = new(big.Int).SetUint64(gasPrice)
+		gasPrice, err := weiFlag(ctx, flags.TransactionGasPriceFlag)
+		if err != nil {
+			return nil, err
+		}
+		cfg.gasPrice = gasPrice
+	}
+
+	if ctx.GlobalIsSet(flags.MaxGasPriceFlag.Name) {
+		maxGasPrice, err := weiFlag(ctx, flags.MaxGasPriceFlag)
+		if err != nil {
+			return nil, err
+		}
+		cfg.maxGasPrice = maxGasPrice
+	}
+	for _, bound := range []struct {
+		flag cli.StringFlag
+		dst  **big.Int
+	}{
+		{flags.SanityMinGasPriceFlag, &cfg.sanityMinGasPrice},
+		{flags.SanityMaxGasPriceFlag, &cfg.sanityMaxGasPrice},
+	} {
+		if !ctx.GlobalIsSet(bound.flag.Name) {
+			continue
+		}
+		price, err := weiFlag(ctx, bound.flag)
+		if err != nil {
+			return nil, err
+		}
+		*bound.dst = price
+	}
+
+	if ctx.GlobalIsSet(flags.SystemConfigAddressFlag.Name) {
+		cfg.systemConfigAddress = common.HexToAddress(ctx.GlobalString(flags.SystemConfigAddressFlag.Name))
+	}
+	if ctx.GlobalIsSet(flags.SafeAddressFlag.Name) {
+		cfg.safeAddress = common.HexToAddress(ctx.GlobalString(flags.SafeAddressFlag.Name))
+	}
+	cfg.safeServiceURL = ctx.GlobalString(flags.SafeServiceURLFlag.Name)
+	if ctx.GlobalIsSet(flags.GasTokenRateFeedFlag.Name) {
+		cfg.gasTokenRateFeed = common.HexToAddress(ctx.GlobalString(flags.GasTokenRateFeedFlag.Name))
+	}
+	cfg.gasTokenMaxRateAge = ctx.GlobalDuration(flags.GasTokenMaxRateAgeFlag.Name)
+	if ctx.GlobalIsSet(flags.FeeVaultAddressFlag.Name) {
+		cfg.feeVaultAddress = common.HexToAddress(ctx.GlobalString(flags.FeeVaultAddressFlag.Name))
+	}
+	cfg.feeVaultInterval = ctx.GlobalDuration(flags.FeeVaultIntervalFlag.Name)
+	cfg.systemConfigOverhead = ctx.GlobalUint64(flags.SystemConfigOverheadFlag.Name)
+	cfg.systemConfigScalar = ctx.GlobalUint64(flags.SystemConfigScalarFlag.Name)
+	cfg.systemConfigInterval = ctx.GlobalDuration(flags.SystemConfigIntervalFlag.Name)
+	cfg.enableGasConfig = ctx.GlobalBoolT(flags.SystemConfigEnableGasConfigFlag.Name)
+	cfg.enableOperatorFee = ctx.GlobalIsSet(flags.SystemConfigOperatorFeeScalarFlag.Name) ||
+		ctx.GlobalIsSet(flags.SystemConfigOperatorFeeConstantFlag.Name)
+	cfg.operatorFeeScalar = ctx.GlobalUint64(flags.SystemConfigOperatorFeeScalarFlag.Name)
+	cfg.operatorFeeConstant = ctx.GlobalUint64(flags.SystemConfigOperatorFeeConstantFlag.Name)
+	cfg.maxOperatorFeeScalar = ctx.GlobalUint64(flags.SystemConfigMaxOperatorFeeScalarFlag.Name)
+	cfg.maxOperatorFeeConstant = ctx.GlobalUint64(flags.SystemConfigMaxOperatorFeeConstantFlag.Name)
+	cfg.operatorFeeSignificanceFactor = ctx.GlobalFloat64(flags.SystemConfigOperatorFeeSignificanceFactorFlag.Name)
+	cfg.l1TxResubmitTimeout = ctx.GlobalDuration(flags.L1TxResubmitTimeoutFlag.Name)
+	if ctx.GlobalIsSet(flags.L1TxMaxFeeFlag.Name) {
+		maxFee, err := weiFlag(ctx, flags.L1TxMaxFeeFlag)
+		if err != nil {
+			return nil, err
+		}
+		cfg.l1TxMaxFee = maxFee
 	}
 
 	if ctx.GlobalIsSet(flags.WaitForReceiptFlag.Name) {
 		cfg.waitForReceipt = true
 	}
+	cfg.dryRun = ctx.GlobalBool(flags.DryRunFlag.Name)
+	// A one-shot run exits after its update, so it must see it confirmed
+	cfg.oneShot = ctx.GlobalBool(flags.OneShotFlag.Name)
+	if cfg.oneShot {
+		cfg.waitForReceipt = true
+	}
+	cfg.adminAddr = ctx.GlobalString(flags.AdminAddrFlag.Name)
+	cfg.adminGRPCAddr = ctx.GlobalString(flags.AdminGRPCAddrFlag.Name)
+	cfg.adminToken = ctx.GlobalString(flags.AdminTokenFlag.Name)
+	if ctx.GlobalIsSet(flags.AdminJWTSecretFileFlag.Name) {
+		secret, err := loadJWTSecret(ctx.GlobalString(flags.AdminJWTSecretFileFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", flags.AdminJWTSecretFileFlag.Name, err)
+		}
+		cfg.adminJWTSecret = secret
+	}
+	cfg.approvalToken = ctx.GlobalString(flags.ApprovalTokenFlag.Name)
+	cfg.approvalTTL = ctx.GlobalDuration(flags.ApprovalTTLFlag.Name)
+	cfg.adminBindAllowlist = ctx.GlobalStringSlice(flags.AdminBindAllowlistFlag.Name)
+	if len(cfg.adminBindAllowlist) == 0 {
+		cfg.adminBindAllowlist = defaultAdminBindAllowlist
+	}
+	cfg.priceAPIAddr = ctx.GlobalString(flags.PriceAPIAddrFlag.Name)
+	cfg.pricer = ctx.GlobalString(flags.PricerFlag.Name)
+	cfg.canaryIncumbentPricer = ctx.GlobalString(flags.CanaryIncumbentPricerFlag.Name)
+	cfg.canaryMaxDelta = ctx.GlobalFloat64(flags.CanaryMaxDeltaFlag.Name)
 
 	cfg.MetricsEnabled = ctx.GlobalBool(flags.MetricsEnabledFlag.Name)
 	cfg.MetricsHTTP = ctx.GlobalString(flags.MetricsHTTPFlag.Name)
 	cfg.MetricsPort = ctx.GlobalInt(flags.MetricsPortFlag.Name)
+	cfg.MetricsBackend = ctx.GlobalString(flags.MetricsBackendFlag.Name)
+	cfg.MetricsStatsDAddress = ctx.GlobalString(flags.MetricsStatsDAddressFlag.Name)
+	cfg.MetricsStatsDPrefix = ctx.GlobalString(flags.MetricsStatsDPrefixFlag.Name)
+	cfg.MetricsStatsDTags = ctx.GlobalStringSlice(flags.MetricsStatsDTagsFlag.Name)
+	cfg.MetricsCloudWatchNamespace = ctx.GlobalString(flags.MetricsCloudWatchNamespaceFlag.Name)
+	cfg.MetricsCloudWatchRegion = ctx.GlobalString(flags.MetricsCloudWatchRegionFlag.Name)
+	cfg.MetricsCloudWatchDimensions = ctx.GlobalStringSlice(flags.MetricsCloudWatchDimensionsFlag.Name)
+	cfg.MetricsPushgatewayURL = ctx.GlobalString(flags.MetricsPushgatewayURLFlag.Name)
+	cfg.MetricsPushgatewayJob = ctx.GlobalString(flags.MetricsPushgatewayJobFlag.Name)
 	cfg.MetricsEnableInfluxDB = ctx.GlobalBool(flags.MetricsEnableInfluxDBFlag.Name)
 	cfg.MetricsInfluxDBEndpoint = ctx.GlobalString(flags.MetricsInfluxDBEndpointFlag.Name)
 	cfg.MetricsInfluxDBDatabase = ctx.GlobalString(flags.MetricsInfluxDBDatabaseFlag.Name)
 	cfg.MetricsInfluxDBUsername = ctx.GlobalString(flags.MetricsInfluxDBUsernameFlag.Name)
 	cfg.MetricsInfluxDBPassword = ctx.GlobalString(flags.MetricsInfluxDBPasswordFlag.Name)
+	cfg.TracingOTLPEndpoint = ctx.GlobalString(flags.TracingOTLPEndpointFlag.Name)
+	cfg.TracingOTLPInsecure = ctx.GlobalBool(flags.TracingOTLPInsecureFlag.Name)
+	cfg.TracingSampleRatio = ctx.GlobalFloat64(flags.TracingSampleRatioFlag.Name)
+	cfg.SentryDSN = ctx.GlobalString(flags.SentryDSNFlag.Name)
+	cfg.SentryEnvironment = ctx.GlobalString(flags.SentryEnvironmentFlag.Name)
+	cfg.sentryFailureThreshold = ctx.GlobalUint64(flags.SentryFailureThresholdFlag.Name)
+
+	if ctx.GlobalIsSet(flags.NetworkFlag.Name) {
+		name := ctx.GlobalString(flags.NetworkFlag.Name)
+		network, err := lookupNetwork(fileCfg, name)
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", flags.NetworkFlag.Name, err)
+		}
+		if err := network.apply(&cfg, ctx.GlobalIsSet); err != nil {
+			return nil, fmt.Errorf("network %q: %w", name, err)
+		}
+	}
+
+	// The shadow pricer inherits everything that is not overridden
+	if ctx.GlobalIsSet(flags.ShadowPricerFlag.Name) {
+		shadow := cfg
+		shadow.pricer = ctx.GlobalString(flags.ShadowPricerFlag.Name)
+		shadow.canaryIncumbentPricer = ""
+		if ctx.GlobalIsSet(flags.ShadowTargetGasPerSecondFlag.Name) {
+			shadow.targetGasPerSecond = ctx.GlobalUint64(flags.ShadowTargetGasPerSecondFlag.Name)
+			shadow.targetGasSchedule = nil
+		}
+		if ctx.GlobalIsSet(flags.ShadowMaxPercentChangePerEpochFlag.Name) {
+			shadow.maxPercentChangePerEpoch = ctx.GlobalFloat64(flags.ShadowMaxPercentChangePerEpochFlag.Name)
+		}
+		cfg.shadow = &shadow
+	}
+
+	warnings = append(warnings, cfg.suspicious()...)
+	if err := warnings.check(ctx.GlobalBool(flags.StrictConfigFlag.Name)); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that the configuration can run the oracle. The chain IDs
+// are checked against the nodes when the oracle is created.
+func (c *Config) Validate() error {
+	if c.layerTwoHttpUrl == "" {
+		return fmt.Errorf("option %q: no L2 HTTP endpoint provided", flags.LayerTwoHttpUrlFlag.Name)
+	}
+	if c.ethereumHttpUrl == "" {
+		return fmt.Errorf("option %q: no L1 HTTP endpoint provided", flags.EthereumHttpUrlFlag.Name)
+	}
+	return c.validateOptions()
+}
+
+// validateOptions checks the options other than the endpoints, which are
+// not needed when the backends are provided
+func (c *Config) validateOptions() error {
+	if !c.hasSigner() && !c.dryRun {
+		return fmt.Errorf("%w: set %q or %q, or run with %q", errNoPrivateKey, flags.PrivateKeyFlag.Name,
+			flags.PrivateKeyFileFlag.Name, flags.DryRunFlag.Name)
+	}
+	switch c.demandBlockTag {
+	case "", "latest", "safe", "finalized":
+	default:
+		return fmt.Errorf("option %q: invalid block tag %q, use latest, safe or finalized",
+			flags.DemandBlockTagFlag.Name, c.demandBlockTag)
+	}
+	if c.adminAddr != "" || c.adminGRPCAddr != "" {
+		if c.adminToken == "" && len(c.adminJWTSecret) == 0 {
+			return fmt.Errorf("the admin API requires %q or %q", flags.AdminTokenFlag.Name,
+				flags.AdminJWTSecretFileFlag.Name)
+		}
+		for _, addr := range []struct {
+			flag  string
+			value string
+		}{{flags.AdminAddrFlag.Name, c.adminAddr}, {flags.AdminGRPCAddrFlag.Name, c.adminGRPCAddr}} {
+			if addr.value == "" {
+				continue
+			}
+			if err := checkBindAddress(addr.value, c.adminBindAllowlist); err != nil {
+				return fmt.Errorf("option %q: %w, see %q", addr.flag, err, flags.AdminBindAllowlistFlag.Name)
+			}
+		}
+	}
+	if c.approvalToken != "" {
+		// A one shot run exits without serving the admin API, so a staged
+		// update would be dropped
+		if c.oneShot {
+			return fmt.Errorf("option %q cannot be used with %q", flags.ApprovalTokenFlag.Name, flags.OneShotFlag.Name)
+		}
+		if c.adminAddr == "" {
+			return fmt.Errorf("option %q: the updates are approved on the admin API, set %q",
+				flags.ApprovalTokenFlag.Name, flags.AdminAddrFlag.Name)
+		}
+		if !c.enableL2GasPrice {
+			return fmt.Errorf("option %q: only the L2 gas price is approved, set %q",
+				flags.ApprovalTokenFlag.Name, flags.EnableL2GasPriceFlag.Name)
+		}
+		// The approver must not be the operator of the admin API
+		if c.approvalToken == c.adminToken {
+			return fmt.Errorf("option %q: the approval token must differ from %q",
+				flags.ApprovalTokenFlag.Name, flags.AdminTokenFlag.Name)
+		}
+		if c.approvalTTL <= 0 {
+			return fmt.Errorf("option %q: ttl must be positive, got %s", flags.ApprovalTTLFlag.Name, c.approvalTTL)
+		}
+	}
+	if c.publishURL != "" {
+		if _, err := publish.New(c.publishURL, c.publishTopic); err != nil {
+			return fmt.Errorf("option %q: %w", flags.PublishURLFlag.Name, err)
+		}
+	}
+	if c.postgresURL != "" {
+		if err := store.CheckURL(c.postgresURL); err != nil {
+			return fmt.Errorf("option %q: %w", flags.PostgresURLFlag.Name, err)
+		}
+	}
+	if c.l1ChainID != nil && c.l2ChainID != nil && c.l1ChainID.Cmp(c.l2ChainID) == 0 {
+		return fmt.Errorf("%w: L1 and L2 are both configured with %d, check %q and %q",
+			errWrongChainID, c.l1ChainID, flags.L1ChainIDFlag.Name, flags.L2ChainIDFlag.Name)
+	}
+	if c.gasTokenRateFeed != (common.Address{}) {
+		if !c.enableL2GasPrice {
+			return fmt.Errorf("option %q: the gas token rate only applies to the L2 gas price, enable %q",
+				flags.GasTokenRateFeedFlag.Name, flags.EnableL2GasPriceFlag.Name)
+		}
+		if c.gasTokenMaxRateAge <= 0 {
+			return fmt.Errorf("option %q: max rate age must be positive, got %s",
+				flags.GasTokenMaxRateAgeFlag.Name, c.gasTokenMaxRateAge)
+		}
+	}
+	if c.leaseURL != "" {
+		if err := leader.CheckURL(c.leaseURL); err != nil {
+			return fmt.Errorf("option %q: %w", flags.HALeaseURLFlag.Name, err)
+		}
+		if c.leaseTTL <= 0 {
+			return fmt.Errorf("option %q: ttl must be positive, got %s", flags.HALeaseTTLFlag.Name, c.leaseTTL)
+		}
+		if c.leaseName == "" {
+			return fmt.Errorf("option %q: no lease name", flags.HALeaseNameFlag.Name)
+		}
+	}
+	if c.feeVaultAddress != (common.Address{}) && c.feeVaultInterval <= 0 {
+		return fmt.Errorf("option %q: interval must be positive, got %s",
+			flags.FeeVaultIntervalFlag.Name, c.feeVaultInterval)
+	}
+	if c.enableOperatorFee {
+		if !c.bedrock() {
+			return fmt.Errorf("option %q: the operator fee is set in the SystemConfig, set %q",
+				flags.SystemConfigOperatorFeeScalarFlag.Name, flags.SystemConfigAddressFlag.Name)
+		}
+		if c.operatorFeeScalar > math.MaxUint32 || c.maxOperatorFeeScalar > math.MaxUint32 {
+			return fmt.Errorf("option %q: the operator fee scalar cannot exceed %d",
+				flags.SystemConfigOperatorFeeScalarFlag.Name, uint32(math.MaxUint32))
+		}
+		if c.operatorFeeSignificanceFactor < 0 || c.operatorFeeSignificanceFactor >= 1 {
+			return fmt.Errorf("option %q: significance factor must be in [0, 1), got %f",
+				flags.SystemConfigOperatorFeeSignificanceFactorFlag.Name, c.operatorFeeSignificanceFactor)
+		}
+	}
+	if c.bedrock() {
+		// The L2 gas price is set by EIP-1559 and the L1 base fee is read
+		// from L1 by the L1Block predeploy
+		if c.enableL2GasPrice || c.enableL1BaseFee {
+			return fmt.Errorf("option %q: %q and %q do not apply to a Bedrock chain",
+				flags.SystemConfigAddressFlag.Name, flags.EnableL2GasPriceFlag.Name, flags.EnableL1BaseFeeFlag.Name)
+		}
+		if c.adminAddr != "" || c.adminGRPCAddr != "" || c.priceAPIAddr != "" {
+			return fmt.Errorf("option %q: the admin and price APIs are not supported for a Bedrock chain",
+				flags.SystemConfigAddressFlag.Name)
+		}
+		if !c.enableGasConfig && !c.enableOperatorFee {
+			return fmt.Errorf("option %q: nothing is set in the SystemConfig, enable the gas config or set %q",
+				flags.SystemConfigEnableGasConfigFlag.Name, flags.SystemConfigOperatorFeeScalarFlag.Name)
+		}
+		if c.enableGasConfig && c.systemConfigScalar == 0 {
+			return fmt.Errorf("option %q: scalar cannot be 0", flags.SystemConfigScalarFlag.Name)
+		}
+		if c.systemConfigInterval <= 0 {
+			return fmt.Errorf("option %q: interval must be positive, got %s",
+				flags.SystemConfigIntervalFlag.Name, c.systemConfigInterval)
+		}
+		if c.l1TxResubmitTimeout <= 0 {
+			return fmt.Errorf("option %q: timeout must be positive, got %s",
+				flags.L1TxResubmitTimeoutFlag.Name, c.l1TxResubmitTimeout)
+		}
+	}
+	if c.safeAddress != (common.Address{}) {
+		if c.bedrock() {
+			return fmt.Errorf("option %q: a Safe is not supported for a Bedrock chain", flags.SafeAddressFlag.Name)
+		}
+		if c.enableL1BaseFee {
+			return fmt.Errorf("option %q: the L1 base fee is not set through a Safe, disable %q",
+				flags.SafeAddressFlag.Name, flags.EnableL1BaseFeeFlag.Name)
+		}
+	}
+	if c.safeServiceURL != "" {
+		if u, err := url.Parse(c.safeServiceURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("option %q: invalid URL %q", flags.SafeServiceURLFlag.Name, c.safeServiceURL)
+		}
+	}
+	if c.enableL2GasPrice {
+		if c.epochLength < time.Second {
+			return fmt.Errorf("option %q: epoch length cannot be less than 1s, got %s",
+				flags.EpochLengthFlag.Name, c.epochLength)
+		}
+		if c.averageBlockGasLimitPerEpoch == 0 {
+			return fmt.Errorf("option %q: average block gas limit cannot be 0",
+				flags.AverageBlockGasLimitPerEpochFlag.Name)
+		}
+		if c.targetGasPerSecond == 0 && len(c.targetGasSchedule) == 0 {
+			return fmt.Errorf("option %q: target gas per second cannot be 0", flags.TargetGasPerSecondFlag.Name)
+		}
+		if c.maxPercentChangePerEpoch < 0 {
+			return fmt.Errorf("option %q: max percent change cannot be negative %f",
+				flags.MaxPercentChangePerEpochFlag.Name, c.maxPercentChangePerEpoch)
+		}
+		if c.l2GasPriceSignificanceFactor < 0 {
+			return fmt.Errorf("option %q: significance factor cannot be negative %f",
+				flags.L2GasPriceSignificanceFactorFlag.Name, c.l2GasPriceSignificanceFactor)
+		}
+		if c.maxGasPrice != nil && c.floorPrice != nil && c.maxGasPrice.Cmp(c.floorPrice) < 0 {
+			return fmt.Errorf("option %q: max gas price %d is below the floor price %d",
+				flags.MaxGasPriceFlag.Name, c.maxGasPrice, c.floorPrice)
+		}
+		if err := c.validateSanityBounds(); err != nil {
+			return err
+		}
+	}
+	if c.enableL1BaseFee && c.l1BaseFeeEpochLength <= 0 {
+		return fmt.Errorf("option %q: epoch length must be positive, got %s",
+			flags.L1BaseFeeEpochLengthFlag.Name, c.l1BaseFeeEpochLength)
+	}
+	if c.MetricsEnabled {
+		switch c.MetricsBackend {
+		case "prometheus", "statsd", "cloudwatch":
+		default:
+			return fmt.Errorf("option %q: invalid metrics backend %q", flags.MetricsBackendFlag.Name,
+				c.MetricsBackend)
+		}
+	}
+	return nil
+}
+
+// durationFlag returns the value of a duration option. The deprecated
+// option in whole seconds is used when only it is set.
+func durationFlag(ctx *cli.Context, warnings *configWarnings, flag cli.DurationFlag,
+	secondsFlag cli.Uint64Flag) time.Duration {
+	if ctx.GlobalIsSet(secondsFlag.Name) {
+		warnings.add("option %q is deprecated, use %q", secondsFlag.Name, flag.Name)
+	}
+	if !ctx.GlobalIsSet(flag.Name) && ctx.GlobalIsSet(secondsFlag.Name) {
+		return time.Duration(ctx.GlobalUint64(secondsFlag.Name)) * time.Second
+	}
+	return ctx.GlobalDuration(flag.Name)
+}
+
+// weiFlag returns the amount of an option in wei
+func weiFlag(ctx *cli.Context, flag cli.StringFlag) (*big.Int, error) {
+	amount, err := gasprices.ParseWei(ctx.GlobalString(flag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("option %q: %w", flag.Name, err)
+	}
+	return amount, nil
+}
+
+// parsePrivateKey parses a hex encoded private key
+func parsePrivateKey(hex string) (*ecdsa.PrivateKey, error) {
+	return crypto.HexToECDSA(strings.TrimPrefix(hex, "0x"))
+}
+
+// loadPrivateKeyFile reads a hex encoded private key from a file. Secrets
+// are often mounted with a trailing newline, so surrounding whitespace is
+// ignored.
+func loadPrivateKeyFile(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(strings.TrimSpace(string(data)))
+	if err != nil {
+		// The contents of the file are not included since they are secret
+		return nil, fmt.Errorf("invalid private key in %s", path)
+	}
+	return key, nil
+}
+
+// LayerTwoHttpUrl returns the configured L2 HTTP endpoint
+func (c *Config) LayerTwoHttpUrl() string {
+	return c.layerTwoHttpUrl
+}
+
+// OneShot returns true when a single epoch is evaluated before exiting
+func (c *Config) OneShot() bool {
+	return c.oneShot
+}
+
+// EthereumHttpUrl returns the configured L1 HTTP endpoint
+func (c *Config) EthereumHttpUrl() string {
+	return c.ethereumHttpUrl
+}
+
+// GasPriceOracleAddress returns the configured address of the gas price
+// oracle
+func (c *Config) GasPriceOracleAddress() common.Address {
+	return c.gasPriceOracleAddress
+}
+
+// DryRun returns true when no transactions are sent
+func (c *Config) DryRun() bool {
+	return c.dryRun
+}
+
+// ReportingTags describes the configuration in error reports. It must not
+// include secrets such as the private key.
+func (c *Config) ReportingTags() map[string]string {
+	tags := map[string]string{
+		"gas_price_oracle": c.gasPriceOracleAddress.Hex(),
+		"pricer":           c.pricer,
+		"dry_run":          strconv.FormatBool(c.dryRun),
+		"epoch_length":     c.epochLength.String(),
+		"target_gas":       strconv.FormatUint(c.targetGasPerSecond, 10),
+		"l1_base_fee":      strconv.FormatBool(c.enableL1BaseFee),
+		"l2_gas_price":     strconv.FormatBool(c.enableL2GasPrice),
+	}
+	if c.bedrock() {
+		tags["gas_config"] = strconv.FormatBool(c.enableGasConfig)
+	}
+	if c.l2ChainID != nil {
+		tags["l2_chain_id"] = c.l2ChainID.String()
+	}
+	if signer, ok := c.signer(); ok {
+		tags["signer"] = signer.Hex()
+	}
+	return tags
+}
+
+// signer returns the address that signs the update transactions, false when
+// there is no signer
+func (c *Config) signer() (common.Address, bool) {
+	if c.signerFn != nil {
+		return c.signerAddress, true
+	}
+	if c.privateKey != nil {
+		return crypto.PubkeyToAddress(c.privateKey.PublicKey), true
+	}
+	return common.Address{}, false
+}
+
+// expectedOwner returns the account that must own the gas price oracle for
+// the updates to succeed, which is the Safe when the updates go through one
+func (c *Config) expectedOwner() (common.Address, bool) {
+	if c.safeAddress != (common.Address{}) {
+		return c.safeAddress, true
+	}
+	return c.signer()
+}
+
+// hasSigner returns true when update transactions can be signed
+func (c *Config) hasSigner() bool {
+	_, ok := c.signer()
+	return ok
+}
+
+// bedrock returns true when the gas config is set in the L1 SystemConfig of
+// a Bedrock chain instead of in the L2 gas price oracle
+func (c *Config) bedrock() bool {
+	return c.systemConfigAddress != (common.Address{})
+}
+
+// l2GasPriceSignificanceFactorFor returns the significance factor to use when
+// moving the L2 gas price from current to next. The directional factors are
+// used when configured, so that prices can be raised and lowered with
+// different thresholds. Otherwise the adaptive factor is used when enabled.
+func (c *Config) l2GasPriceSignificanceFactorFor(current, next *big.Int) float64 {
+	if next.Cmp(current) > 0 && c.l2GasPriceIncreaseSignificanceFactor != nil {
+		return *c.l2GasPriceIncreaseSignificanceFactor
+	}
+	if next.Cmp(current) < 0 && c.l2GasPriceDecreaseSignificanceFactor != nil {
+		return *c.l2GasPriceDecreaseSignificanceFactor
+	}
+	if c.adaptiveSignificance != nil {
+		return c.adaptiveSignificance.Factor()
+	}
+	return c.l2GasPriceSignificanceFactor
+}
 
-	return &cfg
+// usesBlockUsage returns true when the demand is computed from more than
+// the gas used by blocks. Config literals in tests leave the weights unset,
+// which also means that only the gas used is counted.
+func (c *Config) usesBlockUsage() bool {
+	return c.demandWeights != (gasprices.DemandWeights{}) && !c.demandWeights.IsDefault()
 }
@@ -0,0 +1,204 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v2"
+)
+
+// fileConfig represents the options that can be set in the YAML config file
+type fileConfig struct {
+	TargetGasSchedule []targetGasWindowConfig `yaml:"target-gas-schedule"`
+	// Networks are named profiles, one of which is selected with --network
+	Networks map[string]*networkConfig `yaml:"networks"`
+	// Chains are updated by one process, each with its own updater
+	Chains []*chainConfig `yaml:"chains"`
+}
+
+// chainConfig is a chain that is updated next to the other chains of the
+// config file. It starts from the options of the command line and, unlike a
+// network profile, the options of the chain take precedence.
+type chainConfig struct {
+	// Name identifies the chain in the logs and labels its metrics
+	Name           string  `yaml:"name"`
+	PrivateKeyFile *string `yaml:"private-key-file"`
+	networkConfig  `yaml:",inline"`
+}
+
+// networkConfig is the profile of a network. Options that are not set keep
+// the value of the command line, and options that are set on the command
+// line take precedence over the profile.
+type networkConfig struct {
+	EthereumHttpUrl              *string                 `yaml:"ethereum-http-url"`
+	LayerTwoHttpUrl              *string                 `yaml:"layer-two-http-url"`
+	L1ChainID                    *uint64                 `yaml:"l1-chain-id"`
+	L2ChainID                    *uint64                 `yaml:"l2-chain-id"`
+	GasPriceOracleAddress        *string                 `yaml:"gas-price-oracle-address"`
+	Pricer                       *string                 `yaml:"pricer"`
+	FloorPrice                   *string                 `yaml:"floor-price"`
+	MaxGasPrice                  *string                 `yaml:"max-gas-price"`
+	TargetGasPerSecond           *uint64                 `yaml:"target-gas-per-second"`
+	MaxPercentChangePerEpoch     *float64                `yaml:"max-percent-change-per-epoch"`
+	AverageBlockGasLimitPerEpoch *uint64                 `yaml:"average-block-gas-limit-per-epoch"`
+	EpochLength                  *time.Duration          `yaml:"epoch-length"`
+	L1BaseFeeEpochLength         *time.Duration          `yaml:"l1-base-fee-epoch-length"`
+	L2GasPriceSignificanceFactor *float64                `yaml:"significant-factor"`
+	L1BaseFeeSignificanceFactor  *float64                `yaml:"l1-base-fee-significant-factor"`
+	TargetGasSchedule            []targetGasWindowConfig `yaml:"target-gas-schedule"`
+	// The updaters that are enabled, so that the chains of a file can each
+	// manage a subset of the parameters
+	EnableL1BaseFee  *bool `yaml:"enable-l1-base-fee"`
+	EnableL2GasPrice *bool `yaml:"enable-l2-gas-price"`
+	EnableGasConfig  *bool `yaml:"system-config.enable-gas-config"`
+}
+
+// targetGasWindowConfig is a time of day window in UTC with its own target
+// gas per second. Times are in the form of HH:MM
+type targetGasWindowConfig struct {
+	Start              string `yaml:"start"`
+	End                string `yaml:"end"`
+	TargetGasPerSecond uint64 `yaml:"target-gas-per-second"`
+}
+
+// loadConfigFile reads and parses the YAML config file at path. Unknown
+// keys, such as misspelled options, are ignored and added to the warnings.
+func loadConfigFile(path string, warnings *configWarnings) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg fileConfig
+	strictErr := yaml.UnmarshalStrict(data, &cfg)
+	if strictErr == nil {
+		return &cfg, nil
+	}
+	cfg = fileConfig{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse config file %s: %w", path, err)
+	}
+	warnings.add("config file %s: %v", path, strictErr)
+	return &cfg, nil
+}
+
+// apply sets the options of the profile that are not set on the command line
+func (n *networkConfig) apply(cfg *Config, isSet func(name string) bool) error {
+	setString := func(flag string, value *string, dst *string) {
+		if value != nil && !isSet(flag) {
+			*dst = *value
+		}
+	}
+	setUint64 := func(flag string, value *uint64, dst *uint64) {
+		if value != nil && !isSet(flag) {
+			*dst = *value
+		}
+	}
+	setBool := func(flag string, value *bool, dst *bool) {
+		if value != nil && !isSet(flag) {
+			*dst = *value
+		}
+	}
+	setFloat64 := func(flag string, value *float64, dst *float64) {
+		if value != nil && !isSet(flag) {
+			*dst = *value
+		}
+	}
+	// Durations are also set by the deprecated option in seconds
+	setDuration := func(flag, secondsFlag string, value *time.Duration, dst *time.Duration) {
+		if value != nil && !isSet(flag) && !isSet(secondsFlag) {
+			*dst = *value
+		}
+	}
+	setBig := func(flag string, value *uint64, dst **big.Int) {
+		if value != nil && !isSet(flag) {
+			*dst = new(big.Int).SetUint64(*value)
+		}
+	}
+
+	setString(flags.EthereumHttpUrlFlag.Name, n.EthereumHttpUrl, &cfg.ethereumHttpUrl)
+	setString(flags.LayerTwoHttpUrlFlag.Name, n.LayerTwoHttpUrl, &cfg.layerTwoHttpUrl)
+	setBig(flags.L1ChainIDFlag.Name, n.L1ChainID, &cfg.l1ChainID)
+	setBig(flags.L2ChainIDFlag.Name, n.L2ChainID, &cfg.l2ChainID)
+	if n.GasPriceOracleAddress != nil && !isSet(flags.GasPriceOracleAddressFlag.Name) {
+		if !common.IsHexAddress(*n.GasPriceOracleAddress) {
+			return fmt.Errorf("invalid gas price oracle address %q", *n.GasPriceOracleAddress)
+		}
+		cfg.gasPriceOracleAddress = common.HexToAddress(*n.GasPriceOracleAddress)
+	}
+	setString(flags.PricerFlag.Name, n.Pricer, &cfg.pricer)
+	for _, price := range []struct {
+		flag  string
+		value *string
+		dst   **big.Int
+	}{
+		{flags.FloorPriceFlag.Name, n.FloorPrice, &cfg.floorPrice},
+		{flags.MaxGasPriceFlag.Name, n.MaxGasPrice, &cfg.maxGasPrice},
+	} {
+		if price.value == nil || isSet(price.flag) {
+			continue
+		}
+		amount, err := gasprices.ParseWei(*price.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", price.flag, err)
+		}
+		*price.dst = amount
+	}
+	setUint64(flags.TargetGasPerSecondFlag.Name, n.TargetGasPerSecond, &cfg.targetGasPerSecond)
+	setFloat64(flags.MaxPercentChangePerEpochFlag.Name, n.MaxPercentChangePerEpoch, &cfg.maxPercentChangePerEpoch)
+	setUint64(flags.AverageBlockGasLimitPerEpochFlag.Name, n.AverageBlockGasLimitPerEpoch,
+		&cfg.averageBlockGasLimitPerEpoch)
+	setDuration(flags.EpochLengthFlag.Name, flags.EpochLengthSecondsFlag.Name, n.EpochLength, &cfg.epochLength)
+	setDuration(flags.L1BaseFeeEpochLengthFlag.Name, flags.L1BaseFeeEpochLengthSecondsFlag.Name,
+		n.L1BaseFeeEpochLength, &cfg.l1BaseFeeEpochLength)
+	setFloat64(flags.L2GasPriceSignificanceFactorFlag.Name, n.L2GasPriceSignificanceFactor,
+		&cfg.l2GasPriceSignificanceFactor)
+	setFloat64(flags.L1BaseFeeSignificanceFactorFlag.Name, n.L1BaseFeeSignificanceFactor,
+		&cfg.l1BaseFeeSignificanceFactor)
+	setBool(flags.EnableL1BaseFeeFlag.Name, n.EnableL1BaseFee, &cfg.enableL1BaseFee)
+	setBool(flags.EnableL2GasPriceFlag.Name, n.EnableL2GasPrice, &cfg.enableL2GasPrice)
+	setBool(flags.SystemConfigEnableGasConfigFlag.Name, n.EnableGasConfig, &cfg.enableGasConfig)
+	// The schedule of the profile replaces the schedule of the file
+	if n.TargetGasSchedule != nil {
+		schedule, err := parseTargetGasSchedule(n.TargetGasSchedule)
+		if err != nil {
+			return err
+		}
+		cfg.targetGasSchedule = schedule
+	}
+	return nil
+}
+
+// targetGasSchedule converts the configured windows into a schedule that
+// can be used by the gas pricer
+func (f *fileConfig) targetGasSchedule() ([]gasprices.TargetGasWindow, error) {
+	return parseTargetGasSchedule(f.TargetGasSchedule)
+}
+
+// parseTargetGasSchedule parses and validates the windows of a schedule
+func parseTargetGasSchedule(windows []targetGasWindowConfig) ([]gasprices.TargetGasWindow, error) {
+	schedule := make([]gasprices.TargetGasWindow, len(windows))
+	for i, w := range windows {
+		start, err := gasprices.ParseTimeOfDay(w.Start)
+		if err != nil {
+			return nil, fmt.Errorf("target gas window %d: %w", i, err)
+		}
+		end, err := gasprices.ParseTimeOfDay(w.End)
+		if err != nil {
+			return nil, fmt.Errorf("target gas window %d: %w", i, err)
+		}
+		schedule[i] = gasprices.TargetGasWindow{
+			Start:              start,
+			End:                end,
+			TargetGasPerSecond: w.TargetGasPerSecond,
+		}
+	}
+	if err := gasprices.ValidateTargetGasSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
@@ -0,0 +1,205 @@
+package oracle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFileTargetGasSchedule(t *testing.T) {
+	path := writeConfigFile(t, `
+target-gas-schedule:
+  - start: "08:00"
+    end: "18:30"
+    target-gas-per-second: 5000000
+  - start: "22:00"
+    end: "02:00"
+    target-gas-per-second: 20000000
+`)
+	fileCfg, err := loadConfigFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := fileCfg.targetGasSchedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 2 {
+		t.Fatalf("expected 2 windows, got %d", len(schedule))
+	}
+	if schedule[0].Start != 8*time.Hour || schedule[0].End != 18*time.Hour+30*time.Minute {
+		t.Fatalf("first window parsed incorrectly: %+v", schedule[0])
+	}
+	if schedule[1].TargetGasPerSecond != 20_000_000 {
+		t.Fatalf("second window parsed incorrectly: %+v", schedule[1])
+	}
+}
+
+func TestLoadConfigFileInvalidTargetGasSchedule(t *testing.T) {
+	path := writeConfigFile(t, `
+target-gas-schedule:
+  - start: "8am"
+    end: "18:00"
+    target-gas-per-second: 5000000
+`)
+	fileCfg, err := loadConfigFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fileCfg.targetGasSchedule(); err == nil {
+		t.Fatal("expected invalid time of day to fail")
+	}
+}
+
+func TestLoadConfigFileNetworks(t *testing.T) {
+	path := writeConfigFile(t, `
+networks:
+  optimism-mainnet:
+    layer-two-http-url: https://mainnet.optimism.io
+    l2-chain-id: 10
+    gas-price-oracle-address: "0x420000000000000000000000000000000000000F"
+    floor-price: 1000
+    max-gas-price: 10gwei
+    epoch-length: 30s
+    target-gas-schedule:
+      - start: "08:00"
+        end: "18:00"
+        target-gas-per-second: 5000000
+  optimism-goerli:
+    layer-two-http-url: https://goerli.optimism.io
+    l2-chain-id: 420
+`)
+	fileCfg, err := loadConfigFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lookupNetwork(fileCfg, "optimism-kovan"); err == nil {
+		t.Fatal("expected error for an unknown network")
+	}
+	network, err := lookupNetwork(fileCfg, "optimism-mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{
+		layerTwoHttpUrl:    "http://127.0.0.1:9545",
+		epochLength:        10 * time.Second,
+		targetGasPerSecond: 11_000_000,
+	}
+	// Options set on the command line take precedence over the profile
+	isSet := func(name string) bool { return name == "epoch-length" }
+	if err := network.apply(cfg, isSet); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.layerTwoHttpUrl != "https://mainnet.optimism.io" || cfg.l2ChainID.Uint64() != 10 {
+		t.Fatalf("network not applied: %s %d", cfg.layerTwoHttpUrl, cfg.l2ChainID)
+	}
+	if cfg.floorPrice.Uint64() != 1000 || cfg.maxGasPrice.Uint64() != 10_000_000_000 ||
+		len(cfg.targetGasSchedule) != 1 {
+		t.Fatal("pricing parameters not applied")
+	}
+	if cfg.epochLength != 10*time.Second {
+		t.Fatalf("expected the command line epoch length, got %s", cfg.epochLength)
+	}
+	// Options that are not in the profile are kept
+	if cfg.targetGasPerSecond != 11_000_000 || cfg.l1ChainID != nil {
+		t.Fatal("unexpected change of an option that is not in the profile")
+	}
+}
+
+func TestConfigChains(t *testing.T) {
+	path := writeConfigFile(t, `
+chains:
+  - name: op-mainnet
+    layer-two-http-url: https://mainnet.optimism.io
+    l2-chain-id: 10
+    epoch-length: 30s
+  - name: op-goerli
+    layer-two-http-url: https://goerli.optimism.io
+    l2-chain-id: 420
+    floor-price: 1gwei
+    enable-l1-base-fee: false
+`)
+	fileCfg, err := loadConfigFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chains := mustChains(t, &Config{}); len(chains) != 0 {
+		t.Fatal("expected no chains without a config file")
+	}
+
+	base := &Config{
+		layerTwoHttpUrl:    "http://127.0.0.1:9545",
+		epochLength:        10 * time.Second,
+		targetGasPerSecond: 11_000_000,
+		enableL1BaseFee:    true,
+		enableL2GasPrice:   true,
+		chains:             fileCfg.Chains,
+	}
+	chains := mustChains(t, base)
+	if len(chains) != 2 {
+		t.Fatalf("expected 2 chains, got %d", len(chains))
+	}
+	mainnet, goerli := chains[0], chains[1]
+	if mainnet.ChainName() != "op-mainnet" || mainnet.layerTwoHttpUrl != "https://mainnet.optimism.io" ||
+		mainnet.l2ChainID.Uint64() != 10 {
+		t.Fatalf("chain not applied: %s %s %d", mainnet.ChainName(), mainnet.layerTwoHttpUrl, mainnet.l2ChainID)
+	}
+	// The options of a chain take precedence over the command line
+	if mainnet.epochLength != 30*time.Second || goerli.epochLength != 10*time.Second {
+		t.Fatalf("unexpected epoch lengths %s and %s", mainnet.epochLength, goerli.epochLength)
+	}
+	if goerli.floorPrice.Uint64() != 1_000_000_000 || mainnet.floorPrice != nil {
+		t.Fatal("expected the floor price of a chain to apply to that chain only")
+	}
+	if goerli.targetGasPerSecond != 11_000_000 || goerli.chainMetrics == nil {
+		t.Fatal("expected the chain to start from the command line options")
+	}
+	// A chain manages a subset of the parameters
+	if goerli.enableL1BaseFee || !goerli.enableL2GasPrice || !mainnet.enableL1BaseFee {
+		t.Fatal("expected the L1 base fee of op-goerli only to be disabled")
+	}
+	if base.layerTwoHttpUrl != "http://127.0.0.1:9545" {
+		t.Fatal("expected the base config to be kept")
+	}
+
+	// Options that bind an address or write a file cannot be shared
+	shared := *base
+	shared.adminGRPCAddr = "127.0.0.1:7302"
+	if _, err := shared.Chains(); err == nil {
+		t.Fatal("expected an error for an admin API shared by the chains")
+	}
+	// The admin API of the process serves the chains
+	shared = *base
+	shared.adminAddr = "127.0.0.1:7301"
+	if chains := mustChains(t, &shared); chains[0].adminAddr != "" {
+		t.Fatal("expected the chains not to start their own admin API")
+	}
+
+	duplicate := *base
+	duplicate.chains = []*chainConfig{{Name: "op"}, {Name: "op"}}
+	if _, err := duplicate.Chains(); err == nil {
+		t.Fatal("expected an error for a duplicate chain name")
+	}
+	duplicate.chains = []*chainConfig{{Name: "OP Mainnet"}}
+	if _, err := duplicate.Chains(); err == nil {
+		t.Fatal("expected an error for an invalid chain name")
+	}
+}
+
+func mustChains(t *testing.T, cfg *Config) []*Config {
+	chains, err := cfg.Chains()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chains
+}
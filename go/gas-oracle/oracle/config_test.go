@@ -0,0 +1,220 @@
+package oracle
+
+import (
+	"errors"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli"
+)
+
+func TestConfigValidate(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	valid := func() *Config {
+		return &Config{
+			ethereumHttpUrl:              "http://localhost:8545",
+			layerTwoHttpUrl:              "http://localhost:9545",
+			privateKey:                   key,
+			enableL2GasPrice:             true,
+			enableL1BaseFee:              true,
+			epochLength:                  10 * time.Second,
+			l1BaseFeeEpochLength:         15 * time.Second,
+			averageBlockGasLimitPerEpoch: 11_000_000,
+			targetGasPerSecond:           11_000_000,
+			floorPrice:                   big.NewInt(1),
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		expect string
+	}{
+		{"no l2 url", func(c *Config) { c.layerTwoHttpUrl = "" }, "layer-two-http-url"},
+		{"no key", func(c *Config) { c.privateKey = nil }, "no private key"},
+		{"zero epoch length", func(c *Config) { c.epochLength = 0 }, "epoch-length"},
+		{"sub-second epoch length", func(c *Config) { c.epochLength = 500 * time.Millisecond }, "epoch-length"},
+		{"zero l1 epoch length", func(c *Config) { c.l1BaseFeeEpochLength = 0 }, "l1-base-fee-epoch-length"},
+		{"same chain ids", func(c *Config) {
+			c.l1ChainID, c.l2ChainID = big.NewInt(10), big.NewInt(10)
+		}, "wrong chain id"},
+		{"max below floor", func(c *Config) {
+			c.floorPrice, c.maxGasPrice = big.NewInt(100), big.NewInt(10)
+		}, "below the floor price"},
+		{"sanity min above floor", func(c *Config) {
+			c.sanityMinGasPrice = big.NewInt(2)
+		}, "sanity.min-gas-price"},
+		{"sanity max below max", func(c *Config) {
+			c.maxGasPrice, c.sanityMaxGasPrice = big.NewInt(100), big.NewInt(50)
+		}, "sanity.max-gas-price"},
+		{"sanity min above sanity max", func(c *Config) {
+			c.floorPrice, c.sanityMinGasPrice, c.sanityMaxGasPrice = big.NewInt(200), big.NewInt(100), big.NewInt(50)
+		}, "sanity.min-gas-price"},
+		{"safe with l1 base fee", func(c *Config) {
+			c.safeAddress, c.enableL1BaseFee = common.HexToAddress("0x01"), true
+		}, "safe.address"},
+		{"safe service url", func(c *Config) {
+			c.safeServiceURL = "safe-transaction-optimism.safe.global"
+		}, "safe.transaction-service-url"},
+		{"demand block tag", func(c *Config) { c.demandBlockTag = "pending" }, "demand-block-tag"},
+		{"approval without admin api", func(c *Config) {
+			c.approvalToken, c.approvalTTL = "approver", time.Minute
+		}, "approval.token"},
+		{"approval with admin token", func(c *Config) {
+			c.adminAddr, c.adminToken, c.adminBindAllowlist = "127.0.0.1:7301", "secret", defaultAdminBindAllowlist
+			c.approvalToken, c.approvalTTL = "secret", time.Minute
+		}, "approval.token"},
+		{"approval with one shot", func(c *Config) {
+			c.adminAddr, c.adminToken, c.adminBindAllowlist = "127.0.0.1:7301", "secret", defaultAdminBindAllowlist
+			c.approvalToken, c.approvalTTL, c.oneShot = "approver", time.Minute, true
+		}, "one-shot"},
+		{"metrics backend", func(c *Config) {
+			c.MetricsEnabled, c.MetricsBackend = true, "graphite"
+		}, "invalid metrics backend"},
+		{"publish url", func(c *Config) {
+			c.publishURL, c.publishTopic = "kafka://localhost:9092", "gas-oracle"
+		}, "publish.url"},
+		{"postgres url", func(c *Config) {
+			c.postgresURL = "postgres://localhost/oracle"
+		}, "postgres.url"},
+		{"gas token without l2 gas price", func(c *Config) {
+			c.gasTokenRateFeed = common.HexToAddress("0x01")
+			c.gasTokenMaxRateAge = time.Hour
+			c.enableL2GasPrice = false
+		}, "gas-token.rate-feed"},
+		{"gas token without max rate age", func(c *Config) {
+			c.gasTokenRateFeed = common.HexToAddress("0x01")
+		}, "gas-token.max-rate-age"},
+		{"fee vault without interval", func(c *Config) {
+			c.feeVaultAddress = common.HexToAddress("0x4200000000000000000000000000000000000011")
+		}, "fee-vault.interval"},
+		{"lease with an unsupported store", func(c *Config) {
+			c.leaseURL = "zookeeper://localhost:2181"
+			c.leaseName = "gas-oracle"
+			c.leaseTTL = 15 * time.Second
+		}, "ha.lease-url"},
+		{"lease without ttl", func(c *Config) {
+			c.leaseURL = "redis://localhost:6379"
+			c.leaseName = "gas-oracle"
+		}, "ha.lease-ttl"},
+		{"operator fee without system config", func(c *Config) {
+			c.enableOperatorFee = true
+		}, "system-config.operator-fee-scalar"},
+		{"operator fee scalar above uint32", func(c *Config) {
+			c.systemConfigAddress = common.HexToAddress("0x01")
+			c.enableOperatorFee, c.operatorFeeScalar = true, 1<<32
+		}, "system-config.operator-fee-scalar"},
+		{"bedrock with l2 gas price", func(c *Config) {
+			c.systemConfigAddress = common.HexToAddress("0x01")
+		}, "system-config.address"},
+		{"bedrock without scalar", func(c *Config) {
+			c.systemConfigAddress = common.HexToAddress("0x01")
+			c.enableL2GasPrice, c.enableL1BaseFee, c.enableGasConfig = false, false, true
+		}, "system-config.scalar"},
+		{"bedrock without updaters", func(c *Config) {
+			c.systemConfigAddress = common.HexToAddress("0x01")
+			c.enableL2GasPrice, c.enableL1BaseFee = false, false
+		}, "system-config.enable-gas-config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.expect) {
+				t.Fatalf("expected error containing %q, got %v", tt.expect, err)
+			}
+		})
+	}
+
+	// A dry run does not need a key
+	cfg := valid()
+	cfg.privateKey, cfg.dryRun = nil, true
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.dryRun = false
+	if err := cfg.Validate(); !errors.Is(err, errNoPrivateKey) {
+		t.Fatalf("expected %v, got %v", errNoPrivateKey, err)
+	}
+}
+
+func TestLoadPrivateKeyFile(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	hex := common.Bytes2Hex(crypto.FromECDSA(key))
+	dir := t.TempDir()
+
+	// Mounted secrets often end with a newline
+	path := filepath.Join(dir, "key")
+	if err := os.WriteFile(path, []byte("0x"+hex+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadPrivateKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Equal(key) {
+		t.Fatal("loaded a different key")
+	}
+
+	invalid := filepath.Join(dir, "invalid")
+	if err := os.WriteFile(invalid, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = loadPrivateKeyFile(invalid)
+	if err == nil || strings.Contains(err.Error(), "not a key") {
+		t.Fatalf("expected an error without the contents of the file, got %v", err)
+	}
+	if _, err := loadPrivateKeyFile(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected error for a missing file")
+	}
+}
+
+func TestDurationFlag(t *testing.T) {
+	newContext := func(args ...string) *cli.Context {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.EpochLengthFlag.Apply(set)
+		flags.EpochLengthSecondsFlag.Apply(set)
+		if err := set.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cli.NewContext(nil, set, nil)
+	}
+
+	tests := []struct {
+		args     []string
+		expected time.Duration
+	}{
+		{nil, 10 * time.Second},
+		{[]string{"--epoch-length", "2m"}, 2 * time.Minute},
+		{[]string{"--epoch-length-seconds", "30"}, 30 * time.Second},
+		// The duration option takes precedence over the deprecated option
+		{[]string{"--epoch-length", "15s", "--epoch-length-seconds", "30"}, 15 * time.Second},
+	}
+	for _, tt := range tests {
+		ctx := newContext(tt.args...)
+		got := durationFlag(ctx, nil, flags.EpochLengthFlag, flags.EpochLengthSecondsFlag)
+		if got != tt.expected {
+			t.Fatalf("%v: expected %s, got %s", tt.args, tt.expected, got)
+		}
+	}
+
+	// The deprecated option is reported so that --strict-config rejects it
+	var warnings configWarnings
+	durationFlag(newContext("--epoch-length-seconds", "30"), &warnings, flags.EpochLengthFlag,
+		flags.EpochLengthSecondsFlag)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "deprecated") {
+		t.Fatalf("expected a deprecation warning, got %v", warnings)
+	}
+}
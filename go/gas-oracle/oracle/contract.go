@@ -0,0 +1,203 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// eip1967ImplementationSlot is the storage slot of the implementation of an
+// EIP-1967 proxy, bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1)
+var eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// contractBackend is the part of a backend that is used to inspect the
+// gas price oracle contract
+type contractBackend interface {
+	bind.ContractCaller
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// probedMethods are the methods of the versions of the gas price oracle.
+// Their selectors are looked up in the code of the contract to tell which
+// version is deployed.
+var probedMethods = []string{
+	"owner()",
+	"gasPrice()",
+	"setGasPrice(uint256)",
+	"l1BaseFee()",
+	"setL1BaseFee(uint256)",
+	"overhead()",
+	"scalar()",
+	"decimals()",
+	"version()",
+	"pendingOwner()",
+}
+
+// The versions of the gas price oracle that are detected
+const (
+	// contractVersionOVM is the OVM_GasPriceOracle that is owned by the
+	// oracle and sets the L2 gas price and the L1 base fee
+	contractVersionOVM = "ovm"
+	// contractVersionOVMGasPrice is the first OVM_GasPriceOracle, which
+	// only has the L2 gas price
+	contractVersionOVMGasPrice = "ovm-gas-price"
+	// contractVersionBedrock is the GasPriceOracle predeploy of Bedrock,
+	// which only reads the fee parameters of the L1Block predeploy
+	contractVersionBedrock = "bedrock"
+)
+
+// gasPriceOracleContract describes the contract at the gas price oracle
+// address
+type gasPriceOracleContract struct {
+	Address common.Address
+	// Implementation is the implementation of an EIP-1967 proxy, it is the
+	// zero address when the contract is not a proxy
+	Implementation common.Address
+	// Owner is the zero address when the contract has no owner
+	Owner common.Address
+	// PendingOwner is set when the contract has a two-step ownership and a
+	// transfer was started that the new owner did not accept yet
+	PendingOwner common.Address
+	GasPrice     *big.Int
+	// Version is the detected version and SemVer is returned by version(),
+	// which the OVM_GasPriceOracle does not implement
+	Version string
+	SemVer  string
+	// methods are the probed methods that the contract implements, by name
+	methods map[string]bool
+}
+
+// implements returns true when the contract implements the method, such as
+// "setL1BaseFee"
+func (c *gasPriceOracleContract) implements(method string) bool {
+	return c.methods[method]
+}
+
+// resolveGasPriceOracle checks that the address exposes the gas price oracle
+// ABI, so that a wrong address fails at startup rather than with every
+// update. The gas price oracle can be deployed behind an EIP-1967 proxy, in
+// which case the calls go through the proxy and the implementation is
+// resolved to be reported.
+func resolveGasPriceOracle(ctx context.Context, backend contractBackend, address common.Address) (*gasPriceOracleContract, error) {
+	code, err := backend.CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("%w: no contract at %s", errNotGasPriceOracle, address.Hex())
+	}
+	contract := &gasPriceOracleContract{Address: address}
+
+	slot, err := backend.StorageAt(ctx, address, eip1967ImplementationSlot, nil)
+	if err != nil {
+		return nil, err
+	}
+	if implementation := common.BytesToAddress(slot); implementation != (common.Address{}) {
+		code, err = backend.CodeAt(ctx, implementation, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(code) == 0 {
+			return nil, fmt.Errorf("%w: proxy %s points to %s which has no code", errNotGasPriceOracle,
+				address.Hex(), implementation.Hex())
+		}
+		contract.Implementation = implementation
+	}
+
+	// The selectors are in the code of the implementation, since a proxy
+	// delegates every call
+	contract.methods = probeMethods(code)
+
+	caller, err := bindings.NewGasPriceOracleCaller(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	if contract.GasPrice, err = caller.GasPrice(opts); err != nil {
+		return nil, fmt.Errorf("%w: %s does not implement gasPrice(): %v", errNotGasPriceOracle, address.Hex(), err)
+	}
+	if contract.implements("version") {
+		if contract.SemVer, err = callVersion(ctx, backend, address); err != nil {
+			return nil, fmt.Errorf("%w: %s does not implement version(): %v", errNotGasPriceOracle, address.Hex(), err)
+		}
+	}
+	switch {
+	case contract.implements("setGasPrice") && contract.implements("setL1BaseFee"):
+		contract.Version = contractVersionOVM
+	case contract.implements("setGasPrice"):
+		contract.Version = contractVersionOVMGasPrice
+	case contract.SemVer != "":
+		contract.Version = contractVersionBedrock
+	default:
+		return nil, fmt.Errorf("%w: %s implements neither setGasPrice(uint256) nor version()",
+			errNotGasPriceOracle, address.Hex())
+	}
+	if contract.Version != contractVersionBedrock {
+		if contract.Owner, err = caller.Owner(opts); err != nil {
+			return nil, fmt.Errorf("%w: %s does not implement owner(): %v", errNotGasPriceOracle, address.Hex(), err)
+		}
+	}
+	if contract.implements("pendingOwner") {
+		if contract.PendingOwner, err = newPendingOwnerCaller(address, backend)(opts); err != nil {
+			return nil, fmt.Errorf("cannot get pending owner of %s: %w", address.Hex(), err)
+		}
+	}
+
+	if contract.Implementation != (common.Address{}) {
+		log.Info("Resolved gas price oracle proxy", "address", address.Hex(),
+			"implementation", contract.Implementation.Hex(), "version", contract.Version,
+			"semver", contract.SemVer, "owner", contract.Owner.Hex())
+	} else {
+		log.Info("Resolved gas price oracle", "address", address.Hex(), "version", contract.Version,
+			"semver", contract.SemVer, "owner", contract.Owner.Hex())
+	}
+	return contract, nil
+}
+
+// probeMethods returns the probed methods whose selectors are pushed by the
+// code. The Solidity dispatcher compares the selector of a call with each
+// selector of the contract, which it pushes with the shortest PUSH that
+// fits. A selector that appears in the code by accident is not ruled out,
+// but it makes no difference as the calls of a missing method fail anyway.
+func probeMethods(code []byte) map[string]bool {
+	methods := make(map[string]bool)
+	for _, signature := range probedMethods {
+		selector := crypto.Keccak256([]byte(signature))[:4]
+		trimmed := bytes.TrimLeft(selector, "\x00")
+		// PUSH1 is 0x60, so PUSHn is 0x5f + n
+		push := append([]byte{byte(0x5f + len(trimmed))}, trimmed...)
+		if len(trimmed) > 0 && bytes.Contains(code, push) {
+			methods[signature[:strings.IndexByte(signature, '(')]] = true
+		}
+	}
+	return methods
+}
+
+// callVersion returns the semantic version of a contract that implements
+// version()
+func callVersion(ctx context.Context, backend bind.ContractCaller, address common.Address) (string, error) {
+	data, err := backend.CallContract(ctx, ethereum.CallMsg{
+		To:   &address,
+		Data: crypto.Keccak256([]byte("version()"))[:4],
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	values, err := abi.Arguments{{Type: stringType}}.Unpack(data)
+	if err != nil {
+		return "", err
+	}
+	return values[0].(string), nil
+}
+
+var stringType, _ = abi.NewType("string", "", nil)
@@ -0,0 +1,132 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestResolveGasPriceOracle(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	ctx := context.Background()
+	resolved, err := resolveGasPriceOracle(ctx, sim, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Owner != opts.From || resolved.Implementation != (common.Address{}) {
+		t.Fatalf("unexpected contract %+v", resolved)
+	}
+	if resolved.Version != contractVersionOVM || !resolved.implements("setL1BaseFee") || resolved.implements("version") {
+		t.Fatalf("unexpected version %q with methods %v", resolved.Version, resolved.methods)
+	}
+	if resolved.GasPrice == nil {
+		t.Fatal("no gas price")
+	}
+
+	// An account without code cannot be the gas price oracle
+	_, err = resolveGasPriceOracle(ctx, sim, opts.From)
+	if !errors.Is(err, errNotGasPriceOracle) {
+		t.Fatalf("expected %v, got %v", errNotGasPriceOracle, err)
+	}
+
+	// A proxy is resolved to its implementation. The proxy is simulated
+	// with the code of the gas price oracle and the implementation slot.
+	code, err := sim.CodeAt(ctx, addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := common.HexToAddress("0x4200000000000000000000000000000000000099")
+	implementation := common.HexToAddress("0x4200000000000000000000000000000000000098")
+	broken := common.HexToAddress("0x4200000000000000000000000000000000000097")
+	other := common.HexToAddress("0x4200000000000000000000000000000000000096")
+	missing := common.HexToAddress("0x4200000000000000000000000000000000000095")
+	bedrock := common.HexToAddress("0x420000000000000000000000000000000000000F")
+	alloc := core.GenesisAlloc{
+		proxy: {
+			Code:    code,
+			Balance: big.NewInt(0),
+			Storage: map[common.Hash]common.Hash{
+				eip1967ImplementationSlot: common.BytesToHash(implementation.Bytes()),
+			},
+		},
+		implementation: {Code: code, Balance: big.NewInt(0)},
+		broken: {
+			Code:    code,
+			Balance: big.NewInt(0),
+			Storage: map[common.Hash]common.Hash{
+				eip1967ImplementationSlot: common.BytesToHash(missing.Bytes()),
+			},
+		},
+		// STOP returns no data, so the calls cannot be decoded
+		other:   {Code: []byte{0x00}, Balance: big.NewInt(0)},
+		bedrock: {Code: bedrockGasPriceOracleCode(42, "1.0.0"), Balance: big.NewInt(0)},
+	}
+	proxied := backends.NewSimulatedBackend(alloc, 9_000_000)
+
+	resolved, err = resolveGasPriceOracle(ctx, proxied, proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Address != proxy || resolved.Implementation != implementation {
+		t.Fatalf("unexpected contract %+v", resolved)
+	}
+
+	// The Bedrock predeploy has a version but no owner
+	resolved, err = resolveGasPriceOracle(ctx, proxied, bedrock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Version != contractVersionBedrock || resolved.SemVer != "1.0.0" ||
+		resolved.Owner != (common.Address{}) || resolved.GasPrice.Uint64() != 42 {
+		t.Fatalf("unexpected contract %+v", resolved)
+	}
+
+	// The contract does not expose the ABI of the gas price oracle
+	_, err = resolveGasPriceOracle(ctx, proxied, other)
+	if !errors.Is(err, errNotGasPriceOracle) {
+		t.Fatalf("expected %v, got %v", errNotGasPriceOracle, err)
+	}
+	// The implementation of the proxy has no code
+	_, err = resolveGasPriceOracle(ctx, proxied, broken)
+	if !errors.Is(err, errNotGasPriceOracle) {
+		t.Fatalf("expected %v, got %v", errNotGasPriceOracle, err)
+	}
+}
+
+// bedrockGasPriceOracleCode assembles the runtime code of a contract that
+// only implements gasPrice() and version(), like the GasPriceOracle
+// predeploy of Bedrock. The version must fit in 32 bytes.
+func bedrockGasPriceOracleCode(gasPrice byte, version string) []byte {
+	selector := func(signature string) []byte {
+		return crypto.Keccak256([]byte(signature))[:4]
+	}
+	// Load the selector of the call
+	code := []byte{0x60, 0x00, 0x35, 0x60, 0xe0, 0x1c}
+	// Jump to the method with the selector, revert otherwise
+	code = append(append(append(code, 0x80, 0x63), selector("gasPrice()")...), 0x14, 0x60, 30, 0x57)
+	code = append(append(append(code, 0x80, 0x63), selector("version()")...), 0x14, 0x60, 41, 0x57)
+	code = append(code, 0x60, 0x00, 0x80, 0xfd)
+	// gasPrice() returns the gas price
+	code = append(code, 0x5b, 0x60, gasPrice, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3)
+	// version() returns the offset, the length and the padded version
+	code = append(code, 0x5b, 0x60, 0x20, 0x60, 0x00, 0x52, 0x60, byte(len(version)), 0x60, 0x20, 0x52, 0x7f)
+	code = append(code, common.RightPadBytes([]byte(version), 32)...)
+	return append(code, 0x60, 0x40, 0x52, 0x60, 0x60, 0x60, 0x00, 0xf3)
+}
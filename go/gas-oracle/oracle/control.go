@@ -0,0 +1,429 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var pausedGauge = metrics.NewRegisteredGauge("paused", ometrics.DefaultRegistry)
+
+var (
+	errInvalidFloorPrice  = errors.New("floor price must be positive")
+	errInvalidTargetGas   = errors.New("target gas per second must be positive")
+	errFloorAboveMaxPrice = errors.New("floor price cannot exceed the max gas price")
+	errScheduledTarget    = errors.New("target gas per second follows a schedule")
+	errL2GasPriceDisabled = errors.New("L2 gas price updates are disabled")
+)
+
+// controls is the state of the oracle that can be changed at runtime by
+// the admin API. The changes are made from the goroutines of the API, so
+// the floor price and the target are applied to the config and the pricer
+// by the update loop at the start of the next epoch.
+type controls struct {
+	mu                 sync.Mutex
+	paused             bool
+	floorPrice         *big.Int
+	targetGasPerSecond uint64
+	// changed is set when the floor price or the target have not been
+	// applied yet
+	changed bool
+	// forced is set when the next update must be sent even if it would be
+	// held back
+	forced bool
+	// resync is set when the pricer must be moved to the on-chain gas price
+	// at the start of the next epoch, which is the case after a pause
+	resync bool
+
+	epochs       uint64
+	updates      uint64
+	lastDecision *Decision
+	// pending are the transactions that were sent without a receipt yet
+	pending []common.Hash
+	// subscribers receive the decision of every epoch
+	subscribers map[chan *Decision]struct{}
+	metrics     *chainMetrics
+}
+
+func newControls(cfg *Config) *controls {
+	return &controls{
+		floorPrice:         cfg.floorPrice,
+		targetGasPerSecond: cfg.targetGasPerSecond,
+		metrics:            cfg.chainMetrics,
+	}
+}
+
+// isPaused returns true when no transactions should be sent, a nil controls
+// is never paused
+func (c *controls) isPaused() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+// setPaused pauses or resumes the updates and returns true when the state
+// changed. Resuming resynchronizes the pricer with the chain, since the gas
+// price may have been changed while paused.
+func (c *controls) setPaused(paused bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused == paused {
+		return false
+	}
+	c.paused = paused
+	if !paused {
+		c.resync = true
+	}
+	pausedGauge.Update(boolGauge(paused))
+	c.metrics.updatePaused(paused)
+	return true
+}
+
+// requestResync resynchronizes the pricer with the chain at the start of the
+// next epoch
+func (c *controls) requestResync() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resync = true
+}
+
+// takeResync returns true once after resuming or a requested resync, a nil
+// controls never needs a resync
+func (c *controls) takeResync() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resync := c.resync
+	c.resync = false
+	return resync
+}
+
+// setForced makes the next update be sent even if it would be held back
+func (c *controls) setForced() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forced = true
+}
+
+// takeForced returns true once after setForced, a nil controls is never
+// forced
+func (c *controls) takeForced() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	forced := c.forced
+	c.forced = false
+	return forced
+}
+
+// observeDecision records the decision of an epoch
+func (c *controls) observeDecision(d *Decision) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epochs++
+	c.lastDecision = d
+	for ch := range c.subscribers {
+		// A slow subscriber misses decisions instead of delaying the epoch
+		select {
+		case ch <- d:
+		default:
+			log.Warn("Dropping decision for a slow subscriber")
+		}
+	}
+}
+
+// subscribe returns a channel that receives the decision of every epoch
+// and a function that closes it
+func (c *controls) subscribe() (<-chan *Decision, func()) {
+	ch := make(chan *Decision, 16)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subscribers == nil {
+		c.subscribers = make(map[chan *Decision]struct{})
+	}
+	c.subscribers[ch] = struct{}{}
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			delete(c.subscribers, ch)
+			close(ch)
+		})
+	}
+}
+
+// observeSent records a transaction that was sent
+func (c *controls) observeSent(hash common.Hash) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updates++
+	c.pending = append(c.pending, hash)
+}
+
+// apply moves the changed floor price and target to the config and the
+// pricer. It must be called from the update loop.
+func (c *controls) apply(cfg *Config, pricer interface{ SetFloor(*big.Int) error }) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.changed {
+		return nil
+	}
+	if c.floorPrice != nil && (cfg.floorPrice == nil || c.floorPrice.Cmp(cfg.floorPrice) != 0) {
+		if err := pricer.SetFloor(c.floorPrice); err != nil {
+			return err
+		}
+		log.Info("Applied floor price", "previous", cfg.floorPrice, "floor-price", c.floorPrice)
+		cfg.floorPrice = c.floorPrice
+	}
+	if c.targetGasPerSecond != cfg.targetGasPerSecond {
+		log.Info("Applied target gas per second", "previous", cfg.targetGasPerSecond,
+			"target-gas-per-second", c.targetGasPerSecond)
+		cfg.targetGasPerSecond = c.targetGasPerSecond
+	}
+	c.changed = false
+	return nil
+}
+
+// RuntimeState is the state of a running oracle
+type RuntimeState struct {
+	Paused bool `json:"paused"`
+	// Standby is true when the replica does not hold the lease of the leader
+	// in high availability mode
+	Standby bool `json:"standby"`
+	// GasPrice is the L2 gas price of the contract and LocalGasPrice is the
+	// gas price of the pricer
+	GasPrice           *big.Int `json:"gasPrice"`
+	LocalGasPrice      *big.Int `json:"localGasPrice"`
+	FloorPrice         *big.Int `json:"floorPrice"`
+	TargetGasPerSecond uint64   `json:"targetGasPerSecond"`
+	// Epochs and Updates count the epochs that were decided and the
+	// updates that were sent since startup
+	Epochs          uint64    `json:"epochs"`
+	Updates         uint64    `json:"updates"`
+	AvgGasPerSecond float64   `json:"avgGasPerSecond"`
+	LastDecision    *Decision `json:"lastDecision,omitempty"`
+	// PendingTransactions are the sent transactions that are not mined yet
+	PendingTransactions []common.Hash `json:"pendingTransactions"`
+}
+
+// State returns the runtime state of the oracle. The receipts of the
+// pending transactions are fetched to drop the ones that were mined.
+func (g *GasPriceOracle) State(ctx context.Context) (*RuntimeState, error) {
+	caller, err := bindings.NewGasPriceOracleCaller(g.config.gasPriceOracleAddress, g.l2Backend)
+	if err != nil {
+		return nil, err
+	}
+	price, err := caller.GasPrice(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get gas price: %w", err)
+	}
+
+	g.settlePending(ctx)
+
+	// The updater holds its lock while the update loop takes the lock of
+	// the controls, so it is read before the lock of the controls is taken
+	localGasPrice := g.gasPriceUpdater.GetGasPrice()
+	avgGasPerSecond := g.epoch.get()
+	standby := !g.config.leader.isLeader()
+
+	c := g.config.controls
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &RuntimeState{
+		Paused:              c.paused,
+		Standby:             standby,
+		GasPrice:            price,
+		LocalGasPrice:       localGasPrice,
+		FloorPrice:          c.floorPrice,
+		TargetGasPerSecond:  c.targetGasPerSecond,
+		Epochs:              c.epochs,
+		Updates:             c.updates,
+		AvgGasPerSecond:     avgGasPerSecond,
+		LastDecision:        c.lastDecision,
+		PendingTransactions: append([]common.Hash{}, c.pending...),
+	}, nil
+}
+
+// settlePending fetches the receipts of the pending transactions, drops the
+// ones that were mined and returns the ones that are still pending
+func (g *GasPriceOracle) settlePending(ctx context.Context) []common.Hash {
+	c := g.config.controls
+	c.mu.Lock()
+	pending := append([]common.Hash(nil), c.pending...)
+	c.mu.Unlock()
+	mined := make(map[common.Hash]bool)
+	for _, hash := range pending {
+		if g.isMined(ctx, hash) {
+			mined[hash] = true
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var stillPending []common.Hash
+	for _, hash := range c.pending {
+		if !mined[hash] {
+			stillPending = append(stillPending, hash)
+		}
+	}
+	c.pending = stillPending
+	return append([]common.Hash(nil), stillPending...)
+}
+
+// isMined returns true when the transaction has a receipt
+func (g *GasPriceOracle) isMined(ctx context.Context, hash common.Hash) bool {
+	receipt, err := g.l2Backend.TransactionReceipt(ctx, hash)
+	return err == nil && receipt != nil
+}
+
+// Pause stops sending transactions. The epochs are still measured and
+// decided.
+func (g *GasPriceOracle) Pause() {
+	if g.config.controls.setPaused(true) {
+		log.Warn("Paused updates")
+	}
+}
+
+// Resume sends transactions again. The pricer is resynchronized with the
+// on-chain gas price at the start of the next epoch.
+func (g *GasPriceOracle) Resume() {
+	if g.config.controls.setPaused(false) {
+		log.Info("Resumed updates")
+	}
+}
+
+// TogglePause pauses running updates and resumes paused updates
+func (g *GasPriceOracle) TogglePause() {
+	if g.config.controls.isPaused() {
+		g.Resume()
+	} else {
+		g.Pause()
+	}
+}
+
+// ForceUpdate closes the current epoch now and sends the gas price that it
+// evaluates, even when the significance factor or the rate limit would hold
+// it back, and returns its decision. The max gas price and the max change
+// still apply. It runs in the update loop so that it does not race an epoch.
+func (g *GasPriceOracle) ForceUpdate(ctx context.Context) (*Decision, error) {
+	if !g.config.enableL2GasPrice {
+		return nil, errL2GasPriceDisabled
+	}
+	reply := make(chan error, 1)
+	select {
+	case g.force <- reply:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case err := <-reply:
+		if err != nil {
+			return nil, err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	c := g.config.controls
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastDecision, nil
+}
+
+// forceUpdate closes the current epoch after it ran for the elapsed time and
+// sends its gas price from the update loop. An epoch without new blocks
+// evaluates nothing, so the current gas price of the pricer is sent instead.
+func (g *GasPriceOracle) forceUpdate(elapsed time.Duration) error {
+	log.Warn("Forcing an update of the L2 gas price", "elapsed", elapsed)
+	c := g.config.controls
+	c.setForced()
+	err := g.watchdog.run(g.ctx, func(ctx context.Context) error {
+		return g.runEpoch(ctx, func() error {
+			return g.gasPriceUpdater.CloseEpoch(elapsed)
+		})
+	})
+	if err != nil {
+		// A failed epoch must not force the next one
+		c.takeForced()
+		return err
+	}
+	if c.takeForced() {
+		c.setForced()
+		return g.updateL2GasPrice(g.gasPriceUpdater.GetGasPrice())
+	}
+	return nil
+}
+
+// SubscribeDecisions returns a channel that receives the decision of every
+// epoch until the returned function is called
+func (g *GasPriceOracle) SubscribeDecisions() (<-chan *Decision, func()) {
+	return g.config.controls.subscribe()
+}
+
+// SetFloorPrice sets the floor price from the next epoch
+func (g *GasPriceOracle) SetFloorPrice(floorPrice *big.Int) error {
+	if floorPrice == nil || floorPrice.Sign() <= 0 {
+		return errInvalidFloorPrice
+	}
+	if g.config.maxGasPrice != nil && floorPrice.Cmp(g.config.maxGasPrice) > 0 {
+		return errFloorAboveMaxPrice
+	}
+	c := g.config.controls
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	log.Info("Setting floor price", "previous", c.floorPrice, "floor-price", floorPrice)
+	g.config.auditLog.recordChange(AuditKindFloorPrice, c.floorPrice, floorPrice)
+	c.floorPrice = new(big.Int).Set(floorPrice)
+	c.changed = true
+	return nil
+}
+
+// SetTargetGasPerSecond sets the target gas per second from the next epoch.
+// The demand of the current epoch and the pending transactions are kept.
+func (g *GasPriceOracle) SetTargetGasPerSecond(target uint64) error {
+	if target == 0 {
+		return errInvalidTargetGas
+	}
+	if len(g.config.targetGasSchedule) > 0 {
+		return errScheduledTarget
+	}
+	c := g.config.controls
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	log.Info("Setting target gas per second", "previous", c.targetGasPerSecond,
+		"target-gas-per-second", target)
+	g.config.auditLog.recordChange(AuditKindTargetGasPerSecond,
+		new(big.Int).SetUint64(c.targetGasPerSecond), new(big.Int).SetUint64(target))
+	c.targetGasPerSecond = target
+	c.changed = true
+	return nil
+}
@@ -0,0 +1,106 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+)
+
+func TestControlsPause(t *testing.T) {
+	c := newControls(&Config{floorPrice: big.NewInt(1)})
+	if c.takeResync() {
+		t.Fatal("expected no resync before a pause")
+	}
+	if !c.setPaused(true) || c.setPaused(true) {
+		t.Fatal("expected only the first pause to change the state")
+	}
+	if !c.isPaused() || c.takeResync() {
+		t.Fatal("expected paused without a resync")
+	}
+	if !c.setPaused(false) || c.setPaused(false) {
+		t.Fatal("expected only the first resume to change the state")
+	}
+	if !c.takeResync() {
+		t.Fatal("expected a resync after resuming")
+	}
+	if c.takeResync() {
+		t.Fatal("expected a single resync")
+	}
+
+	var nilControls *controls
+	if nilControls.isPaused() || nilControls.takeResync() || nilControls.takeForced() {
+		t.Fatal("expected a nil controls to be inactive")
+	}
+}
+
+func TestForceUpdateClosesEpoch(t *testing.T) {
+	gpo, pricer, sim := newControlledOracle(t)
+	if err := gpo.updateL2GasPrice(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if err := pricer.SetGasPrice(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	// A single block 4% above the target moves the price by less than the
+	// significance factor
+	latest := func() (uint64, error) { return 1, nil }
+	gasUsed := func(*big.Int) (uint64, error) { return 11_440_000, nil }
+	updater, err := gasprices.NewGasPriceUpdater(pricer, 0, 11_000_000, time.Second, latest, gasUsed,
+		gpo.updateL2GasPrice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpo.gasPriceUpdater = updater
+
+	if err := gpo.forceUpdate(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	decision := gpo.config.controls.lastDecision
+	if !decision.Send || decision.Reason != ReasonForced || decision.GasPrice.Uint64() != 104 {
+		t.Fatalf("unexpected decision %+v", decision)
+	}
+	if gpo.config.controls.takeForced() {
+		t.Fatal("expected the forced update to be taken")
+	}
+}
+
+func TestStateDuringCloseEpoch(t *testing.T) {
+	gpo, pricer, _ := newControlledOracle(t)
+	var latest uint64
+	entered := make(chan struct{}, 1)
+	updater, err := gasprices.NewGasPriceUpdater(pricer, 0, 11_000_000, time.Second,
+		func() (uint64, error) { latest++; return latest, nil },
+		func(*big.Int) (uint64, error) { return 0, nil },
+		func(gasPrice *big.Int) error {
+			// Let the state be read while the updater holds its lock
+			entered <- struct{}{}
+			time.Sleep(50 * time.Millisecond)
+			return gpo.updateL2GasPrice(gasPrice)
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpo.gasPriceUpdater = updater
+
+	done := make(chan error, 2)
+	go func() { done <- updater.UpdateGasPrice() }()
+	go func() {
+		<-entered
+		_, err := gpo.State(context.Background())
+		done <- err
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the state and the epoch deadlocked")
+		}
+	}
+}
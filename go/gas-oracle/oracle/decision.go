@@ -0,0 +1,205 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Metrics of the most recent epoch, so that dashboards can reconstruct the
+// pricing history without the logs
+var (
+	epochCounter            = metrics.NewRegisteredCounter("epoch/count", ometrics.DefaultRegistry)
+	epochDemandGauge        = metrics.NewRegisteredGaugeFloat64("epoch/avg_gas_per_second", ometrics.DefaultRegistry)
+	epochCurrentPriceGauge  = metrics.NewRegisteredGauge("epoch/current_price", ometrics.DefaultRegistry)
+	epochComputedPriceGauge = metrics.NewRegisteredGauge("epoch/computed_price", ometrics.DefaultRegistry)
+	epochGasPriceGauge      = metrics.NewRegisteredGauge("epoch/gas_price", ometrics.DefaultRegistry)
+	epochChangeGauge        = metrics.NewRegisteredGaugeFloat64("epoch/change", ometrics.DefaultRegistry)
+	epochClampedMaxGauge    = metrics.NewRegisteredGauge("epoch/clamped_max", ometrics.DefaultRegistry)
+	epochFlooredGauge       = metrics.NewRegisteredGauge("epoch/floored", ometrics.DefaultRegistry)
+	epochSentGauge          = metrics.NewRegisteredGauge("epoch/sent", ometrics.DefaultRegistry)
+	epochTxNonceGauge       = metrics.NewRegisteredGauge("epoch/tx_nonce", ometrics.DefaultRegistry)
+	epochTxSentAtGauge      = metrics.NewRegisteredGauge("epoch/tx_sent_at", ometrics.DefaultRegistry)
+)
+
+// ReasonCode is a machine readable explanation of a gas price decision
+type ReasonCode string
+
+const (
+	// ReasonUpdated means that the gas price is sent
+	ReasonUpdated ReasonCode = "UPDATED"
+	// ReasonDryRun means that the gas price would have been sent
+	ReasonDryRun ReasonCode = "DRY_RUN"
+	// ReasonPaused means that the gas price would have been sent if the
+	// updates were not paused
+	ReasonPaused ReasonCode = "PAUSED"
+	// ReasonStandby means that the gas price would have been sent if the
+	// replica held the lease of the leader
+	ReasonStandby ReasonCode = "STANDBY"
+	// ReasonStaged means that the gas price waits for the approval of a
+	// second operator before it is sent
+	ReasonStaged ReasonCode = "STAGED"
+	// ReasonForced means that the gas price is sent by an operator although
+	// it would have been held back
+	ReasonForced ReasonCode = "FORCED"
+	// ReasonUnchanged means that the gas price is already the current price
+	ReasonUnchanged ReasonCode = "UNCHANGED"
+	// ReasonBelowSignificance means that the change is below the
+	// significance factor
+	ReasonBelowSignificance ReasonCode = "BELOW_SIGNIFICANCE"
+	// ReasonRateLimited means that an update was sent too recently
+	ReasonRateLimited ReasonCode = "RATE_LIMITED"
+	// ReasonClampedMax means that the gas price is held at the max price
+	ReasonClampedMax ReasonCode = "CLAMPED_MAX"
+	// ReasonFloored means that the gas price is held at the floor price
+	ReasonFloored ReasonCode = "FLOORED"
+)
+
+// Decision records why the L2 gas price was or was not updated in an epoch
+type Decision struct {
+	Time time.Time `json:"time"`
+	// AvgGasPerSecond is the demand that the gas pricer saw
+	AvgGasPerSecond float64 `json:"avgGasPerSecond"`
+	// CurrentPrice is the L2 gas price before the decision
+	CurrentPrice *big.Int `json:"currentPrice"`
+	// ComputedPrice is the output of the gas pricer
+	ComputedPrice *big.Int `json:"computedPrice"`
+	// GasPrice is the computed price after rounding and capping
+	GasPrice *big.Int `json:"gasPrice"`
+	// Change is the relative difference between the current and the
+	// candidate gas price that is compared to the significance factor
+	Change             float64 `json:"change"`
+	SignificanceFactor float64 `json:"significanceFactor"`
+	// Send is true when the gas price should be sent
+	Send   bool       `json:"send"`
+	Reason ReasonCode `json:"reason"`
+	// Bound is set when the gas price is held at the floor or max price
+	Bound ReasonCode `json:"bound,omitempty"`
+}
+
+// Action returns "update" when the gas price is sent and "skip" otherwise
+func (d *Decision) Action() string {
+	if d.Send {
+		return "update"
+	}
+	return "skip"
+}
+
+// Capped returns true when the update is held back by the rate limit or by
+// the max gas price
+func (d *Decision) Capped() bool {
+	return !d.Send && (d.Reason == ReasonRateLimited || d.Bound == ReasonClampedMax)
+}
+
+// Event returns the notification of the decision
+func (d *Decision) Event(t notify.EventType, chainID *big.Int) *notify.Event {
+	return &notify.Event{
+		Type:         t,
+		Time:         d.Time,
+		ChainID:      chainID,
+		CurrentPrice: d.CurrentPrice,
+		GasPrice:     d.GasPrice,
+		Reason:       string(d.Reason),
+		Bound:        string(d.Bound),
+	}
+}
+
+// Log emits the decision as a structured log line and counts its reason
+func (d *Decision) Log() {
+	ctx := []interface{}{
+		"action", d.Action(), "reason", d.Reason,
+		"current-price", d.CurrentPrice, "computed-price", d.ComputedPrice,
+		"gas-price", d.GasPrice, "avg-gas-per-second", d.AvgGasPerSecond,
+		"change", d.Change, "significance-factor", d.SignificanceFactor,
+	}
+	if d.Bound != "" {
+		ctx = append(ctx, "bound", d.Bound)
+	}
+	log.Info("gas price decision", ctx...)
+
+	name := "decision/" + strings.ToLower(string(d.Reason))
+	metrics.GetOrRegisterCounter(name, ometrics.DefaultRegistry).Inc(1)
+	d.updateMetrics()
+}
+
+// updateMetrics exports the decision as the metrics of the epoch
+func (d *Decision) updateMetrics() {
+	epochCounter.Inc(1)
+	epochDemandGauge.Update(d.AvgGasPerSecond)
+	epochCurrentPriceGauge.Update(int64(d.CurrentPrice.Uint64()))
+	epochComputedPriceGauge.Update(int64(d.ComputedPrice.Uint64()))
+	epochGasPriceGauge.Update(int64(d.GasPrice.Uint64()))
+	epochChangeGauge.Update(d.Change)
+	epochClampedMaxGauge.Update(boolGauge(d.Bound == ReasonClampedMax))
+	epochFlooredGauge.Update(boolGauge(d.Bound == ReasonFloored))
+	epochSentGauge.Update(boolGauge(d.Send))
+}
+
+// updateTxMetrics exports the transaction sent in the epoch. The hash is in
+// the logs and the trace of the transaction.
+func updateTxMetrics(tx *types.Transaction, sentAt time.Time) {
+	epochTxNonceGauge.Update(int64(tx.Nonce()))
+	epochTxSentAtGauge.Update(sentAt.Unix())
+}
+
+func boolGauge(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// epochState is a DemandObserver that keeps the most recent demand so that
+// it can be included in the decision of the epoch. It also carries the
+// context of the epoch that is being processed so that the spans created
+// while processing it share a trace.
+type epochState struct {
+	mu              sync.Mutex
+	ctx             context.Context
+	avgGasPerSecond float64
+}
+
+func (e *epochState) ObserveDemand(avgGasPerSecond float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.avgGasPerSecond = avgGasPerSecond
+}
+
+// get returns the most recent demand, a nil epochState has no demand
+func (e *epochState) get() float64 {
+	if e == nil {
+		return 0
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.avgGasPerSecond
+}
+
+// setContext sets the context of the epoch that is being processed
+func (e *epochState) setContext(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ctx = ctx
+}
+
+// context returns the context of the epoch that is being processed,
+// falling back to the background context outside of an epoch
+func (e *epochState) context() context.Context {
+	if e == nil {
+		return context.Background()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
@@ -0,0 +1,176 @@
+package oracle
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// recordDecision adds the decision of an epoch to the state database, so
+// that it can be exported. The decision is signed when the oracle has a
+// private key.
+func recordDecision(cfg *Config, d *Decision) {
+	if cfg.stateDB == nil {
+		return
+	}
+	var record interface{} = d
+	var hash common.Hash
+	if cfg.decisionSigner != nil {
+		signed, h, err := cfg.decisionSigner.sign(d)
+		if err != nil {
+			log.Error("cannot sign decision", "message", err)
+			return
+		}
+		record, hash = signed, h
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Error("cannot encode decision", "message", err)
+		return
+	}
+	if err := cfg.stateDB.AppendDecision(d.Time, data); err != nil {
+		log.Error("cannot record decision", "message", err)
+		return
+	}
+	if cfg.decisionSigner != nil {
+		cfg.decisionSigner.prev = hash
+	}
+}
+
+// ReadDecisionHistory returns the decisions from from to to that are
+// recorded in the state database, oldest first
+func ReadDecisionHistory(db *store.StateDB, from, to time.Time) ([]*Decision, error) {
+	if to.Before(from) {
+		return nil, errors.New("the end is before the start")
+	}
+	records, err := db.Decisions(from, to)
+	if err != nil {
+		return nil, err
+	}
+	decisions := make([]*Decision, 0, len(records))
+	for _, record := range records {
+		d := new(Decision)
+		if err := json.Unmarshal(record, d); err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions, nil
+}
+
+// WriteDecisions writes the decisions as csv or as a JSON array. The JSON
+// array is a demand trace of the backtest and the simulation.
+func WriteDecisions(w io.Writer, format string, decisions []*Decision) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(decisions)
+	case "csv":
+		return writeDecisionsCSV(w, decisions)
+	default:
+		return fmt.Errorf("invalid format: %q", format)
+	}
+}
+
+func writeDecisionsCSV(out io.Writer, decisions []*Decision) error {
+	w := csv.NewWriter(out)
+	header := []string{"time", "avg_gas_per_second", "current_price", "computed_price", "gas_price",
+		"change", "significance_factor", "send", "reason", "bound"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, d := range decisions {
+		record := []string{
+			d.Time.UTC().Format(time.RFC3339Nano),
+			strconv.FormatFloat(d.AvgGasPerSecond, 'f', -1, 64),
+			bigString(d.CurrentPrice),
+			bigString(d.ComputedPrice),
+			bigString(d.GasPrice),
+			strconv.FormatFloat(d.Change, 'f', -1, 64),
+			strconv.FormatFloat(d.SignificanceFactor, 'f', -1, 64),
+			strconv.FormatBool(d.Send),
+			string(d.Reason),
+			string(d.Bound),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// decisionHistory serves the recorded decisions of a range as csv, JSON or
+// the signed records as JSON lines,
+//
+//	GET /decision-history?from=2022-01-01T00:00:00Z&to=1640998800&format=csv
+func (h *adminHandler) decisionHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	db := h.gpo.config.stateDB
+	if db == nil {
+		writeAdminError(w, http.StatusNotFound, errNoHistory)
+		return
+	}
+	query := r.URL.Query()
+	from, to, err := parseHistoryRange(query)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format == "signed" {
+		decisions, err := ReadSignedDecisions(db, from, to)
+		if err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		if err := WriteSignedDecisions(w, decisions); err != nil {
+			log.Warn("cannot write decision history", "message", err)
+		}
+		return
+	}
+	if format != "json" && format != "csv" {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("invalid format: %q", format))
+		return
+	}
+	decisions, err := ReadDecisionHistory(db, from, to)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="decisions.csv"`)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	if err := WriteDecisions(w, format, decisions); err != nil {
+		log.Warn("cannot write decision history", "message", err)
+	}
+}
+
+// bigString formats an optional number, nil is empty
+func bigString(n *big.Int) string {
+	if n == nil {
+		return ""
+	}
+	return n.String()
+}
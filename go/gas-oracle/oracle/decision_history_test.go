@@ -0,0 +1,84 @@
+package oracle
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+)
+
+func TestDecisionHistory(t *testing.T) {
+	gpo, _, _ := newControlledOracle(t)
+	db, err := store.OpenStateDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpo.config.stateDB = db
+	defer gpo.config.closeStateDB()
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		recordDecision(gpo.config, &Decision{
+			Time:            start.Add(time.Duration(i) * 10 * time.Second),
+			AvgGasPerSecond: float64(1000 * (i + 1)),
+			CurrentPrice:    big.NewInt(100),
+			ComputedPrice:   big.NewInt(110),
+			GasPrice:        big.NewInt(110),
+			Change:          0.1,
+			Send:            i == 0,
+			Reason:          ReasonUpdated,
+		})
+	}
+
+	decisions, err := ReadDecisionHistory(db, start.Add(time.Second), start.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 2 || decisions[0].AvgGasPerSecond != 2000 || decisions[0].GasPrice.Uint64() != 110 {
+		t.Fatalf("unexpected decisions %+v", decisions)
+	}
+
+	// The JSON export is a demand trace
+	var buf bytes.Buffer
+	if err := WriteDecisions(&buf, "json", decisions); err != nil {
+		t.Fatal(err)
+	}
+	samples, err := ReadDemandTrace(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[1].AvgGasPerSecond != 3000 || samples[1].GasPrice.Uint64() != 110 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	if err := WriteDecisions(&buf, "xml", decisions); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+
+	handler, err := newAdminHandler(gpo, &adminAuth{token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/decision-history?from=1640995200&to=1640995260&format=csv", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/csv" || len(lines) != 4 {
+		t.Fatalf("unexpected export %d %s", resp.StatusCode, body)
+	}
+	if lines[1] != "2022-01-01T00:00:00Z,1000,100,110,110,0.1,0,true,UPDATED," {
+		t.Fatalf("unexpected record %s", lines[1])
+	}
+}
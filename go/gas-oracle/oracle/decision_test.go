@@ -0,0 +1,119 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestDecideL2GasPrice(t *testing.T) {
+	cfg := &Config{
+		floorPrice:                   big.NewInt(10),
+		maxGasPrice:                  big.NewInt(500),
+		l2GasPriceSignificanceFactor: 0.1,
+		minUpdateInterval:            time.Minute,
+	}
+	now := time.Unix(1_000_000, 0)
+	limiter := &rateLimiter{interval: cfg.minUpdateInterval}
+
+	tests := []struct {
+		current  int64
+		computed int64
+		send     bool
+		reason   ReasonCode
+		bound    ReasonCode
+		gasPrice int64
+	}{
+		{100, 100, false, ReasonUnchanged, "", 100},
+		{100, 105, false, ReasonBelowSignificance, "", 105},
+		{100, 200, true, ReasonUpdated, "", 200},
+		{200, 1000, true, ReasonUpdated, ReasonClampedMax, 500},
+		{20, 10, true, ReasonUpdated, ReasonFloored, 10},
+	}
+	for i, tt := range tests {
+		d := decideL2GasPrice(cfg, limiter, big.NewInt(tt.current), big.NewInt(tt.computed), 1000, now)
+		if d.Send != tt.send || d.Reason != tt.reason || d.Bound != tt.bound {
+			t.Fatalf("%d: unexpected decision: send %t, reason %s, bound %s", i, d.Send, d.Reason, d.Bound)
+		}
+		if d.GasPrice.Cmp(big.NewInt(tt.gasPrice)) != 0 {
+			t.Fatalf("%d: expected gas price %d, got %s", i, tt.gasPrice, d.GasPrice)
+		}
+		if d.AvgGasPerSecond != 1000 || !d.Time.Equal(now) {
+			t.Fatalf("%d: unexpected demand or time", i)
+		}
+		if d.Send {
+			if d.Action() != "update" {
+				t.Fatalf("%d: unexpected action %s", i, d.Action())
+			}
+		} else if d.Action() != "skip" {
+			t.Fatalf("%d: unexpected action %s", i, d.Action())
+		}
+	}
+
+	// The change is recorded along with the factor that it is compared to
+	d := decideL2GasPrice(cfg, limiter, big.NewInt(100), big.NewInt(50), 0, now)
+	if d.Change != 0.5 || d.SignificanceFactor != 0.1 {
+		t.Fatalf("unexpected change %f or factor %f", d.Change, d.SignificanceFactor)
+	}
+
+	limiter.record(now)
+	d = decideL2GasPrice(cfg, limiter, big.NewInt(100), big.NewInt(200), 0, now.Add(time.Second))
+	if d.Send || d.Reason != ReasonRateLimited {
+		t.Fatalf("expected rate limited, got %s", d.Reason)
+	}
+}
+
+func TestDecisionMetrics(t *testing.T) {
+	cfg := &Config{
+		floorPrice:                   big.NewInt(10),
+		maxGasPrice:                  big.NewInt(500),
+		l2GasPriceSignificanceFactor: 0.1,
+	}
+	// The metrics of the registry are no-ops while metrics are disabled
+	gauges := []*metrics.Gauge{
+		&epochCurrentPriceGauge, &epochComputedPriceGauge, &epochGasPriceGauge, &epochClampedMaxGauge,
+		&epochFlooredGauge, &epochSentGauge, &epochTxNonceGauge, &epochTxSentAtGauge,
+	}
+	previous := make([]metrics.Gauge, len(gauges))
+	for i, gauge := range gauges {
+		previous[i], *gauge = *gauge, &metrics.StandardGauge{}
+	}
+	counter, demand, change := epochCounter, epochDemandGauge, epochChangeGauge
+	epochCounter, epochDemandGauge, epochChangeGauge = &metrics.StandardCounter{}, &metrics.StandardGaugeFloat64{}, &metrics.StandardGaugeFloat64{}
+	defer func() {
+		for i, gauge := range gauges {
+			*gauge = previous[i]
+		}
+		epochCounter, epochDemandGauge, epochChangeGauge = counter, demand, change
+	}()
+
+	now := time.Unix(1_000_000, 0)
+	d := decideL2GasPrice(cfg, &rateLimiter{}, big.NewInt(200), big.NewInt(1000), 12_000_000, now)
+	d.Log()
+	if epochCounter.Count() != 1 {
+		t.Fatal("expected the epoch to be counted")
+	}
+	if epochDemandGauge.Value() != 12_000_000 || epochCurrentPriceGauge.Value() != 200 ||
+		epochComputedPriceGauge.Value() != 1000 || epochGasPriceGauge.Value() != 500 {
+		t.Fatal("unexpected demand or prices")
+	}
+	if epochClampedMaxGauge.Value() != 1 || epochFlooredGauge.Value() != 0 || epochSentGauge.Value() != 1 {
+		t.Fatal("expected a sent update held at the max gas price")
+	}
+
+	tx := types.NewTransaction(42, common.Address{}, nil, 0, big.NewInt(1), nil)
+	updateTxMetrics(tx, now)
+	if epochTxNonceGauge.Value() != 42 || epochTxSentAtGauge.Value() != now.Unix() {
+		t.Fatal("unexpected transaction metrics")
+	}
+
+	d = decideL2GasPrice(cfg, &rateLimiter{}, big.NewInt(20), big.NewInt(1), 0, now)
+	d.Log()
+	if epochClampedMaxGauge.Value() != 0 || epochFlooredGauge.Value() != 1 {
+		t.Fatal("expected the gas price to be held at the floor")
+	}
+}
@@ -0,0 +1,76 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// demandHead reads the block that the demand is measured up to. Measuring
+// behind the tip keeps the blocks of a brief reorg or a replay of the
+// sequencer out of the accounting of the epochs, since a block that is
+// measured is not measured again. ethclient only reads the latest block, so
+// the other tags are read with the RPC client.
+type demandHead struct {
+	backend       bind.ContractBackend
+	client        *rpc.Client
+	tag           string
+	confirmations uint64
+}
+
+// newDemandHead checks that the L2 node supports the block tag of the
+// configuration
+func newDemandHead(ctx context.Context, cfg *Config, backend bind.ContractBackend, client *rpc.Client) (*demandHead, error) {
+	h := &demandHead{backend: backend, client: client, tag: cfg.demandBlockTag,
+		confirmations: cfg.demandConfirmations}
+	if h.tag == "" {
+		h.tag = "latest"
+	}
+	if h.tag != "latest" && client == nil {
+		return nil, fmt.Errorf("option %q: the %s block is read with an RPC client", flags.DemandBlockTagFlag.Name, h.tag)
+	}
+	if h.tag == "latest" && h.confirmations == 0 {
+		return h, nil
+	}
+	number, err := h.number(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("option %q: %w", flags.DemandBlockTagFlag.Name, err)
+	}
+	log.Info("Measuring the demand behind the tip", "tag", h.tag, "confirmations", h.confirmations,
+		"block", number)
+	return h, nil
+}
+
+// number returns the number of the block of the tag minus the
+// confirmations, or the genesis when the chain is shorter
+func (h *demandHead) number(ctx context.Context) (uint64, error) {
+	var number uint64
+	if h.tag == "latest" {
+		tip, err := h.backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		number = tip.Number.Uint64()
+	} else {
+		var head *struct {
+			Number hexutil.Uint64 `json:"number"`
+		}
+		if err := h.client.CallContext(ctx, &head, "eth_getBlockByNumber", h.tag, false); err != nil {
+			return 0, fmt.Errorf("cannot get the %s block: %w", h.tag, err)
+		}
+		if head == nil {
+			return 0, errors.New("the " + h.tag + " block is not known yet")
+		}
+		number = uint64(head.Number)
+	}
+	if number < h.confirmations {
+		return 0, nil
+	}
+	return number - h.confirmations, nil
+}
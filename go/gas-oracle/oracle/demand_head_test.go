@@ -0,0 +1,72 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestDemandHead(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	for i := 0; i < 5; i++ {
+		sim.Commit()
+	}
+	ctx := context.Background()
+
+	// The demand is measured up to the tip minus the confirmations
+	tests := []struct {
+		confirmations uint64
+		expected      uint64
+	}{{0, 5}, {2, 3}, {10, 0}}
+	for _, tt := range tests {
+		head, err := newDemandHead(ctx, &Config{demandConfirmations: tt.confirmations}, sim, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if number, err := head.number(ctx); err != nil || number != tt.expected {
+			t.Fatalf("%d confirmations: expected %d, got %d, %v", tt.confirmations, tt.expected, number, err)
+		}
+	}
+	if _, err := newDemandHead(ctx, &Config{demandBlockTag: "safe"}, sim, nil); err == nil {
+		t.Fatal("expected an error for the safe tag without an RPC client")
+	}
+
+	var tag string
+	safe := `{"number":"0x10"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		json.Unmarshal(req.Params[0], &tag)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, safe)
+	}))
+	defer server.Close()
+	client, err := rpc.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	head, err := newDemandHead(ctx, &Config{demandBlockTag: "safe", demandConfirmations: 1}, sim, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number, err := head.number(ctx); err != nil || number != 15 || tag != "safe" {
+		t.Fatalf("expected block 15 of the safe tag, got %d of %q, %v", number, tag, err)
+	}
+	// A node that does not know the block of the tag is rejected at startup
+	safe = "null"
+	if _, err := newDemandHead(ctx, &Config{demandBlockTag: "finalized"}, sim, client); err == nil {
+		t.Fatal("expected an error when the finalized block is unknown")
+	}
+}
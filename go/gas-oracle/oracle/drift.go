@@ -0,0 +1,57 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	driftGauge         = metrics.NewRegisteredGaugeFloat64("drift/ratio", ometrics.DefaultRegistry)
+	driftResyncCounter = metrics.NewRegisteredCounter("drift/resync", ometrics.DefaultRegistry)
+)
+
+// checkDrift compares the gas price of the gas pricer with the gas price on
+// chain. They drift apart by up to the significance factor while changes are
+// too small to send, but a larger drift means that the chain did not apply
+// an update, for example because the transaction reverted. The gas pricer is
+// then resynchronized from the chain. The chain does not show an update
+// that is not mined yet, so there is no drift until it is.
+func (g *GasPriceOracle) checkDrift(ctx context.Context, onChain *big.Int) error {
+	if g.updatePending(ctx) {
+		log.Debug("Skipping the drift check, an update is pending")
+		return nil
+	}
+	local := g.gasPriceUpdater.GetGasPrice()
+	drift, _ := relativeDifference(onChain, local).Float64()
+	driftGauge.Update(drift)
+
+	tolerance := g.config.driftTolerance
+	if tolerance <= 0 || drift <= tolerance {
+		return nil
+	}
+	// The gas pricer is expected to move away from the chain in a dry run
+	if g.config.dryRun {
+		log.Debug("gas price drift in dry run", "on-chain", onChain, "local", local, "drift", drift)
+		return nil
+	}
+	log.Warn("Gas price drifted from the chain, resynchronizing", "on-chain", onChain,
+		"local", local, "drift", drift, "tolerance", tolerance)
+	driftResyncCounter.Inc(1)
+	return g.gasPriceUpdater.SetGasPrice(onChain)
+}
+
+// updatePending returns true when an update that was sent, in this process or
+// before the checkpoint was saved, has no receipt yet
+func (g *GasPriceOracle) updatePending(ctx context.Context) bool {
+	if g.config.controls != nil && len(g.settlePending(ctx)) > 0 {
+		return true
+	}
+	if hash, ok := g.config.checkpoint.pendingTransaction(); ok {
+		return !g.isMined(ctx, hash)
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCheckDrift(t *testing.T) {
+	newOracle := func(cfg *Config) *GasPriceOracle {
+		pricer, err := gasprices.NewGasPricer(big.NewInt(1000), big.NewInt(1), func() float64 { return 1 }, 0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		noop := func() (uint64, error) { return 0, nil }
+		updater, err := gasprices.NewGasPriceUpdater(pricer, 0, 1, time.Second, noop,
+			func(*big.Int) (uint64, error) { return 0, nil },
+			func(*big.Int) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &GasPriceOracle{config: cfg, gasPriceUpdater: updater}
+	}
+
+	tests := []struct {
+		name    string
+		cfg     *Config
+		onChain int64
+		expect  int64
+	}{
+		{"disabled", &Config{}, 500, 1000},
+		{"within tolerance", &Config{driftTolerance: 0.6}, 500, 1000},
+		{"beyond tolerance", &Config{driftTolerance: 0.2}, 500, 500},
+		{"dry run", &Config{driftTolerance: 0.2, dryRun: true}, 500, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gpo := newOracle(tt.cfg)
+			if err := gpo.checkDrift(context.Background(), big.NewInt(tt.onChain)); err != nil {
+				t.Fatal(err)
+			}
+			if price := gpo.gasPriceUpdater.GetGasPrice(); price.Int64() != tt.expect {
+				t.Fatalf("expected %d, got %s", tt.expect, price)
+			}
+		})
+	}
+}
+
+func TestCheckDriftPending(t *testing.T) {
+	gpo, _, _ := newControlledOracle(t)
+	gpo.config.driftTolerance = 0.2
+	local := gpo.gasPriceUpdater.GetGasPrice()
+
+	// The chain does not show an update that is not mined yet
+	gpo.config.controls.pending = []common.Hash{common.HexToHash("0x01")}
+	if err := gpo.checkDrift(context.Background(), big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	if price := gpo.gasPriceUpdater.GetGasPrice(); price.Cmp(local) != 0 {
+		t.Fatalf("expected %s while the update is pending, got %s", local, price)
+	}
+
+	gpo.config.controls.pending = nil
+	if err := gpo.checkDrift(context.Background(), big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	if price := gpo.gasPriceUpdater.GetGasPrice(); price.Int64() != 100 {
+		t.Fatalf("expected 100, got %s", price)
+	}
+}
@@ -0,0 +1,83 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var externalUpdateCounter = metrics.NewRegisteredCounter("external/update", ometrics.DefaultRegistry)
+
+// updateWatcher polls the GasPriceUpdated events of the GasPriceOracle to
+// detect gas prices that were set by someone other than this oracle, for
+// example by an operator with the owner key
+type updateWatcher struct {
+	contract *bindings.GasPriceOracleFilterer
+	mu       sync.Mutex
+	// sent holds the transactions sent by this oracle that have not been
+	// seen in an event yet
+	sent map[common.Hash]struct{}
+	// next is the first block that has not been polled
+	next uint64
+}
+
+func newUpdateWatcher(cfg *Config, backend bind.ContractFilterer, start uint64) (*updateWatcher, error) {
+	contract, err := bindings.NewGasPriceOracleFilterer(cfg.gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &updateWatcher{
+		contract: contract,
+		sent:     make(map[common.Hash]struct{}),
+		next:     start,
+	}, nil
+}
+
+// recordSent marks a transaction as sent by this oracle
+func (w *updateWatcher) recordSent(hash common.Hash) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sent[hash] = struct{}{}
+}
+
+// poll returns the GasPriceUpdated events up to the block tip that were not
+// emitted by transactions of this oracle
+func (w *updateWatcher) poll(ctx context.Context, tip uint64) ([]*bindings.GasPriceOracleGasPriceUpdated, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if tip < w.next {
+		return nil, nil
+	}
+	end := tip
+	iter, err := w.contract.FilterGasPriceUpdated(&bind.FilterOpts{
+		Start:   w.next,
+		End:     &end,
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var external []*bindings.GasPriceOracleGasPriceUpdated
+	for iter.Next() {
+		if _, ok := w.sent[iter.Event.Raw.TxHash]; ok {
+			delete(w.sent, iter.Event.Raw.TxHash)
+			continue
+		}
+		external = append(external, iter.Event)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	w.next = tip + 1
+	return external, nil
+}
@@ -0,0 +1,81 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestUpdateWatcher(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+	}
+	tip := func() uint64 {
+		header, err := sim.HeaderByNumber(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return header.Number.Uint64()
+	}
+	cfg.updateWatcher, err = newUpdateWatcher(cfg, sim, tip()+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Updates sent by the oracle are not external
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	events, err := cfg.updateWatcher.poll(context.Background(), tip())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no external updates, got %d", len(events))
+	}
+
+	// An update with the owner key from outside of the oracle
+	opts.GasPrice = big.NewInt(875000000)
+	if _, err := gpo.SetGasPrice(opts, big.NewInt(500)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	events, err = cfg.updateWatcher.poll(context.Background(), tip())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Arg0.Uint64() != 500 {
+		t.Fatalf("expected the external update, got %v", events)
+	}
+
+	// Blocks are only polled once
+	events, err = cfg.updateWatcher.poll(context.Background(), tip())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no new updates, got %d", len(events))
+	}
+}
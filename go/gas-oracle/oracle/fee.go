@@ -0,0 +1,49 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// FeeEstimate is the fee of an L2 transaction at the current parameters of
+// the gas price oracle
+type FeeEstimate struct {
+	GasLimit     uint64   `json:"gasLimit"`
+	GasPrice     *big.Int `json:"gasPrice"`
+	ExecutionFee *big.Int `json:"executionFee"`
+	L1GasUsed    *big.Int `json:"l1GasUsed"`
+	L1BaseFee    *big.Int `json:"l1BaseFee"`
+	L1Fee        *big.Int `json:"l1Fee"`
+	TotalFee     *big.Int `json:"totalFee"`
+}
+
+// EstimateFee returns the fee of a transaction with the serialized data and
+// gas limit: the execution fee at the L2 gas price and the L1 data fee. The
+// L1 data fee is computed by the gas price oracle, so that it uses the same
+// overhead, scalar and L1 base fee as the sequencer.
+func EstimateFee(ctx context.Context, cfg *Config, backend bind.ContractCaller, data []byte, gasLimit uint64) (*FeeEstimate, error) {
+	contract, err := bindings.NewGasPriceOracleCaller(cfg.gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	estimate := &FeeEstimate{GasLimit: gasLimit}
+	if estimate.GasPrice, err = contract.GasPrice(opts); err != nil {
+		return nil, err
+	}
+	if estimate.L1BaseFee, err = contract.L1BaseFee(opts); err != nil {
+		return nil, err
+	}
+	if estimate.L1GasUsed, err = contract.GetL1GasUsed(opts, data); err != nil {
+		return nil, err
+	}
+	if estimate.L1Fee, err = contract.GetL1Fee(opts, data); err != nil {
+		return nil, err
+	}
+	estimate.ExecutionFee = new(big.Int).Mul(estimate.GasPrice, new(big.Int).SetUint64(gasLimit))
+	estimate.TotalFee = new(big.Int).Add(estimate.ExecutionFee, estimate.L1Fee)
+	return estimate, nil
+}
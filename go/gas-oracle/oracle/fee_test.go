@@ -0,0 +1,52 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestEstimateFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	for _, set := range []func() error{
+		func() error { _, err := gpo.SetGasPrice(opts, big.NewInt(2)); return err },
+		func() error { _, err := gpo.SetL1BaseFee(opts, big.NewInt(100)); return err },
+		func() error { _, err := gpo.SetOverhead(opts, big.NewInt(2100)); return err },
+		func() error { _, err := gpo.SetScalar(opts, big.NewInt(1_500_000)); return err },
+		func() error { _, err := gpo.SetDecimals(opts, big.NewInt(6)); return err },
+	} {
+		if err := set(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sim.Commit()
+
+	cfg := &Config{gasPriceOracleAddress: addr}
+	estimate, err := EstimateFee(context.Background(), cfg, sim, []byte{0, 1}, 21000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A zero byte costs 4 gas, a non-zero byte 16 and the signature 68*16
+	l1GasUsed := int64(4 + 16 + 2100 + 68*16)
+	if estimate.L1GasUsed.Int64() != l1GasUsed {
+		t.Fatalf("expected %d L1 gas used, got %s", l1GasUsed, estimate.L1GasUsed)
+	}
+	l1Fee := l1GasUsed * 100 * 3 / 2
+	if estimate.L1Fee.Int64() != l1Fee {
+		t.Fatalf("expected an L1 fee of %d, got %s", l1Fee, estimate.L1Fee)
+	}
+	if estimate.ExecutionFee.Int64() != 42000 || estimate.TotalFee.Int64() != 42000+l1Fee {
+		t.Fatalf("unexpected fees %+v", estimate)
+	}
+}
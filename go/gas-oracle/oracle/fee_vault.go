@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	feeVaultBalanceGauge      = metrics.NewRegisteredGaugeFloat64("fee_vault/balance", ometrics.DefaultRegistry)
+	feeVaultRevenueRateGauge  = metrics.NewRegisteredGaugeFloat64("fee_vault/revenue_rate", ometrics.DefaultRegistry)
+	feeVaultRevenueCounter    = metrics.NewRegisteredCounter("fee_vault/revenue", ometrics.DefaultRegistry)
+	feeVaultWithdrawalCounter = metrics.NewRegisteredCounter("fee_vault/withdrawal", ometrics.DefaultRegistry)
+)
+
+// feeVaultMonitor exports the balance of the sequencer fee vault and the
+// rate at which it collects fees, next to the gas price metrics, so that the
+// revenue impact of the gas price can be seen in one place. The vault is
+// emptied by withdrawals, which are not revenue.
+type feeVaultMonitor struct {
+	backend  BalanceBackend
+	address  common.Address
+	gasPrice func(ctx context.Context) (*big.Int, error)
+	now      func() time.Time
+	// last is the balance at the last check, nil before the first one
+	last     *big.Int
+	lastTime time.Time
+	// rate is the last revenue rate in ether per hour
+	rate float64
+}
+
+func newFeeVaultMonitor(cfg *Config, backend BalanceBackend, gasPrice func(ctx context.Context) (*big.Int, error)) *feeVaultMonitor {
+	if cfg.feeVaultAddress == (common.Address{}) {
+		return nil
+	}
+	log.Info("Monitoring the sequencer fee vault", "address", cfg.feeVaultAddress.Hex(),
+		"interval", cfg.feeVaultInterval)
+	return &feeVaultMonitor{
+		backend:  backend,
+		address:  cfg.feeVaultAddress,
+		gasPrice: gasPrice,
+		now:      time.Now,
+	}
+}
+
+// check fetches the balance of the fee vault and updates the revenue rate
+// from the balance of the last check. The rate is in ether per hour.
+func (m *feeVaultMonitor) check(ctx context.Context) error {
+	balance, err := m.backend.BalanceAt(ctx, m.address, nil)
+	if err != nil {
+		return err
+	}
+	now := m.now()
+	feeVaultBalanceGauge.Update(toEther(balance))
+
+	last, lastTime := m.last, m.lastTime
+	m.last, m.lastTime = balance, now
+	if last == nil {
+		log.Debug("Fetched fee vault balance", "address", m.address.Hex(), "balance", balance)
+		return nil
+	}
+	revenue := new(big.Int).Sub(balance, last)
+	if revenue.Sign() < 0 {
+		// The fees collected since the last check are lost in the
+		// withdrawal, the rate resumes at the next check
+		log.Info("Fee vault was withdrawn", "address", m.address.Hex(), "balance", balance, "previous", last)
+		feeVaultWithdrawalCounter.Inc(1)
+		return nil
+	}
+	elapsed := now.Sub(lastTime)
+	if elapsed <= 0 {
+		return nil
+	}
+	m.rate = toEther(revenue) / elapsed.Hours()
+	feeVaultRevenueCounter.Inc(new(big.Int).Quo(revenue, big.NewInt(params.GWei)).Int64())
+	feeVaultRevenueRateGauge.Update(m.rate)
+
+	gasPrice, err := m.gasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	log.Info("Fee vault revenue", "address", m.address.Hex(), "balance", balance, "revenue", revenue,
+		"ether-per-hour", m.rate, "gas-price", gasPrice)
+	return nil
+}
+
+// toEther converts an amount in wei to ether
+func toEther(amount *big.Int) float64 {
+	ether, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), big.NewFloat(params.Ether)).Float64()
+	return ether
+}
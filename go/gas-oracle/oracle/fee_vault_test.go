@@ -0,0 +1,63 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// vaultBalance is a BalanceBackend that returns the balance of the fee vault
+type vaultBalance struct {
+	balance *big.Int
+}
+
+func (v *vaultBalance) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return new(big.Int).Set(v.balance), nil
+}
+
+func TestFeeVaultMonitor(t *testing.T) {
+	if m := newFeeVaultMonitor(&Config{}, nil, nil); m != nil {
+		t.Fatal("expected no monitor without an address")
+	}
+
+	backend := &vaultBalance{balance: big.NewInt(params.Ether)}
+	gasPrice := func(ctx context.Context) (*big.Int, error) { return big.NewInt(1_000_000), nil }
+	m := newFeeVaultMonitor(&Config{
+		feeVaultAddress:  common.HexToAddress("0x4200000000000000000000000000000000000011"),
+		feeVaultInterval: time.Minute,
+	}, backend, gasPrice)
+	now := time.Unix(1_000_000, 0)
+	m.now = func() time.Time { return now }
+
+	// The first check only records the balance
+	if err := m.check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if m.rate != 0 || m.last.Cmp(backend.balance) != 0 {
+		t.Fatalf("expected no rate at the first check, got %f", m.rate)
+	}
+
+	// Half an ether in half an hour
+	now = now.Add(30 * time.Minute)
+	backend.balance = big.NewInt(3 * params.Ether / 2)
+	if err := m.check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if m.rate != 1 {
+		t.Fatalf("expected a rate of 1 ether per hour, got %f", m.rate)
+	}
+
+	// A withdrawal is not revenue and keeps the last rate
+	now = now.Add(30 * time.Minute)
+	backend.balance = big.NewInt(0)
+	if err := m.check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if m.rate != 1 || m.last.Sign() != 0 {
+		t.Fatalf("expected the rate to resume from the withdrawn balance, got %f from %d", m.rate, m.last)
+	}
+}
@@ -5,15 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
 	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/reporting"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -32,6 +38,12 @@ var (
 	// errNoBaseFee represents the error when the base fee is not found on the
 	// block. This means that the block being queried is pre eip1559
 	errNoBaseFee = errors.New("base fee not found on block")
+	// errNotGasPriceOracle represents the error when the configured address
+	// does not expose the ABI of the gas price oracle
+	errNotGasPriceOracle = errors.New("not a gas price oracle")
+	// errUnexpectedContractVersion represents the error when the version of
+	// the gas price oracle does not match the kind of chain it is updated on
+	errUnexpectedContractVersion = errors.New("unexpected gas price oracle version")
 )
 
 // GasPriceOracle manages a hot key that can update the L2 Gas Price
@@ -39,30 +51,182 @@ type GasPriceOracle struct {
 	l1ChainID       *big.Int
 	l2ChainID       *big.Int
 	ctx             context.Context
+	cancel          context.CancelFunc
 	stop            chan struct{}
+	stopOnce        sync.Once
 	contract        *bindings.GasPriceOracle
 	l2Backend       DeployContractBackend
 	l1Backend       bind.ContractTransactor
 	gasPriceUpdater *gasprices.GasPriceUpdater
-	config          *Config
+	pricer          gasprices.Pricer
+	// updateL2GasPrice decides and sends an L2 gas price like the
+	// GasPriceUpdater does at the end of an epoch
+	updateL2GasPrice func(*big.Int) error
+	// force receives the forced updates that run in the update loop
+	force chan chan error
+	// approve receives the approved updates that are sent in the update
+	// loop
+	approve chan approvalRequest
+	// handoff receives the start of the epoch of the previous leader, which
+	// the update loop continues
+	handoff        chan time.Time
+	epoch          *epochState
+	balanceMonitor *balanceMonitor
+	watchdog       *watchdog
+	config         *Config
+	// epochStart is the start of an epoch that was continued from the
+	// saved state, it is zero when the first epoch starts at startup
+	epochStart time.Time
+	// smoothing is kept in the saved state
+	smoothing *gasprices.SmoothingFilter
+	// failures is the number of consecutive epochs that failed to update
+	failures uint64
+	// gasConfig sets the gas config of a Bedrock chain on L1, the L2 gas
+	// price and the L1 base fee are not updated when it is set
+	gasConfig *gasConfigUpdater
+	// implementation tracks the implementation of a proxied gas price
+	// oracle, it is nil when the gas price oracle is not a proxy
+	implementation *implementationMonitor
+	// owner is nil when the owner is not checked during operation
+	owner *ownerMonitor
+	// feeVault is nil when the sequencer fee vault is not monitored
+	feeVault *feeVaultMonitor
 }
 
 // Start runs the GasPriceOracle
 func (g *GasPriceOracle) Start() error {
+	if err := g.prepare(); err != nil {
+		return err
+	}
+
+	if g.config.enableL1BaseFee {
+		go g.BaseFeeLoop()
+	}
+	if g.config.enableL2GasPrice {
+		go g.Loop()
+	}
+	if g.gasConfig != nil {
+		go g.GasConfigLoop()
+	}
+	if g.balanceMonitor != nil {
+		go g.BalanceLoop()
+	}
+	if g.implementation != nil {
+		go g.ImplementationLoop()
+	}
+	if g.owner != nil {
+		go g.OwnerLoop()
+	}
+	if g.feeVault != nil {
+		go g.FeeVaultLoop()
+	}
+	if g.config.leader != nil {
+		go g.LeaderLoop()
+	}
+	if g.config.adminAddr != "" {
+		if err := g.startAdminServer(); err != nil {
+			return err
+		}
+	}
+	if g.config.adminGRPCAddr != "" {
+		if err := g.startGRPCServer(); err != nil {
+			return err
+		}
+	}
+	if g.config.priceAPIAddr != "" {
+		if err := g.startPriceServer(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunOnce runs a single iteration of each loop of the GasPriceOracle and
+// returns the first error. The L2 gas price is evaluated over a single epoch
+// from the tip at creation, so it waits for the epoch to elapse first.
+func (g *GasPriceOracle) RunOnce() error {
+	if err := g.prepare(); err != nil {
+		return err
+	}
+	// The process exits after the run, so the messages must be published
+	// before returning
+	defer g.config.publisher.Close()
+	defer g.config.closeStateDB()
+
+	var first error
+	g.renewLease()
+	if g.balanceMonitor != nil {
+		if err := g.balanceMonitor.check(g.ctx); err != nil {
+			log.Error("cannot check signer balance", "message", err)
+		}
+	}
+	if g.config.enableL1BaseFee {
+		updateBaseFee, err := wrapUpdateBaseFee(g.l1Backend, g.l2Backend, g.config)
+		if err != nil {
+			return err
+		}
+		_, span := startSpan(g.ctx, "UpdateL1BaseFee")
+		err = updateBaseFee()
+		endSpan(span, err)
+		if err != nil {
+			log.Error("cannot update l1 base fee", "message", err)
+			first = fmt.Errorf("cannot update l1 base fee: %w", err)
+		}
+	}
+	if g.gasConfig != nil {
+		if err := g.gasConfig.update(g.ctx); err != nil {
+			log.Error("cannot update gas config", "message", err)
+			first = err
+		}
+	}
+	if g.config.enableL2GasPrice {
+		select {
+		case start := <-g.handoff:
+			g.epochStart = start
+		default:
+		}
+		wait := g.untilEpochEnd()
+		log.Info("Waiting for the epoch to elapse", "epoch-length", g.config.epochLength, "wait", wait)
+		select {
+		case <-time.After(wait):
+		case <-g.ctx.Done():
+			return g.ctx.Err()
+		}
+		// The lease may have run out during the epoch
+		g.renewLease()
+		if err := g.watchdog.run(g.ctx, g.update); err != nil {
+			log.Error("cannot update gas price", "message", err)
+			if first == nil {
+				first = fmt.Errorf("cannot update gas price: %w", err)
+			}
+		}
+	}
+	return first
+}
+
+// prepare checks that the GasPriceOracle can run and publishes the current
+// gas price of the contract
+func (g *GasPriceOracle) prepare() error {
 	if g.config.l1ChainID == nil {
 		return fmt.Errorf("layer-one: %w", errNoChainID)
 	}
 	if g.config.l2ChainID == nil {
 		return fmt.Errorf("layer-two: %w", errNoChainID)
 	}
-	if g.config.privateKey == nil {
-		return errNoPrivateKey
+	if g.config.dryRun {
+		log.Warn("Dry run enabled, no transactions will be sent")
+		log.Info("Starting Gas Price Oracle", "l1-chain-id", g.l1ChainID,
+			"l2-chain-id", g.l2ChainID)
+	} else {
+		address, ok := g.config.signer()
+		if !ok {
+			return errNoPrivateKey
+		}
+		log.Info("Starting Gas Price Oracle", "l1-chain-id", g.l1ChainID,
+			"l2-chain-id", g.l2ChainID, "address", address.Hex())
 	}
 
-	address := crypto.PubkeyToAddress(g.config.privateKey.PublicKey)
-	log.Info("Starting Gas Price Oracle", "l1-chain-id", g.l1ChainID,
-		"l2-chain-id", g.l2ChainID, "address", address.Hex())
-
 	price, err := g.contract.GasPrice(&bind.CallOpts{
 		Context: context.Background(),
 	})
@@ -70,19 +234,20 @@ func (g *GasPriceOracle) Start() error {
 		return err
 	}
 	gasPriceGauge.Update(int64(price.Uint64()))
-
-	if g.config.enableL1BaseFee {
-		go g.BaseFeeLoop()
-	}
-	if g.config.enableL2GasPrice {
-		go g.Loop()
-	}
-
+	g.config.chainMetrics.updateGasPrice(price)
 	return nil
 }
 
+// Stop stops the loops of the GasPriceOracle, it can be called more than
+// once
 func (g *GasPriceOracle) Stop() {
-	close(g.stop)
+	g.stopOnce.Do(func() {
+		g.config.leader.release(context.Background())
+		if g.cancel != nil {
+			g.cancel()
+		}
+		close(g.stop)
+	})
 }
 
 func (g *GasPriceOracle) Wait() {
@@ -91,17 +256,35 @@ func (g *GasPriceOracle) Wait() {
 
 // ensure makes sure that the configured private key is the owner
 // of the `OVM_GasPriceOracle`. If it is not the owner, then it will
-// not be able to make updates to the L2 gas price.
-func (g *GasPriceOracle) ensure() error {
+// not be able to make updates to the L2 gas price. When it is the pending
+// owner of a two-step ownership transfer, the oracle starts with the updates
+// paused and resumes them once the ownership is accepted.
+func (g *GasPriceOracle) ensure(pendingOwner common.Address) error {
 	owner, err := g.contract.Owner(&bind.CallOpts{
 		Context: g.ctx,
 	})
 	if err != nil {
 		return err
 	}
-	address := crypto.PubkeyToAddress(g.config.privateKey.PublicKey)
+	address, _ := g.config.expectedOwner()
+	if address != owner && address == pendingOwner {
+		if g.owner == nil {
+			log.Error("Signing key is the pending owner, accept the ownership first", "signer", address.Hex(),
+				"owner", owner.Hex())
+			return fmt.Errorf("%w: the signer is the pending owner, run accept-ownership", errInvalidSigningKey)
+		}
+		g.owner.awaitAcceptance(owner)
+		return nil
+	}
 	if address != owner {
 		log.Error("Signing key does not match contract owner", "signer", address.Hex(), "owner", owner.Hex())
+		g.config.notifier.Notify(&notify.Event{
+			Type:    notify.EventOwnerMismatch,
+			Time:    time.Now(),
+			ChainID: g.l2ChainID,
+			Signer:  address.Hex(),
+			Owner:   owner.Hex(),
+		})
 		return errInvalidSigningKey
 	}
 	return nil
@@ -109,25 +292,60 @@ func (g *GasPriceOracle) ensure() error {
 
 // Loop is the main logic of the gas-oracle
 func (g *GasPriceOracle) Loop() {
-	timer := time.NewTicker(time.Duration(g.config.epochLengthSeconds) * time.Second)
+	defer reporting.Recover()
+
+	// A continued epoch ends before the epoch length, the ticker is reset
+	// to the epoch length afterwards
+	first := g.untilEpochEnd()
+	timer := time.NewTicker(first)
 	defer timer.Stop()
+	epochStart := time.Now()
+	if !g.epochStart.IsZero() {
+		epochStart = g.epochStart
+	}
 
 	for {
 		select {
 		case <-timer.C:
 			log.Trace("polling", "time", time.Now())
-			if err := g.Update(); err != nil {
+			if first != g.config.epochLength {
+				timer.Reset(g.config.epochLength)
+				first = g.config.epochLength
+			}
+			epochStart = time.Now()
+			if err := g.watchdog.run(g.ctx, g.update); err != nil {
 				log.Error("cannot update gas price", "message", err)
 			}
 
+		case start := <-g.handoff:
+			first = g.config.epochLength - time.Since(start)
+			if first < time.Second {
+				first = time.Second
+			}
+			timer.Reset(first)
+			epochStart = start
+
+		case reply := <-g.force:
+			// The forced update closes the current epoch, so a full epoch
+			// starts after it
+			reply <- g.forceUpdate(time.Since(epochStart))
+			timer.Reset(g.config.epochLength)
+			epochStart = time.Now()
+
+		case req := <-g.approve:
+			req.reply <- g.approveUpdate(req.id)
+
 		case <-g.ctx.Done():
 			g.Stop()
+			return
 		}
 	}
 }
 
 func (g *GasPriceOracle) BaseFeeLoop() {
-	timer := time.NewTicker(time.Duration(g.config.l1BaseFeeEpochLengthSeconds) * time.Second)
+	defer reporting.Recover()
+
+	timer := time.NewTicker(g.config.l1BaseFeeEpochLength)
 	defer timer.Stop()
 
 	updateBaseFee, err := wrapUpdateBaseFee(g.l1Backend, g.l2Backend, g.config)
@@ -138,53 +356,359 @@ func (g *GasPriceOracle) BaseFeeLoop() {
 	for {
 		select {
 		case <-timer.C:
-			if err := updateBaseFee(); err != nil {
+			_, span := startSpan(g.ctx, "UpdateL1BaseFee")
+			err := updateBaseFee()
+			endSpan(span, err)
+			if err != nil {
 				log.Error("cannot update l1 base fee", "messgae", err)
 			}
 
 		case <-g.ctx.Done():
 			g.Stop()
+			return
+		}
+	}
+}
+
+// GasConfigLoop periodically sets the gas config of a Bedrock chain
+func (g *GasPriceOracle) GasConfigLoop() {
+	defer reporting.Recover()
+
+	timer := time.NewTicker(g.config.systemConfigInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			_, span := startSpan(g.ctx, "UpdateGasConfig")
+			err := g.gasConfig.update(g.ctx)
+			endSpan(span, err)
+			if err != nil {
+				log.Error("cannot update gas config", "message", err)
+			} else {
+				g.config.heartbeat.ping()
+			}
+
+		case <-g.ctx.Done():
+			g.Stop()
+			return
+		}
+	}
+}
+
+// BalanceLoop periodically checks the balance of the signer
+func (g *GasPriceOracle) BalanceLoop() {
+	defer reporting.Recover()
+
+	interval := g.config.balanceCheckInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	timer := time.NewTicker(interval)
+	defer timer.Stop()
+
+	if err := g.balanceMonitor.check(g.ctx); err != nil {
+		log.Error("cannot check signer balance", "message", err)
+	}
+	for {
+		select {
+		case <-timer.C:
+			if err := g.balanceMonitor.check(g.ctx); err != nil {
+				log.Error("cannot check signer balance", "message", err)
+			}
+
+		case <-g.ctx.Done():
+			g.Stop()
+			return
 		}
 	}
 }
 
-// Update will update the gas price
+// FeeVaultLoop periodically checks the balance of the sequencer fee vault
+func (g *GasPriceOracle) FeeVaultLoop() {
+	defer reporting.Recover()
+
+	timer := time.NewTicker(g.config.feeVaultInterval)
+	defer timer.Stop()
+
+	if err := g.feeVault.check(g.ctx); err != nil {
+		log.Error("cannot check fee vault", "message", err)
+	}
+	for {
+		select {
+		case <-timer.C:
+			if err := g.feeVault.check(g.ctx); err != nil {
+				log.Error("cannot check fee vault", "message", err)
+			}
+
+		case <-g.ctx.Done():
+			g.Stop()
+			return
+		}
+	}
+}
+
+// gasPriceAt returns the gas price of the contract
+func (g *GasPriceOracle) gasPriceAt(ctx context.Context) (*big.Int, error) {
+	return g.contract.GasPrice(&bind.CallOpts{Context: ctx})
+}
+
+// ImplementationLoop periodically checks the implementation of the proxy of
+// the gas price oracle
+func (g *GasPriceOracle) ImplementationLoop() {
+	defer reporting.Recover()
+
+	timer := time.NewTicker(g.config.implementationCheckInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if err := g.implementation.check(g.ctx); err != nil {
+				log.Error("cannot check gas price oracle implementation", "message", err)
+			}
+
+		case <-g.ctx.Done():
+			g.Stop()
+			return
+		}
+	}
+}
+
+// OwnerLoop periodically checks that the signer is still the owner of the
+// contract that is updated
+func (g *GasPriceOracle) OwnerLoop() {
+	defer reporting.Recover()
+
+	timer := time.NewTicker(g.config.ownerCheckInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if err := g.owner.check(g.ctx); err != nil {
+				log.Error("cannot check owner", "message", err)
+			}
+
+		case <-g.ctx.Done():
+			g.Stop()
+			return
+		}
+	}
+}
+
+// LeaderLoop renews the lease of the leader at a third of its ttl, so that
+// the leadership does not run out between renewals
+func (g *GasPriceOracle) LeaderLoop() {
+	defer reporting.Recover()
+
+	timer := time.NewTicker(g.config.leaseTTL / 3)
+	defer timer.Stop()
+	// The lease may have been taken when the oracle was created
+	g.takeOverIfPending()
+
+	for {
+		select {
+		case <-timer.C:
+			g.renewLease()
+
+		case <-g.ctx.Done():
+			g.Stop()
+			return
+		}
+	}
+}
+
+// renewLease renews the lease of the leader, if any
+func (g *GasPriceOracle) renewLease() {
+	if g.config.leader == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(g.ctx, g.config.leaseTTL/3)
+	defer cancel()
+	if err := g.config.leader.renew(ctx); err != nil {
+		log.Error("cannot renew lease", "message", err)
+	}
+	g.takeOverIfPending()
+}
+
+// takeOverIfPending adopts the checkpoint of the previous leader after the
+// replica became the leader
+func (g *GasPriceOracle) takeOverIfPending() {
+	if !g.config.leader.pendingHandoff() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(g.ctx, g.config.leaseTTL/3)
+	defer cancel()
+	if err := g.takeOver(ctx); err != nil {
+		log.Error("cannot take over from the previous leader", "message", err)
+	}
+}
+
+// Update will update the gas price. Each update is traced as an epoch
+// that spans fetching the headers, computing the gas price, the
+// significance check and sending the transaction.
 func (g *GasPriceOracle) Update() error {
+	return g.update(g.ctx)
+}
+
+// update runs an epoch within the context, which is cancelled by the
+// watchdog when the epoch exceeds its deadline
+func (g *GasPriceOracle) update(parent context.Context) error {
+	return g.runEpoch(parent, g.gasPriceUpdater.UpdateGasPrice)
+}
+
+// runEpoch runs an epoch that is completed by closeEpoch
+func (g *GasPriceOracle) runEpoch(parent context.Context, closeEpoch func() error) (err error) {
+	ctx, span := startSpan(parent, "Epoch")
+	g.epoch.setContext(ctx)
+	defer func() {
+		g.epoch.setContext(nil)
+		endSpan(span, err)
+		if err == nil {
+			if g.failures > 0 {
+				g.config.notifier.Notify(&notify.Event{
+					Type:    notify.EventUpdateRecovered,
+					Time:    time.Now(),
+					ChainID: g.l2ChainID,
+				})
+			}
+			g.failures = 0
+			g.config.heartbeat.ping()
+			return
+		}
+		g.failures++
+		g.config.notifier.Notify(&notify.Event{
+			Type:                notify.EventUpdateFailed,
+			Time:                time.Now(),
+			ChainID:             g.l2ChainID,
+			Error:               err.Error(),
+			ConsecutiveFailures: g.failures,
+		})
+	}()
+
 	l2GasPrice, err := g.contract.GasPrice(&bind.CallOpts{
-		Context: g.ctx,
+		Context: ctx,
 	})
 	if err != nil {
 		return fmt.Errorf("cannot get gas price: %w", err)
 	}
 
-	if err := g.gasPriceUpdater.UpdateGasPrice(); err != nil {
+	// Updates that were sent while paused, by hand or by another oracle, are
+	// not reverted when resuming
+	if g.config.controls.takeResync() {
+		log.Info("Resynchronizing the gas price after a pause", "gas-price", l2GasPrice,
+			"local", g.gasPriceUpdater.GetGasPrice())
+		if err := g.gasPriceUpdater.SetGasPrice(l2GasPrice); err != nil {
+			return fmt.Errorf("cannot resynchronize gas price: %w", err)
+		}
+	}
+
+	// The update of the leader in the previous epoch has been mined by now
+	g.config.leader.validate(l2GasPrice, g.config.l2GasPriceSignificanceFactor)
+
+	if err := g.config.controls.apply(g.config, g.pricer); err != nil {
+		return fmt.Errorf("cannot apply controls: %w", err)
+	}
+	// The floor price of the controls is in ether with a gas token
+	if err := g.config.gasToken.apply(ctx, g.config, g.pricer); err != nil {
+		return fmt.Errorf("cannot apply gas token rate: %w", err)
+	}
+
+	if err := g.checkExternalUpdates(ctx); err != nil {
+		return fmt.Errorf("cannot check external updates: %w", err)
+	}
+	// Once the update of the previous epoch is mined, the gas pricer
+	// should be close to the chain
+	if err := g.checkDrift(ctx, l2GasPrice); err != nil {
+		return fmt.Errorf("cannot check drift: %w", err)
+	}
+
+	if err := closeEpoch(); err != nil {
 		return fmt.Errorf("cannot update gas price: %w", err)
 	}
 
 	newGasPrice, err := g.contract.GasPrice(&bind.CallOpts{
-		Context: g.ctx,
+		Context: ctx,
 	})
 	if err != nil {
 		return fmt.Errorf("cannot get gas price: %w", err)
 	}
 
 	local := g.gasPriceUpdater.GetGasPrice()
+	g.saveState(ctx, newGasPrice)
+	g.recordPrice(local, newGasPrice)
+
+	if g.config.staleUpdates != nil {
+		tip, err := g.l2Backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("cannot get tip: %w", err)
+		}
+		g.config.staleUpdates.observe(newGasPrice, tip.Number.Uint64(), g.epoch.get(), time.Now())
+	}
+
+	span.SetAttributes(attribute.String("gas_price.original", l2GasPrice.String()),
+		attribute.String("gas_price.current", newGasPrice.String()))
 	log.Info("Update", "original", l2GasPrice, "current", newGasPrice, "local", local)
 	return nil
 }
 
+// checkExternalUpdates moves the gas price of the gas pricer to the most
+// recent gas price that was set outside of the oracle, so that the oracle
+// continues from it instead of reverting it
+func (g *GasPriceOracle) checkExternalUpdates(ctx context.Context) error {
+	watcher := g.config.updateWatcher
+	if watcher == nil {
+		return nil
+	}
+	tip, err := g.l2Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	events, err := watcher.poll(ctx, tip.Number.Uint64())
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	for _, event := range events {
+		log.Warn("Gas price set outside of the oracle", "gas-price", event.Arg0,
+			"hash", event.Raw.TxHash.Hex(), "blocknumber", event.Raw.BlockNumber)
+		externalUpdateCounter.Inc(1)
+		g.config.notifier.Notify(&notify.Event{
+			Type:     notify.EventExternalUpdate,
+			Time:     time.Now(),
+			ChainID:  g.l2ChainID,
+			GasPrice: event.Arg0,
+			TxHash:   event.Raw.TxHash.Hex(),
+		})
+	}
+	latest := events[len(events)-1].Arg0
+	log.Info("Resetting gas price to the external update", "gas-price", latest)
+	return g.gasPriceUpdater.SetGasPrice(latest)
+}
+
 // NewGasPriceOracle creates a new GasPriceOracle based on a Config
 func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
-	// Create the L2 client
-	l2Client, err := ethclient.Dial(cfg.layerTwoHttpUrl)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Create the L2 client, keeping the RPC client for the
+	// non standard namespaces
+	l2RPCClient, l2Guard, err := dialChainIDGuarded(cfg.layerTwoHttpUrl, "L2", cfg.rpcTLS)
 	if err != nil {
 		return nil, err
 	}
+	l2Client := ethclient.NewClient(l2RPCClient)
 
-	l1Client, err := ethclient.Dial(cfg.ethereumHttpUrl)
+	l1RPCClient, l1Guard, err := dialChainIDGuarded(cfg.ethereumHttpUrl, "L1", cfg.rpcTLS)
 	if err != nil {
 		return nil, err
 	}
+	l1Client := ethclient.NewClient(l1RPCClient)
+	cfg.l1ChainIDGuard, cfg.l2ChainIDGuard = l1Guard, l2Guard
 
 	// Ensure that we can actually connect to both backends
 	log.Info("Connecting to layer two")
@@ -198,97 +722,181 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 		return nil, err
 	}
 
+	return newGasPriceOracle(cfg, l1Client, l2Client, l2RPCClient)
+}
+
+// newGasPriceOracle creates a new GasPriceOracle with connected backends.
+// The RPC client is used for the non standard namespaces of the L2 node and
+// may be nil when they are not used.
+func newGasPriceOracle(cfg *Config, l1Client L1Backend, l2Client L2Backend, l2RPCClient *rpc.Client) (*GasPriceOracle, error) {
+	if cfg.bedrock() {
+		return newBedrockGasPriceOracle(cfg, l1Client, l2Client)
+	}
+
 	address := cfg.gasPriceOracleAddress
+	resolved, err := resolveGasPriceOracle(context.Background(), l2Client, address)
+	if err != nil {
+		return nil, err
+	}
+	switch resolved.Version {
+	case contractVersionBedrock:
+		return nil, fmt.Errorf("%w: %s is the Bedrock GasPriceOracle %s, which is configured with --%s",
+			errUnexpectedContractVersion, address.Hex(), resolved.SemVer, flags.SystemConfigAddressFlag.Name)
+	case contractVersionOVMGasPrice:
+		if cfg.enableL1BaseFee {
+			log.Warn("The gas price oracle has no L1 base fee, not updating it", "address", address.Hex())
+			cfg.enableL1BaseFee = false
+		}
+	}
 	contract, err := bindings.NewGasPriceOracle(address, l2Client)
 	if err != nil {
 		return nil, err
 	}
 
-	// Fetch the current gas price to use as the current price
-	currentPrice, err := contract.GasPrice(&bind.CallOpts{
-		Context: context.Background(),
-	})
+	// Use the current gas price of the contract as the current price
+	currentPrice := resolved.GasPrice
+
+	// The admin API changes the floor price and the target through the
+	// controls
+	cfg.controls = newControls(cfg)
+	cfg.approvals = newApprovals(cfg)
+
+	// Create a gas pricer for the gas price updater
+	gasPricer, err := newLivePricer(cfg, currentPrice, time.Now)
 	if err != nil {
 		return nil, err
 	}
+	cfg.gasToken, err = newGasTokenRate(context.Background(), cfg, l1Client)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.gasToken != nil {
+		if err := cfg.gasToken.apply(context.Background(), cfg, gasPricer); err != nil {
+			return nil, fmt.Errorf("cannot convert to the gas token: %w", err)
+		}
+		// The bounds in ether of the new pricer may have moved the current
+		// price, which is in the gas token
+		if err := gasPricer.SetGasPrice(currentPrice); err != nil {
+			return nil, err
+		}
+	}
 
-	// Create a gas pricer for the gas price updater
-	log.Info("Creating GasPricer", "currentPrice", currentPrice,
-		"floorPrice", cfg.floorPrice, "targetGasPerSecond", cfg.targetGasPerSecond,
-		"maxPercentChangePerEpoch", cfg.maxPercentChangePerEpoch)
+	l2ChainID, err := resolveChainID(l2Client, cfg.l2ChainID, "L2")
+	if err != nil {
+		return nil, err
+	}
+	cfg.l2ChainID = l2ChainID
+	l1ChainID, err := resolveChainID(l1Client, cfg.l1ChainID, "L1")
+	if err != nil {
+		return nil, err
+	}
+	cfg.l1ChainID = l1ChainID
 
-	gasPricer, err := gasprices.NewGasPricer(
-		currentPrice.Uint64(),
-		cfg.floorPrice,
-		func() float64 {
-			return float64(cfg.targetGasPerSecond)
-		},
-		cfg.maxPercentChangePerEpoch,
-	)
+	// A dry run does not need a key since no transactions are signed
+	if !cfg.hasSigner() && !cfg.dryRun {
+		return nil, errNoPrivateKey
+	}
+
+	tip, err := l2Client.HeaderByNumber(context.Background(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	l2ChainID, err := l2Client.ChainID(context.Background())
+	cfg.notifier, err = newNotifier(cfg)
 	if err != nil {
 		return nil, err
 	}
-	l1ChainID, err := l1Client.ChainID(context.Background())
+	cfg.publisher, err = newPublisher(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	if cfg.l2ChainID != nil {
-		if cfg.l2ChainID.Cmp(l2ChainID) != 0 {
-			return nil, fmt.Errorf("%w: L2: configured with %d and got %d",
-				errWrongChainID, cfg.l2ChainID, l2ChainID)
-		}
-	} else {
-		cfg.l2ChainID = l2ChainID
+	cfg.heartbeat = newHeartbeat(cfg.heartbeatURL)
+	cfg.staleUpdates = newStaleUpdateMonitor(cfg)
+	if err := openLeaderElection(cfg); err != nil {
+		return nil, err
 	}
 
-	if cfg.l1ChainID != nil {
-		if cfg.l1ChainID.Cmp(l1ChainID) != 0 {
-			return nil, fmt.Errorf("%w: L1: configured with %d and got %d",
-				errWrongChainID, cfg.l1ChainID, l1ChainID)
+	if cfg.auditLogPath != "" && !cfg.dryRun {
+		log.Info("Writing audit log", "path", cfg.auditLogPath)
+		cfg.auditLog, err = openAuditLog(cfg.auditLogPath, cfg.privateKey, cfg)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		cfg.l1ChainID = l1ChainID
 	}
 
-	if cfg.privateKey == nil {
-		return nil, errNoPrivateKey
+	saved, err := openStateDB(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open state: %w", err)
+	}
+	if err := reconcileCheckpoint(context.Background(), cfg, l2Client, saved); err != nil {
+		return nil, fmt.Errorf("cannot reconcile checkpoint: %w", err)
 	}
+	cfg.checkpoint = newCheckpoint(cfg, saved)
+	cfg.decisionSigner, err = newDecisionSigner(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign decisions: %w", err)
+	}
+	restored := restoreState(cfg, saved, tip.Number.Uint64(), currentPrice, time.Now())
 
-	tip, err := l2Client.HeaderByNumber(context.Background(), nil)
+	cfg.warmStart, err = fetchWarmStart(context.Background(), cfg, l2Client, tip.Number.Uint64())
+	if err != nil {
+		return nil, fmt.Errorf("cannot warm start: %w", err)
+	}
+	cfg.warmStart.apply(cfg, gasPricer)
+
+	// Start at the tip, or at the block that the demand is measured up to,
+	// unless the saved epoch is still running
+	head, err := newDemandHead(context.Background(), cfg, l2Client, l2RPCClient)
 	if err != nil {
 		return nil, err
 	}
+	epochStartBlockNumber, err := head.number(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	var epochStart time.Time
+	if restored != nil && !restored.epochStart.IsZero() {
+		epochStartBlockNumber, epochStart = restored.epochStartBlockNumber, restored.epochStart
+		log.Info("Continuing the saved epoch", "epochStartBlockNumber", epochStartBlockNumber,
+			"epochStart", epochStart)
+	}
 
-	// Start at the tip
-	epochStartBlockNumber := tip.Number.Uint64()
+	if cfg.watchExternalUpdates {
+		log.Info("Watching for external gas price updates")
+		cfg.updateWatcher, err = newUpdateWatcher(cfg, l2Client, epochStartBlockNumber+1)
+		if err != nil {
+			return nil, err
+		}
+		// The pending transaction of the checkpoint was sent by the oracle
+		if hash, ok := cfg.checkpoint.pendingTransaction(); ok {
+			cfg.updateWatcher.recordSent(hash)
+		}
+	}
 	// getLatestBlockNumberFn is used by the GasPriceUpdater
 	// to get the latest block number
-	getLatestBlockNumberFn := wrapGetLatestBlockNumberFn(l2Client)
+	// epoch keeps the state of the epoch that is being processed
+	epoch := new(epochState)
+	getLatestBlockNumberFn := traceGetLatestBlockNumberFn(epoch, wrapGetLatestBlockNumberFn(epoch, head))
 	// updateL2GasPriceFn is used by the GasPriceUpdater to
 	// update the gas price
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(l2Client, cfg)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(l2Client, cfg, epoch)
 	if err != nil {
 		return nil, err
 	}
 	// getGasUsedByBlockFn is used by the GasPriceUpdater
 	// to fetch the amount of gas that a block has used
-	getGasUsedByBlockFn := wrapGetGasUsedByBlock(l2Client)
+	getGasUsedByBlockFn := traceGetGasUsedByBlock(epoch, wrapGetGasUsedByBlock(epoch, l2Client))
 
 	log.Info("Creating GasPriceUpdater", "epochStartBlockNumber", epochStartBlockNumber,
 		"averageBlockGasLimitPerEpoch", cfg.averageBlockGasLimitPerEpoch,
-		"epochLengthSeconds", cfg.epochLengthSeconds)
+		"epochLength", cfg.epochLength)
 
 	gasPriceUpdater, err := gasprices.NewGasPriceUpdater(
-		gasPricer,
+		&tracedPricer{Pricer: gasPricer, epoch: epoch},
 		epochStartBlockNumber,
 		cfg.averageBlockGasLimitPerEpoch,
-		cfg.epochLengthSeconds,
+		cfg.epochLength,
 		getLatestBlockNumberFn,
 		getGasUsedByBlockFn,
 		updateL2GasPriceFn,
@@ -298,25 +906,301 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 		return nil, err
 	}
 
+	gasPriceUpdater.AddDemandObserver(epoch)
+	gasPriceUpdater.SetReanchorFn(newReanchorFn(cfg))
+
+	if restored != nil && restored.gasPrice != nil {
+		log.Info("Restoring the saved gas price", "gas-price", restored.gasPrice, "on-chain", currentPrice)
+		if err := gasPriceUpdater.SetGasPrice(restored.gasPrice); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.shadow != nil {
+		log.Info("Enabling shadow pricer", "pricer", cfg.shadow.pricer)
+		shadowPricer, err := newShadowPricer(cfg, gasPricer, currentPrice)
+		if err != nil {
+			return nil, err
+		}
+		gasPriceUpdater.AddDemandObserver(shadowPricer)
+	}
+
+	if cfg.usesBlockUsage() {
+		log.Info("Enabling multi-signal demand", "gasWeight", cfg.demandWeights.Gas,
+			"txWeight", cfg.demandWeights.Tx, "calldataWeight", cfg.demandWeights.CalldataByte)
+		if err := gasPriceUpdater.SetBlockUsage(traceGetBlockUsage(epoch, wrapGetBlockUsage(epoch, l2Client)), cfg.demandWeights); err != nil {
+			return nil, err
+		}
+	}
+
+	demandFilters, err := newDemandFilters(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range demandFilters {
+		gasPriceUpdater.AddDemandFilter(f)
+	}
+	smoothing := smoothingFilter(demandFilters)
+	if smoothing != nil && restored != nil && len(restored.smoothingSamples) > 0 {
+		log.Info("Restoring the saved smoothing samples", "samples", len(restored.smoothingSamples))
+		smoothing.SetSamples(restored.smoothingSamples)
+	}
+
+	if cfg.enableTxPoolSignal {
+		if l2RPCClient == nil {
+			return nil, errors.New("the txpool signal requires an RPC client of the L2 node")
+		}
+		log.Info("Enabling txpool signal", "weight", cfg.txPoolSignalWeight,
+			"queuedWeight", cfg.txPoolQueuedWeight, "gasPerTx", cfg.txPoolGasPerTx)
+		gasPriceUpdater.AddDemandFilter(&txPoolSignal{
+			getStatus:    wrapGetTxPoolStatusFn(l2RPCClient),
+			weight:       cfg.txPoolSignalWeight,
+			queuedWeight: cfg.txPoolQueuedWeight,
+			gasPerTx:     cfg.txPoolGasPerTx,
+			epochLength:  cfg.epochLength,
+		})
+	}
+
+	if cfg.adaptiveSignificance != nil {
+		log.Info("Enabling adaptive significance factor")
+		gasPriceUpdater.AddDemandObserver(cfg.adaptiveSignificance)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	gpo := GasPriceOracle{
 		l2ChainID:       l2ChainID,
 		l1ChainID:       l1ChainID,
-		ctx:             context.Background(),
+		ctx:             ctx,
+		cancel:          cancel,
 		stop:            make(chan struct{}),
 		contract:        contract,
 		gasPriceUpdater: gasPriceUpdater,
-		config:          cfg,
-		l2Backend:       l2Client,
-		l1Backend:       l1Client,
+		pricer:          gasPricer,
+		epoch:           epoch,
+		force:           make(chan chan error),
+		approve:         make(chan approvalRequest),
+		handoff:         make(chan time.Time, 1),
+		// The forced updates share the rate limit with the epochs
+		updateL2GasPrice: updateL2GasPriceFn,
+		watchdog:         newWatchdog(cfg),
+		config:           cfg,
+		epochStart:       epochStart,
+		smoothing:        smoothing,
+		l2Backend:        l2Client,
+		l1Backend:        l1Client,
+		implementation:   newImplementationMonitor(cfg, l2Client, resolved),
 	}
+	gpo.feeVault = newFeeVaultMonitor(cfg, l2Client, gpo.gasPriceAt)
 
-	if err := gpo.ensure(); err != nil {
-		return nil, err
+	if signer, ok := cfg.signer(); ok {
+		var pendingOwner func(*bind.CallOpts) (common.Address, error)
+		if resolved.implements("pendingOwner") {
+			pendingOwner = newPendingOwnerCaller(resolved.Address, l2Client)
+		}
+		gpo.owner = newOwnerMonitor(cfg, "GasPriceOracle", contract.Owner, pendingOwner)
+		if err := gpo.ensure(resolved.PendingOwner); err != nil {
+			// Deliver the notification of the mismatch before exiting
+			cfg.notifier.Close()
+			return nil, err
+		}
+		gpo.balanceMonitor = newBalanceMonitor(l2Client, signer, cfg)
 	}
 
 	return &gpo, nil
 }
 
+// newGasPricer creates the configured pricer starting at the current price.
+// Time based features of the pricer use the now function, so that
+// historical data can be replayed through the pricer.
+func newGasPricer(cfg *Config, currentPrice *big.Int, now func() time.Time) (gasprices.Pricer, error) {
+	name := cfg.pricer
+	if name == "" {
+		name = gasprices.DefaultPricer
+	}
+	log.Info("Creating GasPricer", "pricer", name, "currentPrice", currentPrice,
+		"floorPrice", cfg.floorPrice, "targetGasPerSecond", cfg.targetGasPerSecond,
+		"maxPercentChangePerEpoch", cfg.maxPercentChangePerEpoch)
+
+	getTargetGasPerSecond := func() float64 {
+		return float64(cfg.targetGasPerSecond)
+	}
+	if len(cfg.targetGasSchedule) > 0 {
+		for _, w := range cfg.targetGasSchedule {
+			log.Info("Using scheduled target gas per second", "start", w.Start,
+				"end", w.End, "targetGasPerSecond", w.TargetGasPerSecond)
+		}
+		getTargetGasPerSecond = gasprices.GetScheduledTargetGasPerSecondFn(
+			now,
+			cfg.targetGasSchedule,
+			cfg.targetGasPerSecond,
+		)
+	}
+
+	pricer, err := gasprices.NewPricer(name, gasprices.PricerConfig{
+		CurrentPrice:             currentPrice,
+		FloorPrice:               cfg.floorPrice,
+		GetTargetGasPerSecond:    getTargetGasPerSecond,
+		MaxPercentChangePerEpoch: cfg.maxPercentChangePerEpoch,
+		Now:                      now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.maxGasPrice != nil {
+		log.Info("Capping the gas price", "maxGasPrice", cfg.maxGasPrice)
+		if err := pricer.SetMaxPrice(cfg.maxGasPrice); err != nil {
+			return nil, err
+		}
+	}
+
+	// The remaining features are specific to the proportional pricer
+	gasPricer, ok := pricer.(*gasprices.GasPricer)
+	if !ok {
+		if cfg.dailyPriceChangeBudget > 0 || cfg.enableSurgePricing || cfg.enableIdleDecay {
+			return nil, fmt.Errorf("pricer %q does not support a daily change budget, surge pricing or idle decay", name)
+		}
+		return pricer, nil
+	}
+
+	if cfg.dailyPriceChangeBudget > 0 {
+		log.Info("Limiting the daily gas price change", "budget", cfg.dailyPriceChangeBudget)
+		if err := gasPricer.SetDailyChangeBudget(cfg.dailyPriceChangeBudget); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.enableSurgePricing {
+		log.Info("Enabling surge pricing", "threshold", cfg.surgeThreshold,
+			"epochs", cfg.surgeEpochs, "factor", cfg.surgeFactor,
+			"maxMultiplier", cfg.surgeMaxMultiplier, "decayRate", cfg.surgeDecayRate)
+
+		err := gasPricer.EnableSurge(gasprices.SurgeConfig{
+			Threshold:     cfg.surgeThreshold,
+			Epochs:        cfg.surgeEpochs,
+			Factor:        cfg.surgeFactor,
+			MaxMultiplier: cfg.surgeMaxMultiplier,
+			DecayRate:     cfg.surgeDecayRate,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.enableIdleDecay {
+		log.Info("Enabling idle decay", "threshold", cfg.idleDecayThreshold,
+			"epochs", cfg.idleDecayEpochs, "rate", cfg.idleDecayRate)
+
+		err := gasPricer.EnableIdleDecay(gasprices.IdleDecayConfig{
+			Threshold: cfg.idleDecayThreshold,
+			Epochs:    cfg.idleDecayEpochs,
+			Rate:      cfg.idleDecayRate,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return gasPricer, nil
+}
+
+// newLivePricer creates the pricer whose gas price is sent. During a canary
+// rollout the configured pricer is bounded by the incumbent pricer.
+func newLivePricer(cfg *Config, currentPrice *big.Int, now func() time.Time) (gasprices.Pricer, error) {
+	candidate, err := newGasPricer(cfg, currentPrice, now)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.canaryIncumbentPricer == "" {
+		return candidate, nil
+	}
+
+	log.Info("Enabling canary rollout", "pricer", cfg.pricer,
+		"incumbent", cfg.canaryIncumbentPricer, "maxDelta", cfg.canaryMaxDelta)
+	incumbentCfg := *cfg
+	incumbentCfg.pricer = cfg.canaryIncumbentPricer
+	incumbent, err := newGasPricer(&incumbentCfg, currentPrice, now)
+	if err != nil {
+		return nil, fmt.Errorf("canary incumbent pricer: %w", err)
+	}
+	onFallback := func(candidate, incumbent *big.Int, divergence float64) {
+		log.Error("Canary pricer exceeded max delta", "pricer", cfg.pricer,
+			"candidate", candidate, "incumbent", incumbent, "divergence", divergence)
+		canaryFallbackCounter.Inc(1)
+	}
+	return gasprices.NewCanaryPricer(candidate, incumbent, cfg.canaryMaxDelta, onFallback)
+}
+
+// newShadowPricer creates the pricer of the shadow config that follows the
+// live pricer and exports how far it diverges from it
+func newShadowPricer(cfg *Config, live gasprices.Pricer, currentPrice *big.Int) (*gasprices.ShadowPricer, error) {
+	shadow, err := newGasPricer(cfg.shadow, currentPrice, time.Now)
+	if err != nil {
+		return nil, fmt.Errorf("shadow pricer: %w", err)
+	}
+	observe := func(live, shadow *big.Int, divergence float64) {
+		shadowGasPriceGauge.Update(int64(shadow.Uint64()))
+		shadowDivergenceGauge.Update(divergence)
+	}
+	return gasprices.NewShadowPricer(live, shadow, observe), nil
+}
+
+// newDemandFilters creates the configured filters that are applied to the
+// demand measured from blocks
+func newDemandFilters(cfg *Config) ([]gasprices.DemandFilter, error) {
+	var filters []gasprices.DemandFilter
+	if cfg.enableOutlierRejection {
+		log.Info("Enabling outlier rejection", "method", cfg.outlierMethod,
+			"window", cfg.outlierWindow, "minSamples", cfg.outlierMinSamples,
+			"threshold", cfg.outlierThreshold)
+		f, err := gasprices.NewOutlierFilter(cfg.outlierMethod, cfg.outlierWindow,
+			cfg.outlierMinSamples, cfg.outlierThreshold)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	// Outliers are rejected before they can be averaged into other epochs
+	if cfg.smoothingEpochs > 1 {
+		log.Info("Enabling demand smoothing", "epochs", cfg.smoothingEpochs,
+			"method", cfg.smoothingMethod)
+		f, err := gasprices.NewSmoothingFilter(cfg.smoothingMethod, cfg.smoothingEpochs)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// chainIDReader is implemented by backends that report their chain ID
+type chainIDReader interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// resolveChainID checks the configured chain ID against the chain ID of the
+// backend, or reads it from the backend when it is not configured. Backends
+// that do not report their chain ID, such as the simulated backend, need it
+// to be configured.
+func resolveChainID(backend interface{}, configured *big.Int, layer string) (*big.Int, error) {
+	reader, ok := backend.(chainIDReader)
+	if !ok {
+		if configured == nil {
+			return nil, fmt.Errorf("%s: %w", layer, errNoChainID)
+		}
+		return configured, nil
+	}
+	chainID, err := reader.ChainID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if configured != nil && configured.Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("%w: %s: configured with %d and got %d",
+			errWrongChainID, layer, configured, chainID)
+	}
+	return chainID, nil
+}
+
 // Ensure that we can actually connect
 func ensureConnection(client *ethclient.Client) error {
 	t := time.NewTicker(1 * time.Second)
@@ -335,3 +1219,44 @@ func ensureConnection(client *ethclient.Client) error {
 	}
 	return nil
 }
+
+// newNotifier creates the dispatcher for the configured notifications, it is
+// nil when no notifications are configured
+func newNotifier(cfg *Config) (*notify.Dispatcher, error) {
+	var notifiers []notify.Notifier
+	if len(cfg.webhookURLs) > 0 {
+		log.Info("Enabling webhook notifications", "urls", len(cfg.webhookURLs))
+		notifiers = append(notifiers, notify.NewWebhook(cfg.webhookURLs))
+	}
+	chats := []struct {
+		format notify.ChatFormat
+		url    string
+	}{
+		{notify.ChatFormatSlack, cfg.slackWebhookURL},
+		{notify.ChatFormatDiscord, cfg.discordWebhookURL},
+	}
+	for _, c := range chats {
+		if c.url == "" {
+			continue
+		}
+		log.Info("Enabling chat notifications", "format", c.format,
+			"minPriceChange", cfg.chatMinPriceChange, "failureThreshold", cfg.chatFailureThreshold)
+		chat, err := notify.NewChat(c.format, c.url, cfg.chatMinPriceChange, cfg.chatFailureThreshold)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, chat)
+	}
+	if cfg.SentryDSN != "" {
+		notifiers = append(notifiers, reporting.NewSentry(cfg.sentryFailureThreshold))
+	}
+	if cfg.pagerDutyRoutingKey != "" {
+		log.Info("Enabling PagerDuty alerts", "failureThreshold", cfg.pagerDutyFailureThreshold)
+		notifiers = append(notifiers, notify.NewPagerDuty(cfg.pagerDutyURL, cfg.pagerDutyRoutingKey,
+			cfg.pagerDutyFailureThreshold))
+	}
+	if len(notifiers) == 0 {
+		return nil, nil
+	}
+	return notify.NewDispatcher(notifiers...), nil
+}
@@ -0,0 +1,160 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var gasTokenRateGauge = metrics.NewRegisteredGaugeFloat64("gas_token/rate", ometrics.DefaultRegistry)
+
+// errStaleGasTokenRate represents the error when the price feed of the gas
+// token has not been updated within the max rate age
+var errStaleGasTokenRate = errors.New("gas token rate is stale")
+
+// gasTokenRate folds the exchange rate of a custom gas token into the gas
+// price, so that users pay a stable fee in ether. The pricer works in the gas
+// token like the chain does, while the floor and the max gas price are
+// configured in ether and converted at the latest rate. When the rate moves,
+// the gas price is rescaled by the change.
+type gasTokenRate struct {
+	feed     *bindings.AggregatorV3Caller
+	address  common.Address
+	decimals uint8
+	maxAge   time.Duration
+	now      func() time.Time
+	// rate is the price of the gas token in ether that the pricer was last
+	// converted at, nil before the first conversion
+	rate *big.Rat
+}
+
+// newGasTokenRate reads the decimals of the price feed on L1. It returns nil
+// when the gas token is ether.
+func newGasTokenRate(ctx context.Context, cfg *Config, backend L1Backend) (*gasTokenRate, error) {
+	if cfg.gasTokenRateFeed == (common.Address{}) {
+		return nil, nil
+	}
+	caller, ok := backend.(bind.ContractCaller)
+	if !ok {
+		return nil, errNoL1ContractBackend
+	}
+	feed, err := bindings.NewAggregatorV3Caller(cfg.gasTokenRateFeed, caller)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := feed.Decimals(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get decimals of gas token price feed %s: %w", cfg.gasTokenRateFeed.Hex(), err)
+	}
+	log.Info("Converting the gas price to the gas token", "feed", cfg.gasTokenRateFeed.Hex(),
+		"decimals", decimals, "max-age", cfg.gasTokenMaxRateAge)
+	return &gasTokenRate{
+		feed:     feed,
+		address:  cfg.gasTokenRateFeed,
+		decimals: decimals,
+		maxAge:   cfg.gasTokenMaxRateAge,
+		now:      time.Now,
+	}, nil
+}
+
+// fetch returns the latest price of the gas token in ether. A stale or
+// non positive answer is an error, so that no gas price is sent from it.
+func (r *gasTokenRate) fetch(ctx context.Context) (*big.Rat, error) {
+	round, err := r.feed.LatestRoundData(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get gas token rate: %w", err)
+	}
+	if round.Answer.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid gas token rate %s at round %s", round.Answer, round.RoundId)
+	}
+	updated := time.Unix(round.UpdatedAt.Int64(), 0)
+	if age := r.now().Sub(updated); age > r.maxAge {
+		return nil, fmt.Errorf("%w: round %s was updated %s ago", errStaleGasTokenRate, round.RoundId, age)
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(r.decimals)), nil)
+	return new(big.Rat).SetFrac(round.Answer, unit), nil
+}
+
+// apply converts the floor and the max gas price of the config to the gas
+// token at the latest rate and rescales the gas price of the pricer by the
+// change of the rate. It must be called from the update loop.
+func (r *gasTokenRate) apply(ctx context.Context, cfg *Config, pricer gasprices.Pricer) error {
+	if r == nil {
+		return nil
+	}
+	rate, err := r.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	value, _ := rate.Float64()
+	gasTokenRateGauge.Update(value)
+
+	// The pricer starts with the bounds in ether, which is a rate of 1.
+	// Lowered bounds are set before the raised ones, so that the floor does
+	// not exceed the max gas price in between. The order is reversed when
+	// the bounds were changed in ether since the last conversion.
+	previous := r.rate
+	if previous == nil {
+		previous = big.NewRat(1, 1)
+	}
+	setFloor := func() error {
+		if cfg.floorPrice == nil {
+			return nil
+		}
+		return pricer.SetFloor(toGasToken(cfg.floorPrice, rate))
+	}
+	setMaxPrice := func() error {
+		if cfg.maxGasPrice == nil {
+			return nil
+		}
+		return pricer.SetMaxPrice(toGasToken(cfg.maxGasPrice, rate))
+	}
+	first, second := setFloor, setMaxPrice
+	if rate.Cmp(previous) < 0 {
+		first, second = setMaxPrice, setFloor
+	}
+	if err := first(); err != nil {
+		if err := second(); err != nil {
+			return err
+		}
+		if err := first(); err != nil {
+			return err
+		}
+	} else if err := second(); err != nil {
+		return err
+	}
+
+	if r.rate == nil || r.rate.Cmp(rate) == 0 {
+		r.rate = rate
+		return nil
+	}
+	price := pricer.GetGasPrice()
+	rescaled := new(big.Rat).Mul(new(big.Rat).SetInt(price), new(big.Rat).Quo(r.rate, rate))
+	next := new(big.Int).Quo(rescaled.Num(), rescaled.Denom())
+	log.Info("Gas token rate changed, rescaling the gas price", "feed", r.address.Hex(),
+		"previous", r.rate.FloatString(8), "rate", rate.FloatString(8), "gas-price", price, "new-gas-price", next)
+	r.rate = rate
+	return pricer.SetGasPrice(next)
+}
+
+// toGasToken converts an amount in wei to the gas token at the rate, which
+// is the price of the gas token in ether. The amount is at least 1, the
+// lowest floor price.
+func toGasToken(amount *big.Int, rate *big.Rat) *big.Int {
+	converted := new(big.Rat).Quo(new(big.Rat).SetInt(amount), rate)
+	value := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if value.Sign() == 0 {
+		value.SetInt64(1)
+	}
+	return value
+}
@@ -0,0 +1,112 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeRateFeed is an L1 backend with a Chainlink price feed at every address
+type fakeRateFeed struct {
+	bind.ContractTransactor
+	abi       abi.ABI
+	answer    *big.Int
+	updatedAt time.Time
+}
+
+func (f *fakeRateFeed) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (f *fakeRateFeed) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := f.abi.MethodById(call.Data)
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "decimals":
+		return method.Outputs.Pack(uint8(8))
+	case "latestRoundData":
+		updated := big.NewInt(f.updatedAt.Unix())
+		return method.Outputs.Pack(big.NewInt(7), f.answer, updated, updated, big.NewInt(7))
+	}
+	return nil, errors.New("not implemented")
+}
+
+func TestGasTokenRate(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(bindings.AggregatorV3MetaData.ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1_000_000, 0)
+	// The gas token is worth half an ether
+	feed := &fakeRateFeed{abi: parsed, answer: big.NewInt(50_000_000), updatedAt: now}
+
+	cfg := &Config{
+		gasTokenRateFeed:   common.HexToAddress("0x01"),
+		gasTokenMaxRateAge: time.Hour,
+		floorPrice:         big.NewInt(100),
+		maxGasPrice:        big.NewInt(10_000),
+	}
+	r, err := newGasTokenRate(context.Background(), cfg, feed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = func() time.Time { return now }
+	target := func() float64 { return 11_000_000 }
+	pricer, err := gasprices.NewGasPricer(big.NewInt(1000), cfg.floorPrice, target, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pricer.SetMaxPrice(cfg.maxGasPrice); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first rate converts the bounds and keeps the gas price
+	if err := r.apply(context.Background(), cfg, pricer); err != nil {
+		t.Fatal(err)
+	}
+	if price := pricer.GetGasPrice(); price.Uint64() != 1000 {
+		t.Fatalf("expected the gas price to be kept, got %d", price)
+	}
+
+	// The gas price doubles when the gas token is worth half as much
+	feed.answer = big.NewInt(25_000_000)
+	if err := r.apply(context.Background(), cfg, pricer); err != nil {
+		t.Fatal(err)
+	}
+	if price := pricer.GetGasPrice(); price.Uint64() != 2000 {
+		t.Fatalf("expected a gas price of 2000, got %d", price)
+	}
+
+	// The bounds follow the rate, the floor of 100 wei is 100 at par
+	feed.answer = big.NewInt(100_000_000)
+	if err := r.apply(context.Background(), cfg, pricer); err != nil {
+		t.Fatal(err)
+	}
+	if price := pricer.GetGasPrice(); price.Uint64() != 500 {
+		t.Fatalf("expected a gas price of 500, got %d", price)
+	}
+	if err := pricer.SetGasPrice(big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	if price := pricer.GetGasPrice(); price.Uint64() != 100 {
+		t.Fatalf("expected the floor price of 100, got %d", price)
+	}
+
+	// A stale rate is not used
+	now = now.Add(2 * time.Hour)
+	if err := r.apply(context.Background(), cfg, pricer); !errors.Is(err, errStaleGasTokenRate) {
+		t.Fatalf("expected %v, got %v", errStaleGasTokenRate, err)
+	}
+}
@@ -0,0 +1,177 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/api"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer serves the gRPC API of a running oracle, which mirrors the
+// admin HTTP API
+type grpcServer struct {
+	api.UnimplementedGasOracleServer
+	gpo *GasPriceOracle
+}
+
+func (s *grpcServer) GetStatus(ctx context.Context, _ *api.GetStatusRequest) (*api.Status, error) {
+	return s.status(ctx)
+}
+
+func (s *grpcServer) SetParams(ctx context.Context, req *api.SetParamsRequest) (*api.Status, error) {
+	if req.FloorPrice != "" {
+		floorPrice, err := gasprices.ParseWei(req.FloorPrice)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "floor price: %v", err)
+		}
+		if err := s.gpo.SetFloorPrice(floorPrice); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if req.TargetGasPerSecond != 0 {
+		if err := s.gpo.SetTargetGasPerSecond(req.TargetGasPerSecond); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	return s.status(ctx)
+}
+
+func (s *grpcServer) Pause(ctx context.Context, _ *api.PauseRequest) (*api.Status, error) {
+	s.gpo.Pause()
+	return s.status(ctx)
+}
+
+func (s *grpcServer) Resume(ctx context.Context, _ *api.ResumeRequest) (*api.Status, error) {
+	s.gpo.Resume()
+	return s.status(ctx)
+}
+
+func (s *grpcServer) EpochEvents(_ *api.EpochEventsRequest, stream api.GasOracle_EpochEventsServer) error {
+	decisions, unsubscribe := s.gpo.SubscribeDecisions()
+	defer unsubscribe()
+	for {
+		select {
+		case d := <-decisions:
+			if err := stream.Send(epochEventProto(d)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *grpcServer) status(ctx context.Context) (*api.Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, adminRequestTimeout)
+	defer cancel()
+	state, err := s.gpo.State(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	out := &api.Status{
+		Paused:             state.Paused,
+		GasPrice:           state.GasPrice.String(),
+		LocalGasPrice:      state.LocalGasPrice.String(),
+		TargetGasPerSecond: state.TargetGasPerSecond,
+		Epochs:             state.Epochs,
+		Updates:            state.Updates,
+		AvgGasPerSecond:    state.AvgGasPerSecond,
+	}
+	if state.FloorPrice != nil {
+		out.FloorPrice = state.FloorPrice.String()
+	}
+	if state.LastDecision != nil {
+		out.LastEpoch = epochEventProto(state.LastDecision)
+	}
+	for _, hash := range state.PendingTransactions {
+		out.PendingTransactions = append(out.PendingTransactions, hash.Hex())
+	}
+	return out, nil
+}
+
+func epochEventProto(d *Decision) *api.EpochEvent {
+	event := &api.EpochEvent{
+		Time:               d.Time.UnixNano() / 1e6,
+		AvgGasPerSecond:    d.AvgGasPerSecond,
+		Change:             d.Change,
+		SignificanceFactor: d.SignificanceFactor,
+		Send:               d.Send,
+		Reason:             string(d.Reason),
+		Bound:              string(d.Bound),
+	}
+	if d.CurrentPrice != nil {
+		event.CurrentPrice = d.CurrentPrice.String()
+	}
+	if d.ComputedPrice != nil {
+		event.ComputedPrice = d.ComputedPrice.String()
+	}
+	if d.GasPrice != nil {
+		event.GasPrice = d.GasPrice.String()
+	}
+	return event
+}
+
+// grpcAuthorize returns nil when the metadata of the call carries a valid
+// bearer token
+func grpcAuthorize(ctx context.Context, auth *adminAuth, method string) error {
+	err := errUnauthorized
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, header := range md.Get("authorization") {
+			if err = auth.authorize(header); err == nil {
+				return nil
+			}
+		}
+	}
+	log.Warn("Unauthorized gRPC call", "method", method, "message", err)
+	return status.Error(codes.Unauthenticated, err.Error())
+}
+
+// newGRPCServer creates a gRPC server of the GasOracle service whose calls
+// must be authorized
+func newGRPCServer(gpo *GasPriceOracle, auth *adminAuth) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := grpcAuthorize(ctx, auth, info.FullMethod); err != nil {
+				return nil, err
+			}
+			resp, err := handler(ctx, req)
+			if err == nil && info.FullMethod != "/gasoracle.v1.GasOracle/GetStatus" {
+				log.Info("Admin action", "method", info.FullMethod)
+			}
+			return resp, err
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := grpcAuthorize(ss.Context(), auth, info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	api.RegisterGasOracleServer(server, &grpcServer{gpo: gpo})
+	return server
+}
+
+// startGRPCServer serves the gRPC API at the configured address. The address
+// is bound before returning so that a busy port fails the startup.
+func (g *GasPriceOracle) startGRPCServer() error {
+	listener, err := net.Listen("tcp", g.config.adminGRPCAddr)
+	if err != nil {
+		return fmt.Errorf("cannot start gRPC server: %w", err)
+	}
+	server := newGRPCServer(g, newAdminAuth(g.config))
+	log.Info("Starting gRPC server", "addr", listener.Addr().String())
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Error("Failure in running gRPC server", "message", err)
+		}
+	}()
+	return nil
+}
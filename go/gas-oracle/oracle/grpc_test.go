@@ -0,0 +1,98 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCServer(t *testing.T) {
+	gpo, _, _ := newControlledOracle(t)
+	listener := bufconn.Listen(1 << 20)
+	server := newGRPCServer(gpo, &adminAuth{token: "secret"})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufconn", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := api.NewGasOracleClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.GetStatus(ctx, &api.GetStatusRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected unauthenticated without a token, got %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret")
+	st, err := client.GetStatus(ctx, &api.GetStatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Paused || st.LocalGasPrice != "10" || st.TargetGasPerSecond != 11_000_000 {
+		t.Fatalf("unexpected status %v", st)
+	}
+
+	if st, err = client.Pause(ctx, &api.PauseRequest{}); err != nil || !st.Paused {
+		t.Fatalf("expected paused, got %v %v", st, err)
+	}
+	if st, err = client.Resume(ctx, &api.ResumeRequest{}); err != nil || st.Paused {
+		t.Fatalf("expected resumed, got %v %v", st, err)
+	}
+
+	_, err = client.SetParams(ctx, &api.SetParamsRequest{FloorPrice: "2000"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected a floor above the max gas price to be rejected, got %v", err)
+	}
+	st, err = client.SetParams(ctx, &api.SetParamsRequest{FloorPrice: "100", TargetGasPerSecond: 5_000_000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.FloorPrice != "100" || st.TargetGasPerSecond != 5_000_000 {
+		t.Fatalf("unexpected status %v", st)
+	}
+
+	stream, err := client.EpochEvents(ctx, &api.EpochEventsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wait for the subscription before deciding an epoch
+	for {
+		gpo.config.controls.mu.Lock()
+		n := len(gpo.config.controls.subscribers)
+		gpo.config.controls.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	gpo.config.controls.observeDecision(&Decision{
+		Time:          time.Unix(10, 0),
+		CurrentPrice:  big.NewInt(10),
+		ComputedPrice: big.NewInt(20),
+		GasPrice:      big.NewInt(20),
+		Send:          true,
+		Reason:        ReasonUpdated,
+	})
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Time != 10_000 || event.GasPrice != "20" || !event.Send || event.Reason != "UPDATED" {
+		t.Fatalf("unexpected event %v", event)
+	}
+}
@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// takeOver adopts the checkpoint that the previous leader saved with the
+// lease, like a restarted oracle adopts its saved state. The epoch of the
+// previous leader is continued when it is still running, so that its blocks
+// are neither measured twice nor skipped. The new leader waits for the
+// update transaction of the previous leader while it is pending, so that its
+// nonce is not reused. The replica stays on standby until the checkpoint is
+// adopted and the next renewal tries again.
+func (g *GasPriceOracle) takeOver(ctx context.Context) error {
+	l := g.config.leader
+	saved, err := l.loadState(ctx)
+	if err != nil {
+		return err
+	}
+	if err := reconcileCheckpoint(ctx, g.config, g.l2Backend, saved); err != nil {
+		return err
+	}
+	if saved != nil && saved.PendingTransaction != nil && sameDeployment(g.config, saved) {
+		pending := saved.PendingTransaction
+		log.Info("Waiting for the update transaction of the previous leader", "hash", pending.Hash,
+			"nonce", pending.Nonce)
+		return nil
+	}
+
+	tip, err := g.l2Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	onChain, err := g.contract.GasPrice(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+	if restored := restoreState(g.config, saved, tip.Number.Uint64(), onChain, time.Now()); restored != nil {
+		if !restored.epochStart.IsZero() {
+			log.Info("Continuing the epoch of the previous leader", "epochStartBlockNumber",
+				restored.epochStartBlockNumber, "epochStart", restored.epochStart)
+			g.gasPriceUpdater.SetEpochStartBlockNumber(restored.epochStartBlockNumber)
+			select {
+			case g.handoff <- restored.epochStart:
+			default:
+			}
+		}
+		// The gas price of the previous leader replaces the resync to the
+		// chain when the chain did not change since
+		if restored.gasPrice != nil {
+			log.Info("Restoring the gas price of the previous leader", "gas-price", restored.gasPrice)
+			g.config.controls.takeResync()
+			if err := g.gasPriceUpdater.SetGasPrice(restored.gasPrice); err != nil {
+				return err
+			}
+		}
+		if g.smoothing != nil && len(restored.smoothingSamples) > 0 {
+			g.smoothing.SetSamples(restored.smoothingSamples)
+		}
+	}
+	if saved != nil && sameDeployment(g.config, saved) {
+		g.config.checkpoint.adopt(saved)
+	}
+	l.completeHandoff()
+	log.Info("Took over from the previous leader, sending transactions", "replica", l.holder)
+	return nil
+}
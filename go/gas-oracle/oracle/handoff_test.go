@@ -0,0 +1,93 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+func TestTakeOver(t *testing.T) {
+	gpo, pricer, sim := newControlledOracle(t)
+	cfg := gpo.config
+	cfg.epochLength = time.Minute
+	cfg.leaseTTL = 15 * time.Second
+	lease := &memoryLease{holder: "b"}
+	cfg.leader = &leaderElection{
+		lease:    lease,
+		holder:   "a",
+		ttl:      cfg.leaseTTL,
+		controls: cfg.controls,
+		now:      time.Now,
+	}
+	cfg.checkpoint = newCheckpoint(cfg, nil)
+	ctx := context.Background()
+
+	// The previous leader died after sending an update in an epoch that is
+	// still running
+	opts, _ := bind.NewKeyedTransactorWithChainID(cfg.privateKey, big.NewInt(1337))
+	tx, err := gpo.contract.SetGasPrice(opts, big.NewInt(25))
+	if err != nil {
+		t.Fatal(err)
+	}
+	epochStart := time.Now().Add(-20 * time.Second)
+	state, _ := json.Marshal(&store.State{
+		ChainID:               big.NewInt(1337),
+		Address:               cfg.gasPriceOracleAddress.Hex(),
+		EpochStartBlockNumber: 1,
+		EpochStartTime:        epochStart,
+		GasPrice:              big.NewInt(26),
+		OnChainGasPrice:       big.NewInt(1),
+		PendingTransaction: &store.PendingTransaction{
+			Hash:     tx.Hash().Hex(),
+			Nonce:    tx.Nonce(),
+			GasPrice: big.NewInt(25),
+			SignedAt: time.Now(),
+		},
+	})
+	lease.state = state
+	lease.Release(ctx, "b")
+	if err := cfg.leader.renew(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The nonce of the pending transaction is not reused
+	gpo.takeOverIfPending()
+	if cfg.leader.isLeader() {
+		t.Fatal("expected the new leader to wait for the pending transaction")
+	}
+
+	sim.Commit()
+	gpo.takeOverIfPending()
+	if !cfg.leader.isLeader() {
+		t.Fatal("expected the new leader to take over")
+	}
+	if gpo.gasPriceUpdater.EpochStartBlockNumber() != 1 {
+		t.Fatalf("expected the epoch of the previous leader, got %d", gpo.gasPriceUpdater.EpochStartBlockNumber())
+	}
+	select {
+	case start := <-gpo.handoff:
+		if !start.Equal(epochStart) {
+			t.Fatalf("unexpected epoch start %s", start)
+		}
+	default:
+		t.Fatal("expected the epoch of the previous leader to be continued")
+	}
+	if pricer.GetGasPrice().Uint64() != 26 || cfg.controls.takeResync() {
+		t.Fatalf("expected the gas price of the previous leader, got %s", pricer.GetGasPrice())
+	}
+
+	// The new leader saves its checkpoint with the lease
+	gpo.saveState(ctx, big.NewInt(25))
+	saved, err := cfg.leader.loadState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.PendingTransaction != nil || saved.LastSentGasPrice.Uint64() != 25 || saved.OnChainGasPrice.Uint64() != 25 {
+		t.Fatalf("unexpected checkpoint %+v", saved)
+	}
+}
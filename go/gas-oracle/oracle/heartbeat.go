@@ -0,0 +1,62 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var heartbeatFailureCounter = metrics.NewRegisteredCounter("heartbeat/failure", ometrics.DefaultRegistry)
+
+// heartbeat pings a dead man's switch such as healthchecks.io after every
+// successful epoch. When the pings stop, because the process died or because
+// the loop is stuck, the external service raises an alert.
+type heartbeat struct {
+	url    string
+	client *http.Client
+}
+
+func newHeartbeat(url string) *heartbeat {
+	if url == "" {
+		return nil
+	}
+	return &heartbeat{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ping sends a ping in the background so that a slow endpoint does not
+// delay the epochs
+func (h *heartbeat) ping() {
+	if h == nil {
+		return
+	}
+	go func() {
+		if err := h.send(context.Background()); err != nil {
+			heartbeatFailureCounter.Inc(1)
+			log.Warn("cannot ping heartbeat", "message", err)
+		}
+	}()
+}
+
+func (h *heartbeat) send(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	if newHeartbeat("") != nil {
+		t.Fatal("expected no heartbeat without a url")
+	}
+	// A missing heartbeat is not pinged
+	var h *heartbeat
+	h.ping()
+
+	pings := make(chan struct{}, 1)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		pings <- struct{}{}
+	}))
+	defer server.Close()
+
+	h = newHeartbeat(server.URL)
+	h.ping()
+	select {
+	case <-pings:
+	case <-time.After(time.Second):
+		t.Fatal("expected a ping")
+	}
+
+	status = http.StatusInternalServerError
+	if err := h.send(context.Background()); err == nil {
+		t.Fatal("expected error for a failed ping")
+	}
+}
@@ -0,0 +1,216 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// historyChunkSize is the number of blocks that are filtered per request,
+// since nodes limit the range of a log query
+const historyChunkSize = 5000
+
+// HistoryEvent is an update of the gas price oracle that was found in the
+// history of the chain
+type HistoryEvent struct {
+	Kind        AuditKind   `json:"kind"`
+	BlockNumber uint64      `json:"blockNumber"`
+	Timestamp   uint64      `json:"timestamp"`
+	TxHash      common.Hash `json:"txHash"`
+	// OldValue is the value before the update, it is not set for the first
+	// update of a kind when the node cannot serve the state before it
+	OldValue *big.Int `json:"oldValue"`
+	NewValue *big.Int `json:"newValue"`
+
+	logIndex uint
+}
+
+// FetchHistory returns the GasPriceUpdated and L1BaseFeeUpdated events of the
+// gas price oracle from start to end, in the order that they were emitted.
+// The value before the first update of each kind is read from the state at
+// the previous block, which requires an archive node.
+func FetchHistory(ctx context.Context, cfg *Config, backend L2Backend, start, end uint64) ([]*HistoryEvent, error) {
+	if end < start {
+		return nil, errors.New("the end block is before the start block")
+	}
+	contract, err := bindings.NewGasPriceOracle(cfg.gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*HistoryEvent
+	for from := start; from <= end; from += historyChunkSize {
+		to := from + historyChunkSize - 1
+		if to > end {
+			to = end
+		}
+		log.Debug("Fetching gas price oracle events", "start", from, "end", to)
+		opts := &bind.FilterOpts{Start: from, End: &to, Context: ctx}
+
+		gasPrices, err := contract.FilterGasPriceUpdated(opts)
+		if err != nil {
+			return nil, err
+		}
+		for gasPrices.Next() {
+			events = append(events, newHistoryEvent(AuditKindL2GasPrice, gasPrices.Event.Arg0,
+				gasPrices.Event.Raw.BlockNumber, gasPrices.Event.Raw.TxHash, gasPrices.Event.Raw.Index))
+		}
+		gasPrices.Close()
+		if err := gasPrices.Error(); err != nil {
+			return nil, err
+		}
+
+		baseFees, err := contract.FilterL1BaseFeeUpdated(opts)
+		if err != nil {
+			return nil, err
+		}
+		for baseFees.Next() {
+			events = append(events, newHistoryEvent(AuditKindL1BaseFee, baseFees.Event.Arg0,
+				baseFees.Event.Raw.BlockNumber, baseFees.Event.Raw.TxHash, baseFees.Event.Raw.Index))
+		}
+		baseFees.Close()
+		if err := baseFees.Error(); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return events[i].logIndex < events[j].logIndex
+	})
+
+	// Each update replaces the value of the previous update of its kind
+	previous := make(map[AuditKind]*big.Int)
+	timestamps := make(map[uint64]uint64)
+	for _, event := range events {
+		if prev, ok := previous[event.Kind]; ok {
+			event.OldValue = prev
+		} else {
+			event.OldValue = valueBefore(ctx, contract, event.Kind, event.BlockNumber)
+		}
+		previous[event.Kind] = event.NewValue
+
+		timestamp, ok := timestamps[event.BlockNumber]
+		if !ok {
+			header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(event.BlockNumber))
+			if err != nil {
+				return nil, err
+			}
+			timestamp = header.Time
+			timestamps[event.BlockNumber] = timestamp
+		}
+		event.Timestamp = timestamp
+	}
+	return events, nil
+}
+
+// WatchHistory polls the chain for updates of the gas price oracle from the
+// start block and calls fn with each update in the order that they were
+// emitted, until the context is done. It polls like the updateWatcher rather
+// than subscribing, so that it works with an HTTP endpoint.
+func WatchHistory(ctx context.Context, cfg *Config, backend L2Backend, start uint64, interval time.Duration,
+	fn func(*HistoryEvent) error) error {
+	next := start
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		tip, err := backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Warn("cannot fetch the tip", "message", err)
+		} else if tip.Number.Uint64() >= next {
+			events, err := FetchHistory(ctx, cfg, backend, next, tip.Number.Uint64())
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			for _, event := range events {
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			next = tip.Number.Uint64() + 1
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// FetchStoredHistory returns the successful updates from start to end that
+// are recorded in the database of the configuration. The timestamp of an
+// update is when its receipt was seen by the oracle, and its old value is
+// the value of the previous recorded update of its kind.
+func FetchStoredHistory(ctx context.Context, cfg *Config, start, end uint64) ([]*HistoryEvent, error) {
+	if cfg.postgresURL == "" {
+		return nil, errors.New("no postgres database is configured")
+	}
+	if end < start {
+		return nil, errors.New("the end block is before the start block")
+	}
+	db, err := store.OpenPostgres(ctx, cfg.postgresURL)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	updates, err := db.Updates(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*HistoryEvent, 0, len(updates))
+	for _, u := range updates {
+		event := newHistoryEvent(AuditKind(u.Kind), u.NewValue, u.BlockNumber, common.HexToHash(u.Hash), 0)
+		event.Timestamp = uint64(u.Time.Unix())
+		event.OldValue = u.OldValue
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func newHistoryEvent(kind AuditKind, value *big.Int, block uint64, txHash common.Hash, index uint) *HistoryEvent {
+	return &HistoryEvent{
+		Kind:        kind,
+		BlockNumber: block,
+		TxHash:      txHash,
+		NewValue:    value,
+		logIndex:    index,
+	}
+}
+
+// valueBefore reads the value of the kind at the block before the block, it
+// returns nil when the node cannot serve the state of that block
+func valueBefore(ctx context.Context, contract *bindings.GasPriceOracle, kind AuditKind, block uint64) *big.Int {
+	if block == 0 {
+		return nil
+	}
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(block - 1)}
+	var value *big.Int
+	var err error
+	switch kind {
+	case AuditKindL2GasPrice:
+		value, err = contract.GasPrice(opts)
+	case AuditKindL1BaseFee:
+		value, err = contract.L1BaseFee(opts)
+	}
+	if err != nil {
+		log.Debug("cannot read the value before the first update", "kind", kind, "block", block, "message", err)
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,117 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestFetchHistory(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	if _, err := gpo.SetGasPrice(opts, big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if _, err := gpo.SetL1BaseFee(opts, big.NewInt(30)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gpo.SetGasPrice(opts, big.NewInt(200)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{gasPriceOracleAddress: addr}
+	events, err := FetchHistory(context.Background(), cfg, sim, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	// The simulated backend cannot serve the state of past blocks, so the
+	// first update of each kind has no old value
+	expected := []struct {
+		kind       AuditKind
+		block      uint64
+		old, value *big.Int
+	}{
+		{AuditKindL2GasPrice, 2, nil, big.NewInt(100)},
+		{AuditKindL1BaseFee, 3, nil, big.NewInt(30)},
+		{AuditKindL2GasPrice, 3, big.NewInt(100), big.NewInt(200)},
+	}
+	for i, e := range expected {
+		event := events[i]
+		if event.Kind != e.kind || event.BlockNumber != e.block || event.NewValue.Cmp(e.value) != 0 {
+			t.Fatalf("event %d: unexpected %+v", i, event)
+		}
+		if (e.old == nil) != (event.OldValue == nil) || (e.old != nil && event.OldValue.Cmp(e.old) != 0) {
+			t.Fatalf("event %d: expected old value %v, got %v", i, e.old, event.OldValue)
+		}
+		if event.Timestamp == 0 {
+			t.Fatalf("event %d: no timestamp", i)
+		}
+	}
+
+	if _, err := FetchHistory(context.Background(), cfg, sim, 3, 2); err == nil {
+		t.Fatal("expected an error for an empty range")
+	}
+}
+
+func TestWatchHistory(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	// Updates before the start block are not printed
+	if _, err := gpo.SetGasPrice(opts, big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan *HistoryEvent, 10)
+	done := make(chan error, 1)
+	cfg := &Config{gasPriceOracleAddress: addr}
+	go func() {
+		done <- WatchHistory(ctx, cfg, sim, 3, 10*time.Millisecond, func(event *HistoryEvent) error {
+			events <- event
+			return nil
+		})
+	}()
+
+	if _, err := gpo.SetGasPrice(opts, big.NewInt(200)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	select {
+	case event := <-events:
+		if event.BlockNumber != 3 || event.NewValue.Uint64() != 200 {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
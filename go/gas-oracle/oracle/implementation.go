@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var implementationChangeCounter = metrics.NewRegisteredCounter("contract/implementation_change", ometrics.DefaultRegistry)
+
+// implementationMonitor tracks the implementation of a gas price oracle that
+// is deployed behind an EIP-1967 proxy. An upgrade of the proxy can change
+// the ABI under the running oracle, so the new implementation is validated
+// like at startup and the updates are paused when it is not compatible.
+type implementationMonitor struct {
+	cfg     *Config
+	backend contractBackend
+	// contract is the last resolved contract
+	contract *gasPriceOracleContract
+}
+
+// newImplementationMonitor returns nil when the gas price oracle is not a
+// proxy or when the check is disabled
+func newImplementationMonitor(cfg *Config, backend contractBackend, resolved *gasPriceOracleContract) *implementationMonitor {
+	if resolved.Implementation == (common.Address{}) || cfg.implementationCheckInterval <= 0 {
+		return nil
+	}
+	log.Info("Tracking the gas price oracle implementation", "address", resolved.Address.Hex(),
+		"implementation", resolved.Implementation.Hex(), "interval", cfg.implementationCheckInterval)
+	return &implementationMonitor{cfg: cfg, backend: backend, contract: resolved}
+}
+
+// check reads the implementation slot of the proxy and validates the
+// implementation when it changed. The change is notified once, with the
+// error when the updates were paused.
+func (m *implementationMonitor) check(ctx context.Context) error {
+	slot, err := m.backend.StorageAt(ctx, m.contract.Address, eip1967ImplementationSlot, nil)
+	if err != nil {
+		return fmt.Errorf("cannot get implementation: %w", err)
+	}
+	implementation := common.BytesToAddress(slot)
+	if implementation == m.contract.Implementation {
+		return nil
+	}
+	previous := m.contract
+	log.Warn("Gas price oracle implementation changed", "address", previous.Address.Hex(),
+		"previous", previous.Implementation.Hex(), "implementation", implementation.Hex())
+	implementationChangeCounter.Inc(1)
+
+	resolved, err := resolveGasPriceOracle(ctx, m.backend, previous.Address)
+	if err == nil {
+		err = m.compatible(previous, resolved)
+	}
+	event := &notify.Event{
+		Type:                   notify.EventImplementationChanged,
+		Time:                   time.Now(),
+		ChainID:                m.cfg.l2ChainID,
+		Implementation:         implementation.Hex(),
+		PreviousImplementation: previous.Implementation.Hex(),
+	}
+	if err != nil {
+		log.Error("Gas price oracle implementation is not compatible, pausing updates",
+			"implementation", implementation.Hex(), "message", err)
+		m.cfg.controls.setPaused(true)
+		event.Error = err.Error()
+	}
+	m.cfg.notifier.Notify(event)
+
+	// The new implementation is tracked even when it is not compatible, so
+	// that the next upgrade is detected
+	if resolved != nil {
+		m.contract = resolved
+	} else {
+		m.contract = &gasPriceOracleContract{Address: previous.Address, Implementation: implementation,
+			Version: previous.Version}
+	}
+	return nil
+}
+
+// compatible checks that the new implementation can be updated like the
+// previous one
+func (m *implementationMonitor) compatible(previous, resolved *gasPriceOracleContract) error {
+	if resolved.Version != previous.Version {
+		return fmt.Errorf("%w: the version changed from %s to %s", errUnexpectedContractVersion,
+			previous.Version, resolved.Version)
+	}
+	if signer, ok := m.cfg.expectedOwner(); ok && signer != resolved.Owner {
+		return fmt.Errorf("%w: the owner changed to %s", errInvalidSigningKey, resolved.Owner.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// upgradableProxy serves the implementation slot of the proxy so that the
+// test can upgrade it
+type upgradableProxy struct {
+	*backends.SimulatedBackend
+	proxy          common.Address
+	implementation common.Address
+}
+
+func (u *upgradableProxy) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	if account == u.proxy && key == eip1967ImplementationSlot {
+		return common.BytesToHash(u.implementation.Bytes()).Bytes(), nil
+	}
+	return u.SimulatedBackend.StorageAt(ctx, account, key, blockNumber)
+}
+
+func TestImplementationMonitor(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	code, err := sim.CodeAt(context.Background(), addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := common.HexToAddress("0x4200000000000000000000000000000000000099")
+	first := common.HexToAddress("0x4200000000000000000000000000000000000098")
+	second := common.HexToAddress("0x4200000000000000000000000000000000000097")
+	bedrock := common.HexToAddress("0x4200000000000000000000000000000000000096")
+	backend := &upgradableProxy{
+		SimulatedBackend: backends.NewSimulatedBackend(core.GenesisAlloc{
+			proxy:   {Code: code, Balance: big.NewInt(0)},
+			first:   {Code: code, Balance: big.NewInt(0)},
+			second:  {Code: code, Balance: big.NewInt(0)},
+			bedrock: {Code: bedrockGasPriceOracleCode(1, "1.0.0"), Balance: big.NewInt(0)},
+		}, 9_000_000),
+		proxy:          proxy,
+		implementation: first,
+	}
+
+	ctx := context.Background()
+	resolved, err := resolveGasPriceOracle(ctx, backend, proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{implementationCheckInterval: time.Minute}
+	cfg.controls = newControls(cfg)
+	if m := newImplementationMonitor(cfg, backend, &gasPriceOracleContract{Address: addr}); m != nil {
+		t.Fatal("expected no monitor without a proxy")
+	}
+	m := newImplementationMonitor(cfg, backend, resolved)
+	if m == nil {
+		t.Fatal("expected a monitor of the proxy")
+	}
+
+	// Nothing happens while the implementation stays the same
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.controls.isPaused() {
+		t.Fatal("expected the updates to run")
+	}
+
+	// An upgrade to a compatible implementation keeps the updates running
+	backend.implementation = second
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.controls.isPaused() || m.contract.Implementation != second {
+		t.Fatalf("expected the updates to run with %s, got %s", second.Hex(), m.contract.Implementation.Hex())
+	}
+
+	// An upgrade to another version pauses the updates
+	backend.implementation = bedrock
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.controls.isPaused() || m.contract.Implementation != bedrock {
+		t.Fatalf("expected the updates to be paused with %s, got %s", bedrock.Hex(), m.contract.Implementation.Hex())
+	}
+}
@@ -0,0 +1,59 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// adminRPCNamespace is the namespace of the JSON-RPC methods of the admin
+// API, such as gasoracle_getStatus
+const adminRPCNamespace = "gasoracle"
+
+// adminRPCAPI is the JSON-RPC service of the admin API
+type adminRPCAPI struct {
+	gpo *GasPriceOracle
+}
+
+// GetStatus returns the runtime state of the oracle
+func (a *adminRPCAPI) GetStatus(ctx context.Context) (*RuntimeState, error) {
+	ctx, cancel := context.WithTimeout(ctx, adminRequestTimeout)
+	defer cancel()
+	return a.gpo.State(ctx)
+}
+
+// SetTargetGasPerSecond sets the target gas per second from the next epoch
+func (a *adminRPCAPI) SetTargetGasPerSecond(target uint64) error {
+	return a.gpo.SetTargetGasPerSecond(target)
+}
+
+// SetFloorPrice sets the floor price in wei from the next epoch
+func (a *adminRPCAPI) SetFloorPrice(floorPrice *big.Int) error {
+	return a.gpo.SetFloorPrice(floorPrice)
+}
+
+// Pause stops sending transactions
+func (a *adminRPCAPI) Pause() {
+	a.gpo.Pause()
+}
+
+// Resume sends transactions again
+func (a *adminRPCAPI) Resume() {
+	a.gpo.Resume()
+}
+
+// ForceUpdate closes the current epoch, sends its gas price and returns its
+// decision
+func (a *adminRPCAPI) ForceUpdate(ctx context.Context) (*Decision, error) {
+	return a.gpo.ForceUpdate(ctx)
+}
+
+// newAdminRPCServer creates the JSON-RPC server of the admin API
+func newAdminRPCServer(gpo *GasPriceOracle) (*rpc.Server, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName(adminRPCNamespace, &adminRPCAPI{gpo: gpo}); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
@@ -0,0 +1,101 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestAdminRPC(t *testing.T) {
+	gpo, pricer, sim := newControlledOracle(t)
+	handler, err := newAdminHandler(gpo, &adminAuth{token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	// Serve the forced updates like the update loop
+	go func() {
+		for reply := range gpo.force {
+			reply <- gpo.forceUpdate(time.Second)
+		}
+	}()
+	defer close(gpo.force)
+
+	client, err := rpc.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+	var state RuntimeState
+	if err := client.CallContext(ctx, &state, "gasoracle_getStatus"); err == nil {
+		t.Fatal("expected an error without a token")
+	}
+
+	client.SetHeader("Authorization", "Bearer secret")
+	if err := client.CallContext(ctx, &state, "gasoracle_getStatus"); err != nil {
+		t.Fatal(err)
+	}
+	if state.Paused || state.LocalGasPrice.Uint64() != 10 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	if err := client.CallContext(ctx, nil, "gasoracle_setTargetGasPerSecond", 5_000_000); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CallContext(ctx, nil, "gasoracle_setTargetGasPerSecond", 0); err == nil {
+		t.Fatal("expected a zero target to be rejected")
+	}
+	if err := client.CallContext(ctx, nil, "gasoracle_pause"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CallContext(ctx, &state, "gasoracle_getStatus"); err != nil {
+		t.Fatal(err)
+	}
+	if !state.Paused || state.TargetGasPerSecond != 5_000_000 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+	if err := client.CallContext(ctx, nil, "gasoracle_resume"); err != nil {
+		t.Fatal(err)
+	}
+	// Run the epoch that resynchronizes the pricer after resuming
+	if err := gpo.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Move the contract to 100, then the pricer by less than the
+	// significance factor so that only a forced update sends it
+	if err := gpo.updateL2GasPrice(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if err := pricer.SetGasPrice(big.NewInt(101)); err != nil {
+		t.Fatal(err)
+	}
+	var decision Decision
+	if err := client.CallContext(ctx, &decision, "gasoracle_forceUpdate"); err != nil {
+		t.Fatal(err)
+	}
+	if !decision.Send || decision.Reason != ReasonForced || decision.GasPrice.Uint64() != 101 {
+		t.Fatalf("unexpected decision %+v", decision)
+	}
+	sim.Commit()
+	contract, err := bindings.NewGasPriceOracleCaller(gpo.config.gasPriceOracleAddress, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gasPrice, err := contract.GasPrice(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.Uint64() != 101 {
+		t.Fatalf("expected the forced gas price, got %d", gasPrice)
+	}
+}
@@ -0,0 +1,192 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// l1TxFeeBump is the factor by which both fees of a resubmitted transaction
+// are raised. Nodes only replace a pending transaction when both fees rise by
+// at least 10%.
+var l1TxFeeBump = big.NewRat(9, 8)
+
+// l1TxPollInterval is how often the receipts of the pending L1 transactions
+// are polled
+var l1TxPollInterval = 2 * time.Second
+
+// l1ContractBackend is the part of the L1 backend that is used to call and
+// transact with the contracts on L1
+type l1ContractBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// l1TxManager sends transactions to L1 and resubmits them with higher fees
+// until one of them is mined. The base fee of L1 moves much more than the one
+// of L2, so a transaction gets stuck when the base fee rises above its fee
+// cap.
+type l1TxManager struct {
+	backend         l1ContractBackend
+	opts            *bind.TransactOpts
+	resubmitTimeout time.Duration
+	maxFee          *big.Int
+	chainIDGuard    *chainIDGuard
+	chainID         *big.Int
+}
+
+func newL1TxManager(cfg *Config, backend l1ContractBackend) (*l1TxManager, error) {
+	opts, err := newTransactOptsForChain(cfg, cfg.l1ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return &l1TxManager{
+		backend:         backend,
+		opts:            opts,
+		resubmitTimeout: cfg.l1TxResubmitTimeout,
+		maxFee:          cfg.l1TxMaxFee,
+		chainIDGuard:    cfg.l1ChainIDGuard,
+		chainID:         cfg.l1ChainID,
+	}, nil
+}
+
+// send creates a transaction with newTx and sends it until it is mined. The
+// resubmissions reuse the nonce, so only one of them can be mined, and the
+// receipt of that one is returned.
+func (m *l1TxManager) send(ctx context.Context, newTx func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Receipt, error) {
+	if err := m.chainIDGuard.verify(ctx, m.chainID); err != nil {
+		return nil, err
+	}
+	nonce, err := m.backend.PendingNonceAt(ctx, m.opts.From)
+	if err != nil {
+		return nil, err
+	}
+	tipCap, feeCap, err := m.suggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sent []common.Hash
+	for {
+		opts := *m.opts
+		opts.Context = ctx
+		opts.Nonce = new(big.Int).SetUint64(nonce)
+		opts.GasTipCap, opts.GasFeeCap = m.capFees(tipCap, feeCap)
+		tx, err := newTx(&opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.backend.SendTransaction(ctx, tx); err != nil {
+			if len(sent) == 0 {
+				return nil, fmt.Errorf("cannot send L1 transaction: %w", err)
+			}
+			// A capped fee may not be enough to replace the pending
+			// transaction, which can still be mined
+			log.Warn("cannot resubmit L1 transaction", "nonce", nonce, "message", err)
+		} else {
+			log.Info("L1 transaction sent", "hash", tx.Hash().Hex(), "nonce", nonce,
+				"gas-tip-cap", opts.GasTipCap, "gas-fee-cap", opts.GasFeeCap)
+			sent = append(sent, tx.Hash())
+		}
+
+		receipt, err := m.waitForAny(ctx, sent)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return receipt, fmt.Errorf("L1 transaction %s reverted", receipt.TxHash.Hex())
+			}
+			return receipt, nil
+		}
+
+		suggestedTip, suggestedFee, err := m.suggestFees(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tipCap = bumpFee(tipCap, suggestedTip)
+		feeCap = bumpFee(feeCap, suggestedFee)
+		log.Warn("L1 transaction not mined, resubmitting", "nonce", nonce,
+			"timeout", m.resubmitTimeout, "gas-tip-cap", tipCap, "gas-fee-cap", feeCap)
+	}
+}
+
+// suggestFees returns the suggested tip and a fee cap that covers a doubling
+// of the base fee of the tip
+func (m *l1TxManager) suggestFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	tipCap, err := m.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if head.BaseFee == nil {
+		return nil, nil, errNoBaseFee
+	}
+	feeCap := new(big.Int).Mul(head.BaseFee, big.NewInt(2))
+	return tipCap, feeCap.Add(feeCap, tipCap), nil
+}
+
+// capFees limits the fees to the max fee
+func (m *l1TxManager) capFees(tipCap, feeCap *big.Int) (*big.Int, *big.Int) {
+	if m.maxFee == nil || feeCap.Cmp(m.maxFee) <= 0 {
+		return tipCap, feeCap
+	}
+	log.Warn("L1 transaction fee capped", "gas-fee-cap", feeCap, "max-fee", m.maxFee)
+	if tipCap.Cmp(m.maxFee) > 0 {
+		tipCap = m.maxFee
+	}
+	return tipCap, m.maxFee
+}
+
+// waitForAny waits up to the resubmit timeout for one of the transactions
+// to be mined. It returns nil when none of them was.
+func (m *l1TxManager) waitForAny(ctx context.Context, hashes []common.Hash) (*types.Receipt, error) {
+	timeout := time.NewTimer(m.resubmitTimeout)
+	defer timeout.Stop()
+	poll := time.NewTicker(l1TxPollInterval)
+	defer poll.Stop()
+	for {
+		select {
+		case <-poll.C:
+		case <-timeout.C:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		for _, hash := range hashes {
+			receipt, err := m.backend.TransactionReceipt(ctx, hash)
+			if errors.Is(err, ethereum.NotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if receipt != nil {
+				return receipt, nil
+			}
+		}
+	}
+}
+
+// bumpFee raises a fee by the fee bump, rounding up, or to the suggested fee
+// when that is higher
+func bumpFee(fee, suggested *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, l1TxFeeBump.Num())
+	bumped.Add(bumped, new(big.Int).Sub(l1TxFeeBump.Denom(), big.NewInt(1)))
+	bumped.Quo(bumped, l1TxFeeBump.Denom())
+	if suggested.Cmp(bumped) > 0 {
+		return new(big.Int).Set(suggested)
+	}
+	return bumped
+}
@@ -0,0 +1,83 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestL1TxManagerResubmits(t *testing.T) {
+	defer func(interval time.Duration) { l1TxPollInterval = interval }(l1TxPollInterval)
+	l1TxPollInterval = time.Millisecond
+
+	key, _ := crypto.GenerateKey()
+	l1 := newFakeSystemConfig(t, crypto.PubkeyToAddress(key.PublicKey))
+	// The first transaction is never mined
+	l1.drop = 1
+	cfg := &Config{
+		privateKey:          key,
+		l1ChainID:           big.NewInt(1),
+		l1TxResubmitTimeout: 20 * time.Millisecond,
+	}
+	m, err := newL1TxManager(cfg, l1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		data, err := l1.abi.Pack("setGasConfig", big.NewInt(188), big.NewInt(684_000))
+		if err != nil {
+			return nil, err
+		}
+		to := common.HexToAddress("0x01")
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   cfg.l1ChainID,
+			Nonce:     opts.Nonce.Uint64(),
+			GasTipCap: opts.GasTipCap,
+			GasFeeCap: opts.GasFeeCap,
+			Gas:       50_000,
+			To:        &to,
+			Data:      data,
+		})
+		return opts.Signer(opts.From, tx)
+	}
+
+	receipt, err := m.send(context.Background(), newTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l1.sent) != 2 || receipt.TxHash != l1.sent[1].Hash() {
+		t.Fatalf("expected the resubmission to be mined, got %d transactions", len(l1.sent))
+	}
+	first, second := l1.sent[0], l1.sent[1]
+	if first.Nonce() != second.Nonce() {
+		t.Fatalf("expected the same nonce, got %d and %d", first.Nonce(), second.Nonce())
+	}
+	// The fee cap covers a doubling of the base fee
+	if first.GasFeeCap().Uint64() != 21_000_000_000 || first.GasTipCap().Uint64() != 1_000_000_000 {
+		t.Fatalf("unexpected fees %d %d", first.GasTipCap(), first.GasFeeCap())
+	}
+	// Both fees are raised by 12.5%
+	if second.GasTipCap().Uint64() != 1_125_000_000 || second.GasFeeCap().Uint64() != 23_625_000_000 {
+		t.Fatalf("unexpected fees of the resubmission %d %d", second.GasTipCap(), second.GasFeeCap())
+	}
+
+	// The fees are capped
+	l1.drop = 0
+	cfg.l1TxMaxFee = big.NewInt(5_000_000_000)
+	m, err = newL1TxManager(cfg, l1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.send(context.Background(), newTx); err != nil {
+		t.Fatal(err)
+	}
+	if last := l1.sent[len(l1.sent)-1]; last.GasFeeCap().Uint64() != 5_000_000_000 {
+		t.Fatalf("expected the fee cap to be capped, got %d", last.GasFeeCap())
+	}
+}
@@ -0,0 +1,271 @@
+package oracle
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/leader"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	leaderGauge         = metrics.NewRegisteredGauge("leader", ometrics.DefaultRegistry)
+	leaderChangeCounter = metrics.NewRegisteredCounter("leader/change", ometrics.DefaultRegistry)
+	leaderErrorCounter  = metrics.NewRegisteredCounter("leader/error", ometrics.DefaultRegistry)
+	standbyDivergence   = metrics.NewRegisteredGaugeFloat64("standby/divergence", ometrics.DefaultRegistry)
+)
+
+// leaderElection elects the replica that sends the transactions when
+// several replicas run for high availability. Every replica runs its epochs,
+// so that the standby replicas are ready to take over, but only the holder of
+// the lease sends. The lease is renewed at a third of its ttl, and a replica
+// stops sending at two thirds of the ttl after its last renewal, before
+// another replica can take the lease. The leader saves its checkpoint with
+// the lease, and a new leader only sends once it has adopted the checkpoint
+// of the previous leader. A nil leaderElection is always the leader.
+type leaderElection struct {
+	lease    leader.Lease
+	holder   string
+	ttl      time.Duration
+	controls *controls
+	now      func() time.Time
+
+	mu     sync.Mutex
+	leader bool
+	// handoff is set from becoming the leader until the checkpoint of the
+	// previous leader is adopted
+	handoff bool
+	// until is when the leadership of the last renewal ends
+	until time.Time
+	// expected is the L2 gas price that the replica would have left on chain
+	// in its last epoch on standby, which is compared with the gas price of
+	// the leader at the start of the next epoch
+	expected *big.Int
+}
+
+func newLeaderElection(ctx context.Context, cfg *Config) (*leaderElection, error) {
+	if cfg.leaseURL == "" {
+		return nil, nil
+	}
+	// The chains of one process each have their own lease
+	name := cfg.leaseName
+	if cfg.chainName != "" {
+		name += "/" + cfg.chainName
+	}
+	lease, err := leader.New(ctx, cfg.leaseURL, name, cfg.leaseTLS)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open lease: %w", err)
+	}
+	holder := cfg.replicaID
+	if holder == "" {
+		if holder, err = defaultReplicaID(); err != nil {
+			return nil, err
+		}
+	}
+	log.Info("Electing the replica that sends transactions", "lease", name, "replica", holder, "ttl", cfg.leaseTTL)
+	return &leaderElection{
+		lease:    lease,
+		holder:   holder,
+		ttl:      cfg.leaseTTL,
+		controls: cfg.controls,
+		now:      time.Now,
+	}, nil
+}
+
+// openLeaderElection opens the lease of the config and renews it once, so
+// that the first epoch knows whether the replica is the leader
+func openLeaderElection(cfg *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.leaseTTL)
+	defer cancel()
+	election, err := newLeaderElection(ctx, cfg)
+	if err != nil || election == nil {
+		return err
+	}
+	if err := election.renew(ctx); err != nil {
+		log.Error("cannot renew lease", "message", err)
+	}
+	cfg.leader = election
+	return nil
+}
+
+// defaultReplicaID identifies the replica by its host name and a random
+// suffix, so that a restarted replica does not renew the lease of its
+// previous run
+func defaultReplicaID() (string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return host + "-" + hex.EncodeToString(suffix), nil
+}
+
+// isLeader returns true when the replica holds the lease and may send
+func (l *leaderElection) isLeader() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leader && !l.handoff && l.now().Before(l.until)
+}
+
+// renew acquires or renews the lease. A replica that becomes the leader
+// resynchronizes the pricer with the chain, since the gas price was sent by
+// the previous leader.
+func (l *leaderElection) renew(ctx context.Context) error {
+	start := l.now()
+	held, err := l.lease.Acquire(ctx, l.holder, l.ttl)
+	if err != nil {
+		leaderErrorCounter.Inc(1)
+		// The leadership runs out at the end of the last renewal
+		return err
+	}
+	l.mu.Lock()
+	if held {
+		l.until = start.Add(l.ttl * 2 / 3)
+	}
+	changed := held != l.leader
+	l.leader = held
+	if changed {
+		l.handoff = held
+	}
+	l.mu.Unlock()
+	if !changed {
+		return nil
+	}
+	leaderGauge.Update(boolGauge(held))
+	leaderChangeCounter.Inc(1)
+	if held {
+		log.Info("Replica is the leader, sending transactions", "replica", l.holder)
+		l.controls.requestResync()
+	} else {
+		log.Warn("Replica lost the lease, standing by", "replica", l.holder)
+	}
+	return nil
+}
+
+// pendingHandoff returns true when the replica holds the lease and did not
+// adopt the checkpoint of the previous leader yet
+func (l *leaderElection) pendingHandoff() bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leader && l.handoff
+}
+
+// completeHandoff lets the new leader send
+func (l *leaderElection) completeHandoff() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handoff = false
+}
+
+// saveState saves the checkpoint with the lease while the replica is the
+// leader, so that the next leader continues from it
+func (l *leaderElection) saveState(state *store.State) {
+	if l == nil || !l.isLeader() {
+		return
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		log.Error("cannot encode checkpoint", "message", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), l.ttl/3)
+	defer cancel()
+	saved, err := l.lease.SaveState(ctx, l.holder, data)
+	if err != nil {
+		leaderErrorCounter.Inc(1)
+		log.Error("cannot save checkpoint with the lease", "message", err)
+	} else if !saved {
+		log.Warn("Replica lost the lease, not saving the checkpoint", "replica", l.holder)
+	}
+}
+
+// loadState returns the checkpoint that was saved with the lease, it is nil
+// when no leader saved one
+func (l *leaderElection) loadState(ctx context.Context) (*store.State, error) {
+	data, err := l.lease.LoadState(ctx)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	state := new(store.State)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// observeStandby keeps the L2 gas price that the decision of a standby
+// replica would have left on chain
+func (l *leaderElection) observeStandby(d *Decision) {
+	if l == nil {
+		return
+	}
+	expected := d.CurrentPrice
+	if d.Send {
+		expected = d.GasPrice
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.expected = new(big.Int).Set(expected)
+}
+
+// validate compares the L2 gas price of the leader with the gas price that
+// the replica decided on standby in the previous epoch. Replicas with the
+// same configuration decide the same gas price from the same blocks, so a
+// divergence larger than the significance factor means that their
+// configurations drifted apart. It returns false when the replica was not on
+// standby.
+func (l *leaderElection) validate(onChain *big.Int, significanceFactor float64) (float64, bool) {
+	if l == nil {
+		return 0, false
+	}
+	l.mu.Lock()
+	expected := l.expected
+	l.expected = nil
+	l.mu.Unlock()
+	if expected == nil {
+		return 0, false
+	}
+	divergence, _ := relativeDifference(onChain, expected).Float64()
+	standbyDivergence.Update(divergence)
+	if divergence > significanceFactor {
+		log.Warn("Standby replica diverges from the leader", "on-chain", onChain,
+			"expected", expected, "divergence", divergence)
+	} else {
+		log.Debug("Standby replica follows the leader", "on-chain", onChain,
+			"expected", expected, "divergence", divergence)
+	}
+	return divergence, true
+}
+
+// release gives up the lease so that another replica takes over without
+// waiting for the lease to expire
+func (l *leaderElection) release(ctx context.Context) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.leader = false
+	l.mu.Unlock()
+	if err := l.lease.Release(ctx, l.holder); err != nil {
+		log.Warn("cannot release lease", "message", err)
+	}
+}
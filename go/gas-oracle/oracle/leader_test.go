@@ -0,0 +1,137 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// memoryLease is a lease that is held by the holder of the field
+type memoryLease struct {
+	holder string
+	state  []byte
+	err    error
+}
+
+func (m *memoryLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.holder == "" {
+		m.holder = holder
+	}
+	return m.holder == holder, nil
+}
+
+func (m *memoryLease) Release(ctx context.Context, holder string) error {
+	if m.holder == holder {
+		m.holder = ""
+	}
+	return nil
+}
+
+func (m *memoryLease) SaveState(ctx context.Context, holder string, state []byte) (bool, error) {
+	if m.holder != holder {
+		return false, nil
+	}
+	m.state = append([]byte(nil), state...)
+	return true, nil
+}
+
+func (m *memoryLease) LoadState(ctx context.Context) ([]byte, error) {
+	return m.state, nil
+}
+
+func (m *memoryLease) Close() error { return nil }
+
+func TestLeaderElection(t *testing.T) {
+	var nilElection *leaderElection
+	if !nilElection.isLeader() {
+		t.Fatal("expected a nil election to be the leader")
+	}
+
+	cfg := &Config{}
+	cfg.controls = newControls(cfg)
+	lease := &memoryLease{holder: "b"}
+	now := time.Unix(1_000_000, 0)
+	l := &leaderElection{
+		lease:    lease,
+		holder:   "a",
+		ttl:      15 * time.Second,
+		controls: cfg.controls,
+		now:      func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	// The lease of another replica keeps this one on standby
+	if err := l.renew(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if l.isLeader() {
+		t.Fatal("expected a standby replica")
+	}
+
+	// The replica takes over the released lease and resynchronizes
+	lease.Release(ctx, "b")
+	if err := l.renew(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if l.isLeader() || !l.pendingHandoff() {
+		t.Fatal("expected the new leader to wait for the handoff")
+	}
+	l.completeHandoff()
+	if !l.isLeader() || !cfg.controls.takeResync() {
+		t.Fatal("expected the new leader to resynchronize")
+	}
+
+	// The leadership runs out when the lease cannot be renewed, before the
+	// lease expires for the other replicas
+	lease.err = errors.New("connection refused")
+	now = now.Add(5 * time.Second)
+	if err := l.renew(ctx); err == nil {
+		t.Fatal("expected the renewal to fail")
+	}
+	if !l.isLeader() {
+		t.Fatal("expected the leadership to last until the end of the last renewal")
+	}
+	now = now.Add(5 * time.Second)
+	if l.isLeader() {
+		t.Fatal("expected the leadership to run out")
+	}
+
+	lease.err = nil
+	l.release(ctx)
+	if l.isLeader() || lease.holder != "" {
+		t.Fatal("expected the lease to be released")
+	}
+}
+
+func TestLeaderElectionValidate(t *testing.T) {
+	var nilElection *leaderElection
+	nilElection.observeStandby(&Decision{CurrentPrice: big.NewInt(1)})
+	if _, ok := nilElection.validate(big.NewInt(1), 0.05); ok {
+		t.Fatal("expected no validation without an election")
+	}
+
+	l := &leaderElection{}
+	if _, ok := l.validate(big.NewInt(100), 0.05); ok {
+		t.Fatal("expected no validation before a standby epoch")
+	}
+
+	// The replica would have sent the gas price that the leader sent
+	l.observeStandby(&Decision{CurrentPrice: big.NewInt(100), GasPrice: big.NewInt(120), Send: true})
+	if divergence, ok := l.validate(big.NewInt(120), 0.05); !ok || divergence != 0 {
+		t.Fatalf("expected no divergence, got %f %t", divergence, ok)
+	}
+	if _, ok := l.validate(big.NewInt(120), 0.05); ok {
+		t.Fatal("expected a single validation per epoch")
+	}
+
+	// The replica would have kept the gas price that the leader changed
+	l.observeStandby(&Decision{CurrentPrice: big.NewInt(120), GasPrice: big.NewInt(121)})
+	if divergence, ok := l.validate(big.NewInt(160), 0.05); !ok || divergence != 0.25 {
+		t.Fatalf("expected a divergence of 0.25, got %f %t", divergence, ok)
+	}
+}
@@ -0,0 +1,180 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// CheckGasPrice returns the bounds of the configuration that a manually set
+// gas price violates, given the current gas price
+func CheckGasPrice(cfg *Config, current, price *big.Int) []string {
+	var violations []string
+	if cfg.floorPrice != nil && price.Cmp(cfg.floorPrice) < 0 {
+		violations = append(violations, fmt.Sprintf("%s is below the floor price of %s", price, cfg.floorPrice))
+	}
+	if cfg.maxGasPrice != nil && price.Cmp(cfg.maxGasPrice) > 0 {
+		violations = append(violations, fmt.Sprintf("%s is above the max gas price of %s", price, cfg.maxGasPrice))
+	}
+	if change := percentChange(current, price); cfg.maxPercentChangePerEpoch > 0 && change > cfg.maxPercentChangePerEpoch {
+		violations = append(violations, fmt.Sprintf("a change of %.2f%% from %s exceeds the max change per epoch of %.2f%%",
+			change*100, current, cfg.maxPercentChangePerEpoch*100))
+	}
+	return violations
+}
+
+// CheckL1BaseFee returns the bounds of the configuration that a manually set
+// L1 base fee violates, given the current L1 base fee of the gas price oracle
+// and the base fee of the L1 tip when it is known
+func CheckL1BaseFee(cfg *Config, current, tip, baseFee *big.Int) []string {
+	var violations []string
+	if baseFee.Sign() == 0 {
+		violations = append(violations, "an L1 base fee of 0 makes the L1 data free")
+	}
+	if change := percentChange(current, baseFee); cfg.maxPercentChangePerEpoch > 0 && change > cfg.maxPercentChangePerEpoch {
+		violations = append(violations, fmt.Sprintf("a change of %.2f%% from %s exceeds the max change per epoch of %.2f%%",
+			change*100, current, cfg.maxPercentChangePerEpoch*100))
+	}
+	if tip != nil && isDifferenceSignificant(tip, baseFee, cfg.l1BaseFeeSignificanceFactor) {
+		violations = append(violations, fmt.Sprintf("%s differs from the base fee of %s of the L1 tip by more than %.2f%%",
+			baseFee, tip, cfg.l1BaseFeeSignificanceFactor*100))
+	}
+	return violations
+}
+
+// percentChange returns the relative change from current to value, a change
+// from zero is an infinite change
+func percentChange(current, value *big.Int) float64 {
+	if current.Sign() == 0 {
+		if value.Sign() == 0 {
+			return 0
+		}
+		return 1e18
+	}
+	diff := new(big.Float).SetInt(new(big.Int).Sub(value, current))
+	change, _ := new(big.Float).Quo(diff, new(big.Float).SetInt(current)).Float64()
+	if change < 0 {
+		return -change
+	}
+	return change
+}
+
+// SetGasPrice sends a transaction that sets the L2 gas price outside of the
+// update loop, such as when an operator intervenes manually. The bounds are
+// not checked, see CheckGasPrice.
+func SetGasPrice(ctx context.Context, cfg *Config, backend L2Backend, price *big.Int) (*types.Transaction, error) {
+	contract, err := bindings.NewGasPriceOracle(cfg.gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	return sendManualUpdate(ctx, cfg, backend, &AuditRecord{Kind: AuditKindL2GasPrice, Value: price}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.SetGasPrice(opts, price)
+	})
+}
+
+// SetL1BaseFee sends a transaction that sets the L1 base fee outside of the
+// update loop. The bounds are not checked, see CheckL1BaseFee.
+func SetL1BaseFee(ctx context.Context, cfg *Config, backend L2Backend, baseFee *big.Int) (*types.Transaction, error) {
+	contract, err := bindings.NewGasPriceOracle(cfg.gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	return sendManualUpdate(ctx, cfg, backend, &AuditRecord{Kind: AuditKindL1BaseFee, Value: baseFee}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.SetL1BaseFee(opts, baseFee)
+	})
+}
+
+// TransferOwnership sends a transaction that transfers the ownership of the
+// gas price oracle to the new owner, which must then sign the updates
+func TransferOwnership(ctx context.Context, cfg *Config, backend L2Backend, newOwner common.Address) (*types.Transaction, error) {
+	contract, err := bindings.NewGasPriceOracle(cfg.gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	return sendManualUpdate(ctx, cfg, backend, &AuditRecord{Kind: AuditKindOwnership, NewOwner: &newOwner}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.TransferOwnership(opts, newOwner)
+	})
+}
+
+// AcceptOwnership sends a transaction that accepts the ownership of a gas
+// price oracle with a two-step ownership, the signer must be its pending
+// owner
+func AcceptOwnership(ctx context.Context, cfg *Config, backend L2Backend) (*types.Transaction, error) {
+	signer, ok := cfg.signer()
+	if !ok {
+		return nil, errNoPrivateKey
+	}
+	contract := bind.NewBoundContract(cfg.gasPriceOracleAddress, ownable2StepABI, backend, backend, nil)
+	return sendManualUpdate(ctx, cfg, backend, &AuditRecord{Kind: AuditKindOwnership, NewOwner: &signer}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.Transact(opts, "acceptOwnership")
+	})
+}
+
+// sendManualUpdate signs a transaction to the gas price oracle with the
+// signer of the configuration and sends it the same way that the update loop
+// does: the transaction is recorded in the audit log as the entry when one is
+// configured and the receipt is awaited when wait-for-receipt is set.
+func sendManualUpdate(ctx context.Context, cfg *Config, backend L2Backend, entry *AuditRecord,
+	build func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	chainID, err := resolveChainID(backend, cfg.l2ChainID, "L2")
+	if err != nil {
+		return nil, err
+	}
+	cfg.l2ChainID = chainID
+	opts, err := newTransactOpts(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = ctx
+
+	if cfg.gasPrice != nil {
+		opts.GasPrice = cfg.gasPrice
+	} else {
+		gasPrice, err := backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts.GasPrice = gasPrice
+	}
+
+	audit := cfg.auditLog
+	if audit == nil && cfg.auditLogPath != "" {
+		audit, err = openAuditLog(cfg.auditLogPath, cfg.privateKey, cfg)
+		if err != nil {
+			return nil, err
+		}
+		defer audit.close()
+	}
+
+	tx, err := build(opts)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("sending manual update", "kind", entry.Kind, "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
+		"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
+	if err := backend.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	log.Info("Manual update transaction sent", "kind", entry.Kind, "hash", tx.Hash().Hex())
+	audit.write(entry, tx)
+
+	if cfg.waitForReceipt {
+		receipt, err := waitForReceipt(ctx, backend, tx)
+		if err != nil {
+			return tx, err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return tx, fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+		}
+		log.Info("Manual update transaction confirmed", "hash", tx.Hash().Hex(),
+			"gas-used", receipt.GasUsed, "blocknumber", receipt.BlockNumber)
+	}
+	return tx, nil
+}
@@ -0,0 +1,192 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCheckGasPrice(t *testing.T) {
+	cfg := &Config{
+		floorPrice:               big.NewInt(10),
+		maxGasPrice:              big.NewInt(1000),
+		maxPercentChangePerEpoch: 0.5,
+	}
+	cases := []struct {
+		current, price int64
+		violations     int
+	}{
+		{100, 120, 0},
+		{100, 150, 0},
+		{100, 151, 1},
+		{100, 5, 2},
+		{800, 1100, 1},
+		{0, 100, 1},
+		{0, 0, 1},
+	}
+	for _, c := range cases {
+		violations := CheckGasPrice(cfg, big.NewInt(c.current), big.NewInt(c.price))
+		if len(violations) != c.violations {
+			t.Fatalf("%d -> %d: expected %d violations, got %v", c.current, c.price, c.violations, violations)
+		}
+	}
+}
+
+func TestCheckL1BaseFee(t *testing.T) {
+	cfg := &Config{
+		maxPercentChangePerEpoch:    0.5,
+		l1BaseFeeSignificanceFactor: 0.1,
+	}
+	cases := []struct {
+		current, baseFee int64
+		tip              *big.Int
+		violations       int
+	}{
+		{100, 120, nil, 0},
+		{100, 200, nil, 1},
+		{100, 0, nil, 2},
+		{100, 120, big.NewInt(125), 0},
+		{100, 120, big.NewInt(150), 1},
+	}
+	for _, c := range cases {
+		violations := CheckL1BaseFee(cfg, big.NewInt(c.current), c.tip, big.NewInt(c.baseFee))
+		if len(violations) != c.violations {
+			t.Fatalf("%d -> %d: expected %d violations, got %v", c.current, c.baseFee, c.violations, violations)
+		}
+	}
+}
+
+func TestSetGasPrice(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	cfg := &Config{
+		gasPriceOracleAddress: addr,
+		l2ChainID:             big.NewInt(1337),
+		privateKey:            key,
+		auditLogPath:          auditPath,
+	}
+	tx, err := SetGasPrice(context.Background(), cfg, sim, big.NewInt(1234))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	gasPrice, err := gpo.GasPrice(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.Uint64() != 1234 {
+		t.Fatalf("expected gas price 1234, got %s", gasPrice)
+	}
+
+	file, err := os.Open(auditPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	records, _, err := verifyAuditLog(file, &signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records != 1 {
+		t.Fatalf("expected 1 audit record, got %d", records)
+	}
+	if tx.Nonce() != 1 {
+		t.Fatalf("expected nonce 1, got %d", tx.Nonce())
+	}
+}
+
+func TestSetL1BaseFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		gasPriceOracleAddress: addr,
+		l2ChainID:             big.NewInt(1337),
+		privateKey:            key,
+	}
+	if _, err := SetL1BaseFee(context.Background(), cfg, sim, big.NewInt(30)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	baseFee, err := gpo.L1BaseFee(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseFee.Uint64() != 30 {
+		t.Fatalf("expected L1 base fee 30, got %s", baseFee)
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	cfg := &Config{
+		gasPriceOracleAddress: addr,
+		l2ChainID:             big.NewInt(1337),
+		privateKey:            key,
+		auditLogPath:          auditPath,
+	}
+	newOwner := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	if _, err := TransferOwnership(context.Background(), cfg, sim, newOwner); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	owner, err := gpo.Owner(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner != newOwner {
+		t.Fatalf("expected owner %s, got %s", newOwner.Hex(), owner.Hex())
+	}
+
+	data, err := os.ReadFile(auditPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record AuditRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Kind != AuditKindOwnership || record.NewOwner == nil || *record.NewOwner != newOwner {
+		t.Fatalf("unexpected audit record %+v", record)
+	}
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	if _, err := VerifyAuditLog(bytes.NewReader(data), &signer); err != nil {
+		t.Fatal(err)
+	}
+}
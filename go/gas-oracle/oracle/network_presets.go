@@ -0,0 +1,81 @@
+package oracle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// networkPresetsYAML are the built-in profiles of the known deployments,
+// in the format of the networks of the config file. They set the chain IDs,
+// the address of the OVM_GasPriceOracle and the pricing parameters, but not
+// the RPC URLs which depend on the nodes of the operator.
+const networkPresetsYAML = `
+mainnet:
+  l1-chain-id: 1
+  l2-chain-id: 10
+  gas-price-oracle-address: "0x420000000000000000000000000000000000000F"
+  floor-price: 0.001gwei
+  target-gas-per-second: 11000000
+  max-percent-change-per-epoch: 0.1
+  significant-factor: 0.05
+goerli:
+  l1-chain-id: 5
+  l2-chain-id: 420
+  gas-price-oracle-address: "0x420000000000000000000000000000000000000F"
+  floor-price: 0.001gwei
+  target-gas-per-second: 11000000
+  max-percent-change-per-epoch: 0.1
+  significant-factor: 0.05
+kovan:
+  l1-chain-id: 42
+  l2-chain-id: 69
+  gas-price-oracle-address: "0x420000000000000000000000000000000000000F"
+  floor-price: 0.001gwei
+  target-gas-per-second: 11000000
+  max-percent-change-per-epoch: 0.1
+  significant-factor: 0.05
+`
+
+// networkPresets returns the built-in profiles by name
+func networkPresets() (map[string]*networkConfig, error) {
+	var presets map[string]*networkConfig
+	if err := yaml.UnmarshalStrict([]byte(networkPresetsYAML), &presets); err != nil {
+		return nil, fmt.Errorf("cannot parse network presets: %w", err)
+	}
+	return presets, nil
+}
+
+// lookupNetwork returns the profile of the named network. A profile of the
+// config file takes precedence over the built-in preset of the same name.
+// The config file may be nil.
+func lookupNetwork(fileCfg *fileConfig, name string) (*networkConfig, error) {
+	var profiles map[string]*networkConfig
+	if fileCfg != nil {
+		profiles = fileCfg.Networks
+	}
+	if network := profiles[name]; network != nil {
+		return network, nil
+	}
+	presets, err := networkPresets()
+	if err != nil {
+		return nil, err
+	}
+	if network := presets[name]; network != nil {
+		return network, nil
+	}
+
+	names := make([]string, 0, len(profiles)+len(presets))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	for name := range presets {
+		if _, ok := profiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown network %q, the known networks are %s", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNetworkPresets(t *testing.T) {
+	presets, err := networkPresets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainIDs := map[string][2]uint64{
+		"mainnet": {1, 10},
+		"goerli":  {5, 420},
+		"kovan":   {42, 69},
+	}
+	for name, ids := range chainIDs {
+		if presets[name] == nil {
+			t.Fatalf("missing preset %s", name)
+		}
+		cfg := &Config{}
+		if err := presets[name].apply(cfg, func(string) bool { return false }); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.l1ChainID.Uint64() != ids[0] || cfg.l2ChainID.Uint64() != ids[1] {
+			t.Fatalf("%s: unexpected chain IDs %d and %d", name, cfg.l1ChainID, cfg.l2ChainID)
+		}
+		if cfg.gasPriceOracleAddress != common.HexToAddress("0x420000000000000000000000000000000000000F") {
+			t.Fatalf("%s: unexpected gas price oracle address %s", name, cfg.gasPriceOracleAddress)
+		}
+		if cfg.floorPrice == nil || cfg.floorPrice.Sign() <= 0 {
+			t.Fatalf("%s: no floor price", name)
+		}
+	}
+}
+
+func TestLookupNetwork(t *testing.T) {
+	// Presets are used without a config file
+	network, err := lookupNetwork(nil, "goerli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{}
+	// Options set on the command line take precedence over the preset
+	isSet := func(name string) bool { return name == "l2-chain-id" }
+	if err := network.apply(cfg, isSet); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.l2ChainID != nil || cfg.l1ChainID.Uint64() != 5 {
+		t.Fatal("unexpected chain IDs")
+	}
+
+	// A profile of the config file takes precedence over the preset
+	url := "http://sequencer:8545"
+	fileCfg := &fileConfig{Networks: map[string]*networkConfig{
+		"goerli": {LayerTwoHttpUrl: &url},
+	}}
+	network, err = lookupNetwork(fileCfg, "goerli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.LayerTwoHttpUrl == nil || network.L2ChainID != nil {
+		t.Fatal("expected the profile of the config file")
+	}
+
+	if _, err := lookupNetwork(fileCfg, "ropsten"); err == nil {
+		t.Fatal("expected error for an unknown network")
+	}
+}
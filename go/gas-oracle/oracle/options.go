@@ -0,0 +1,330 @@
+package oracle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// L1Backend is the interface to the L1 node, which is used to read the L1
+// base fee
+type L1Backend interface {
+	bind.ContractTransactor
+}
+
+// L2Backend is the interface to the L2 node, which is used to read blocks
+// and to send the updates. An *ethclient.Client implements it, as does the
+// simulated backend of the go-ethereum bindings. Backends that do not
+// implement ChainID need the chain ID to be configured.
+type L2Backend interface {
+	DeployContractBackend
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// options are the settings of New
+type options struct {
+	cfg         *Config
+	l1Backend   L1Backend
+	l2Backend   L2Backend
+	l2RPCClient *rpc.Client
+}
+
+// Option configures a GasPriceOracle created with New
+type Option func(*options) error
+
+// DefaultConfig returns a Config with the defaults of the command line
+// options. Neither the L2 gas price nor the L1 base fee is updated until
+// they are enabled, while the gas config of a Bedrock chain is set unless it
+// is disabled.
+func DefaultConfig() *Config {
+	rounding, _ := gasprices.NewRounding(gasprices.RoundingNone, nil, 0)
+	floorPrice, _ := gasprices.ParseWei(flags.FloorPriceFlag.Value)
+	return &Config{
+		ethereumHttpUrl:              flags.EthereumHttpUrlFlag.Value,
+		layerTwoHttpUrl:              flags.LayerTwoHttpUrlFlag.Value,
+		gasPriceOracleAddress:        common.HexToAddress(flags.GasPriceOracleAddressFlag.Value),
+		pricer:                       flags.PricerFlag.Value,
+		floorPrice:                   floorPrice,
+		targetGasPerSecond:           flags.TargetGasPerSecondFlag.Value,
+		maxPercentChangePerEpoch:     flags.MaxPercentChangePerEpochFlag.Value,
+		averageBlockGasLimitPerEpoch: flags.AverageBlockGasLimitPerEpochFlag.Value,
+		epochLength:                  flags.EpochLengthFlag.Value,
+		l1BaseFeeEpochLength:         flags.L1BaseFeeEpochLengthFlag.Value,
+		l2GasPriceSignificanceFactor: flags.L2GasPriceSignificanceFactorFlag.Value,
+		l1BaseFeeSignificanceFactor:  flags.L1BaseFeeSignificanceFactorFlag.Value,
+		gasPriceRounding:             rounding,
+		balanceCheckInterval:         flags.BalanceCheckIntervalFlag.Value,
+		implementationCheckInterval:  flags.ImplementationCheckIntervalFlag.Value,
+		ownerCheckInterval:           flags.OwnerCheckIntervalFlag.Value,
+		approvalTTL:                  flags.ApprovalTTLFlag.Value,
+		demandBlockTag:               flags.DemandBlockTagFlag.Value,
+		feeVaultInterval:             flags.FeeVaultIntervalFlag.Value,
+		gasTokenMaxRateAge:           flags.GasTokenMaxRateAgeFlag.Value,
+		leaseName:                    flags.HALeaseNameFlag.Value,
+		leaseTTL:                     flags.HALeaseTTLFlag.Value,
+		enableGasConfig:              true,
+	}
+}
+
+// New creates a GasPriceOracle from options, without command line options,
+// so that other programs can embed the oracle. It starts from the
+// DefaultConfig, or from the Config of WithConfig. Backends that are not
+// provided are dialed from the HTTP endpoints of the Config.
+func New(opts ...Option) (*GasPriceOracle, error) {
+	o := &options{cfg: DefaultConfig()}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	cfg := o.cfg
+
+	if o.l2Backend == nil {
+		if cfg.layerTwoHttpUrl == "" {
+			return nil, errors.New("no L2 backend or HTTP endpoint provided")
+		}
+		client, guard, err := dialChainIDGuarded(cfg.layerTwoHttpUrl, "L2", cfg.rpcTLS)
+		if err != nil {
+			return nil, err
+		}
+		o.l2RPCClient = client
+		o.l2Backend = ethclient.NewClient(client)
+		cfg.l2ChainIDGuard = guard
+	}
+	if o.l1Backend == nil {
+		if cfg.ethereumHttpUrl == "" {
+			return nil, errors.New("no L1 backend or HTTP endpoint provided")
+		}
+		client, guard, err := dialChainIDGuarded(cfg.ethereumHttpUrl, "L1", cfg.rpcTLS)
+		if err != nil {
+			return nil, err
+		}
+		o.l1Backend = ethclient.NewClient(client)
+		cfg.l1ChainIDGuard = guard
+	}
+	if err := cfg.validateOptions(); err != nil {
+		return nil, err
+	}
+	return newGasPriceOracle(cfg, o.l1Backend, o.l2Backend, o.l2RPCClient)
+}
+
+// WithConfig starts from a copy of the Config, such as one created from
+// the command line options with NewConfig. It replaces the settings of the
+// options before it, so it comes first.
+func WithConfig(cfg *Config) Option {
+	return func(o *options) error {
+		copied := *cfg
+		o.cfg = &copied
+		return nil
+	}
+}
+
+// WithL1Backend uses the backend for the L1 node instead of dialing the L1
+// HTTP endpoint
+func WithL1Backend(backend L1Backend) Option {
+	return func(o *options) error {
+		o.l1Backend = backend
+		return nil
+	}
+}
+
+// WithL2Backend uses the backend for the L2 node instead of dialing the L2
+// HTTP endpoint
+func WithL2Backend(backend L2Backend) Option {
+	return func(o *options) error {
+		o.l2Backend = backend
+		return nil
+	}
+}
+
+// WithL2RPCClient uses the RPC client for the L2 node, which also serves
+// the non standard namespaces such as the txpool
+func WithL2RPCClient(client *rpc.Client) Option {
+	return func(o *options) error {
+		o.l2RPCClient = client
+		o.l2Backend = ethclient.NewClient(client)
+		return nil
+	}
+}
+
+// WithChainIDs sets the chain IDs, which are checked against backends that
+// report their chain ID
+func WithChainIDs(l1ChainID, l2ChainID *big.Int) Option {
+	return func(o *options) error {
+		o.cfg.l1ChainID = l1ChainID
+		o.cfg.l2ChainID = l2ChainID
+		return nil
+	}
+}
+
+// WithGasPriceOracleAddress sets the address of the OVM_GasPriceOracle
+func WithGasPriceOracleAddress(address common.Address) Option {
+	return func(o *options) error {
+		o.cfg.gasPriceOracleAddress = address
+		return nil
+	}
+}
+
+// WithRPCTLSConfig dials the HTTP endpoints of the Config with the TLS
+// configuration, such as one with a client certificate for mutual TLS
+func WithRPCTLSConfig(config *tls.Config) Option {
+	return func(o *options) error {
+		o.cfg.rpcTLS = config
+		return nil
+	}
+}
+
+// WithPrivateKey signs the updates with the private key
+func WithPrivateKey(key *ecdsa.PrivateKey) Option {
+	return func(o *options) error {
+		o.cfg.privateKey = key
+		o.cfg.signerFn = nil
+		return nil
+	}
+}
+
+// WithSigner signs the updates with the signer, such as a remote signer,
+// instead of a private key. The audit log needs a private key.
+func WithSigner(address common.Address, signer bind.SignerFn) Option {
+	return func(o *options) error {
+		if signer == nil {
+			return errors.New("signer cannot be nil")
+		}
+		o.cfg.signerAddress = address
+		o.cfg.signerFn = signer
+		o.cfg.privateKey = nil
+		return nil
+	}
+}
+
+// WithDryRun computes the updates without sending them
+func WithDryRun(dryRun bool) Option {
+	return func(o *options) error {
+		o.cfg.dryRun = dryRun
+		return nil
+	}
+}
+
+// WithL2GasPrice enables updating the L2 gas price
+func WithL2GasPrice(enabled bool) Option {
+	return func(o *options) error {
+		o.cfg.enableL2GasPrice = enabled
+		return nil
+	}
+}
+
+// WithL1BaseFee enables updating the L1 base fee
+func WithL1BaseFee(enabled bool) Option {
+	return func(o *options) error {
+		o.cfg.enableL1BaseFee = enabled
+		return nil
+	}
+}
+
+// WithPricer selects a pricer registered with gasprices.RegisterPricer
+func WithPricer(name string) Option {
+	return func(o *options) error {
+		o.cfg.pricer = name
+		return nil
+	}
+}
+
+// WithFloorPrice sets the lowest L2 gas price in wei
+func WithFloorPrice(price *big.Int) Option {
+	return func(o *options) error {
+		o.cfg.floorPrice = price
+		return nil
+	}
+}
+
+// WithMaxGasPrice sets the highest L2 gas price in wei, nil for no cap
+func WithMaxGasPrice(price *big.Int) Option {
+	return func(o *options) error {
+		o.cfg.maxGasPrice = price
+		return nil
+	}
+}
+
+// WithSanityBounds sets the lowest and the highest L2 gas prices that are
+// sent, updates outside of them are refused. A nil bound is not checked.
+func WithSanityBounds(min, max *big.Int) Option {
+	return func(o *options) error {
+		o.cfg.sanityMinGasPrice = min
+		o.cfg.sanityMaxGasPrice = max
+		return nil
+	}
+}
+
+// WithTargetGasPerSecond sets the demand that the pricer targets
+func WithTargetGasPerSecond(target uint64) Option {
+	return func(o *options) error {
+		o.cfg.targetGasPerSecond = target
+		return nil
+	}
+}
+
+// WithMaxPercentChangePerEpoch sets the largest change of the gas price per
+// epoch as a fraction
+func WithMaxPercentChangePerEpoch(change float64) Option {
+	return func(o *options) error {
+		o.cfg.maxPercentChangePerEpoch = change
+		return nil
+	}
+}
+
+// WithAverageBlockGasLimitPerEpoch sets the average gas limit of the blocks
+// of an epoch
+func WithAverageBlockGasLimitPerEpoch(limit uint64) Option {
+	return func(o *options) error {
+		o.cfg.averageBlockGasLimitPerEpoch = limit
+		return nil
+	}
+}
+
+// WithEpochLength sets the length of the epochs of the L2 gas price
+func WithEpochLength(length time.Duration) Option {
+	return func(o *options) error {
+		o.cfg.epochLength = length
+		return nil
+	}
+}
+
+// WithL1BaseFeeEpochLength sets the polling time of the L1 base fee
+func WithL1BaseFeeEpochLength(length time.Duration) Option {
+	return func(o *options) error {
+		o.cfg.l1BaseFeeEpochLength = length
+		return nil
+	}
+}
+
+// WithDemandBlockTag measures the demand up to the block of the tag, latest,
+// safe or finalized, minus the confirmations
+func WithDemandBlockTag(tag string, confirmations uint64) Option {
+	return func(o *options) error {
+		o.cfg.demandBlockTag = tag
+		o.cfg.demandConfirmations = confirmations
+		return nil
+	}
+}
+
+// WithSignificanceFactor only updates the L2 gas price when it changes by
+// more than the factor
+func WithSignificanceFactor(factor float64) Option {
+	return func(o *options) error {
+		o.cfg.l2GasPriceSignificanceFactor = factor
+		return nil
+	}
+}
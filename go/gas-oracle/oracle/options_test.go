@@ -0,0 +1,75 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestNew(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if _, err := gpo.SetGasPrice(opts, big.NewInt(1000)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	// The simulated backend does not report its chain ID
+	_, err = New(WithL1Backend(sim), WithL2Backend(sim), WithPrivateKey(key), WithGasPriceOracleAddress(addr))
+	if !errors.Is(err, errNoChainID) {
+		t.Fatalf("expected %v, got %v", errNoChainID, err)
+	}
+
+	// Sign with an injected signer rather than the private key
+	signer := types.LatestSignerForChainID(big.NewInt(1337))
+	signed := 0
+	signerFn := func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		signed++
+		return types.SignTx(tx, signer, key)
+	}
+	oracle, err := New(
+		WithL1Backend(sim),
+		WithL2Backend(sim),
+		WithChainIDs(big.NewInt(1), big.NewInt(1337)),
+		WithGasPriceOracleAddress(addr),
+		WithSigner(opts.From, signerFn),
+		WithL2GasPrice(true),
+		WithFloorPrice(big.NewInt(1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oracle.config.epochLength == 0 || oracle.config.targetGasPerSecond == 0 {
+		t.Fatal("defaults not applied")
+	}
+
+	// Without demand the gas price is lowered
+	sim.Commit()
+	if err := oracle.Update(); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if signed != 1 {
+		t.Fatalf("expected the update to be signed by the signer, signed %d", signed)
+	}
+	price, err := gpo.GasPrice(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(1000)) >= 0 {
+		t.Fatalf("expected the gas price to be lowered, got %d", price)
+	}
+}
@@ -0,0 +1,149 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	ownerChangeCounter = metrics.NewRegisteredCounter("contract/owner_change", ometrics.DefaultRegistry)
+	ownerMismatchGauge = metrics.NewRegisteredGauge("contract/owner_mismatch", ometrics.DefaultRegistry)
+	ownerPendingGauge  = metrics.NewRegisteredGauge("contract/owner_pending", ometrics.DefaultRegistry)
+)
+
+// ownable2StepABI are the methods of a contract with a two-step ownership,
+// where the new owner accepts the ownership that the owner transferred
+var ownable2StepABI = mustParseABI(`[
+	{"inputs":[],"name":"pendingOwner","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"acceptOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"}
+]`)
+
+// newPendingOwnerCaller returns a function that reads the pending owner of a
+// contract with a two-step ownership
+func newPendingOwnerCaller(address common.Address, backend bind.ContractCaller) func(*bind.CallOpts) (common.Address, error) {
+	contract := bind.NewBoundContract(address, ownable2StepABI, backend, nil, nil)
+	return func(opts *bind.CallOpts) (common.Address, error) {
+		var out []interface{}
+		if err := contract.Call(opts, &out, "pendingOwner"); err != nil {
+			return common.Address{}, err
+		}
+		return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+	}
+}
+
+// ownerMonitor checks the owner of the contract that is updated during
+// operation, since the ownership can be transferred away from the signer
+// after it was verified at startup. The updates of the signer would revert,
+// so they are paused until the ownership is restored and they are resumed.
+type ownerMonitor struct {
+	cfg *Config
+	// contract names the contract in the logs
+	contract string
+	owner    func(*bind.CallOpts) (common.Address, error)
+	// pendingOwner is nil when the contract does not have a two-step
+	// ownership
+	pendingOwner func(*bind.CallOpts) (common.Address, error)
+	// mismatch is true when the owner is not the signer anymore
+	mismatch bool
+	// pending is true when the signer is the pending owner and the updates
+	// were paused until it accepts the ownership
+	pending bool
+}
+
+// newOwnerMonitor returns nil without a signer, such as in a dry run, or when
+// the check is disabled
+func newOwnerMonitor(cfg *Config, contract string, owner, pendingOwner func(*bind.CallOpts) (common.Address, error)) *ownerMonitor {
+	if _, ok := cfg.signer(); !ok || cfg.ownerCheckInterval <= 0 {
+		return nil
+	}
+	log.Info("Tracking the owner", "contract", contract, "interval", cfg.ownerCheckInterval)
+	return &ownerMonitor{cfg: cfg, contract: contract, owner: owner, pendingOwner: pendingOwner}
+}
+
+// check reads the owner of the contract and pauses the updates when it is
+// not the signer. The mismatch is notified once, and the updates are paused
+// again on every check until the ownership is restored, so that resuming
+// them does not send updates that revert. While the signer is the pending
+// owner the updates are paused without a notification, and they are resumed
+// once the ownership is accepted.
+func (m *ownerMonitor) check(ctx context.Context) error {
+	opts := &bind.CallOpts{Context: ctx}
+	owner, err := m.owner(opts)
+	if err != nil {
+		return fmt.Errorf("cannot get owner of %s: %w", m.contract, err)
+	}
+	expected, _ := m.cfg.expectedOwner()
+	if owner == expected {
+		switch {
+		case m.pending:
+			m.pending = false
+			ownerPendingGauge.Update(0)
+			log.Info("Ownership was accepted, resuming updates", "contract", m.contract, "owner", owner.Hex())
+			m.cfg.controls.setPaused(false)
+		case m.mismatch:
+			m.mismatch = false
+			ownerMismatchGauge.Update(0)
+			log.Warn("Signing key is the owner again, the updates stay paused until they are resumed",
+				"contract", m.contract, "owner", owner.Hex())
+		}
+		return nil
+	}
+	if m.pendingOwner != nil {
+		pendingOwner, err := m.pendingOwner(opts)
+		if err != nil {
+			return fmt.Errorf("cannot get pending owner of %s: %w", m.contract, err)
+		}
+		if pendingOwner == expected {
+			if !m.pending {
+				m.awaitAcceptance(owner)
+			}
+			m.cfg.controls.setPaused(true)
+			return nil
+		}
+	}
+	// The transfer to the signer was cancelled or the ownership went to
+	// another account
+	if m.pending {
+		m.pending = false
+		ownerPendingGauge.Update(0)
+	}
+	m.cfg.controls.setPaused(true)
+	if m.mismatch {
+		return nil
+	}
+	m.mismatch = true
+	ownerChangeCounter.Inc(1)
+	ownerMismatchGauge.Update(1)
+	log.Error("Ownership was transferred away from the signing key, pausing updates",
+		"contract", m.contract, "expected", expected.Hex(), "owner", owner.Hex())
+	m.cfg.notifier.Notify(&notify.Event{
+		Type:    notify.EventOwnerMismatch,
+		Time:    time.Now(),
+		ChainID: m.cfg.l2ChainID,
+		Signer:  expected.Hex(),
+		Owner:   owner.Hex(),
+	})
+	return nil
+}
+
+// awaitAcceptance pauses the updates while the signer is the pending owner
+// of the contract
+func (m *ownerMonitor) awaitAcceptance(owner common.Address) {
+	m.pending = true
+	m.mismatch = false
+	ownerMismatchGauge.Update(0)
+	ownerPendingGauge.Update(1)
+	expected, _ := m.cfg.expectedOwner()
+	log.Warn("Signing key is the pending owner, pausing updates until it accepts the ownership",
+		"contract", m.contract, "expected", expected.Hex(), "owner", owner.Hex())
+	m.cfg.controls.setPaused(true)
+}
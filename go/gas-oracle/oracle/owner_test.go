@@ -0,0 +1,132 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestOwnerMonitor(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	otherOpts, _ := bind.NewKeyedTransactorWithChainID(other, big.NewInt(1337))
+	balance, _ := new(big.Int).SetString("900000000000000000000000000000000", 10)
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{
+		opts.From:      {Balance: balance},
+		otherOpts.From: {Balance: balance},
+	}, 9_000_000)
+	_, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{ownerCheckInterval: time.Minute}
+	cfg.controls = newControls(cfg)
+	if m := newOwnerMonitor(cfg, "GasPriceOracle", gpo.Owner, nil); m != nil {
+		t.Fatal("expected no monitor without a signer")
+	}
+	cfg.privateKey = key
+	m := newOwnerMonitor(cfg, "GasPriceOracle", gpo.Owner, nil)
+	if m == nil {
+		t.Fatal("expected a monitor of the owner")
+	}
+
+	ctx := context.Background()
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.controls.isPaused() {
+		t.Fatal("expected the updates to run while the signer is the owner")
+	}
+
+	// The ownership is transferred away, the updates are paused
+	if _, err := gpo.TransferOwnership(opts, otherOpts.From); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.controls.isPaused() || !m.mismatch {
+		t.Fatal("expected the updates to be paused after the ownership changed")
+	}
+	// Resuming does not last while the signer is not the owner
+	cfg.controls.setPaused(false)
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.controls.isPaused() {
+		t.Fatal("expected the updates to be paused again")
+	}
+
+	// The ownership is restored, the updates stay paused until resumed
+	if _, err := gpo.TransferOwnership(otherOpts, opts.From); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.controls.isPaused() || m.mismatch {
+		t.Fatal("expected the updates to stay paused once the ownership is restored")
+	}
+	cfg.controls.setPaused(false)
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.controls.isPaused() {
+		t.Fatal("expected the updates to run after they were resumed")
+	}
+}
+
+func TestOwnerMonitorPending(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	previous := common.HexToAddress("0x01")
+	owner, pendingOwner := previous, signer
+	cfg := &Config{ownerCheckInterval: time.Minute, privateKey: key}
+	cfg.controls = newControls(cfg)
+	m := newOwnerMonitor(cfg, "GasPriceOracle",
+		func(*bind.CallOpts) (common.Address, error) { return owner, nil },
+		func(*bind.CallOpts) (common.Address, error) { return pendingOwner, nil })
+
+	// The signer is the pending owner, the updates wait for the acceptance
+	// without an alert
+	ctx := context.Background()
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.controls.isPaused() || !m.pending || m.mismatch {
+		t.Fatal("expected the updates to wait for the acceptance")
+	}
+
+	// The ownership is accepted, the updates are resumed
+	owner, pendingOwner = signer, common.Address{}
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.controls.isPaused() || m.pending {
+		t.Fatal("expected the updates to resume once the ownership is accepted")
+	}
+
+	// A transfer to the signer that is cancelled is a mismatch
+	owner, pendingOwner = previous, signer
+	m.awaitAcceptance(previous)
+	pendingOwner = common.Address{}
+	if err := m.check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.controls.isPaused() || m.pending || !m.mismatch {
+		t.Fatal("expected a mismatch after the transfer was cancelled")
+	}
+}
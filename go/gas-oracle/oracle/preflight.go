@@ -0,0 +1,161 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Exit codes of the preflight checks, so that a deploy pipeline can tell
+// which check failed. The first check that fails determines the exit code.
+const (
+	PreflightOK       = 0
+	PreflightConfig   = 2
+	PreflightL1RPC    = 3
+	PreflightL2RPC    = 4
+	PreflightChainID  = 5
+	PreflightContract = 6
+	PreflightSigner   = 7
+	PreflightOwner    = 8
+	PreflightBalance  = 9
+)
+
+// PreflightCheck is the result of a single preflight check
+type PreflightCheck struct {
+	Name string `json:"name"`
+	// Code is the exit code when the check failed, it is PreflightOK when
+	// the check passed or was skipped
+	Code    int    `json:"code"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+// PreflightReport is the result of the preflight checks
+type PreflightReport struct {
+	Checks []*PreflightCheck `json:"checks"`
+}
+
+// Code returns the exit code of the first check that failed
+func (r *PreflightReport) Code() int {
+	for _, check := range r.Checks {
+		if !check.Passed && !check.Skipped {
+			return check.Code
+		}
+	}
+	return PreflightOK
+}
+
+func (r *PreflightReport) pass(name, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, &PreflightCheck{Name: name, Passed: true, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (r *PreflightReport) fail(name string, code int, err error) {
+	r.Checks = append(r.Checks, &PreflightCheck{Name: name, Code: code, Detail: err.Error()})
+}
+
+func (r *PreflightReport) skip(name, failed string) {
+	r.Checks = append(r.Checks, &PreflightCheck{Name: name, Skipped: true, Detail: fmt.Sprintf("skipped because %s failed", failed)})
+}
+
+// Preflight checks that the oracle can run with the configuration before it
+// is deployed: both RPC endpoints respond, their chain IDs match the
+// configuration, the gas price oracle is deployed at the configured address,
+// the signer is its owner and the balance of the signer is at least
+// minBalance, or the low balance threshold when minBalance is nil. Without
+// either any balance passes.
+func Preflight(ctx context.Context, cfg *Config, l1Backend L1Backend, l2Backend L2Backend, minBalance *big.Int) *PreflightReport {
+	report := &PreflightReport{}
+	if minBalance == nil {
+		minBalance = cfg.lowBalanceThreshold
+	}
+
+	l1OK := false
+	if tip, err := l1Backend.HeaderByNumber(ctx, nil); err != nil {
+		report.fail("l1-rpc", PreflightL1RPC, err)
+	} else {
+		l1OK = true
+		report.pass("l1-rpc", "block %d", tip.Number)
+	}
+
+	l2OK := false
+	if tip, err := l2Backend.HeaderByNumber(ctx, nil); err != nil {
+		report.fail("l2-rpc", PreflightL2RPC, err)
+	} else {
+		l2OK = true
+		report.pass("l2-rpc", "block %d", tip.Number)
+	}
+
+	if l1OK && l2OK {
+		l1ChainID, l1Err := resolveChainID(l1Backend, cfg.l1ChainID, "L1")
+		l2ChainID, l2Err := resolveChainID(l2Backend, cfg.l2ChainID, "L2")
+		switch {
+		case l1Err != nil:
+			report.fail("chain-id", PreflightChainID, l1Err)
+		case l2Err != nil:
+			report.fail("chain-id", PreflightChainID, l2Err)
+		case l1ChainID.Cmp(l2ChainID) == 0:
+			report.fail("chain-id", PreflightChainID, fmt.Errorf("%w: L1 and L2 both have chain id %d",
+				errWrongChainID, l1ChainID))
+		default:
+			report.pass("chain-id", "L1 %d, L2 %d", l1ChainID, l2ChainID)
+		}
+	} else {
+		report.skip("chain-id", "an RPC check")
+	}
+
+	if !l2OK {
+		for _, name := range []string{"contract", "signer", "owner", "balance"} {
+			report.skip(name, "l2-rpc")
+		}
+		return report
+	}
+
+	resolved, err := resolveGasPriceOracle(ctx, l2Backend, cfg.gasPriceOracleAddress)
+	if err != nil {
+		report.fail("contract", PreflightContract, err)
+	} else if resolved.Implementation != (common.Address{}) {
+		report.pass("contract", "%s (%s), proxy of %s", resolved.Address.Hex(), resolved.Version,
+			resolved.Implementation.Hex())
+	} else {
+		report.pass("contract", "%s (%s)", resolved.Address.Hex(), resolved.Version)
+	}
+
+	signer, ok := cfg.signer()
+	if !ok {
+		report.fail("signer", PreflightSigner, errNoPrivateKey)
+		report.skip("owner", "signer")
+		report.skip("balance", "signer")
+		return report
+	}
+	report.pass("signer", "%s", signer.Hex())
+
+	switch {
+	case resolved == nil:
+		report.skip("owner", "contract")
+	case resolved.Version == contractVersionBedrock:
+		report.fail("owner", PreflightOwner, fmt.Errorf("%w: the Bedrock GasPriceOracle has no owner",
+			errUnexpectedContractVersion))
+	case resolved.Owner != signer:
+		report.fail("owner", PreflightOwner, fmt.Errorf("signer %s is not the owner %s",
+			signer.Hex(), resolved.Owner.Hex()))
+	default:
+		report.pass("owner", "%s", resolved.Owner.Hex())
+	}
+
+	balance, err := l2Backend.BalanceAt(ctx, signer, nil)
+	switch {
+	case err != nil:
+		report.fail("balance", PreflightBalance, err)
+	case minBalance == nil && balance.Sign() == 0:
+		report.fail("balance", PreflightBalance, errors.New("the signer has no balance"))
+	case minBalance != nil && balance.Cmp(minBalance) < 0:
+		report.fail("balance", PreflightBalance, fmt.Errorf("balance of %s wei is below %s wei", balance, minBalance))
+	default:
+		report.pass("balance", "%s wei", balance)
+	}
+	return report
+}
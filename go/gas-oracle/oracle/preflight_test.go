@@ -0,0 +1,100 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// chainIDBackend is a simulated backend that reports a chain ID
+type chainIDBackend struct {
+	*backends.SimulatedBackend
+	chainID *big.Int
+}
+
+func (b *chainIDBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return b.chainID, nil
+}
+
+func TestPreflight(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	l1 := &chainIDBackend{sim, big.NewInt(1)}
+
+	cfg := &Config{
+		gasPriceOracleAddress: addr,
+		l2ChainID:             big.NewInt(1337),
+		privateKey:            key,
+	}
+	report := Preflight(context.Background(), cfg, l1, sim, nil)
+	if code := report.Code(); code != PreflightOK {
+		t.Fatalf("expected the checks to pass, got %d: %+v", code, report.Checks)
+	}
+
+	cases := []struct {
+		name       string
+		cfg        *Config
+		l1         L1Backend
+		minBalance *big.Int
+		code       int
+	}{
+		{
+			name: "wrong L1 chain id",
+			cfg:  &Config{gasPriceOracleAddress: addr, l1ChainID: big.NewInt(5), l2ChainID: big.NewInt(1337), privateKey: key},
+			l1:   l1,
+			code: PreflightChainID,
+		},
+		{
+			name: "same chain ids",
+			cfg:  &Config{gasPriceOracleAddress: addr, l2ChainID: big.NewInt(1337), privateKey: key},
+			l1:   &chainIDBackend{sim, big.NewInt(1337)},
+			code: PreflightChainID,
+		},
+		{
+			name: "no contract",
+			cfg:  &Config{gasPriceOracleAddress: common.HexToAddress("0x1234"), l2ChainID: big.NewInt(1337), privateKey: key},
+			l1:   l1,
+			code: PreflightContract,
+		},
+		{
+			name: "no signer",
+			cfg:  &Config{gasPriceOracleAddress: addr, l2ChainID: big.NewInt(1337)},
+			l1:   l1,
+			code: PreflightSigner,
+		},
+		{
+			name:       "low balance",
+			cfg:        &Config{gasPriceOracleAddress: addr, l2ChainID: big.NewInt(1337), privateKey: key},
+			l1:         l1,
+			minBalance: new(big.Int).Lsh(big.NewInt(1), 64),
+			code:       PreflightBalance,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			report := Preflight(context.Background(), c.cfg, c.l1, sim, c.minBalance)
+			if code := report.Code(); code != c.code {
+				t.Fatalf("expected %d, got %d: %+v", c.code, code, report.Checks)
+			}
+		})
+	}
+
+	other, _ := crypto.GenerateKey()
+	cfg.privateKey = other
+	report = Preflight(context.Background(), cfg, l1, sim, nil)
+	if code := report.Code(); code != PreflightOwner {
+		t.Fatalf("expected %d, got %d: %+v", PreflightOwner, code, report.Checks)
+	}
+}
@@ -0,0 +1,189 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// priceQuoteTTL is how long a quote is served before the parameters are
+// read from the chain again, so that a burst of requests costs a single
+// round of calls to the L2 node
+const priceQuoteTTL = 2 * time.Second
+
+// GasPriceQuote is the gas price that the oracle recommends and the L1 fee
+// parameters of the gas price oracle
+type GasPriceQuote struct {
+	// GasPrice is the higher of the gas price of the contract and of the
+	// pricer, which is accepted by the sequencer and follows the epochs
+	// that are not sent yet
+	GasPrice        *big.Int  `json:"gasPrice"`
+	OnChainGasPrice *big.Int  `json:"onChainGasPrice"`
+	L1BaseFee       *big.Int  `json:"l1BaseFee"`
+	Overhead        *big.Int  `json:"overhead"`
+	Scalar          *big.Int  `json:"scalar"`
+	Decimals        *big.Int  `json:"decimals"`
+	Time            time.Time `json:"time"`
+}
+
+// priceAPI serves the quotes of the read-only price API
+type priceAPI struct {
+	gpo *GasPriceOracle
+	now func() time.Time
+
+	mu    sync.Mutex
+	quote *GasPriceQuote
+}
+
+func newPriceAPI(gpo *GasPriceOracle) *priceAPI {
+	return &priceAPI{gpo: gpo, now: time.Now}
+}
+
+// get returns the cached quote or reads a new one when it expired
+func (p *priceAPI) get(ctx context.Context) (*GasPriceQuote, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.quote != nil && p.now().Sub(p.quote.Time) < priceQuoteTTL {
+		return p.quote, nil
+	}
+	caller, err := bindings.NewGasPriceOracleCaller(p.gpo.config.gasPriceOracleAddress, p.gpo.l2Backend)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	quote := &GasPriceQuote{Time: p.now()}
+	if quote.OnChainGasPrice, err = caller.GasPrice(opts); err != nil {
+		return nil, fmt.Errorf("cannot get gas price: %w", err)
+	}
+	if quote.L1BaseFee, err = caller.L1BaseFee(opts); err != nil {
+		return nil, fmt.Errorf("cannot get l1 base fee: %w", err)
+	}
+	if quote.Overhead, err = caller.Overhead(opts); err != nil {
+		return nil, fmt.Errorf("cannot get overhead: %w", err)
+	}
+	if quote.Scalar, err = caller.Scalar(opts); err != nil {
+		return nil, fmt.Errorf("cannot get scalar: %w", err)
+	}
+	if quote.Decimals, err = caller.Decimals(opts); err != nil {
+		return nil, fmt.Errorf("cannot get decimals: %w", err)
+	}
+	quote.GasPrice = quote.OnChainGasPrice
+	if local := p.gpo.gasPriceUpdater.GetGasPrice(); local.Cmp(quote.GasPrice) > 0 {
+		quote.GasPrice = local
+	}
+	p.quote = quote
+	return quote, nil
+}
+
+// ethPriceAPI is the subset of the eth namespace that wallets use to price
+// a transaction
+type ethPriceAPI struct {
+	prices *priceAPI
+}
+
+// GasPrice returns the recommended L2 gas price
+func (a *ethPriceAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	quote, err := a.prices.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(quote.GasPrice), nil
+}
+
+// ChainId returns the L2 chain ID
+func (a *ethPriceAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(a.prices.gpo.config.l2ChainID)
+}
+
+// rollupPriceAPI serves rollup_gasPrices like l2geth
+type rollupPriceAPI struct {
+	prices *priceAPI
+}
+
+// RollupGasPrices are the L1 and L2 gas prices of rollup_gasPrices
+type RollupGasPrices struct {
+	L1GasPrice *hexutil.Big `json:"l1GasPrice"`
+	L2GasPrice *hexutil.Big `json:"l2GasPrice"`
+}
+
+// GasPrices returns the L1 base fee and the recommended L2 gas price
+func (a *rollupPriceAPI) GasPrices(ctx context.Context) (*RollupGasPrices, error) {
+	quote, err := a.prices.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &RollupGasPrices{
+		L1GasPrice: (*hexutil.Big)(quote.L1BaseFee),
+		L2GasPrice: (*hexutil.Big)(quote.GasPrice),
+	}, nil
+}
+
+// newPriceHandler serves the quote as JSON at /gas-price and the JSON-RPC
+// methods eth_gasPrice, eth_chainId and rollup_gasPrices at /. Browsers may
+// call it from any origin since it is read-only.
+func newPriceHandler(gpo *GasPriceOracle) (http.Handler, error) {
+	prices := newPriceAPI(gpo)
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &ethPriceAPI{prices: prices}); err != nil {
+		return nil, err
+	}
+	if err := server.RegisterName("rollup", &rollupPriceAPI{prices: prices}); err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", server)
+	mux.HandleFunc("/gas-price", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), adminRequestTimeout)
+		defer cancel()
+		quote, err := prices.get(ctx)
+		if err != nil {
+			writeAdminError(w, http.StatusBadGateway, err)
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, quote)
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+// startPriceServer serves the read-only price API at the configured address
+func (g *GasPriceOracle) startPriceServer() error {
+	handler, err := newPriceHandler(g)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", g.config.priceAPIAddr)
+	if err != nil {
+		return fmt.Errorf("cannot start price server: %w", err)
+	}
+	server := &http.Server{Handler: handler}
+	log.Info("Starting price server", "addr", listener.Addr().String())
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("Failure in running price server", "message", err)
+		}
+	}()
+	return nil
+}
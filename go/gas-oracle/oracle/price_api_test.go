@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestPriceAPI(t *testing.T) {
+	gpo, pricer, sim := newControlledOracle(t)
+	if err := gpo.updateL2GasPrice(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	// The contract is above the pricer
+	prices := newPriceAPI(gpo)
+	now := time.Now()
+	prices.now = func() time.Time { return now }
+	quote, err := prices.get(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.GasPrice.Uint64() != 100 || quote.OnChainGasPrice.Uint64() != 100 || quote.Decimals == nil {
+		t.Fatalf("unexpected quote %+v", quote)
+	}
+
+	// The pricer is above the contract, which is served once the quote
+	// expired
+	if err := pricer.SetGasPrice(big.NewInt(150)); err != nil {
+		t.Fatal(err)
+	}
+	if quote, _ := prices.get(context.Background()); quote.GasPrice.Uint64() != 100 {
+		t.Fatalf("expected the cached quote, got %+v", quote)
+	}
+	now = now.Add(priceQuoteTTL)
+	if quote, _ := prices.get(context.Background()); quote.GasPrice.Uint64() != 150 || quote.OnChainGasPrice.Uint64() != 100 {
+		t.Fatalf("unexpected quote %+v", quote)
+	}
+
+	handler, err := newPriceHandler(gpo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := rpc.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	var gasPrice hexutil.Big
+	if err := client.CallContext(context.Background(), &gasPrice, "eth_gasPrice"); err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.ToInt().Uint64() != 150 {
+		t.Fatalf("unexpected gas price %s", &gasPrice)
+	}
+	var chainID hexutil.Big
+	if err := client.CallContext(context.Background(), &chainID, "eth_chainId"); err != nil {
+		t.Fatal(err)
+	}
+	if chainID.ToInt().Uint64() != 1337 {
+		t.Fatalf("unexpected chain id %s", &chainID)
+	}
+	var rollup RollupGasPrices
+	if err := client.CallContext(context.Background(), &rollup, "rollup_gasPrices"); err != nil {
+		t.Fatal(err)
+	}
+	if rollup.L2GasPrice.ToInt().Uint64() != 150 || rollup.L1GasPrice == nil {
+		t.Fatalf("unexpected gas prices %+v", rollup)
+	}
+
+	resp, err := http.Get(server.URL + "/gas-price")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatal("expected the price API to allow any origin")
+	}
+	var served GasPriceQuote
+	if err := json.NewDecoder(resp.Body).Decode(&served); err != nil {
+		t.Fatal(err)
+	}
+	if served.GasPrice.Uint64() != 150 {
+		t.Fatalf("unexpected quote %+v", served)
+	}
+}
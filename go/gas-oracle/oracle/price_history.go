@@ -0,0 +1,163 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxPriceHistoryPoints bounds the points of a query of the price history,
+// a larger range needs a coarser resolution
+const maxPriceHistoryPoints = 10_000
+
+// defaultPriceHistoryRange is the range of a query without a start
+const defaultPriceHistoryRange = time.Hour
+
+var errNoHistory = errors.New("no history, see --state-db")
+
+// recordPrice adds the gas price of the gas pricer and on chain at the end
+// of an epoch to the price history, and prunes the points that are older
+// than the retention
+func (g *GasPriceOracle) recordPrice(local, onChain *big.Int) {
+	db := g.config.stateDB
+	if db == nil {
+		return
+	}
+	now := time.Now()
+	if err := db.AppendPrice(&store.PricePoint{Time: now, GasPrice: local, OnChainGasPrice: onChain}); err != nil {
+		log.Error("cannot record price", "message", err)
+		return
+	}
+	if g.config.priceHistoryRetention > 0 {
+		if err := db.PrunePrices(now.Add(-g.config.priceHistoryRetention)); err != nil {
+			log.Error("cannot prune price history", "message", err)
+		}
+	}
+}
+
+// PriceHistory returns the gas prices that were recorded from from to to.
+// With a resolution the range is divided into intervals of the resolution,
+// and the last point of each interval is returned, which is the gas price
+// at the end of the interval.
+func (g *GasPriceOracle) PriceHistory(from, to time.Time, resolution time.Duration) ([]*store.PricePoint, error) {
+	db := g.config.stateDB
+	if db == nil {
+		return nil, errNoHistory
+	}
+	if to.Before(from) {
+		return nil, errors.New("the end is before the start")
+	}
+	if resolution < 0 {
+		return nil, errors.New("negative resolution")
+	}
+	if resolution > 0 && to.Sub(from)/resolution >= maxPriceHistoryPoints {
+		return nil, fmt.Errorf("more than %d intervals, use a coarser resolution", maxPriceHistoryPoints)
+	}
+	points, err := db.Prices(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if resolution > 0 {
+		points = downsamplePrices(points, from, resolution)
+	}
+	if len(points) > maxPriceHistoryPoints {
+		return nil, fmt.Errorf("more than %d points, use a resolution", maxPriceHistoryPoints)
+	}
+	return points, nil
+}
+
+// downsamplePrices keeps the last point of each interval of the resolution
+// from the start
+func downsamplePrices(points []*store.PricePoint, from time.Time, resolution time.Duration) []*store.PricePoint {
+	var sampled []*store.PricePoint
+	bucket := int64(-1)
+	for _, p := range points {
+		b := int64(p.Time.Sub(from) / resolution)
+		if b == bucket {
+			sampled[len(sampled)-1] = p
+			continue
+		}
+		bucket = b
+		sampled = append(sampled, p)
+	}
+	return sampled
+}
+
+// priceHistory serves the recorded gas prices of a range,
+//
+//	GET /history?from=2022-01-01T00:00:00Z&to=1640998800&resolution=1m
+//
+// The times are RFC 3339 or unix seconds, from defaults to an hour before to
+// and to defaults to now
+func (h *adminHandler) priceHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	query := r.URL.Query()
+	from, to, err := parseHistoryRange(query)
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+	var resolution time.Duration
+	if v := query.Get("resolution"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			writeAdminError(w, http.StatusBadRequest, fmt.Errorf("resolution: %w", err))
+			return
+		}
+		resolution = d
+	}
+
+	points, err := h.gpo.PriceHistory(from, to, resolution)
+	if errors.Is(err, errNoHistory) {
+		writeAdminError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeAdminError(w, http.StatusBadRequest, err)
+		return
+	}
+	if points == nil {
+		points = []*store.PricePoint{}
+	}
+	writeAdminJSON(w, http.StatusOK, points)
+}
+
+// parseHistoryRange parses the from and to times of a query of a history,
+// from defaults to an hour before to and to defaults to now
+func parseHistoryRange(query url.Values) (time.Time, time.Time, error) {
+	to := time.Now()
+	if v := query.Get("to"); v != "" {
+		t, err := ParseHistoryTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to: %w", err)
+		}
+		to = t
+	}
+	from := to.Add(-defaultPriceHistoryRange)
+	if v := query.Get("from"); v != "" {
+		t, err := ParseHistoryTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from: %w", err)
+		}
+		from = t
+	}
+	return from, to, nil
+}
+
+// ParseHistoryTime parses an RFC 3339 time or unix seconds
+func ParseHistoryTime(v string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,91 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+)
+
+func TestPriceHistory(t *testing.T) {
+	gpo, _, _ := newControlledOracle(t)
+	handler, err := newAdminHandler(gpo, &adminAuth{token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	get := func(query string) (int, []*store.PricePoint) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/history"+query, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var points []*store.PricePoint
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&points); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return resp.StatusCode, points
+	}
+
+	if status, _ := get(""); status != http.StatusNotFound {
+		t.Fatalf("expected not found without a state database, got %d", status)
+	}
+
+	db, err := store.OpenStateDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpo.config.stateDB = db
+	defer gpo.config.closeStateDB()
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 12; i++ {
+		err := db.AppendPrice(&store.PricePoint{
+			Time:            start.Add(time.Duration(i) * 10 * time.Second),
+			GasPrice:        big.NewInt(int64(100 + i)),
+			OnChainGasPrice: big.NewInt(100),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	status, points := get("?from=2022-01-01T00:00:00Z&to=2022-01-01T00:01:00Z")
+	if status != http.StatusOK || len(points) != 7 {
+		t.Fatalf("unexpected history %d %v", status, points)
+	}
+	// The last point of each minute is the gas price at its end
+	status, points = get("?from=1640995200&to=1640995320&resolution=1m")
+	if status != http.StatusOK || len(points) != 2 || points[0].GasPrice.Uint64() != 105 || points[1].GasPrice.Uint64() != 111 {
+		t.Fatalf("unexpected history %d %v", status, points)
+	}
+	if status, points = get("?from=2021-01-01T00:00:00Z&to=2021-01-02T00:00:00Z"); status != http.StatusOK || len(points) != 0 {
+		t.Fatalf("unexpected history %d %v", status, points)
+	}
+
+	for _, query := range []string{"?from=yesterday", "?resolution=1", "?from=1640995320&to=1640995200", "?from=0&resolution=1s"} {
+		if status, _ := get(query); status != http.StatusBadRequest {
+			t.Fatalf("%s: expected bad request, got %d", query, status)
+		}
+	}
+
+	// The epochs are recorded and pruned after the retention
+	gpo.config.priceHistoryRetention = time.Hour
+	gpo.recordPrice(big.NewInt(10), big.NewInt(9))
+	points, err = gpo.PriceHistory(start, time.Now().Add(time.Second), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 1 || points[0].GasPrice.Uint64() != 10 || points[0].OnChainGasPrice.Uint64() != 9 {
+		t.Fatalf("unexpected history %v", points)
+	}
+}
@@ -0,0 +1,79 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/publish"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// newPublisher creates the dispatcher of the configured message bus and
+// database, it is nil when neither is configured
+func newPublisher(cfg *Config) (*publish.Dispatcher, error) {
+	var stores, publishers []publish.Publisher
+	if cfg.publishURL != "" {
+		publisher, err := publish.New(cfg.publishURL, cfg.publishTopic)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Publishing decisions and transactions", "topic", cfg.publishTopic)
+		publishers = append(publishers, publisher)
+	}
+	if cfg.postgresURL != "" {
+		db, err := store.OpenPostgres(context.Background(), cfg.postgresURL)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Recording epochs and transactions in postgres")
+		stores = append(stores, db)
+	}
+	if len(stores) == 0 && len(publishers) == 0 {
+		return nil, nil
+	}
+	return publish.NewDispatcher(stores, publishers...), nil
+}
+
+// publishDecision publishes the decision of an epoch
+func publishDecision(cfg *Config, d *Decision) {
+	cfg.publisher.Publish(&publish.Message{
+		Type:     publish.MessageDecision,
+		Time:     d.Time,
+		ChainID:  cfg.l2ChainID,
+		Decision: d,
+	})
+}
+
+// publishTransaction publishes an update transaction that was sent
+func publishTransaction(cfg *Config, kind AuditKind, tx *types.Transaction, value *big.Int) {
+	cfg.publisher.Publish(&publish.Message{
+		Type:    publish.MessageTransaction,
+		Time:    time.Now(),
+		ChainID: cfg.l2ChainID,
+		Transaction: &publish.Transaction{
+			Kind:     string(kind),
+			Hash:     tx.Hash().Hex(),
+			Nonce:    tx.Nonce(),
+			GasPrice: tx.GasPrice(),
+			Value:    value,
+		},
+	})
+}
+
+// publishReceipt publishes the receipt of an update transaction
+func publishReceipt(cfg *Config, receipt *types.Receipt) {
+	cfg.publisher.Publish(&publish.Message{
+		Type:    publish.MessageReceipt,
+		Time:    time.Now(),
+		ChainID: cfg.l2ChainID,
+		Receipt: &publish.Receipt{
+			Hash:        receipt.TxHash.Hex(),
+			BlockNumber: receipt.BlockNumber.Uint64(),
+			GasUsed:     receipt.GasUsed,
+			Status:      receipt.Status,
+		},
+	})
+}
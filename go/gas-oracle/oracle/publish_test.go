@@ -0,0 +1,36 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/publish"
+)
+
+type chanPublisher chan *publish.Message
+
+func (c chanPublisher) Publish(ctx context.Context, msg *publish.Message) error {
+	c <- msg
+	return nil
+}
+
+func TestPublishUpdates(t *testing.T) {
+	gpo, _, _ := newControlledOracle(t)
+	messages := make(chanPublisher, 2)
+	gpo.config.publisher = publish.NewDispatcher(nil, messages)
+	if err := gpo.updateL2GasPrice(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	gpo.config.publisher.Close()
+
+	decision, tx := <-messages, <-messages
+	if decision.Type != publish.MessageDecision || decision.ChainID.Uint64() != 1337 ||
+		decision.Decision.(*Decision).GasPrice.Uint64() != 100 {
+		t.Fatalf("unexpected decision %+v", decision)
+	}
+	if tx.Type != publish.MessageTransaction || tx.Transaction.Kind != string(AuditKindL2GasPrice) ||
+		tx.Transaction.Value.Uint64() != 100 || tx.Transaction.Hash == "" {
+		t.Fatalf("unexpected transaction %+v", tx.Transaction)
+	}
+}
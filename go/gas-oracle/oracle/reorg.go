@@ -0,0 +1,62 @@
+package oracle
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var epochReanchorCounter = metrics.NewRegisteredCounter("epoch/reanchor", ometrics.DefaultRegistry)
+
+// newReanchorFn returns the function that the updater calls when the chain
+// went below the start of the epoch. The epoch restarts at the latest block,
+// and with --reorg-reuse-demand it is completed with the demand of the last
+// epoch so that the update is not skipped.
+func newReanchorFn(cfg *Config) gasprices.ReanchorFn {
+	return func(epochStartBlockNumber, latestBlockNumber uint64) (float64, bool) {
+		epochReanchorCounter.Inc(1)
+		if !cfg.reorgReuseDemand {
+			log.Warn("Skipping the epoch after a reorg", "epoch-start", epochStartBlockNumber,
+				"latest", latestBlockNumber)
+			return 0, false
+		}
+		demand, ok := lastDemand(cfg)
+		if !ok {
+			log.Warn("Skipping the epoch after a reorg, no demand is recorded",
+				"epoch-start", epochStartBlockNumber, "latest", latestBlockNumber)
+			return 0, false
+		}
+		log.Warn("Completing the epoch after a reorg with the demand of the last epoch",
+			"epoch-start", epochStartBlockNumber, "latest", latestBlockNumber, "avg-gas-per-second", demand)
+		return demand, true
+	}
+}
+
+// lastDemand returns the demand of the last epoch that was decided. The
+// decisions of the state database are kept across restarts, as when the
+// saved epoch starts above a chain that was reset.
+func lastDemand(cfg *Config) (float64, bool) {
+	if cfg.stateDB != nil {
+		record, err := cfg.stateDB.LastDecision()
+		if err != nil {
+			log.Warn("cannot read the last decision", "message", err)
+		} else if record != nil {
+			d := new(Decision)
+			if err := json.Unmarshal(record, d); err == nil {
+				return d.AvgGasPerSecond, true
+			}
+		}
+	}
+	if cfg.controls == nil {
+		return 0, false
+	}
+	cfg.controls.mu.Lock()
+	defer cfg.controls.mu.Unlock()
+	if cfg.controls.lastDecision == nil {
+		return 0, false
+	}
+	return cfg.controls.lastDecision.AvgGasPerSecond, true
+}
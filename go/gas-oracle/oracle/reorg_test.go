@@ -0,0 +1,42 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+)
+
+func TestReanchorFn(t *testing.T) {
+	cfg := &Config{floorPrice: big.NewInt(1)}
+	cfg.controls = newControls(cfg)
+	cfg.controls.observeDecision(&Decision{AvgGasPerSecond: 1000})
+
+	// The epoch is skipped unless the demand is reused
+	reanchor := newReanchorFn(cfg)
+	if _, ok := reanchor(10, 4); ok {
+		t.Fatal("expected the epoch to be skipped")
+	}
+	cfg.reorgReuseDemand = true
+	if demand, ok := reanchor(10, 4); !ok || demand != 1000 {
+		t.Fatalf("expected the demand of the last decision, got %f", demand)
+	}
+
+	// The decisions that are kept across restarts take precedence
+	db, err := store.OpenStateDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.stateDB = db
+	defer cfg.closeStateDB()
+	recordDecision(cfg, &Decision{Time: time.Now(), AvgGasPerSecond: 2500, GasPrice: big.NewInt(1)})
+	if demand, ok := reanchor(10, 4); !ok || demand != 2500 {
+		t.Fatalf("expected the demand of the recorded decision, got %f", demand)
+	}
+
+	cfg = &Config{reorgReuseDemand: true}
+	if _, ok := newReanchorFn(cfg)(10, 4); ok {
+		t.Fatal("expected the epoch to be skipped without a decision")
+	}
+}
@@ -0,0 +1,84 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var txRevertCounter = metrics.NewRegisteredCounter("tx/simulation_reverted", ometrics.DefaultRegistry)
+
+// errUpdateReverts represents the error when the simulation of an update
+// reverts, so that it is not sent
+var errUpdateReverts = errors.New("update would revert")
+
+// gasPriceOracleABI is used to encode the calls that are simulated
+var gasPriceOracleABI = mustParseABI(bindings.GasPriceOracleABI)
+
+func mustParseABI(data string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// simulateUpdate runs the call of an update from the signer with eth_call
+// before it is signed and sent, so that an update that reverts, such as when
+// the contract is paused or its ownership changed, does not spend gas and
+// fails with the reason of the revert rather than with a failed gas
+// estimation
+func simulateUpdate(ctx context.Context, backend bind.ContractCaller, from, to common.Address, method string, args ...interface{}) error {
+	data, err := gasPriceOracleABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	_, err = backend.CallContract(ctx, ethereum.CallMsg{From: from, To: &to, Data: data}, nil)
+	if err == nil {
+		return nil
+	}
+	reason, reverted := revertReason(err)
+	if !reverted {
+		// The node could not run the call, the update is sent anyway and
+		// fails on its own if it has to
+		log.Warn("cannot simulate update", "method", method, "message", err)
+		return nil
+	}
+	txRevertCounter.Inc(1)
+	log.Error("Update would revert, not sending it", "method", method, "from", from.Hex(), "reason", reason)
+	return fmt.Errorf("%w: %s: %s", errUpdateReverts, method, reason)
+}
+
+// revertReason decodes the reason of a call that reverted. The nodes and the
+// simulated backend return the revert data with the error. It returns false
+// when the error is not a revert.
+func revertReason(err error) (string, bool) {
+	var dataErr interface{ ErrorData() interface{} }
+	if !errors.As(err, &dataErr) {
+		if strings.Contains(err.Error(), "execution reverted") {
+			return err.Error(), true
+		}
+		return "", false
+	}
+	encoded, _ := dataErr.ErrorData().(string)
+	data, decodeErr := hexutil.Decode(encoded)
+	if decodeErr != nil || len(data) == 0 {
+		return err.Error(), true
+	}
+	if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+		return reason, true
+	}
+	// A custom error is identified by its selector
+	return fmt.Sprintf("%s (revert data %s)", err, hexutil.Encode(data)), true
+}
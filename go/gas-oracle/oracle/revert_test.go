@@ -0,0 +1,60 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSimulateUpdate(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	// The ownership changed, the update is not sent and fails with the
+	// reason of the revert
+	opts.GasPrice = big.NewInt(875000000)
+	if _, err := gpo.TransferOwnership(opts, common.HexToAddress("0x01")); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	nonce, err := sim.PendingNonceAt(context.Background(), opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = updateL2GasPriceFn(big.NewInt(200))
+	if !errors.Is(err, errUpdateReverts) || !strings.Contains(err.Error(), "caller is not the owner") {
+		t.Fatalf("expected %v with the revert reason, got %v", errUpdateReverts, err)
+	}
+	if pending, _ := sim.PendingNonceAt(context.Background(), opts.From); pending != nonce {
+		t.Fatal("expected the reverting update not to be sent")
+	}
+}
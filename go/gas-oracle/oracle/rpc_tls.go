@@ -0,0 +1,89 @@
+package oracle
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// loadRPCTLSConfig returns the TLS configuration of the connections to the
+// RPC endpoints with a client certificate, for endpoints that require mutual
+// TLS. The CA bundle replaces the system roots to verify the endpoints. It
+// returns nil when no option is set.
+func loadRPCTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" && caFile == "" {
+		return nil, nil
+	}
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("the client certificate and its key must be set together")
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load the client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if caFile != "" {
+		data, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate in the CA bundle %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// dialRPC connects to an endpoint that is not guarded by a chainIDGuard. The
+// websocket endpoints use the TLS configuration as well, the IPC endpoints do
+// not need it.
+func dialRPC(rawURL string, tlsConfig *tls.Config) (*rpc.Client, error) {
+	u, err := url.Parse(rawURL)
+	if tlsConfig == nil || err != nil {
+		return rpc.Dial(rawURL)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig.Clone()
+		return rpc.DialHTTPWithClient(rawURL, &http.Client{Transport: transport})
+	case "ws", "wss":
+		// The buffer sizes and the timeout of the default dialer of the rpc
+		// package
+		dialer := websocket.Dialer{
+			ReadBufferSize:   1024,
+			WriteBufferSize:  1024,
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: 45 * time.Second,
+			TLSClientConfig:  tlsConfig.Clone(),
+		}
+		return rpc.DialWebsocketWithDialer(context.Background(), rawURL, "", dialer)
+	default:
+		return rpc.Dial(rawURL)
+	}
+}
+
+// Dial connects to an endpoint with the TLS client certificate of the
+// configuration, if any
+func (c *Config) Dial(rawURL string) (*ethclient.Client, error) {
+	client, err := dialRPC(rawURL, c.rpcTLS)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
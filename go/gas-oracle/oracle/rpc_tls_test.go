@@ -0,0 +1,113 @@
+package oracle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRPCTLSConfig(t *testing.T) {
+	// A client certificate that the endpoint trusts
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gas-oracle"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0xa"}`, req.ID)
+	}))
+	clients := x509.NewCertPool()
+	clients.AddCert(cert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clients}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	write := func(name, kind string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: data}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	certFile := write("client.pem", "CERTIFICATE", der)
+	keyFile := write("client.key", "EC PRIVATE KEY", keyDER)
+	caFile := write("ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	if config, err := loadRPCTLSConfig("", "", ""); config != nil || err != nil {
+		t.Fatalf("expected no TLS configuration, got %v", err)
+	}
+	if _, err := loadRPCTLSConfig(certFile, "", caFile); err == nil {
+		t.Fatal("expected an error for a certificate without its key")
+	}
+	if _, err := loadRPCTLSConfig("", "", keyFile); err == nil {
+		t.Fatal("expected an error for a CA bundle without certificates")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chainID := func(config *tls.Config) error {
+		client, guard, err := dialChainIDGuarded(server.URL, "L2", config)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		return guard.verify(ctx, big.NewInt(10))
+	}
+
+	// The endpoint refuses a client without a certificate
+	caOnly, err := loadRPCTLSConfig("", "", caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chainID(caOnly); err == nil {
+		t.Fatal("expected an error without a client certificate")
+	}
+	config, err := loadRPCTLSConfig(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chainID(config); err != nil {
+		t.Fatal(err)
+	}
+	client, err := (&Config{rpcTLS: config}).Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if id, err := client.ChainID(ctx); err != nil || id.Uint64() != 10 {
+		t.Fatalf("unexpected chain id %v, %v", id, err)
+	}
+}
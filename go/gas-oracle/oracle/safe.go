@@ -0,0 +1,222 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	safeProposalCounter        = metrics.NewRegisteredCounter("safe/proposed", ometrics.DefaultRegistry)
+	safeProposalPendingCounter = metrics.NewRegisteredCounter("safe/proposal_pending", ometrics.DefaultRegistry)
+	safeProposalFailureCounter = metrics.NewRegisteredCounter("safe/proposal_failure", ometrics.DefaultRegistry)
+)
+
+// safeABI are the methods of a Safe that the updates go through
+var safeABI = mustParseABI(`[
+	{"inputs":[],"name":"nonce","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"getThreshold","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"owner","type":"address"}],"name":"isOwner","outputs":[{"name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"_nonce","type":"uint256"}],"name":"getTransactionHash","outputs":[{"name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}],"name":"execTransaction","outputs":[{"name":"","type":"bool"}],"stateMutability":"payable","type":"function"}
+]`)
+
+// safeProposer sends the updates of a gas price oracle that is owned by a
+// Safe, so that an oracle owned by governance can still be automated. When
+// the threshold of the Safe is 1 the signer executes the update through the
+// Safe, otherwise the update is proposed to the Safe transaction service for
+// the other owners to confirm and execute.
+type safeProposer struct {
+	cfg      *Config
+	contract *bind.BoundContract
+	client   *http.Client
+	// proposedNonce is the nonce of the Safe of the last proposal, no other
+	// update is proposed until the Safe executed or rejected it
+	proposedNonce *big.Int
+}
+
+// newSafeProposer returns nil without a Safe. It checks that the signer is an
+// owner of the Safe and that the updates can be proposed when the threshold
+// requires confirmations.
+func newSafeProposer(cfg *Config, backend bind.ContractBackend) (*safeProposer, error) {
+	if cfg.safeAddress == (common.Address{}) {
+		return nil, nil
+	}
+	p := &safeProposer{
+		cfg:      cfg,
+		contract: bind.NewBoundContract(cfg.safeAddress, safeABI, backend, backend, nil),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	ctx := context.Background()
+	signer, _ := cfg.signer()
+	var owner bool
+	if err := p.call(ctx, &owner, "isOwner", signer); err != nil {
+		return nil, fmt.Errorf("cannot read the owners of the Safe %s: %w", cfg.safeAddress.Hex(), err)
+	}
+	if !owner {
+		return nil, fmt.Errorf("%w: %s is not an owner of the Safe %s", errInvalidSigningKey, signer.Hex(),
+			cfg.safeAddress.Hex())
+	}
+	threshold, err := p.threshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if threshold.Cmp(common.Big1) > 0 {
+		if cfg.safeServiceURL == "" {
+			return nil, fmt.Errorf("the Safe %s has a threshold of %d, the updates are proposed to the Safe transaction service, set --%s",
+				cfg.safeAddress.Hex(), threshold, flags.SafeServiceURLFlag.Name)
+		}
+		// The proposals are signed like messages rather than transactions
+		if cfg.privateKey == nil {
+			return nil, fmt.Errorf("the Safe %s has a threshold of %d, proposing updates needs a private key",
+				cfg.safeAddress.Hex(), threshold)
+		}
+	}
+	log.Info("Updating the gas price oracle through a Safe", "safe", cfg.safeAddress.Hex(),
+		"threshold", threshold, "signer", signer.Hex())
+	return p, nil
+}
+
+// submit returns the transaction that executes the call of the gas price
+// oracle through the Safe when the signer can execute it alone. Otherwise the
+// call is proposed to the Safe transaction service and no transaction is
+// returned.
+func (p *safeProposer) submit(ctx context.Context, opts *bind.TransactOpts, data []byte) (*types.Transaction, error) {
+	threshold, err := p.threshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if threshold.Cmp(common.Big1) > 0 {
+		return nil, p.propose(ctx, opts.From, data)
+	}
+	// The signature of the owner that sends the transaction is its address
+	// with a v of 1, which the Safe approves without a signed hash
+	signature := make([]byte, 65)
+	copy(signature[12:32], opts.From.Bytes())
+	signature[64] = 1
+	return p.contract.Transact(opts, "execTransaction", p.cfg.gasPriceOracleAddress, common.Big0, data, uint8(0),
+		common.Big0, common.Big0, common.Big0, common.Address{}, common.Address{}, signature)
+}
+
+// safeProposal is a transaction that is proposed to the Safe transaction
+// service, with the signature of the proposer
+type safeProposal struct {
+	To                      common.Address `json:"to"`
+	Value                   string         `json:"value"`
+	Data                    hexutil.Bytes  `json:"data"`
+	Operation               int            `json:"operation"`
+	SafeTxGas               string         `json:"safeTxGas"`
+	BaseGas                 string         `json:"baseGas"`
+	GasPrice                string         `json:"gasPrice"`
+	GasToken                common.Address `json:"gasToken"`
+	RefundReceiver          common.Address `json:"refundReceiver"`
+	Nonce                   string         `json:"nonce"`
+	ContractTransactionHash common.Hash    `json:"contractTransactionHash"`
+	Sender                  common.Address `json:"sender"`
+	Signature               hexutil.Bytes  `json:"signature"`
+	Origin                  string         `json:"origin"`
+}
+
+// propose signs the hash of the Safe transaction and posts it to the Safe
+// transaction service. Nothing is proposed while the last proposal waits for
+// the confirmations, since the Safe executes a single transaction per nonce.
+func (p *safeProposer) propose(ctx context.Context, from common.Address, data []byte) error {
+	var nonce *big.Int
+	if err := p.call(ctx, &nonce, "nonce"); err != nil {
+		return fmt.Errorf("cannot get the nonce of the Safe: %w", err)
+	}
+	if p.proposedNonce != nil && p.proposedNonce.Cmp(nonce) == 0 {
+		safeProposalPendingCounter.Inc(1)
+		log.Info("The last proposal is not executed yet, not proposing the update", "safe", p.cfg.safeAddress.Hex(),
+			"nonce", nonce)
+		return nil
+	}
+
+	var hash [32]byte
+	if err := p.call(ctx, &hash, "getTransactionHash", p.cfg.gasPriceOracleAddress, common.Big0, data, uint8(0),
+		common.Big0, common.Big0, common.Big0, common.Address{}, common.Address{}, nonce); err != nil {
+		return fmt.Errorf("cannot get the hash of the Safe transaction: %w", err)
+	}
+	signature, err := crypto.Sign(hash[:], p.cfg.privateKey)
+	if err != nil {
+		return err
+	}
+	// The Safe expects the v of a signed hash to be 27 or 28
+	signature[64] += 27
+
+	body, err := json.Marshal(&safeProposal{
+		To:                      p.cfg.gasPriceOracleAddress,
+		Value:                   "0",
+		Data:                    data,
+		SafeTxGas:               "0",
+		BaseGas:                 "0",
+		GasPrice:                "0",
+		Nonce:                   nonce.String(),
+		ContractTransactionHash: hash,
+		Sender:                  from,
+		Signature:               signature,
+		Origin:                  "gas-oracle",
+	})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/safes/%s/multisig-transactions/",
+		strings.TrimSuffix(p.cfg.safeServiceURL, "/"), p.cfg.safeAddress.Hex())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		safeProposalFailureCounter.Inc(1)
+		return fmt.Errorf("cannot propose to the Safe transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		safeProposalFailureCounter.Inc(1)
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Safe transaction service returned status %d: %s", resp.StatusCode,
+			strings.TrimSpace(string(message)))
+	}
+	p.proposedNonce = nonce
+	safeProposalCounter.Inc(1)
+	log.Info("Update proposed to the Safe", "safe", p.cfg.safeAddress.Hex(), "nonce", nonce,
+		"safe-tx-hash", common.Hash(hash).Hex())
+	return nil
+}
+
+func (p *safeProposer) threshold(ctx context.Context) (*big.Int, error) {
+	var threshold *big.Int
+	if err := p.call(ctx, &threshold, "getThreshold"); err != nil {
+		return nil, fmt.Errorf("cannot get the threshold of the Safe %s: %w", p.cfg.safeAddress.Hex(), err)
+	}
+	return threshold, nil
+}
+
+// call calls a view method of the Safe that returns a single value
+func (p *safeProposer) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var out []interface{}
+	if err := p.contract.Call(&bind.CallOpts{Context: ctx}, &out, method, args...); err != nil {
+		return err
+	}
+	abi.ConvertType(out[0], result)
+	return nil
+}
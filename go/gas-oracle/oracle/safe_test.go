@@ -0,0 +1,148 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeSafe answers the calls of a Safe so that the test does not deploy one
+type fakeSafe struct {
+	*backends.SimulatedBackend
+	safe      common.Address
+	owner     common.Address
+	threshold int64
+	nonce     int64
+}
+
+func (f *fakeSafe) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To == nil || *call.To != f.safe {
+		return f.SimulatedBackend.CallContract(ctx, call, blockNumber)
+	}
+	method, err := safeABI.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "nonce":
+		return method.Outputs.Pack(big.NewInt(f.nonce))
+	case "getThreshold":
+		return method.Outputs.Pack(big.NewInt(f.threshold))
+	case "isOwner":
+		return method.Outputs.Pack(args[0].(common.Address) == f.owner)
+	default:
+		var hash [32]byte
+		copy(hash[:], crypto.Keccak256(call.Data))
+		return method.Outputs.Pack(hash)
+	}
+}
+
+func TestSafeProposer(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	backend := &fakeSafe{
+		SimulatedBackend: sim,
+		safe:             common.HexToAddress("0x5afe"),
+		owner:            signer,
+		threshold:        2,
+	}
+
+	var proposals []safeProposal
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/safes/"+backend.safe.Hex()+"/multisig-transactions/" {
+			http.NotFound(w, r)
+			return
+		}
+		var proposal safeProposal
+		if err := json.NewDecoder(r.Body).Decode(&proposal); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		proposals = append(proposals, proposal)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: common.HexToAddress("0x420000000000000000000000000000000000000F"),
+		safeAddress:           backend.safe,
+	}
+	if _, err := newSafeProposer(cfg, backend); err == nil {
+		t.Fatal("expected an error without a transaction service")
+	}
+	cfg.safeServiceURL = server.URL + "/"
+	p, err := newSafeProposer(cfg, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := newTransactOpts(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := gasPriceOracleABI.Pack("setGasPrice", big.NewInt(100))
+
+	// The update is proposed with the signature of the hash of the Safe
+	// transaction, and not proposed again until the Safe executed it
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if tx, err := p.submit(ctx, opts, data); err != nil || tx != nil {
+			t.Fatalf("expected a proposal, got %v, %v", tx, err)
+		}
+	}
+	if len(proposals) != 1 {
+		t.Fatalf("expected 1 proposal, got %d", len(proposals))
+	}
+	proposal := proposals[0]
+	if proposal.To != cfg.gasPriceOracleAddress || proposal.Nonce != "0" || proposal.Sender != signer {
+		t.Fatalf("unexpected proposal %+v", proposal)
+	}
+	signature := append([]byte{}, proposal.Signature...)
+	signature[64] -= 27
+	pub, err := crypto.SigToPub(proposal.ContractTransactionHash.Bytes(), signature)
+	if err != nil || crypto.PubkeyToAddress(*pub) != signer {
+		t.Fatalf("expected the proposal to be signed by %s", signer.Hex())
+	}
+	backend.nonce++
+	if _, err := p.submit(ctx, opts, data); err != nil || len(proposals) != 2 || proposals[1].Nonce != "1" {
+		t.Fatalf("expected a proposal for the next nonce, got %v", err)
+	}
+
+	// The signer executes the update alone with a threshold of 1
+	backend.threshold = 1
+	opts.GasPrice, opts.GasLimit = big.NewInt(875000000), 100_000
+	tx, err := p.submit(ctx, opts, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx == nil || *tx.To() != backend.safe || len(proposals) != 2 {
+		t.Fatal("expected a transaction to the Safe")
+	}
+	args, err := safeABI.Methods["execTransaction"].Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0].(common.Address) != cfg.gasPriceOracleAddress || common.BytesToAddress(args[9].([]byte)[:32]) != signer {
+		t.Fatal("expected the call of the gas price oracle approved by the sender")
+	}
+
+	// The signer must be an owner of the Safe
+	backend.owner = common.HexToAddress("0x01")
+	if _, err := newSafeProposer(cfg, backend); err == nil {
+		t.Fatal("expected an error when the signer is not an owner")
+	}
+}
@@ -0,0 +1,88 @@
+package oracle
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+)
+
+// sampleConfigTemplate is the commented sample config file. The values are
+// the defaults of the command line options.
+var sampleConfigTemplate = template.Must(template.New("config").Parse(`# Sample gas oracle config file, select it with --config-file and the
+# profile with --network. Options set on the command line take precedence
+# over the profile, and options that are not in the profile keep the value of
+# the command line.
+
+# Time of day windows in UTC with their own target gas per second, used
+# instead of target-gas-per-second within the window. Windows where the end
+# is before the start wrap around midnight.
+# target-gas-schedule:
+#   - start: "13:00"
+#     end: "21:00"
+#     target-gas-per-second: 8000000
+
+networks:
+  # The name of the profile, selected with --network=my-network. The names
+  # mainnet, goerli and kovan replace the built-in presets.
+  my-network:
+    # L1 HTTP endpoint, used to read the L1 base fee
+    ethereum-http-url: {{printf "%q" .EthereumHttpUrl}}
+    # Sequencer HTTP endpoint, used to read blocks and send updates
+    layer-two-http-url: {{printf "%q" .LayerTwoHttpUrl}}
+    # Chain IDs, checked against the nodes at startup and read from the
+    # nodes when unset
+    # l1-chain-id: 1
+    # l2-chain-id: 10
+    # Address of the OVM_GasPriceOracle, an EIP-1967 proxy is resolved
+    gas-price-oracle-address: {{printf "%q" .GasPriceOracleAddress}}
+
+    # Pricing algorithm used to compute the L2 gas price: proportional moves
+    # the gas price toward the target gas per second, fixed keeps it
+    pricer: {{.Pricer}}
+    # Lowest L2 gas price, in wei unless a unit such as gwei is given
+    floor-price: {{.FloorPrice}}
+    # Highest L2 gas price, unset for no cap
+    # max-gas-price: 10gwei
+    # Gas per second that the proportional pricer targets. The gas price
+    # rises while blocks use more and falls while they use less.
+    target-gas-per-second: {{.TargetGasPerSecond}}
+    # Largest change of the gas price per epoch as a fraction, 0.1 is 10%
+    max-percent-change-per-epoch: {{.MaxPercentChangePerEpoch}}
+    # Average gas limit of the blocks of an epoch
+    average-block-gas-limit-per-epoch: {{.AverageBlockGasLimitPerEpoch}}
+    # Length of the epochs that the demand is measured over, such as 15s
+    epoch-length: {{.EpochLength}}
+    # Polling time for updating the L1 base fee
+    l1-base-fee-epoch-length: {{.L1BaseFeeEpochLength}}
+    # Only update the L2 gas price when it changes by more than this fraction
+    significant-factor: {{.L2GasPriceSignificanceFactor}}
+    # Only update the L1 base fee when it changes by more than this fraction
+    l1-base-fee-significant-factor: {{.L1BaseFeeSignificanceFactor}}
+
+    # The parameters that the oracle updates, the others are left to be set
+    # manually. The gas config is the overhead and the scalar of the
+    # SystemConfig of a Bedrock chain.
+    # enable-l2-gas-price: true
+    # enable-l1-base-fee: true
+    # system-config.enable-gas-config: false
+`))
+
+// WriteSampleConfig writes a commented sample config file with the defaults
+// of every option that the config file can set
+func WriteSampleConfig(w io.Writer) error {
+	return sampleConfigTemplate.Execute(w, map[string]interface{}{
+		"EthereumHttpUrl":              flags.EthereumHttpUrlFlag.Value,
+		"LayerTwoHttpUrl":              flags.LayerTwoHttpUrlFlag.Value,
+		"GasPriceOracleAddress":        flags.GasPriceOracleAddressFlag.Value,
+		"Pricer":                       flags.PricerFlag.Value,
+		"FloorPrice":                   flags.FloorPriceFlag.Value,
+		"TargetGasPerSecond":           flags.TargetGasPerSecondFlag.Value,
+		"MaxPercentChangePerEpoch":     flags.MaxPercentChangePerEpochFlag.Value,
+		"AverageBlockGasLimitPerEpoch": flags.AverageBlockGasLimitPerEpochFlag.Value,
+		"EpochLength":                  flags.EpochLengthFlag.Value,
+		"L1BaseFeeEpochLength":         flags.L1BaseFeeEpochLengthFlag.Value,
+		"L2GasPriceSignificanceFactor": flags.L2GasPriceSignificanceFactorFlag.Value,
+		"L1BaseFeeSignificanceFactor":  flags.L1BaseFeeSignificanceFactorFlag.Value,
+	})
+}
@@ -0,0 +1,35 @@
+package oracle
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteSampleConfig(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSampleConfig(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := writeConfigFile(t, buf.String())
+	fileCfg, err := loadConfigFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network, err := lookupNetwork(fileCfg, "my-network")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The sample sets the defaults
+	cfg := &Config{}
+	if err := network.apply(cfg, func(string) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.floorPrice.Uint64() != 1 || cfg.epochLength != 10*time.Second || cfg.l1BaseFeeEpochLength != 15*time.Second {
+		t.Fatal("unexpected defaults")
+	}
+	if cfg.targetGasPerSecond != 11_000_000 || cfg.maxPercentChangePerEpoch != 0.1 || cfg.pricer != "proportional" {
+		t.Fatal("unexpected pricing parameters")
+	}
+}
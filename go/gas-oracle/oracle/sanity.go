@@ -0,0 +1,93 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	txSanityRefusedCounter = metrics.NewRegisteredCounter("tx/sanity_refused", ometrics.DefaultRegistry)
+	txInvalidPriceCounter  = metrics.NewRegisteredCounter("tx/invalid_price", ometrics.DefaultRegistry)
+)
+
+// errSanityBound represents the error when an update that is about to be
+// sent is outside of the sanity bounds
+var errSanityBound = errors.New("gas price is outside of the sanity bounds")
+
+// validateSanityBounds makes sure that the sanity bounds do not refuse the
+// gas prices that the floor price and the max gas price allow
+func (c *Config) validateSanityBounds() error {
+	if c.sanityMinGasPrice != nil && c.sanityMaxGasPrice != nil && c.sanityMinGasPrice.Cmp(c.sanityMaxGasPrice) > 0 {
+		return fmt.Errorf("option %q: %d is above %q %d", flags.SanityMinGasPriceFlag.Name,
+			c.sanityMinGasPrice, flags.SanityMaxGasPriceFlag.Name, c.sanityMaxGasPrice)
+	}
+	if c.sanityMinGasPrice != nil && c.floorPrice != nil && c.sanityMinGasPrice.Cmp(c.floorPrice) > 0 {
+		return fmt.Errorf("option %q: %d is above the floor price %d", flags.SanityMinGasPriceFlag.Name,
+			c.sanityMinGasPrice, c.floorPrice)
+	}
+	if c.sanityMaxGasPrice != nil && c.maxGasPrice != nil && c.sanityMaxGasPrice.Cmp(c.maxGasPrice) < 0 {
+		return fmt.Errorf("option %q: %d is below the max gas price %d", flags.SanityMaxGasPriceFlag.Name,
+			c.sanityMaxGasPrice, c.maxGasPrice)
+	}
+	return nil
+}
+
+// checkSanityBounds refuses the gas price of a decision that is about to be
+// sent when it is outside of the sanity bounds. The pricer and the decision
+// already keep the gas price between the floor price and the max gas price,
+// so a refused gas price means that one of them is broken. The refusal is
+// alerted since the gas price is not updated until it is fixed.
+func checkSanityBounds(cfg *Config, d *Decision) error {
+	var violation string
+	switch {
+	case cfg.sanityMinGasPrice != nil && d.GasPrice.Cmp(cfg.sanityMinGasPrice) < 0:
+		violation = fmt.Sprintf("%s is below the sanity min gas price of %s", d.GasPrice, cfg.sanityMinGasPrice)
+	case cfg.sanityMaxGasPrice != nil && d.GasPrice.Cmp(cfg.sanityMaxGasPrice) > 0:
+		violation = fmt.Sprintf("%s is above the sanity max gas price of %s", d.GasPrice, cfg.sanityMaxGasPrice)
+	default:
+		return nil
+	}
+	txSanityRefusedCounter.Inc(1)
+	log.Error("Refusing to send the L2 gas price", "gas-price", d.GasPrice, "current", d.CurrentPrice,
+		"computed", d.ComputedPrice, "reason", violation)
+	event := d.Event(notify.EventUpdateRefused, cfg.l2ChainID)
+	event.Error = violation
+	cfg.notifier.Notify(event)
+	return fmt.Errorf("%w: %s", errSanityBound, violation)
+}
+
+// checkComputedPrice refuses a gas price or a demand that a pricer produced
+// from NaN, infinite, negative or overflowed values before a decision is
+// made from it. Nothing is recorded for the epoch and the refusal is
+// alerted like a violated sanity bound.
+func checkComputedPrice(cfg *Config, price *big.Int, avgGasPerSecond float64) error {
+	err := gasprices.CheckWei(price)
+	if err == nil {
+		if err = gasprices.CheckQuantity(avgGasPerSecond); err != nil {
+			err = fmt.Errorf("invalid demand: %w", err)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	txInvalidPriceCounter.Inc(1)
+	log.Error("Refusing to decide the L2 gas price", "gas-price", price, "avg-gas-per-second", avgGasPerSecond,
+		"message", err)
+	cfg.notifier.Notify(&notify.Event{
+		Type:     notify.EventUpdateRefused,
+		Time:     time.Now(),
+		ChainID:  cfg.l2ChainID,
+		GasPrice: price,
+		Error:    err.Error(),
+	})
+	return err
+}
@@ -0,0 +1,118 @@
+package oracle
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSanityBounds(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+		sanityMinGasPrice:     big.NewInt(10),
+		sanityMaxGasPrice:     big.NewInt(1000),
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	// Gas prices outside of the bounds are refused, even without a floor
+	// price or a max gas price that would clamp them
+	for _, price := range []int64{5, 5000} {
+		if err := updateL2GasPriceFn(big.NewInt(price)); !errors.Is(err, errSanityBound) {
+			t.Fatalf("expected %v for %d, got %v", errSanityBound, price, err)
+		}
+		sim.Commit()
+	}
+	onChain, err := gpo.GasPrice(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onChain.Int64() != 100 {
+		t.Fatalf("expected the refused gas prices not to be sent, got %d", onChain)
+	}
+}
+
+func TestComputedPriceRefused(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	events := make(chanNotifier, 1)
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+		notifier:              notify.NewDispatcher(events),
+	}
+	epoch := new(epochState)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := func() *notify.Event {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("event not delivered")
+		}
+		return nil
+	}
+
+	overflow := new(big.Int).Lsh(big.NewInt(1), 256)
+	if err := updateL2GasPriceFn(overflow); !errors.Is(err, gasprices.ErrInvalidWei) {
+		t.Fatalf("expected %v, got %v", gasprices.ErrInvalidWei, err)
+	}
+	if event := next(); event.Type != notify.EventUpdateRefused || event.GasPrice.Cmp(overflow) != 0 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	epoch.ObserveDemand(math.NaN())
+	if err := updateL2GasPriceFn(big.NewInt(100)); !errors.Is(err, gasprices.ErrInvalidFloat) {
+		t.Fatalf("expected %v, got %v", gasprices.ErrInvalidFloat, err)
+	}
+	if event := next(); event.Type != notify.EventUpdateRefused || event.Error == "" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	sim.Commit()
+	onChain, err := gpo.GasPrice(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onChain.Sign() != 0 {
+		t.Fatalf("expected the refused gas prices not to be sent, got %d", onChain)
+	}
+}
@@ -0,0 +1,198 @@
+package oracle
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errUnsignedDecision represents the error when a decision was recorded
+// without a signature, by an oracle without a private key
+var errUnsignedDecision = errors.New("the decision is not signed")
+
+// SignedDecision is the record of a decision signed by the signer of the
+// oracle, so that third parties can verify that the published gas prices
+// follow from the demand of the epochs and the declared configuration. Like
+// the audit log, each record includes the hash of the previous record, so
+// that records cannot be removed or reordered without breaking the chain.
+type SignedDecision struct {
+	*Decision
+	ChainID        *big.Int       `json:"chainId"`
+	GasPriceOracle common.Address `json:"gasPriceOracle"`
+	// ConfigDigest is the hash of the options that determine the decisions,
+	// the same as the digest of the audit log
+	ConfigDigest common.Hash    `json:"configDigest"`
+	Signer       common.Address `json:"signer"`
+	PrevHash     common.Hash    `json:"prevHash"`
+	Signature    hexutil.Bytes  `json:"signature,omitempty"`
+}
+
+// hash is the hash of the record without its signature, which is what the
+// signature signs and what the next record refers to
+func (r *SignedDecision) hash() (common.Hash, error) {
+	unsigned := *r
+	unsigned.Signature = nil
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// decisionSigner signs the decisions that are recorded in the state
+// database. It is only used from the update loop.
+type decisionSigner struct {
+	key            *ecdsa.PrivateKey
+	chainID        *big.Int
+	gasPriceOracle common.Address
+	configDigest   common.Hash
+	prev           common.Hash
+}
+
+// newDecisionSigner returns nil when the decisions are not recorded or there
+// is no private key to sign them with. The chain continues from the last
+// recorded decision.
+func newDecisionSigner(cfg *Config) (*decisionSigner, error) {
+	if cfg.stateDB == nil || cfg.privateKey == nil {
+		return nil, nil
+	}
+	digest, err := configDigest(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &decisionSigner{
+		key:            cfg.privateKey,
+		chainID:        cfg.l2ChainID,
+		gasPriceOracle: cfg.gasPriceOracleAddress,
+		configDigest:   digest,
+	}
+	last, err := cfg.stateDB.LastDecision()
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		record := new(SignedDecision)
+		if err := json.Unmarshal(last, record); err != nil {
+			return nil, fmt.Errorf("invalid decision record: %w", err)
+		}
+		// A decision that was recorded unsigned starts a new chain
+		if len(record.Signature) > 0 {
+			if s.prev, err = record.hash(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	log.Info("Signing the decision records", "signer", crypto.PubkeyToAddress(cfg.privateKey.PublicKey).Hex())
+	return s, nil
+}
+
+// sign returns the signed record of the decision and its hash, which the
+// next record refers to once this one is recorded
+func (s *decisionSigner) sign(d *Decision) (*SignedDecision, common.Hash, error) {
+	record := &SignedDecision{
+		Decision:       d,
+		ChainID:        s.chainID,
+		GasPriceOracle: s.gasPriceOracle,
+		ConfigDigest:   s.configDigest,
+		Signer:         crypto.PubkeyToAddress(s.key.PublicKey),
+		PrevHash:       s.prev,
+	}
+	hash, err := record.hash()
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	record.Signature, err = crypto.Sign(hash.Bytes(), s.key)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	return record, hash, nil
+}
+
+// ReadSignedDecisions returns the records of the decisions from from to to
+// that are recorded in the state database, oldest first
+func ReadSignedDecisions(db *store.StateDB, from, to time.Time) ([]*SignedDecision, error) {
+	if to.Before(from) {
+		return nil, errors.New("the end is before the start")
+	}
+	records, err := db.Decisions(from, to)
+	if err != nil {
+		return nil, err
+	}
+	decisions := make([]*SignedDecision, 0, len(records))
+	for _, record := range records {
+		d := new(SignedDecision)
+		if err := json.Unmarshal(record, d); err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions, nil
+}
+
+// WriteSignedDecisions writes the records of the decisions as JSON lines,
+// which VerifySignedDecisions reads
+func WriteSignedDecisions(w io.Writer, decisions []*SignedDecision) error {
+	enc := json.NewEncoder(w)
+	for _, d := range decisions {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifySignedDecisions checks the chain and the signatures of the records
+// of an export of the signed decisions and returns the number of records.
+// The first record may follow a record that is not exported. When signer is
+// set every record must be signed by it.
+func VerifySignedDecisions(r io.Reader, signer *common.Address) (int, error) {
+	var prev *common.Hash
+	n := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		n++
+		record := new(SignedDecision)
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return n, fmt.Errorf("record %d: %w", n, err)
+		}
+		if record.Decision == nil {
+			return n, fmt.Errorf("record %d: no decision", n)
+		}
+		if len(record.Signature) == 0 {
+			return n, fmt.Errorf("record %d: %w", n, errUnsignedDecision)
+		}
+		if prev != nil && record.PrevHash != *prev {
+			return n, fmt.Errorf("record %d: does not follow the previous record", n)
+		}
+		hash, err := record.hash()
+		if err != nil {
+			return n, fmt.Errorf("record %d: %w", n, err)
+		}
+		pub, err := crypto.SigToPub(hash.Bytes(), record.Signature)
+		if err != nil {
+			return n, fmt.Errorf("record %d: %w", n, err)
+		}
+		if crypto.PubkeyToAddress(*pub) != record.Signer {
+			return n, fmt.Errorf("record %d: invalid signature", n)
+		}
+		if signer != nil && record.Signer != *signer {
+			return n, fmt.Errorf("record %d: signed by %s", n, record.Signer.Hex())
+		}
+		prev = &hash
+	}
+	return n, scanner.Err()
+}
@@ -0,0 +1,98 @@
+package oracle
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSignedDecisions(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	db, err := store.OpenStateDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: common.HexToAddress("0x420000000000000000000000000000000000000F"),
+		floorPrice:            big.NewInt(1),
+		stateDB:               db,
+	}
+	defer cfg.closeStateDB()
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := func(i int) {
+		recordDecision(cfg, &Decision{
+			Time:            start.Add(time.Duration(i) * 10 * time.Second),
+			AvgGasPerSecond: 1234.5 * float64(i),
+			CurrentPrice:    big.NewInt(100),
+			ComputedPrice:   big.NewInt(105),
+			GasPrice:        big.NewInt(105),
+			Change:          0.05,
+			Send:            i%2 == 0,
+			Reason:          ReasonUpdated,
+		})
+	}
+	// The decisions recorded without a key are not signed
+	record(0)
+	if cfg.decisionSigner, err = newDecisionSigner(cfg); err != nil {
+		t.Fatal(err)
+	}
+	record(1)
+	record(2)
+	// A restarted oracle continues the chain
+	if cfg.decisionSigner, err = newDecisionSigner(cfg); err != nil {
+		t.Fatal(err)
+	}
+	record(3)
+
+	export := func(from time.Time) []byte {
+		decisions, err := ReadSignedDecisions(db, from, start.Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := WriteSignedDecisions(&buf, decisions); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	if _, err := VerifySignedDecisions(bytes.NewReader(export(start)), nil); !errors.Is(err, errUnsignedDecision) {
+		t.Fatalf("expected the unsigned decision to be rejected, got %v", err)
+	}
+	data := export(start.Add(time.Second))
+	if n, err := VerifySignedDecisions(bytes.NewReader(data), &signer); err != nil || n != 3 {
+		t.Fatalf("expected 3 verified decisions, got %d, %v", n, err)
+	}
+	// The export of a later range starts within the chain
+	if n, err := VerifySignedDecisions(bytes.NewReader(export(start.Add(15*time.Second))), &signer); err != nil || n != 2 {
+		t.Fatalf("expected 2 verified decisions, got %d, %v", n, err)
+	}
+	// The unsigned records are still read as decisions
+	decisions, err := ReadDecisionHistory(db, start, start.Add(time.Hour))
+	if err != nil || len(decisions) != 4 || decisions[3].AvgGasPerSecond != 3703.5 {
+		t.Fatalf("unexpected decisions %v, %v", decisions, err)
+	}
+
+	other := common.HexToAddress("0x01")
+	if _, err := VerifySignedDecisions(bytes.NewReader(data), &other); err == nil {
+		t.Fatal("expected an error for another signer")
+	}
+	tampered := bytes.Replace(data, []byte(`"gasPrice":105`), []byte(`"gasPrice":106`), 1)
+	if _, err := VerifySignedDecisions(bytes.NewReader(tampered), nil); err == nil {
+		t.Fatal("expected an error for a tampered decision")
+	}
+	lines := bytes.Split(data, []byte("\n"))
+	removed := bytes.Join([][]byte{lines[0], lines[2]}, []byte("\n"))
+	if _, err := VerifySignedDecisions(bytes.NewReader(removed), nil); err == nil {
+		t.Fatal("expected an error for a removed decision")
+	}
+}
@@ -0,0 +1,221 @@
+package oracle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"time"
+)
+
+// DemandSample is the demand of a single epoch of a recorded demand trace.
+// The decision records of the oracle and the epochs of a backtest both
+// decode into it.
+type DemandSample struct {
+	Time            time.Time `json:"time"`
+	AvgGasPerSecond float64   `json:"avgGasPerSecond"`
+	// GasPrice is the gas price that was decided in the epoch, the first
+	// one is the initial gas price of a simulation
+	GasPrice *big.Int `json:"gasPrice"`
+}
+
+// ReadDemandTrace reads a demand trace, which is either the JSON output of a
+// backtest, a JSON array of samples or a sample per line such as the
+// decision records of the JSON logs
+func ReadDemandTrace(r io.Reader) ([]DemandSample, error) {
+	var samples []DemandSample
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 && raw[0] == '[' {
+			var batch []DemandSample
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				return nil, err
+			}
+			samples = append(samples, batch...)
+			continue
+		}
+		var backtest struct {
+			Epochs []DemandSample `json:"epochs"`
+		}
+		if err := json.Unmarshal(raw, &backtest); err != nil {
+			return nil, err
+		}
+		if backtest.Epochs != nil {
+			samples = append(samples, backtest.Epochs...)
+			continue
+		}
+		var sample DemandSample
+		if err := json.Unmarshal(raw, &sample); err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("the demand trace is empty")
+	}
+	return samples, nil
+}
+
+// SimulationParams are the pricing parameters of a single simulation
+type SimulationParams struct {
+	TargetGasPerSecond       uint64  `json:"targetGasPerSecond"`
+	MaxPercentChangePerEpoch float64 `json:"maxPercentChangePerEpoch"`
+	SignificanceFactor       float64 `json:"significanceFactor"`
+}
+
+// SimulationResult is the outcome of replaying a demand trace with a set of
+// parameters
+type SimulationResult struct {
+	Params  SimulationParams `json:"params"`
+	Epochs  int              `json:"epochs"`
+	Updates int              `json:"updates"`
+	// UpdatesPerHour is the update frequency over the duration of the trace
+	UpdatesPerHour float64 `json:"updatesPerHour"`
+	// Volatility is the standard deviation of the relative change of the
+	// on-chain gas price per epoch and MaxChange is the largest change
+	Volatility    float64  `json:"volatility"`
+	MaxChange     float64  `json:"maxChange"`
+	FinalGasPrice *big.Int `json:"finalGasPrice"`
+	MinGasPrice   *big.Int `json:"minGasPrice"`
+	MaxGasPrice   *big.Int `json:"maxGasPrice"`
+}
+
+// Simulate replays the demand trace through the gas pricer with every
+// combination of the parameters, an empty range only holds the configured
+// value. The other pricing options come from the configuration. Each epoch
+// is decided like the update loop decides it and a sent update lands
+// immediately. A nil initialPrice starts at the gas price of the first
+// sample, or the floor price.
+func Simulate(cfg *Config, trace []DemandSample, initialPrice *big.Int, targets []uint64,
+	maxChanges, significanceFactors []float64) ([]*SimulationResult, error) {
+	if initialPrice == nil {
+		initialPrice = trace[0].GasPrice
+	}
+	if initialPrice == nil {
+		initialPrice = cfg.floorPrice
+	}
+	if initialPrice == nil {
+		return nil, errors.New("no initial gas price")
+	}
+	if len(targets) == 0 {
+		targets = []uint64{cfg.targetGasPerSecond}
+	}
+	if len(maxChanges) == 0 {
+		maxChanges = []float64{cfg.maxPercentChangePerEpoch}
+	}
+	if len(significanceFactors) == 0 {
+		significanceFactors = []float64{cfg.l2GasPriceSignificanceFactor}
+	}
+
+	var results []*SimulationResult
+	for _, target := range targets {
+		for _, maxChange := range maxChanges {
+			for _, factor := range significanceFactors {
+				params := SimulationParams{
+					TargetGasPerSecond:       target,
+					MaxPercentChangePerEpoch: maxChange,
+					SignificanceFactor:       factor,
+				}
+				result, err := simulate(cfg, trace, initialPrice, params)
+				if err != nil {
+					return nil, fmt.Errorf("%+v: %w", params, err)
+				}
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
+
+func simulate(cfg *Config, trace []DemandSample, initialPrice *big.Int, params SimulationParams) (*SimulationResult, error) {
+	simCfg := *cfg
+	simCfg.targetGasPerSecond = params.TargetGasPerSecond
+	simCfg.maxPercentChangePerEpoch = params.MaxPercentChangePerEpoch
+	simCfg.l2GasPriceSignificanceFactor = params.SignificanceFactor
+	// The swept significance factor replaces the directional and the
+	// adaptive ones, which would otherwise take precedence
+	simCfg.l2GasPriceIncreaseSignificanceFactor = nil
+	simCfg.l2GasPriceDecreaseSignificanceFactor = nil
+	simCfg.adaptiveSignificance = nil
+
+	start := trace[0].Time
+	if start.IsZero() {
+		start = time.Unix(0, 0)
+	}
+	now := start
+	pricer, err := newGasPricer(&simCfg, initialPrice, func() time.Time { return now })
+	if err != nil {
+		return nil, err
+	}
+	limiter := &rateLimiter{interval: simCfg.minUpdateInterval}
+
+	onChain := new(big.Int).Set(initialPrice)
+	result := &SimulationResult{
+		Params:      params,
+		MinGasPrice: new(big.Int).Set(onChain),
+		MaxGasPrice: new(big.Int).Set(onChain),
+	}
+	var sum, sumSquares float64
+	for i, sample := range trace {
+		// Samples without a time are an epoch length apart
+		if sample.Time.IsZero() {
+			now = start.Add(time.Duration(i+1) * simCfg.epochLength)
+		} else {
+			now = sample.Time
+		}
+		computed, err := pricer.CompleteEpoch(sample.AvgGasPerSecond)
+		if err != nil {
+			return nil, err
+		}
+		decision := decideL2GasPrice(&simCfg, limiter, onChain, computed, sample.AvgGasPerSecond, now)
+
+		change := 0.0
+		if decision.Send {
+			change = relativeChange(onChain, decision.GasPrice)
+			onChain = decision.GasPrice
+			limiter.record(now)
+			result.Updates++
+		}
+		sum += change
+		sumSquares += change * change
+		if math.Abs(change) > result.MaxChange {
+			result.MaxChange = math.Abs(change)
+		}
+		if onChain.Cmp(result.MinGasPrice) < 0 {
+			result.MinGasPrice = new(big.Int).Set(onChain)
+		}
+		if onChain.Cmp(result.MaxGasPrice) > 0 {
+			result.MaxGasPrice = new(big.Int).Set(onChain)
+		}
+	}
+
+	result.Epochs = len(trace)
+	mean := sum / float64(result.Epochs)
+	result.Volatility = math.Sqrt(math.Max(sumSquares/float64(result.Epochs)-mean*mean, 0))
+	if hours := now.Sub(start).Hours(); hours > 0 {
+		result.UpdatesPerHour = float64(result.Updates) / hours
+	}
+	result.FinalGasPrice = new(big.Int).Set(onChain)
+	return result, nil
+}
+
+// relativeChange returns the change from old to updated relative to old,
+// which is negative for a decrease
+func relativeChange(old, updated *big.Int) float64 {
+	if old.Sign() == 0 {
+		return 0
+	}
+	diff := new(big.Float).SetInt(new(big.Int).Sub(updated, old))
+	change, _ := diff.Quo(diff, new(big.Float).SetInt(old)).Float64()
+	return change
+}
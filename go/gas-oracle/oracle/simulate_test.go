@@ -0,0 +1,90 @@
+package oracle
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadDemandTrace(t *testing.T) {
+	backtest := `{"epochs": [{"time": "2022-01-01T00:00:00Z", "avgGasPerSecond": 100, "gasPrice": 1000},
+		{"time": "2022-01-01T00:00:10Z", "avgGasPerSecond": 200, "gasPrice": 1000}], "updates": 0}`
+	lines := `{"time": "2022-01-01T00:00:00Z", "avgGasPerSecond": 100}
+{"time": "2022-01-01T00:00:10Z", "avgGasPerSecond": 200}
+`
+	array := `[{"avgGasPerSecond": 100}, {"avgGasPerSecond": 200}]`
+	for _, trace := range []string{backtest, lines, array} {
+		samples, err := ReadDemandTrace(strings.NewReader(trace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(samples) != 2 || samples[0].AvgGasPerSecond != 100 || samples[1].AvgGasPerSecond != 200 {
+			t.Fatalf("unexpected samples %+v", samples)
+		}
+	}
+	samples, _ := ReadDemandTrace(strings.NewReader(backtest))
+	if samples[0].GasPrice.Uint64() != 1000 {
+		t.Fatalf("expected the gas price of the backtest, got %v", samples[0].GasPrice)
+	}
+
+	if _, err := ReadDemandTrace(strings.NewReader("")); err == nil {
+		t.Fatal("expected an error for an empty trace")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	cfg := &Config{
+		floorPrice:                   big.NewInt(1),
+		targetGasPerSecond:           11_000_000,
+		maxPercentChangePerEpoch:     0.1,
+		averageBlockGasLimitPerEpoch: 11_000_000,
+		epochLength:                  10 * time.Second,
+		l2GasPriceSignificanceFactor: 0.05,
+	}
+	// Demand at twice the target for an hour
+	var trace []DemandSample
+	for i := 0; i < 360; i++ {
+		trace = append(trace, DemandSample{AvgGasPerSecond: 22_000_000})
+	}
+
+	results, err := Simulate(cfg, trace, big.NewInt(1000), nil, []float64{0.05, 0.1}, []float64{0, 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Params.TargetGasPerSecond != 11_000_000 || r.Epochs != 360 {
+			t.Fatalf("unexpected result %+v", r)
+		}
+		if r.FinalGasPrice.Cmp(big.NewInt(1000)) <= 0 {
+			t.Fatalf("unexpected result %+v", r)
+		}
+		// Every epoch is sent without a significance factor, so each step
+		// is bounded by the max change up to rounding
+		if r.Params.SignificanceFactor == 0 && r.MaxChange > r.Params.MaxPercentChangePerEpoch+0.01 {
+			t.Fatalf("unexpected max change %+v", r)
+		}
+	}
+
+	// A larger significance factor sends fewer, larger updates
+	if results[1].Updates >= results[0].Updates || results[1].MaxChange <= results[0].MaxChange {
+		t.Fatalf("expected fewer updates with a larger significance factor, got %d and %d",
+			results[0].Updates, results[1].Updates)
+	}
+	if results[0].UpdatesPerHour <= 0 {
+		t.Fatalf("expected updates per hour, got %f", results[0].UpdatesPerHour)
+	}
+
+	// Without an initial price the first gas price of the trace is used
+	trace[0].GasPrice = big.NewInt(2000)
+	results, err = Simulate(cfg, trace[:1], nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].MinGasPrice.Uint64() != 2000 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+}
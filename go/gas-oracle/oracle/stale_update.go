@@ -0,0 +1,124 @@
+package oracle
+
+import (
+	"math/big"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	staleEpochsGauge          = metrics.NewRegisteredGauge("stale/epochs_since_update", ometrics.DefaultRegistry)
+	staleLastUpdateTimeGauge  = metrics.NewRegisteredGauge("stale/last_update_time", ometrics.DefaultRegistry)
+	staleLastUpdateBlockGauge = metrics.NewRegisteredGauge("stale/last_update_block", ometrics.DefaultRegistry)
+	staleAlarmGauge           = metrics.NewRegisteredGauge("stale/alarm", ometrics.DefaultRegistry)
+)
+
+// staleUpdateMonitor raises an alarm when no update has landed on chain for
+// a number of epochs while the demand has been changing. A gas price that
+// does not move while the demand is steady is expected, but one that does
+// not move while the demand changes means that updates are not landing.
+type staleUpdateMonitor struct {
+	maxEpochs    uint64
+	demandChange float64
+	notifier     *notify.Dispatcher
+	chainID      *big.Int
+
+	// lastPrice is the on-chain gas price of the previous epoch
+	lastPrice       *big.Int
+	lastUpdateTime  time.Time
+	lastUpdateBlock uint64
+	// epochs is the number of epochs since the last update and minDemand
+	// and maxDemand are the range of their demand
+	epochs    uint64
+	minDemand float64
+	maxDemand float64
+	stale     bool
+}
+
+func newStaleUpdateMonitor(cfg *Config) *staleUpdateMonitor {
+	if cfg.staleUpdateEpochs == 0 {
+		return nil
+	}
+	return &staleUpdateMonitor{
+		maxEpochs:    cfg.staleUpdateEpochs,
+		demandChange: cfg.staleUpdateDemandChange,
+		notifier:     cfg.notifier,
+		chainID:      cfg.l2ChainID,
+	}
+}
+
+// observe records the on-chain gas price at the end of an epoch along with
+// the demand of the epoch. An update has landed when the on-chain gas price
+// changed since the previous epoch, the block is the tip at which that was
+// observed.
+func (s *staleUpdateMonitor) observe(onChain *big.Int, block uint64, demand float64, now time.Time) {
+	if s == nil {
+		return
+	}
+	if s.lastPrice == nil || s.lastPrice.Cmp(onChain) != 0 {
+		s.landed(onChain, block, now)
+		return
+	}
+
+	s.epochs++
+	if s.epochs == 1 || demand < s.minDemand {
+		s.minDemand = demand
+	}
+	if s.epochs == 1 || demand > s.maxDemand {
+		s.maxDemand = demand
+	}
+	staleEpochsGauge.Update(int64(s.epochs))
+
+	if s.stale || s.epochs < s.maxEpochs || !s.demandChanged() {
+		return
+	}
+	s.stale = true
+	staleAlarmGauge.Update(1)
+	log.Warn("No gas price update has landed while the demand changed", "epochs", s.epochs,
+		"gas-price", onChain, "last-update", s.lastUpdateTime, "last-update-block", s.lastUpdateBlock,
+		"min-demand", s.minDemand, "max-demand", s.maxDemand)
+	s.notifier.Notify(&notify.Event{
+		Type:              notify.EventStaleUpdate,
+		Time:              now,
+		ChainID:           s.chainID,
+		GasPrice:          onChain,
+		EpochsSinceUpdate: s.epochs,
+		LastUpdateBlock:   s.lastUpdateBlock,
+	})
+}
+
+// landed resets the monitor after the on-chain gas price changed
+func (s *staleUpdateMonitor) landed(onChain *big.Int, block uint64, now time.Time) {
+	if s.stale {
+		log.Info("Gas price update landed", "epochs", s.epochs, "gas-price", onChain)
+		s.notifier.Notify(&notify.Event{
+			Type:              notify.EventStaleUpdateRecovered,
+			Time:              now,
+			ChainID:           s.chainID,
+			GasPrice:          onChain,
+			EpochsSinceUpdate: s.epochs,
+		})
+	}
+	s.lastPrice = new(big.Int).Set(onChain)
+	s.lastUpdateTime = now
+	s.lastUpdateBlock = block
+	s.epochs = 0
+	s.stale = false
+	staleEpochsGauge.Update(0)
+	staleAlarmGauge.Update(0)
+	staleLastUpdateTimeGauge.Update(now.Unix())
+	staleLastUpdateBlockGauge.Update(int64(block))
+}
+
+// demandChanged returns true when the range of the demand since the last
+// update exceeds the demand change relative to the highest demand
+func (s *staleUpdateMonitor) demandChanged() bool {
+	if s.maxDemand <= 0 {
+		return false
+	}
+	return (s.maxDemand-s.minDemand)/s.maxDemand > s.demandChange
+}
@@ -0,0 +1,85 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+)
+
+type recordingNotifier struct {
+	events chan *notify.Event
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, event *notify.Event) error {
+	r.events <- event
+	return nil
+}
+
+func TestStaleUpdateMonitor(t *testing.T) {
+	if newStaleUpdateMonitor(&Config{}) != nil {
+		t.Fatal("expected no monitor when disabled")
+	}
+
+	recorder := &recordingNotifier{events: make(chan *notify.Event, 8)}
+	dispatcher := notify.NewDispatcher(recorder)
+	defer dispatcher.Close()
+	s := newStaleUpdateMonitor(&Config{
+		staleUpdateEpochs:       3,
+		staleUpdateDemandChange: 0.1,
+		notifier:                dispatcher,
+	})
+	now := time.Unix(1000, 0)
+	price := big.NewInt(1000)
+
+	// Steady demand does not raise the alarm
+	s.observe(price, 10, 100, now)
+	for i := 0; i < 5; i++ {
+		s.observe(price, 11, 100, now)
+	}
+	if s.stale {
+		t.Fatal("expected no alarm for steady demand")
+	}
+
+	// A landed update resets the count
+	s.observe(big.NewInt(900), 20, 100, now)
+	if s.epochs != 0 || s.lastUpdateBlock != 20 {
+		t.Fatal("expected the update to be recorded")
+	}
+	s.observe(big.NewInt(900), 21, 100, now)
+	s.observe(big.NewInt(900), 22, 150, now)
+	if s.stale {
+		t.Fatal("expected no alarm before the number of epochs")
+	}
+	s.observe(big.NewInt(900), 23, 120, now)
+	if !s.stale {
+		t.Fatal("expected the alarm when the demand changed")
+	}
+
+	expect := func(eventType notify.EventType) *notify.Event {
+		select {
+		case event := <-recorder.events:
+			if event.Type != eventType {
+				t.Fatalf("expected %s, got %s", eventType, event.Type)
+			}
+			return event
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s", eventType)
+		}
+		return nil
+	}
+	event := expect(notify.EventStaleUpdate)
+	if event.EpochsSinceUpdate != 3 || event.LastUpdateBlock != 20 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	// The alarm is raised once and cleared when an update lands
+	s.observe(big.NewInt(900), 24, 200, now)
+	s.observe(big.NewInt(950), 25, 200, now)
+	expect(notify.EventStaleUpdateRecovered)
+	if s.stale {
+		t.Fatal("expected the alarm to clear")
+	}
+}
@@ -0,0 +1,140 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// restoredState is the part of a saved state that is still valid after a
+// restart
+type restoredState struct {
+	// epochStartBlockNumber and epochStart are set when the saved epoch is
+	// still running, so that it is continued
+	epochStartBlockNumber uint64
+	epochStart            time.Time
+	// gasPrice is set when the gas price on chain did not change since the
+	// state was saved
+	gasPrice *big.Int
+	// smoothingSamples are set when they are within the smoothing window
+	smoothingSamples []float64
+}
+
+// restoreState returns what still applies of the saved state, given the tip
+// and the gas price on chain. A state of another chain or contract is
+// ignored, and so is an epoch that would have ended already or that starts
+// after the tip.
+func restoreState(cfg *Config, saved *store.State, tip uint64, onChainGasPrice *big.Int, now time.Time) *restoredState {
+	if saved == nil {
+		return nil
+	}
+	if !sameDeployment(cfg, saved) {
+		log.Warn("Ignoring the saved state of another chain or contract", "chain-id", saved.ChainID,
+			"address", saved.Address)
+		return nil
+	}
+	restored := new(restoredState)
+	age := now.Sub(saved.EpochStartTime)
+	if age >= 0 && age < cfg.epochLength && saved.EpochStartBlockNumber <= tip {
+		restored.epochStartBlockNumber = saved.EpochStartBlockNumber
+		restored.epochStart = saved.EpochStartTime
+	}
+	// The gas price was set by someone else while the oracle was stopped,
+	// so the gas pricer continues from the chain
+	if saved.GasPrice != nil && saved.OnChainGasPrice != nil && saved.OnChainGasPrice.Cmp(onChainGasPrice) == 0 {
+		restored.gasPrice = saved.GasPrice
+	}
+	window := time.Duration(cfg.smoothingEpochs) * cfg.epochLength
+	if age >= 0 && age < window {
+		restored.smoothingSamples = saved.SmoothingSamples
+	}
+	return restored
+}
+
+// openStateDB opens the configured state database and loads its state
+func openStateDB(cfg *Config) (*store.State, error) {
+	if cfg.stateDBPath == "" {
+		return nil, nil
+	}
+	db, err := store.OpenStateDB(cfg.stateDBPath)
+	if err != nil {
+		return nil, err
+	}
+	state, err := db.Load()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	log.Info("Keeping the state across restarts", "path", cfg.stateDBPath)
+	cfg.stateDB = db
+	return state, nil
+}
+
+// saveState checkpoints the epoch that starts now. The receipt of the
+// update transaction of the epoch is usually available by then.
+func (g *GasPriceOracle) saveState(ctx context.Context, onChainGasPrice *big.Int) {
+	c := g.config.checkpoint
+	if c == nil {
+		return
+	}
+	var receipt *types.Receipt
+	if hash, ok := c.pendingTransaction(); ok {
+		// A transaction that is not mined yet stays pending
+		receipt, _ = g.l2Backend.TransactionReceipt(ctx, hash)
+	}
+	epochStartBlockNumber := g.gasPriceUpdater.EpochStartBlockNumber()
+	gasPrice := g.gasPriceUpdater.GetGasPrice()
+	var samples []float64
+	if g.smoothing != nil {
+		samples = g.smoothing.Samples()
+	}
+	c.update(func(s *store.State) {
+		s.ChainID = g.config.l2ChainID
+		s.Address = g.config.gasPriceOracleAddress.Hex()
+		s.EpochStartBlockNumber = epochStartBlockNumber
+		s.EpochStartTime = time.Now()
+		s.GasPrice = gasPrice
+		s.OnChainGasPrice = onChainGasPrice
+		s.SmoothingSamples = samples
+		settlePendingTransaction(s, receipt)
+	})
+}
+
+// smoothingFilter returns the smoothing filter of the filters, if any
+func smoothingFilter(filters []gasprices.DemandFilter) *gasprices.SmoothingFilter {
+	for _, f := range filters {
+		if s, ok := f.(*gasprices.SmoothingFilter); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// untilEpochEnd returns the time until the current epoch ends, which is
+// shorter than the epoch length for an epoch that was continued
+func (g *GasPriceOracle) untilEpochEnd() time.Duration {
+	if g.epochStart.IsZero() {
+		return g.config.epochLength
+	}
+	remaining := g.config.epochLength - time.Since(g.epochStart)
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+	return remaining
+}
+
+// closeStateDB closes the state database, if any
+func (c *Config) closeStateDB() {
+	if c.stateDB == nil {
+		return
+	}
+	if err := c.stateDB.Close(); err != nil {
+		log.Error("cannot close state", "message", err)
+	}
+	c.stateDB = nil
+}
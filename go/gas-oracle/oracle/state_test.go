@@ -0,0 +1,101 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/store"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRestoreState(t *testing.T) {
+	address := common.HexToAddress("0x420000000000000000000000000000000000000F")
+	cfg := &Config{
+		l2ChainID:             big.NewInt(10),
+		gasPriceOracleAddress: address,
+		epochLength:           10 * time.Second,
+		smoothingEpochs:       6,
+	}
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	saved := func() *store.State {
+		return &store.State{
+			ChainID:               big.NewInt(10),
+			Address:               address.Hex(),
+			EpochStartBlockNumber: 100,
+			EpochStartTime:        now.Add(-5 * time.Second),
+			GasPrice:              big.NewInt(1100),
+			OnChainGasPrice:       big.NewInt(1000),
+			SmoothingSamples:      []float64{1, 2},
+		}
+	}
+
+	if restored := restoreState(cfg, nil, 110, big.NewInt(1000), now); restored != nil {
+		t.Fatalf("unexpected restored state %+v", restored)
+	}
+
+	// A restart within the epoch continues it
+	restored := restoreState(cfg, saved(), 110, big.NewInt(1000), now)
+	if restored.epochStartBlockNumber != 100 || !restored.epochStart.Equal(now.Add(-5*time.Second)) ||
+		restored.gasPrice.Uint64() != 1100 || len(restored.smoothingSamples) != 2 {
+		t.Fatalf("unexpected restored state %+v", restored)
+	}
+
+	// After the epoch ended a new epoch starts, the samples are kept within
+	// the smoothing window
+	state := saved()
+	state.EpochStartTime = now.Add(-30 * time.Second)
+	restored = restoreState(cfg, state, 110, big.NewInt(1000), now)
+	if !restored.epochStart.IsZero() || restored.gasPrice == nil || len(restored.smoothingSamples) != 2 {
+		t.Fatalf("unexpected restored state %+v", restored)
+	}
+	state.EpochStartTime = now.Add(-time.Minute)
+	if restored = restoreState(cfg, state, 110, big.NewInt(1000), now); len(restored.smoothingSamples) != 0 {
+		t.Fatalf("unexpected samples %v", restored.smoothingSamples)
+	}
+
+	// An epoch after the tip is not continued
+	if restored = restoreState(cfg, saved(), 99, big.NewInt(1000), now); !restored.epochStart.IsZero() {
+		t.Fatalf("unexpected restored epoch %+v", restored)
+	}
+
+	// The gas price was changed on chain while stopped
+	if restored = restoreState(cfg, saved(), 110, big.NewInt(2000), now); restored.gasPrice != nil {
+		t.Fatalf("unexpected restored gas price %v", restored.gasPrice)
+	}
+
+	// The state of another chain is ignored
+	state = saved()
+	state.ChainID = big.NewInt(69)
+	if restored = restoreState(cfg, state, 110, big.NewInt(1000), now); restored != nil {
+		t.Fatalf("unexpected restored state %+v", restored)
+	}
+}
+
+func TestSaveState(t *testing.T) {
+	gpo, pricer, _ := newControlledOracle(t)
+	db, err := store.OpenStateDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpo.config.stateDB = db
+	gpo.config.checkpoint = newCheckpoint(gpo.config, nil)
+	defer gpo.config.closeStateDB()
+
+	if err := pricer.SetGasPrice(big.NewInt(42)); err != nil {
+		t.Fatal(err)
+	}
+	gpo.saveState(context.Background(), big.NewInt(40))
+	state, err := db.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ChainID.Uint64() != 1337 || state.Address != gpo.config.gasPriceOracleAddress.Hex() ||
+		state.GasPrice.Uint64() != 42 || state.OnChainGasPrice.Uint64() != 40 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+	if time.Since(state.EpochStartTime) > time.Minute {
+		t.Fatalf("unexpected epoch start %s", state.EpochStartTime)
+	}
+}
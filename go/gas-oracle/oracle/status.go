@@ -0,0 +1,106 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Status is the on-chain state of the gas price oracle along with the
+// signer of the configuration
+type Status struct {
+	ChainID     *big.Int       `json:"chainId"`
+	BlockNumber uint64         `json:"blockNumber"`
+	Address     common.Address `json:"address"`
+	// Implementation is set when the address is an EIP-1967 proxy
+	Implementation *common.Address `json:"implementation,omitempty"`
+	// Version is the detected version of the contract, SemVer is returned
+	// by the contracts that implement version()
+	Version   string   `json:"version"`
+	SemVer    string   `json:"semver,omitempty"`
+	GasPrice  *big.Int `json:"gasPrice"`
+	L1BaseFee *big.Int `json:"l1BaseFee,omitempty"`
+	Overhead  *big.Int `json:"overhead,omitempty"`
+	Scalar    *big.Int `json:"scalar,omitempty"`
+	Decimals  *big.Int `json:"decimals,omitempty"`
+	// Owner is the zero address when the contract has no owner
+	Owner common.Address `json:"owner"`
+	// PendingOwner is set when a two-step ownership transfer waits for the
+	// new owner to accept it
+	PendingOwner *common.Address `json:"pendingOwner,omitempty"`
+	// The signer is not set when there is no key, such as in a dry run
+	Signer        *common.Address `json:"signer,omitempty"`
+	SignerBalance *big.Int        `json:"signerBalance,omitempty"`
+	SignerIsOwner bool            `json:"signerIsOwner"`
+	// SignerIsPendingOwner is true when the signer can accept the ownership
+	SignerIsPendingOwner bool `json:"signerIsPendingOwner,omitempty"`
+}
+
+// FetchStatus reads the status of the gas price oracle of the configuration
+// at the tip of the chain
+func FetchStatus(ctx context.Context, cfg *Config, backend L2Backend) (*Status, error) {
+	chainID, err := resolveChainID(backend, cfg.l2ChainID, "L2")
+	if err != nil {
+		return nil, err
+	}
+	tip, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveGasPriceOracle(ctx, backend, cfg.gasPriceOracleAddress)
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{
+		ChainID:     chainID,
+		BlockNumber: tip.Number.Uint64(),
+		Address:     resolved.Address,
+		Version:     resolved.Version,
+		SemVer:      resolved.SemVer,
+		GasPrice:    resolved.GasPrice,
+		Owner:       resolved.Owner,
+	}
+	if resolved.Implementation != (common.Address{}) {
+		status.Implementation = &resolved.Implementation
+	}
+	if resolved.PendingOwner != (common.Address{}) {
+		status.PendingOwner = &resolved.PendingOwner
+	}
+
+	contract, err := bindings.NewGasPriceOracleCaller(cfg.gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	// The first version of the contract only has the L2 gas price
+	opts := &bind.CallOpts{Context: ctx}
+	for _, read := range []struct {
+		method string
+		value  **big.Int
+		call   func(*bind.CallOpts) (*big.Int, error)
+	}{
+		{"l1BaseFee", &status.L1BaseFee, contract.L1BaseFee},
+		{"overhead", &status.Overhead, contract.Overhead},
+		{"scalar", &status.Scalar, contract.Scalar},
+		{"decimals", &status.Decimals, contract.Decimals},
+	} {
+		if !resolved.implements(read.method) {
+			continue
+		}
+		if *read.value, err = read.call(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if signer, ok := cfg.signer(); ok {
+		status.Signer = &signer
+		status.SignerIsOwner = signer == resolved.Owner
+		status.SignerIsPendingOwner = status.PendingOwner != nil && signer == *status.PendingOwner
+		if status.SignerBalance, err = backend.BalanceAt(ctx, signer, nil); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
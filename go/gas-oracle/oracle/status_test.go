@@ -0,0 +1,63 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestFetchStatus(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if _, err := gpo.SetGasPrice(opts, big.NewInt(1000)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		gasPriceOracleAddress: addr,
+		l2ChainID:             big.NewInt(1337),
+		privateKey:            key,
+	}
+	status, err := FetchStatus(context.Background(), cfg, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.GasPrice.Uint64() != 1000 || status.Owner != opts.From || status.ChainID.Uint64() != 1337 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if !status.SignerIsOwner || status.SignerBalance.Sign() <= 0 || status.Implementation != nil {
+		t.Fatalf("unexpected signer status %+v", status)
+	}
+
+	// Another key is not the owner
+	other, _ := crypto.GenerateKey()
+	cfg.privateKey = other
+	status, err = FetchStatus(context.Background(), cfg, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.SignerIsOwner || *status.Signer != crypto.PubkeyToAddress(other.PublicKey) {
+		t.Fatalf("unexpected signer status %+v", status)
+	}
+
+	// Without a key there is no signer
+	cfg.privateKey = nil
+	status, err = FetchStatus(context.Background(), cfg, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Signer != nil || status.SignerBalance != nil {
+		t.Fatalf("unexpected signer status %+v", status)
+	}
+}
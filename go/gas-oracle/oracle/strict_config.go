@@ -0,0 +1,64 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// configWarnings are the problems of a configuration that do not stop the
+// oracle from running, such as deprecated options or unknown keys of the
+// config file. They are logged, or fail the configuration with
+// --strict-config.
+type configWarnings []string
+
+func (w *configWarnings) add(format string, args ...interface{}) {
+	if w == nil {
+		return
+	}
+	*w = append(*w, fmt.Sprintf(format, args...))
+}
+
+// check logs the warnings, or returns them as an error in strict mode
+func (w configWarnings) check(strict bool) error {
+	if len(w) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("option %q: %s", flags.StrictConfigFlag.Name, strings.Join(w, "; "))
+	}
+	for _, warning := range w {
+		log.Warn("Suspicious configuration", "message", warning)
+	}
+	return nil
+}
+
+// suspicious returns the combinations of options that are valid but are
+// unlikely to be intended
+func (c *Config) suspicious() configWarnings {
+	var w configWarnings
+	// A Bedrock chain sets the SystemConfig instead
+	if !c.bedrock() && !c.enableL2GasPrice && !c.enableL1BaseFee {
+		w.add("neither %q nor %q is enabled, nothing is updated", flags.EnableL2GasPriceFlag.Name,
+			flags.EnableL1BaseFeeFlag.Name)
+	}
+	if c.maxGasPrice != nil && c.floorPrice != nil && c.maxGasPrice.Cmp(c.floorPrice) < 0 {
+		w.add("%q %d is below %q %d", flags.MaxGasPriceFlag.Name, c.maxGasPrice, flags.FloorPriceFlag.Name,
+			c.floorPrice)
+	}
+	if c.enableIdleDecay && c.targetGasPerSecond > 0 && c.idleDecayThreshold >= float64(c.targetGasPerSecond) {
+		w.add("%q %v is not below %q %d, the gas price decays at the target demand",
+			flags.IdleDecayThresholdFlag.Name, c.idleDecayThreshold, flags.TargetGasPerSecondFlag.Name,
+			c.targetGasPerSecond)
+	}
+	if c.minUpdateInterval > 0 && c.minUpdateInterval <= c.epochLength {
+		w.add("%q %s is not longer than %q %s and has no effect", flags.MinUpdateIntervalFlag.Name,
+			c.minUpdateInterval, flags.EpochLengthFlag.Name, c.epochLength)
+	}
+	if c.dryRun && c.auditLogPath != "" {
+		w.add("%q is not written with %q", flags.AuditLogFlag.Name, flags.DryRunFlag.Name)
+	}
+	return w
+}
@@ -0,0 +1,92 @@
+package oracle
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestConfigSuspicious(t *testing.T) {
+	newConfig := func() *Config {
+		return &Config{
+			enableL2GasPrice:   true,
+			floorPrice:         big.NewInt(1),
+			targetGasPerSecond: 11_000_000,
+			epochLength:        10 * time.Second,
+		}
+	}
+	if w := newConfig().suspicious(); len(w) != 0 {
+		t.Fatalf("unexpected warnings %v", w)
+	}
+	// A Bedrock chain updates neither
+	bedrock := newConfig()
+	bedrock.enableL2GasPrice = false
+	bedrock.systemConfigAddress = common.HexToAddress("0x01")
+	if w := bedrock.suspicious(); len(w) != 0 {
+		t.Fatalf("unexpected warnings %v", w)
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(*Config)
+		expected string
+	}{
+		{"nothing enabled", func(c *Config) { c.enableL2GasPrice = false }, "nothing is updated"},
+		{"floor above cap", func(c *Config) { c.maxGasPrice = big.NewInt(0) }, "max-gas-price"},
+		{"idle decay at target", func(c *Config) {
+			c.enableIdleDecay = true
+			c.idleDecayThreshold = 20_000_000
+		}, "idle-decay-threshold"},
+		{"min update interval", func(c *Config) { c.minUpdateInterval = 5 * time.Second }, "min-update-interval"},
+		{"dry run audit log", func(c *Config) {
+			c.dryRun = true
+			c.auditLogPath = "audit.jsonl"
+		}, "audit-log"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			tt.mutate(cfg)
+			w := cfg.suspicious()
+			if len(w) != 1 || !strings.Contains(w[0], tt.expected) {
+				t.Fatalf("expected a warning about %s, got %v", tt.expected, w)
+			}
+			// Warnings are logged unless the config is strict
+			if err := w.check(false); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.check(true); err == nil || !strings.Contains(err.Error(), "strict-config") {
+				t.Fatalf("expected strict config error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileUnknownKeys(t *testing.T) {
+	path := writeConfigFile(t, `
+networks:
+  optimism-mainnet:
+    l2-chain-id: 10
+    floor-prise: 1000
+`)
+	var warnings configWarnings
+	fileCfg, err := loadConfigFile(path, &warnings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "floor-prise") {
+		t.Fatalf("expected a warning about the unknown key, got %v", warnings)
+	}
+	// The known keys are still applied
+	if network := fileCfg.Networks["optimism-mainnet"]; network == nil || *network.L2ChainID != 10 {
+		t.Fatal("known keys not loaded")
+	}
+
+	warnings = nil
+	if _, err := loadConfigFile(writeConfigFile(t, "networks: {}\n"), &warnings); err != nil || len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v %v", warnings, err)
+	}
+}
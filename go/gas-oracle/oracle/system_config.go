@@ -0,0 +1,326 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/flags"
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	systemConfigOverheadGauge = metrics.NewRegisteredGauge("system_config/overhead", ometrics.DefaultRegistry)
+	systemConfigScalarGauge   = metrics.NewRegisteredGauge("system_config/scalar", ometrics.DefaultRegistry)
+
+	systemConfigOperatorFeeScalarGauge   = metrics.NewRegisteredGauge("system_config/operator_fee_scalar", ometrics.DefaultRegistry)
+	systemConfigOperatorFeeConstantGauge = metrics.NewRegisteredGauge("system_config/operator_fee_constant", ometrics.DefaultRegistry)
+)
+
+// errNoL1ContractBackend represents the error when the L1 backend cannot
+// call contracts or fetch receipts, which is needed for a Bedrock chain
+var errNoL1ContractBackend = errors.New("the L1 backend cannot call contracts or fetch receipts")
+
+// gasConfigUpdater keeps the gas config of the SystemConfig of a Bedrock
+// chain at the configured overhead and scalar. Bedrock nodes derive the
+// L1 fee parameters from the SystemConfig on L1, so they are set with L1
+// transactions rather than with the L2 gas price oracle.
+type gasConfigUpdater struct {
+	cfg      *Config
+	contract *bindings.SystemConfig
+	// txs is nil in a dry run
+	txs *l1TxManager
+	// operatorFee is nil when the operator fee is not set, it holds the
+	// configured parameters capped by the max ones
+	operatorFee *operatorFee
+}
+
+// operatorFee are the parameters of the operator fee of a SystemConfig, which
+// charges every transaction the constant plus the gas used times the scalar
+type operatorFee struct {
+	scalar   uint32
+	constant uint64
+}
+
+func newGasConfigUpdater(cfg *Config, backend L1Backend) (*gasConfigUpdater, error) {
+	l1, ok := backend.(l1ContractBackend)
+	if !ok {
+		return nil, errNoL1ContractBackend
+	}
+	contract, err := bindings.NewSystemConfig(cfg.systemConfigAddress, l1)
+	if err != nil {
+		return nil, err
+	}
+	u := &gasConfigUpdater{cfg: cfg, contract: contract}
+	if cfg.enableOperatorFee {
+		// Older SystemConfigs do not have the operator fee
+		if _, err := contract.OperatorFeeScalar(&bind.CallOpts{Context: context.Background()}); err != nil {
+			return nil, fmt.Errorf("SystemConfig %s does not support the operator fee: %w",
+				cfg.systemConfigAddress.Hex(), err)
+		}
+		u.operatorFee = cappedOperatorFee(cfg)
+	}
+	if !cfg.dryRun {
+		if u.txs, err = newL1TxManager(cfg, l1); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// ensure makes sure that the signer is the owner of the SystemConfig, which
+// is the only account that can set the gas config
+func (u *gasConfigUpdater) ensure(ctx context.Context) error {
+	owner, err := u.contract.Owner(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("cannot get owner of SystemConfig %s: %w", u.cfg.systemConfigAddress.Hex(), err)
+	}
+	address, _ := u.cfg.signer()
+	if address != owner {
+		log.Error("Signing key does not match SystemConfig owner", "signer", address.Hex(), "owner", owner.Hex())
+		u.cfg.notifier.Notify(&notify.Event{
+			Type:    notify.EventOwnerMismatch,
+			Time:    time.Now(),
+			ChainID: u.cfg.l2ChainID,
+			Signer:  address.Hex(),
+			Owner:   owner.Hex(),
+		})
+		return errInvalidSigningKey
+	}
+	return nil
+}
+
+// cappedOperatorFee returns the configured operator fee, capped by the max
+// scalar and constant
+func cappedOperatorFee(cfg *Config) *operatorFee {
+	scalar, constant := cfg.operatorFeeScalar, cfg.operatorFeeConstant
+	if cfg.maxOperatorFeeScalar > 0 && scalar > cfg.maxOperatorFeeScalar {
+		log.Warn("Capping the operator fee scalar", "scalar", scalar, "max", cfg.maxOperatorFeeScalar)
+		scalar = cfg.maxOperatorFeeScalar
+	}
+	if cfg.maxOperatorFeeConstant > 0 && constant > cfg.maxOperatorFeeConstant {
+		log.Warn("Capping the operator fee constant", "constant", constant, "max", cfg.maxOperatorFeeConstant)
+		constant = cfg.maxOperatorFeeConstant
+	}
+	return &operatorFee{scalar: uint32(scalar), constant: constant}
+}
+
+// update sets the gas config and the operator fee of the SystemConfig when
+// they are enabled and differ from the configured ones
+func (u *gasConfigUpdater) update(ctx context.Context) error {
+	if u.cfg.enableGasConfig {
+		if err := u.updateGasConfig(ctx); err != nil {
+			return err
+		}
+	}
+	if u.operatorFee != nil {
+		return u.updateOperatorFee(ctx)
+	}
+	return nil
+}
+
+// updateGasConfig sets the gas config when the overhead or the scalar of the
+// SystemConfig differ from the configured ones. It waits for the
+// transaction to be mined.
+func (u *gasConfigUpdater) updateGasConfig(ctx context.Context) error {
+	opts := &bind.CallOpts{Context: ctx}
+	overhead, err := u.contract.Overhead(opts)
+	if err != nil {
+		return fmt.Errorf("cannot get overhead: %w", err)
+	}
+	scalar, err := u.contract.Scalar(opts)
+	if err != nil {
+		return fmt.Errorf("cannot get scalar: %w", err)
+	}
+	systemConfigOverheadGauge.Update(int64(overhead.Uint64()))
+	systemConfigScalarGauge.Update(int64(scalar.Uint64()))
+
+	wantOverhead := new(big.Int).SetUint64(u.cfg.systemConfigOverhead)
+	wantScalar := new(big.Int).SetUint64(u.cfg.systemConfigScalar)
+	if overhead.Cmp(wantOverhead) == 0 && scalar.Cmp(wantScalar) == 0 {
+		log.Debug("gas config is up to date", "overhead", overhead, "scalar", scalar)
+		return nil
+	}
+
+	if u.cfg.dryRun {
+		log.Info("dry run: would set the gas config", "overhead", overhead, "scalar", scalar,
+			"new-overhead", wantOverhead, "new-scalar", wantScalar)
+		return nil
+	}
+	if u.cfg.controls.isPaused() {
+		log.Warn("Updates are paused, not setting the gas config", "overhead", overhead, "scalar", scalar)
+		return nil
+	}
+	if !u.cfg.leader.isLeader() {
+		log.Info("Replica is on standby, not setting the gas config", "overhead", overhead, "scalar", scalar)
+		return nil
+	}
+
+	log.Info("Setting the gas config", "overhead", overhead, "scalar", scalar,
+		"new-overhead", wantOverhead, "new-scalar", wantScalar)
+	receipt, err := u.txs.send(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return u.contract.SetGasConfig(opts, wantOverhead, wantScalar)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set gas config: %w", err)
+	}
+	log.Info("gas config transaction confirmed", "hash", receipt.TxHash.Hex(),
+		"gas-used", receipt.GasUsed, "blocknumber", receipt.BlockNumber)
+	systemConfigOverheadGauge.Update(int64(wantOverhead.Uint64()))
+	systemConfigScalarGauge.Update(int64(wantScalar.Uint64()))
+	return nil
+}
+
+// updateOperatorFee sets the operator fee when its scalar or its constant
+// changes by more than the significance factor. It waits for the
+// transaction to be mined.
+func (u *gasConfigUpdater) updateOperatorFee(ctx context.Context) error {
+	opts := &bind.CallOpts{Context: ctx}
+	scalar, err := u.contract.OperatorFeeScalar(opts)
+	if err != nil {
+		return fmt.Errorf("cannot get operator fee scalar: %w", err)
+	}
+	constant, err := u.contract.OperatorFeeConstant(opts)
+	if err != nil {
+		return fmt.Errorf("cannot get operator fee constant: %w", err)
+	}
+	systemConfigOperatorFeeScalarGauge.Update(int64(scalar))
+	systemConfigOperatorFeeConstantGauge.Update(int64(constant))
+
+	want := u.operatorFee
+	factor := u.cfg.operatorFeeSignificanceFactor
+	if !significantChange(uint64(scalar), uint64(want.scalar), factor) &&
+		!significantChange(constant, want.constant, factor) {
+		log.Debug("operator fee is up to date", "scalar", scalar, "constant", constant)
+		return nil
+	}
+
+	if u.cfg.dryRun {
+		log.Info("dry run: would set the operator fee", "scalar", scalar, "constant", constant,
+			"new-scalar", want.scalar, "new-constant", want.constant)
+		return nil
+	}
+	if u.cfg.controls.isPaused() {
+		log.Warn("Updates are paused, not setting the operator fee", "scalar", scalar, "constant", constant)
+		return nil
+	}
+	if !u.cfg.leader.isLeader() {
+		log.Info("Replica is on standby, not setting the operator fee", "scalar", scalar, "constant", constant)
+		return nil
+	}
+
+	log.Info("Setting the operator fee", "scalar", scalar, "constant", constant,
+		"new-scalar", want.scalar, "new-constant", want.constant)
+	receipt, err := u.txs.send(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return u.contract.SetOperatorFeeScalars(opts, want.scalar, want.constant)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set operator fee: %w", err)
+	}
+	log.Info("operator fee transaction confirmed", "hash", receipt.TxHash.Hex(),
+		"gas-used", receipt.GasUsed, "blocknumber", receipt.BlockNumber)
+	systemConfigOperatorFeeScalarGauge.Update(int64(want.scalar))
+	systemConfigOperatorFeeConstantGauge.Update(int64(want.constant))
+	return nil
+}
+
+// significantChange returns true when moving from current to next changes
+// the value by more than the factor
+func significantChange(current, next uint64, factor float64) bool {
+	if current == next {
+		return false
+	}
+	diff, _ := relativeDifference(new(big.Int).SetUint64(current), new(big.Int).SetUint64(next)).Float64()
+	return diff > factor
+}
+
+// newBedrockGasPriceOracle creates a GasPriceOracle that sets the gas config
+// of a Bedrock chain. The gas price oracle predeploy of Bedrock still serves
+// the gas price, which is exported as a metric.
+func newBedrockGasPriceOracle(cfg *Config, l1Client L1Backend, l2Client L2Backend) (*GasPriceOracle, error) {
+	resolved, err := resolveGasPriceOracle(context.Background(), l2Client, cfg.gasPriceOracleAddress)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.Version != contractVersionBedrock {
+		return nil, fmt.Errorf("%w: %s is an OVM_GasPriceOracle, which is updated on L2 without --%s",
+			errUnexpectedContractVersion, cfg.gasPriceOracleAddress.Hex(), flags.SystemConfigAddressFlag.Name)
+	}
+	contract, err := bindings.NewGasPriceOracle(cfg.gasPriceOracleAddress, l2Client)
+	if err != nil {
+		return nil, err
+	}
+	cfg.controls = newControls(cfg)
+
+	l2ChainID, err := resolveChainID(l2Client, cfg.l2ChainID, "L2")
+	if err != nil {
+		return nil, err
+	}
+	cfg.l2ChainID = l2ChainID
+	l1ChainID, err := resolveChainID(l1Client, cfg.l1ChainID, "L1")
+	if err != nil {
+		return nil, err
+	}
+	cfg.l1ChainID = l1ChainID
+
+	if !cfg.hasSigner() && !cfg.dryRun {
+		return nil, errNoPrivateKey
+	}
+	cfg.notifier, err = newNotifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.heartbeat = newHeartbeat(cfg.heartbeatURL)
+	if err := openLeaderElection(cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.enableGasConfig {
+		log.Info("Setting the gas config of a Bedrock chain", "systemConfig", cfg.systemConfigAddress.Hex(),
+			"overhead", cfg.systemConfigOverhead, "scalar", cfg.systemConfigScalar)
+	} else {
+		log.Info("Not setting the gas config of a Bedrock chain, it is managed manually",
+			"systemConfig", cfg.systemConfigAddress.Hex())
+	}
+	gasConfig, err := newGasConfigUpdater(cfg, l1Client)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gpo := &GasPriceOracle{
+		l2ChainID: l2ChainID,
+		l1ChainID: l1ChainID,
+		ctx:       ctx,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+		contract:  contract,
+		config:    cfg,
+		l2Backend: l2Client,
+		l1Backend: l1Client,
+		gasConfig: gasConfig,
+	}
+	gpo.feeVault = newFeeVaultMonitor(cfg, l2Client, gpo.gasPriceAt)
+
+	if signer, ok := cfg.signer(); ok {
+		if err := gasConfig.ensure(context.Background()); err != nil {
+			// Deliver the notification of the mismatch before exiting
+			cfg.notifier.Close()
+			return nil, err
+		}
+		// The transactions are paid for on L1
+		if backend, ok := l1Client.(BalanceBackend); ok {
+			gpo.balanceMonitor = newBalanceMonitor(backend, signer, cfg)
+		}
+		gpo.owner = newOwnerMonitor(cfg, "SystemConfig", gasConfig.contract.Owner, nil)
+	}
+	return gpo, nil
+}
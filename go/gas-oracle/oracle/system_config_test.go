@@ -0,0 +1,338 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// fakeSystemConfig is an L1 backend with a SystemConfig at every address.
+// The transactions that set the gas config are mined when they are sent,
+// except for the first drop transactions which stay pending forever.
+type fakeSystemConfig struct {
+	mu       sync.Mutex
+	abi      abi.ABI
+	owner    common.Address
+	overhead *big.Int
+	scalar   *big.Int
+	baseFee  *big.Int
+	// operatorFee is nil for a SystemConfig without the operator fee
+	operatorFee *operatorFee
+	drop        int
+	sent        []*types.Transaction
+	receipts    map[common.Hash]*types.Receipt
+}
+
+func newFakeSystemConfig(t *testing.T, owner common.Address) *fakeSystemConfig {
+	parsed, err := abi.JSON(strings.NewReader(bindings.SystemConfigMetaData.ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fakeSystemConfig{
+		abi:      parsed,
+		owner:    owner,
+		overhead: big.NewInt(2100),
+		scalar:   big.NewInt(1_000_000),
+		baseFee:  big.NewInt(10_000_000_000),
+		receipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (f *fakeSystemConfig) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (f *fakeSystemConfig) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	method, err := f.abi.MethodById(call.Data)
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "owner":
+		return method.Outputs.Pack(f.owner)
+	case "overhead":
+		return method.Outputs.Pack(f.overhead)
+	case "scalar":
+		return method.Outputs.Pack(f.scalar)
+	case "operatorFeeScalar", "operatorFeeConstant":
+		if f.operatorFee == nil {
+			return nil, errors.New("execution reverted")
+		}
+		if method.Name == "operatorFeeScalar" {
+			return method.Outputs.Pack(f.operatorFee.scalar)
+		}
+		return method.Outputs.Pack(f.operatorFee.constant)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeSystemConfig) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &types.Header{Number: big.NewInt(1), BaseFee: new(big.Int).Set(f.baseFee)}, nil
+}
+
+func (f *fakeSystemConfig) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (f *fakeSystemConfig) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return uint64(len(f.receipts)), nil
+}
+
+func (f *fakeSystemConfig) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1_000_000_000), nil
+}
+
+func (f *fakeSystemConfig) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1_000_000_000), nil
+}
+
+func (f *fakeSystemConfig) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 50_000, nil
+}
+
+func (f *fakeSystemConfig) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, tx)
+	if len(f.sent) <= f.drop {
+		return nil
+	}
+	method, err := f.abi.MethodById(tx.Data())
+	if err != nil {
+		return err
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		return err
+	}
+	switch method.Name {
+	case "setGasConfig":
+		f.overhead, f.scalar = args[0].(*big.Int), args[1].(*big.Int)
+	case "setOperatorFeeScalars":
+		f.operatorFee = &operatorFee{scalar: args[0].(uint32), constant: args[1].(uint64)}
+	}
+	f.receipts[tx.Hash()] = &types.Receipt{
+		TxHash:      tx.Hash(),
+		Status:      types.ReceiptStatusSuccessful,
+		BlockNumber: big.NewInt(int64(len(f.receipts) + 1)),
+		GasUsed:     30_000,
+	}
+	return nil
+}
+
+func (f *fakeSystemConfig) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return nil, nil
+}
+
+func (f *fakeSystemConfig) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeSystemConfig) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if receipt, ok := f.receipts[txHash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func TestGasConfigUpdater(t *testing.T) {
+	defer func(interval time.Duration) { l1TxPollInterval = interval }(l1TxPollInterval)
+	l1TxPollInterval = time.Millisecond
+
+	key, _ := crypto.GenerateKey()
+	l1 := newFakeSystemConfig(t, crypto.PubkeyToAddress(key.PublicKey))
+	cfg := &Config{
+		privateKey:           key,
+		l1ChainID:            big.NewInt(1),
+		systemConfigAddress:  common.HexToAddress("0x229047fed2591dbec1eF1118d64F7aF3dB9EB290"),
+		systemConfigOverhead: 188,
+		systemConfigScalar:   684_000,
+		enableGasConfig:      true,
+		l1TxResubmitTimeout:  time.Minute,
+	}
+	u, err := newGasConfigUpdater(cfg, l1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.ensure(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := u.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(l1.sent) != 1 || l1.overhead.Uint64() != 188 || l1.scalar.Uint64() != 684_000 {
+		t.Fatalf("unexpected gas config %d %d after %d transactions", l1.overhead, l1.scalar, len(l1.sent))
+	}
+	if chainID := l1.sent[0].ChainId(); chainID.Uint64() != 1 || l1.sent[0].Type() != types.DynamicFeeTxType {
+		t.Fatalf("expected a dynamic fee transaction of L1, got type %d of chain %d", l1.sent[0].Type(), chainID)
+	}
+
+	// Nothing is sent when the gas config is up to date
+	if err := u.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(l1.sent) != 1 {
+		t.Fatalf("expected no transaction, got %d", len(l1.sent))
+	}
+
+	// Paused updates are not sent
+	cfg.controls = newControls(cfg)
+	cfg.controls.setPaused(true)
+	cfg.systemConfigScalar = 700_000
+	if err := u.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(l1.sent) != 1 {
+		t.Fatalf("expected no transaction while paused, got %d", len(l1.sent))
+	}
+
+	// A gas config that is managed manually is not set
+	cfg.controls.setPaused(false)
+	cfg.enableGasConfig = false
+	if err := u.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(l1.sent) != 1 || l1.scalar.Uint64() != 684_000 {
+		t.Fatalf("expected the disabled gas config to be kept, got %d after %d transactions", l1.scalar, len(l1.sent))
+	}
+
+	// Another owner cannot set the gas config
+	l1.owner = common.HexToAddress("0x01")
+	if err := u.ensure(context.Background()); !errors.Is(err, errInvalidSigningKey) {
+		t.Fatalf("expected %v, got %v", errInvalidSigningKey, err)
+	}
+}
+
+func TestOperatorFee(t *testing.T) {
+	defer func(interval time.Duration) { l1TxPollInterval = interval }(l1TxPollInterval)
+	l1TxPollInterval = time.Millisecond
+
+	key, _ := crypto.GenerateKey()
+	l1 := newFakeSystemConfig(t, crypto.PubkeyToAddress(key.PublicKey))
+	cfg := &Config{
+		privateKey:                    key,
+		l1ChainID:                     big.NewInt(1),
+		systemConfigAddress:           common.HexToAddress("0x229047fed2591dbec1eF1118d64F7aF3dB9EB290"),
+		systemConfigOverhead:          2100,
+		systemConfigScalar:            1_000_000,
+		enableGasConfig:               true,
+		l1TxResubmitTimeout:           time.Minute,
+		enableOperatorFee:             true,
+		operatorFeeScalar:             2_000_000,
+		operatorFeeConstant:           1000,
+		maxOperatorFeeScalar:          1_500_000,
+		operatorFeeSignificanceFactor: 0.1,
+	}
+
+	// An older SystemConfig does not have the operator fee
+	if _, err := newGasConfigUpdater(cfg, l1); err == nil {
+		t.Fatal("expected the operator fee to be unsupported")
+	}
+
+	l1.operatorFee = &operatorFee{}
+	u, err := newGasConfigUpdater(cfg, l1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Only the operator fee is sent since the gas config is up to date, with
+	// the scalar capped
+	if len(l1.sent) != 1 || l1.operatorFee.scalar != 1_500_000 || l1.operatorFee.constant != 1000 {
+		t.Fatalf("unexpected operator fee %+v after %d transactions", l1.operatorFee, len(l1.sent))
+	}
+
+	// A change within the significance factor is not sent
+	l1.operatorFee = &operatorFee{scalar: 1_400_000, constant: 1050}
+	if err := u.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(l1.sent) != 1 {
+		t.Fatalf("expected no transaction, got %d", len(l1.sent))
+	}
+	l1.operatorFee.constant = 500
+	if err := u.update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(l1.sent) != 2 || l1.operatorFee.constant != 1000 {
+		t.Fatalf("expected the operator fee to be set, got %+v after %d transactions", l1.operatorFee, len(l1.sent))
+	}
+}
+
+func TestBedrockGasPriceOracle(t *testing.T) {
+	defer func(interval time.Duration) { l1TxPollInterval = interval }(l1TxPollInterval)
+	l1TxPollInterval = time.Millisecond
+
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	ovm, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	addr := common.HexToAddress("0x420000000000000000000000000000000000000F")
+	l2 := backends.NewSimulatedBackend(core.GenesisAlloc{
+		signer: {Balance: big.NewInt(params.Ether)},
+		addr:   {Code: bedrockGasPriceOracleCode(1, "1.0.0"), Balance: big.NewInt(0)},
+	}, 9_000_000)
+
+	l1 := newFakeSystemConfig(t, signer)
+	cfg := &Config{
+		privateKey:            key,
+		l1ChainID:             big.NewInt(1),
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: ovm,
+		systemConfigAddress:   common.HexToAddress("0x229047fed2591dbec1eF1118d64F7aF3dB9EB290"),
+		systemConfigOverhead:  188,
+		systemConfigScalar:    684_000,
+		systemConfigInterval:  time.Minute,
+		enableGasConfig:       true,
+		l1TxResubmitTimeout:   time.Minute,
+	}
+	// The OVM_GasPriceOracle is not the one of a Bedrock chain
+	if _, err := newGasPriceOracle(cfg, l1, sim, nil); !errors.Is(err, errUnexpectedContractVersion) {
+		t.Fatalf("expected %v, got %v", errUnexpectedContractVersion, err)
+	}
+
+	cfg.gasPriceOracleAddress = addr
+	gpo, err := newGasPriceOracle(cfg, l1, l2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gpo.gasConfig == nil || gpo.gasPriceUpdater != nil {
+		t.Fatal("expected only the gas config to be updated")
+	}
+	if err := gpo.RunOnce(); err != nil {
+		t.Fatal(err)
+	}
+	if l1.overhead.Uint64() != 188 || l1.scalar.Uint64() != 684_000 {
+		t.Fatalf("unexpected gas config %d %d", l1.overhead, l1.scalar)
+	}
+}
@@ -0,0 +1,88 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+
+// startSpan starts a span as a child of the span in the context. Spans are
+// discarded unless a tracer provider has been installed.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends a span, recording the error that it failed with
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceGetLatestBlockNumberFn traces fetching the tip of the chain
+func traceGetLatestBlockNumberFn(epoch *epochState, fn gasprices.GetLatestBlockNumberFn) gasprices.GetLatestBlockNumberFn {
+	return func() (uint64, error) {
+		_, span := startSpan(epoch.context(), "FetchLatestHeader")
+		number, err := fn()
+		span.SetAttributes(attribute.Int64("block.number", int64(number)))
+		endSpan(span, err)
+		return number, err
+	}
+}
+
+// traceGetGasUsedByBlock traces fetching the header of each block in the
+// epoch
+func traceGetGasUsedByBlock(epoch *epochState, fn gasprices.GetGasUsedByBlockFn) gasprices.GetGasUsedByBlockFn {
+	return func(number *big.Int) (uint64, error) {
+		_, span := startSpan(epoch.context(), "FetchHeader", attribute.Int64("block.number", number.Int64()))
+		gasUsed, err := fn(number)
+		span.SetAttributes(attribute.Int64("block.gas_used", int64(gasUsed)))
+		endSpan(span, err)
+		return gasUsed, err
+	}
+}
+
+// traceGetBlockUsage traces fetching each block in the epoch when the
+// demand uses more than the gas of the blocks
+func traceGetBlockUsage(epoch *epochState, fn gasprices.GetBlockUsageFn) gasprices.GetBlockUsageFn {
+	return func(number *big.Int) (*gasprices.BlockUsage, error) {
+		_, span := startSpan(epoch.context(), "FetchBlock", attribute.Int64("block.number", number.Int64()))
+		usage, err := fn(number)
+		if usage != nil {
+			span.SetAttributes(
+				attribute.Int64("block.gas_used", int64(usage.GasUsed)),
+				attribute.Int64("block.tx_count", int64(usage.TxCount)),
+			)
+		}
+		endSpan(span, err)
+		return usage, err
+	}
+}
+
+// tracedPricer traces the computation of the gas price of each epoch
+type tracedPricer struct {
+	gasprices.Pricer
+	epoch *epochState
+}
+
+func (t *tracedPricer) CompleteEpoch(avgGasPerSecond float64) (*big.Int, error) {
+	_, span := startSpan(t.epoch.context(), "CompleteEpoch",
+		attribute.Float64("demand.avg_gas_per_second", avgGasPerSecond),
+		attribute.String("gas_price.previous", t.Pricer.GetGasPrice().String()),
+	)
+	gasPrice, err := t.Pricer.CompleteEpoch(avgGasPerSecond)
+	if gasPrice != nil {
+		span.SetAttributes(attribute.String("gas_price.computed", gasPrice.String()))
+	}
+	endSpan(span, err)
+	return gasPrice, err
+}
@@ -0,0 +1,75 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWrapUpdateL2GasPriceFnTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+	}
+
+	epoch := new(epochState)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, root := startSpan(epoch.context(), "Epoch")
+	epoch.setContext(ctx)
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	root.End()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	for _, name := range []string{"Epoch", "UpdateL2GasPrice", "DecideL2GasPrice", "SendTransaction"} {
+		if _, ok := spans[name]; !ok {
+			t.Fatalf("missing span %s", name)
+		}
+	}
+	if spans["UpdateL2GasPrice"].Parent().SpanID() != spans["Epoch"].SpanContext().SpanID() {
+		t.Fatal("update is not part of the epoch")
+	}
+	if spans["SendTransaction"].Parent().SpanID() != spans["UpdateL2GasPrice"].SpanContext().SpanID() {
+		t.Fatal("send is not part of the update")
+	}
+	var reason string
+	for _, attr := range spans["DecideL2GasPrice"].Attributes() {
+		if attr.Key == "decision.reason" {
+			reason = attr.Value.AsString()
+		}
+	}
+	if reason != string(ReasonUpdated) {
+		t.Fatalf("unexpected decision reason %q", reason)
+	}
+}
@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	txPoolPendingGauge = metrics.NewRegisteredGauge("txpool/pending", ometrics.DefaultRegistry)
+	txPoolQueuedGauge  = metrics.NewRegisteredGauge("txpool/queued", ometrics.DefaultRegistry)
+)
+
+// txPoolStatus is the response of the txpool_status RPC
+type txPoolStatus struct {
+	Pending hexutil.Uint64 `json:"pending"`
+	Queued  hexutil.Uint64 `json:"queued"`
+}
+
+// getTxPoolStatusFn fetches the status of the sequencer's transaction pool
+type getTxPoolStatusFn func() (*txPoolStatus, error)
+
+// wrapGetTxPoolStatusFn binds a getTxPoolStatusFn to an RPC client
+func wrapGetTxPoolStatusFn(client *rpc.Client) getTxPoolStatusFn {
+	return func() (*txPoolStatus, error) {
+		var status txPoolStatus
+		if err := client.CallContext(context.Background(), &status, "txpool_status"); err != nil {
+			return nil, err
+		}
+		return &status, nil
+	}
+}
+
+// txPoolSignal is a DemandFilter that adds the gas waiting in the sequencer's
+// transaction pool to the demand measured from blocks. A backed up pending
+// queue raises the gas price before the transactions are included in blocks.
+type txPoolSignal struct {
+	getStatus    getTxPoolStatusFn
+	weight       float64
+	queuedWeight float64
+	gasPerTx     uint64
+	epochLength  time.Duration
+}
+
+// FilterDemand blends the transaction pool into the demand. The pending
+// transactions are assumed to use gasPerTx each and to be included within
+// the next epoch. Queued transactions cannot be executed until their nonce
+// gap is filled, so they are weighted separately. When the transaction pool
+// cannot be queried the demand is used as is.
+func (s *txPoolSignal) FilterDemand(avgGasPerSecond float64) (float64, error) {
+	status, err := s.getStatus()
+	if err != nil {
+		log.Warn("cannot fetch txpool status", "message", err)
+		return avgGasPerSecond, nil
+	}
+	txPoolPendingGauge.Update(int64(status.Pending))
+	txPoolQueuedGauge.Update(int64(status.Queued))
+
+	txs := float64(status.Pending) + s.queuedWeight*float64(status.Queued)
+	waiting := txs * float64(s.gasPerTx) / s.epochLength.Seconds()
+	demand := avgGasPerSecond + s.weight*waiting
+	log.Debug("blended txpool into demand", "pending", uint64(status.Pending),
+		"queued", uint64(status.Queued), "average-gas-per-second", avgGasPerSecond,
+		"demand", demand)
+	return demand, nil
+}
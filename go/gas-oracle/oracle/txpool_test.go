@@ -0,0 +1,72 @@
+package oracle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type mockTxPoolAPI struct {
+	status txPoolStatus
+}
+
+func (m *mockTxPoolAPI) Status() txPoolStatus {
+	return m.status
+}
+
+func TestWrapGetTxPoolStatusFn(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	api := &mockTxPoolAPI{status: txPoolStatus{Pending: 10, Queued: 3}}
+	if err := server.RegisterName("txpool", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	status, err := wrapGetTxPoolStatusFn(client)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Pending != 10 || status.Queued != 3 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestTxPoolSignal(t *testing.T) {
+	var statusErr error
+	signal := &txPoolSignal{
+		getStatus: func() (*txPoolStatus, error) {
+			if statusErr != nil {
+				return nil, statusErr
+			}
+			return &txPoolStatus{Pending: hexutil.Uint64(100), Queued: hexutil.Uint64(40)}, nil
+		},
+		weight:       0.5,
+		queuedWeight: 0.25,
+		gasPerTx:     100_000,
+		epochLength:  10 * time.Second,
+	}
+
+	// (100 + 0.25 * 40) * 100000 / 10 = 1.1M gas per second waiting
+	demand, err := signal.FilterDemand(1_000_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if demand != 1_550_000 {
+		t.Fatalf("expected 1550000, got %f", demand)
+	}
+
+	// The demand is used as is when the txpool is unavailable
+	statusErr = errors.New("txpool namespace not available")
+	demand, err = signal.FilterDemand(1_000_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if demand != 1_000_000 {
+		t.Fatalf("expected 1000000, got %f", demand)
+	}
+}
@@ -7,18 +7,27 @@ import (
 	"time"
 
 	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
 	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
 	txSendCounter           = metrics.NewRegisteredCounter("tx/send", ometrics.DefaultRegistry)
 	txNotSignificantCounter = metrics.NewRegisteredCounter("tx/not_significant", ometrics.DefaultRegistry)
+	txRateLimitedCounter    = metrics.NewRegisteredCounter("tx/rate_limited", ometrics.DefaultRegistry)
+	txDryRunCounter         = metrics.NewRegisteredCounter("tx/dry_run", ometrics.DefaultRegistry)
+	dryRunGasPriceGauge     = metrics.NewRegisteredGauge("dry_run/gas_price", ometrics.DefaultRegistry)
+	shadowGasPriceGauge     = metrics.NewRegisteredGauge("shadow/gas_price", ometrics.DefaultRegistry)
+	shadowDivergenceGauge   = metrics.NewRegisteredGaugeFloat64("shadow/divergence", ometrics.DefaultRegistry)
+	canaryFallbackCounter   = metrics.NewRegisteredCounter("canary/fallback", ometrics.DefaultRegistry)
 	gasPriceGauge           = metrics.NewRegisteredGauge("gas_price", ometrics.DefaultRegistry)
 	txConfTimer             = metrics.NewRegisteredTimer("tx/confirmed", ometrics.DefaultRegistry)
 	txSendTimer             = metrics.NewRegisteredTimer("tx/send", ometrics.DefaultRegistry)
@@ -26,24 +35,21 @@ var (
 
 // getLatestBlockNumberFn is used by the GasPriceUpdater
 // to get the latest block number. The outer function binds the
-// inner function to a `bind.ContractBackend` which is implemented
-// by the `ethclient.Client`
-func wrapGetLatestBlockNumberFn(backend bind.ContractBackend) func() (uint64, error) {
+// inner function to the demandHead, which reads the tip or a block
+// behind it. Requests are bound to the context of the epoch so that
+// they are cancelled with it.
+func wrapGetLatestBlockNumberFn(epoch *epochState, head *demandHead) func() (uint64, error) {
 	return func() (uint64, error) {
-		tip, err := backend.HeaderByNumber(context.Background(), nil)
-		if err != nil {
-			return 0, err
-		}
-		return tip.Number.Uint64(), nil
+		return head.number(epoch.context())
 	}
 }
 
 // wrapGetGasUsedByBlock is used by the GasPriceUpdater to get
 // the amount of gas used by a particular block. This is used to
 // track gas usage over time
-func wrapGetGasUsedByBlock(backend bind.ContractBackend) func(*big.Int) (uint64, error) {
+func wrapGetGasUsedByBlock(epoch *epochState, backend bind.ContractBackend) func(*big.Int) (uint64, error) {
 	return func(number *big.Int) (uint64, error) {
-		block, err := backend.HeaderByNumber(context.Background(), number)
+		block, err := backend.HeaderByNumber(epoch.context(), number)
 		if err != nil {
 			return 0, err
 		}
@@ -51,6 +57,35 @@ func wrapGetGasUsedByBlock(backend bind.ContractBackend) func(*big.Int) (uint64,
 	}
 }
 
+// BlockBackend can fetch full blocks including their transactions
+type BlockBackend interface {
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+}
+
+// wrapGetBlockUsage is used by the GasPriceUpdater to get the gas, the
+// number of transactions and the calldata bytes used by a particular block
+func wrapGetBlockUsage(epoch *epochState, backend BlockBackend) gasprices.GetBlockUsageFn {
+	return func(number *big.Int) (*gasprices.BlockUsage, error) {
+		block, err := backend.BlockByNumber(epoch.context(), number)
+		if err != nil {
+			return nil, err
+		}
+		return blockUsage(block), nil
+	}
+}
+
+// blockUsage returns the resources used by a block
+func blockUsage(block *types.Block) *gasprices.BlockUsage {
+	usage := &gasprices.BlockUsage{
+		GasUsed: block.GasUsed(),
+		TxCount: uint64(len(block.Transactions())),
+	}
+	for _, tx := range block.Transactions() {
+		usage.CalldataBytes += uint64(len(tx.Data()))
+	}
+	return usage
+}
+
 // DeployContractBackend represents the union of the
 // DeployBackend and the ContractBackend
 type DeployContractBackend interface {
@@ -59,29 +94,26 @@ type DeployContractBackend interface {
 }
 
 // updateL2GasPriceFn is used by the GasPriceUpdater
-// to update the L2 gas price
+// to update the L2 gas price. The demand of the epoch is included in the
+// decision record of each epoch when set, and the update is traced as part
+// of the epoch.
 // perhaps this should take an options struct along with the backend?
 // how can this continue to be decomposed?
-func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(uint64) error, error) {
-	if cfg.privateKey == nil {
-		return nil, errNoPrivateKey
-	}
-	if cfg.l2ChainID == nil {
-		return nil, errNoChainID
-	}
-
-	opts, err := bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
-	if err != nil {
-		return nil, err
-	}
-	// Once https://github.com/ethereum/go-ethereum/pull/23062 is released
-	// then we can remove setting the context here
-	if opts.Context == nil {
-		opts.Context = context.Background()
+func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config, epoch *epochState) (func(*big.Int) error, error) {
+	// A transactor is only needed when transactions are sent
+	var opts *bind.TransactOpts
+	// safe is nil when the signer owns the gas price oracle
+	var safe *safeProposer
+	if !cfg.dryRun {
+		var err error
+		opts, err = newTransactOpts(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if safe, err = newSafeProposer(cfg, backend); err != nil {
+			return nil, err
+		}
 	}
-	// Don't send the transaction using the `contract` so that we can inspect
-	// it beforehand
-	opts.NoSend = true
 
 	// Create a new contract bindings in scope of the updateL2GasPriceFn
 	// that is returned from this function
@@ -90,11 +122,32 @@ func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(ui
 		return nil, err
 	}
 
-	return func(updatedGasPrice uint64) error {
-		log.Trace("UpdateL2GasPriceFn", "gas-price", updatedGasPrice)
+	// Keep track of when the last update was sent so that updates can be
+	// rate limited, starting from the last update found on chain
+	limiter := &rateLimiter{interval: cfg.minUpdateInterval, last: cfg.warmStart.lastUpdateTime()}
+	// capped is set while the updates are held back, so that only the first
+	// capped decision is notified
+	capped := false
+
+	// send signs and sends the decided update. It is called from the update
+	// loop, either for the decision of the epoch or for an approved update.
+	send := func(ctx context.Context, decision *Decision) error {
+		updatedGasPrice := decision.GasPrice
+		if err := cfg.l2ChainIDGuard.verify(ctx, cfg.l2ChainID); err != nil {
+			return err
+		}
+		// The Safe is the sender of the calls that it executes
+		from := opts.From
+		if safe != nil {
+			from = cfg.safeAddress
+		}
+		if err := simulateUpdate(ctx, backend, from, cfg.gasPriceOracleAddress, "setGasPrice", updatedGasPrice); err != nil {
+			return err
+		}
+
 		if cfg.gasPrice == nil {
 			// Set the gas price manually to use legacy transactions
-			gasPrice, err := backend.SuggestGasPrice(context.Background())
+			gasPrice, err := backend.SuggestGasPrice(ctx)
 			if err != nil {
 				log.Error("cannot fetch gas price", "message", err)
 				return err
@@ -106,84 +159,330 @@ func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(ui
 			opts.GasPrice = cfg.gasPrice
 		}
 
-		// Query the current L2 gas price
-		currentPrice, err := contract.GasPrice(&bind.CallOpts{
-			Context: context.Background(),
-		})
-		if err != nil {
-			log.Error("cannot fetch current gas price", "message", err)
-			return err
-		}
-
-		// no need to update when they are the same
-		if currentPrice.Uint64() == updatedGasPrice {
-			log.Info("gas price did not change", "gas-price", updatedGasPrice)
-			txNotSignificantCounter.Inc(1)
-			return nil
-		}
-
-		// Only update the gas price when it must be changed by at least
-		// a paramaterizable amount.
-		if !isDifferenceSignificant(currentPrice.Uint64(), updatedGasPrice, cfg.l2GasPriceSignificanceFactor) {
-			log.Info("gas price did not significantly change", "min-factor", cfg.l2GasPriceSignificanceFactor,
-				"current-price", currentPrice, "next-price", updatedGasPrice)
-			txNotSignificantCounter.Inc(1)
-			return nil
-		}
-
 		// Set the gas price by sending a transaction
-		tx, err := contract.SetGasPrice(opts, new(big.Int).SetUint64(updatedGasPrice))
-		if err != nil {
+		opts.Context = ctx
+		var tx *types.Transaction
+		var err error
+		if safe != nil {
+			data, err := gasPriceOracleABI.Pack("setGasPrice", updatedGasPrice)
+			if err != nil {
+				return err
+			}
+			if tx, err = safe.submit(ctx, opts, data); err != nil {
+				return err
+			}
+			if tx == nil {
+				// The update was proposed, the owners of the Safe execute it
+				limiter.record(time.Now())
+				return nil
+			}
+		} else if tx, err = contract.SetGasPrice(opts, updatedGasPrice); err != nil {
 			return err
 		}
 
 		log.Debug("updating L2 gas price", "tx.gasPrice", tx.GasPrice(), "tx.gasLimit", tx.Gas(),
 			"tx.data", hexutil.Encode(tx.Data()), "tx.to", tx.To().Hex(), "tx.nonce", tx.Nonce())
+		_, sendSpan := startSpan(ctx, "SendTransaction",
+			attribute.String("tx.hash", tx.Hash().Hex()))
 		pre := time.Now()
-		if err := backend.SendTransaction(context.Background(), tx); err != nil {
+		cfg.checkpoint.recordSigned(tx, updatedGasPrice)
+		err = backend.SendTransaction(ctx, tx)
+		endSpan(sendSpan, err)
+		if err != nil {
 			return err
 		}
 		txSendTimer.Update(time.Since(pre))
+		limiter.record(time.Now())
 		log.Info("L2 gas price transaction sent", "hash", tx.Hash().Hex())
+		updateTxMetrics(tx, time.Now())
+		cfg.auditLog.record(AuditKindL2GasPrice, tx, updatedGasPrice)
+		publishTransaction(cfg, AuditKindL2GasPrice, tx, updatedGasPrice)
+		cfg.updateWatcher.recordSent(tx.Hash())
+		cfg.controls.observeSent(tx.Hash())
+		event := decision.Event(notify.EventUpdateSent, cfg.l2ChainID)
+		event.TxHash = tx.Hash().Hex()
+		cfg.notifier.Notify(event)
 
-		gasPriceGauge.Update(int64(updatedGasPrice))
+		gasPriceGauge.Update(int64(updatedGasPrice.Uint64()))
 		txSendCounter.Inc(1)
+		cfg.chainMetrics.recordSent(updatedGasPrice)
 
 		if cfg.waitForReceipt {
 			// Keep track of the time it takes to confirm the transaction
 			pre := time.Now()
 			// Wait for the receipt
-			receipt, err := waitForReceipt(backend, tx)
+			_, receiptSpan := startSpan(ctx, "WaitForReceipt",
+				attribute.String("tx.hash", tx.Hash().Hex()))
+			receipt, err := waitForReceipt(ctx, backend, tx)
+			if err == nil {
+				receiptSpan.SetAttributes(attribute.Int64("tx.gas_used", int64(receipt.GasUsed)))
+			}
+			endSpan(receiptSpan, err)
 			if err != nil {
 				return err
 			}
 			txConfTimer.Update(time.Since(pre))
+			publishReceipt(cfg, receipt)
+			cfg.checkpoint.recordReceipt(receipt)
 
 			log.Info("L2 gas price transaction confirmed", "hash", tx.Hash().Hex(),
 				"gas-used", receipt.GasUsed, "blocknumber", receipt.BlockNumber)
 		}
 		return nil
+	}
+
+	return func(updatedGasPrice *big.Int) (err error) {
+		log.Trace("UpdateL2GasPriceFn", "gas-price", updatedGasPrice)
+		ctx, span := startSpan(epoch.context(), "UpdateL2GasPrice")
+		defer func() { endSpan(span, err) }()
+
+		avgGasPerSecond := epoch.get()
+		if err := checkComputedPrice(cfg, updatedGasPrice, avgGasPerSecond); err != nil {
+			return err
+		}
+
+		// Query the current L2 gas price
+		currentPrice, err := contract.GasPrice(&bind.CallOpts{
+			Context: ctx,
+		})
+		if err != nil {
+			log.Error("cannot fetch current gas price", "message", err)
+			return err
+		}
+
+		_, decideSpan := startSpan(ctx, "DecideL2GasPrice")
+		decision := decideL2GasPrice(cfg, limiter, currentPrice, updatedGasPrice, avgGasPerSecond, time.Now())
+		// The forced flag is taken even when the update would be sent anyway
+		if cfg.controls.takeForced() && !decision.Send && decision.Reason != ReasonUnchanged {
+			decision.Send = true
+			decision.Reason = ReasonForced
+		}
+		paused := cfg.controls.isPaused()
+		standby := !cfg.leader.isLeader()
+		if decision.Send && cfg.dryRun {
+			decision.Reason = ReasonDryRun
+		} else if decision.Send && paused {
+			decision.Reason = ReasonPaused
+		} else if decision.Send && standby {
+			decision.Reason = ReasonStandby
+		} else if decision.Send && cfg.approvals != nil {
+			decision.Reason = ReasonStaged
+		}
+		if standby {
+			cfg.leader.observeStandby(decision)
+		}
+		decideSpan.SetAttributes(
+			attribute.String("gas_price.current", currentPrice.String()),
+			attribute.String("gas_price.computed", updatedGasPrice.String()),
+			attribute.String("gas_price.decided", decision.GasPrice.String()),
+			attribute.String("decision.reason", string(decision.Reason)),
+			attribute.Bool("decision.send", decision.Send),
+		)
+		decideSpan.End()
+		decision.Log()
+		cfg.chainMetrics.observeDecision(decision)
+		cfg.controls.observeDecision(decision)
+		recordDecision(cfg, decision)
+		publishDecision(cfg, decision)
+		if decision.Capped() && !capped {
+			cfg.notifier.Notify(decision.Event(notify.EventUpdateSkipped, cfg.l2ChainID))
+		}
+		capped = decision.Capped()
+		if !decision.Send {
+			return nil
+		}
+		updatedGasPrice = decision.GasPrice
+
+		if cfg.dryRun {
+			dryRunGasPriceGauge.Update(int64(updatedGasPrice.Uint64()))
+			txDryRunCounter.Inc(1)
+			limiter.record(time.Now())
+			return nil
+		}
+		if paused {
+			log.Warn("Updates are paused, not sending the L2 gas price", "gas-price", updatedGasPrice)
+			return nil
+		}
+		if standby {
+			log.Info("Replica is on standby, not sending the L2 gas price", "gas-price", updatedGasPrice)
+			return nil
+		}
+
+		// The last check before the transaction is built, whatever the
+		// pricer and the decision did
+		if err := checkSanityBounds(cfg, decision); err != nil {
+			return err
+		}
+
+		if cfg.approvals != nil {
+			// A second operator approves the update before it is sent
+			stageUpdate(cfg, decision, func(ctx context.Context) error {
+				return send(ctx, decision)
+			})
+			return nil
+		}
+		return send(ctx, decision)
 	}, nil
 }
 
+// newTransactOpts creates the options used to sign transactions that update
+// the GasPriceOracle. The transactions are not sent by the contract bindings
+// so that they can be inspected beforehand.
+func newTransactOpts(cfg *Config) (*bind.TransactOpts, error) {
+	return newTransactOptsForChain(cfg, cfg.l2ChainID)
+}
+
+// newTransactOptsForChain creates the options to sign transactions of the
+// chain. A configured signer function signs for any chain.
+func newTransactOptsForChain(cfg *Config, chainID *big.Int) (*bind.TransactOpts, error) {
+	if !cfg.hasSigner() {
+		return nil, errNoPrivateKey
+	}
+	if chainID == nil {
+		return nil, errNoChainID
+	}
+
+	var opts *bind.TransactOpts
+	if cfg.signerFn != nil {
+		opts = &bind.TransactOpts{From: cfg.signerAddress, Signer: cfg.signerFn}
+	} else {
+		var err error
+		opts, err = bind.NewKeyedTransactorWithChainID(cfg.privateKey, chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Once https://github.com/ethereum/go-ethereum/pull/23062 is released
+	// then we can remove setting the context here
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+	opts.NoSend = true
+	return opts, nil
+}
+
+// rateLimiter ensures that no more than one update is sent per interval
+type rateLimiter struct {
+	interval time.Duration
+	last     time.Time
+}
+
+// allow returns true if an update can be sent at the given time along with
+// the time elapsed since the last update
+func (r *rateLimiter) allow(now time.Time) (bool, time.Duration) {
+	if r.interval <= 0 || r.last.IsZero() {
+		return true, 0
+	}
+	elapsed := now.Sub(r.last)
+	return elapsed >= r.interval, elapsed
+}
+
+// record marks that an update was sent at the given time
+func (r *rateLimiter) record(now time.Time) {
+	r.last = now
+}
+
+// decideL2GasPrice rounds and caps the gas price computed by the gas pricer
+// and then decides if it is worth sending given the current L2 gas price.
+// The GasPrice of the returned Decision is what should be sent.
+func decideL2GasPrice(cfg *Config, limiter *rateLimiter, currentPrice, computedPrice *big.Int, avgGasPerSecond float64, now time.Time) *Decision {
+	d := &Decision{
+		Time:            now,
+		AvgGasPerSecond: avgGasPerSecond,
+		CurrentPrice:    currentPrice,
+		ComputedPrice:   computedPrice,
+	}
+
+	// Round before comparing so that noise below the rounding
+	// precision does not trigger an update
+	updatedGasPrice := cfg.gasPriceRounding.Round(computedPrice)
+	// Rounding up must never push the gas price past the hard cap
+	if cfg.maxGasPrice != nil && updatedGasPrice.Cmp(cfg.maxGasPrice) > 0 {
+		log.Warn("gas price exceeds max gas price", "gas-price", updatedGasPrice,
+			"max-gas-price", cfg.maxGasPrice)
+		updatedGasPrice = new(big.Int).Set(cfg.maxGasPrice)
+	}
+	d.GasPrice = updatedGasPrice
+	if cfg.maxGasPrice != nil && updatedGasPrice.Cmp(cfg.maxGasPrice) == 0 {
+		d.Bound = ReasonClampedMax
+	} else if cfg.floorPrice != nil && updatedGasPrice.Cmp(cfg.floorPrice) <= 0 {
+		d.Bound = ReasonFloored
+	}
+
+	// no need to update when they are the same
+	if currentPrice.Cmp(updatedGasPrice) == 0 {
+		txNotSignificantCounter.Inc(1)
+		d.Reason = ReasonUnchanged
+		return d
+	}
+
+	// Only update the gas price when it must be changed by at least
+	// a paramaterizable amount.
+	d.SignificanceFactor = cfg.l2GasPriceSignificanceFactorFor(currentPrice, updatedGasPrice)
+	change, _ := relativeDifference(currentPrice, updatedGasPrice).Float64()
+	d.Change = change
+	if !isDifferenceSignificant(currentPrice, updatedGasPrice, d.SignificanceFactor) {
+		txNotSignificantCounter.Inc(1)
+		d.Reason = ReasonBelowSignificance
+		return d
+	}
+
+	// Never send more than one update per minimum update interval
+	if ok, elapsed := limiter.allow(now); !ok {
+		log.Debug("gas price update rate limited", "elapsed", elapsed,
+			"min-update-interval", cfg.minUpdateInterval)
+		txRateLimitedCounter.Inc(1)
+		d.Reason = ReasonRateLimited
+		return d
+	}
+	d.Send = true
+	d.Reason = ReasonUpdated
+	return d
+}
+
+// relativeDifference returns 1 - (min/max) where min and max are the gas
+// prices, or 0 when both are zero
+func relativeDifference(a, b *big.Int) *big.Rat {
+	max, min := a, b
+	if min.Cmp(max) > 0 {
+		max, min = min, max
+	}
+	// Both values are zero so there is no difference
+	if max.Sign() == 0 {
+		return new(big.Rat)
+	}
+	factor := new(big.Rat).SetFrac(min, max)
+	return factor.Sub(big.NewRat(1, 1), factor)
+}
+
 // Only update the gas price when it must be changed by at least
 // a paramaterizable amount. If the param is greater than the result
 // of 1 - (min/max) where min and max are the gas prices then do not
-// update the gas price
-func isDifferenceSignificant(a, b uint64, c float64) bool {
-	max := max(a, b)
-	min := min(a, b)
-	factor := 1 - (float64(min) / float64(max))
-	return c <= factor
+// update the gas price. The factor is computed exactly, so it is not
+// affected by the precision of large wei values.
+func isDifferenceSignificant(a, b *big.Int, c float64) bool {
+	// Both values are zero so there is no difference
+	if a.Sign() == 0 && b.Sign() == 0 {
+		return false
+	}
+	threshold, err := gasprices.NewRatFromFloat(c)
+	if err != nil {
+		return false
+	}
+	return threshold.Cmp(relativeDifference(a, b)) <= 0
 }
 
-// Wait for the receipt by polling the backend
-func waitForReceipt(backend DeployContractBackend, tx *types.Transaction) (*types.Receipt, error) {
+// Wait for the receipt by polling the backend until the context is done
+func waitForReceipt(ctx context.Context, backend DeployContractBackend, tx *types.Transaction) (*types.Receipt, error) {
 	t := time.NewTicker(300 * time.Millisecond)
-	receipt := new(types.Receipt)
-	var err error
-	for range t.C {
-		receipt, err = backend.TransactionReceipt(context.Background(), tx.Hash())
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		receipt, err := backend.TransactionReceipt(ctx, tx.Hash())
 		if errors.Is(err, ethereum.NotFound) {
 			continue
 		}
@@ -191,23 +490,7 @@ func waitForReceipt(backend DeployContractBackend, tx *types.Transaction) (*type
 			return nil, err
 		}
 		if receipt != nil {
-			t.Stop()
-			break
+			return receipt, nil
 		}
 	}
-	return receipt, nil
-}
-
-func max(a, b uint64) uint64 {
-	if a >= b {
-		return a
-	}
-	return b
-}
-
-func min(a, b uint64) uint64 {
-	if a >= b {
-		return b
-	}
-	return a
 }
@@ -5,8 +5,11 @@ import (
 	"crypto/ecdsa"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/core"
@@ -21,7 +24,7 @@ func TestWrapGetLatestBlockNumberFn(t *testing.T) {
 	sim, db := newSimulatedBackend(key)
 	chain := sim.Blockchain()
 
-	getLatest := wrapGetLatestBlockNumberFn(sim)
+	getLatest := wrapGetLatestBlockNumberFn(nil, &demandHead{backend: sim, tag: "latest"})
 
 	// Generate a valid chain of 10 blocks
 	blocks, _ := core.GenerateChain(chain.Config(), chain.CurrentBlock(), chain.Engine(), db, 10, nil)
@@ -51,6 +54,37 @@ func TestWrapGetLatestBlockNumberFn(t *testing.T) {
 	}
 }
 
+func TestWrapGetBlockUsage(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	_, tx, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	receipt, err := sim.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := wrapGetBlockUsage(nil, sim)(big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.GasUsed != receipt.GasUsed {
+		t.Fatalf("expected gas used %d, got %d", receipt.GasUsed, usage.GasUsed)
+	}
+	if usage.TxCount != 1 {
+		t.Fatalf("expected 1 tx, got %d", usage.TxCount)
+	}
+	if usage.CalldataBytes != uint64(len(tx.Data())) {
+		t.Fatalf("expected %d calldata bytes, got %d", len(tx.Data()), usage.CalldataBytes)
+	}
+}
+
 func TestWrapUpdateL2GasPriceFn(t *testing.T) {
 	key, _ := crypto.GenerateKey()
 	sim, _ := newSimulatedBackend(key)
@@ -69,13 +103,13 @@ func TestWrapUpdateL2GasPriceFn(t *testing.T) {
 		gasPrice:              big.NewInt(783460975),
 	}
 
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	for i := uint64(0); i < 10; i++ {
-		err := updateL2GasPriceFn(i)
+		err := updateL2GasPriceFn(new(big.Int).SetUint64(i))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -110,7 +144,7 @@ func TestWrapUpdateL2GasPriceFnNoUpdates(t *testing.T) {
 		// the new gas price must change be 50% for it to actually update
 		l2GasPriceSignificanceFactor: 0.5,
 	}
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -124,7 +158,7 @@ func TestWrapUpdateL2GasPriceFnNoUpdates(t *testing.T) {
 		}
 
 		// Call the updateL2GasPriceFn and commit the state
-		if err := updateL2GasPriceFn(price); err != nil {
+		if err := updateL2GasPriceFn(new(big.Int).SetUint64(price)); err != nil {
 			t.Fatal(err)
 		}
 		sim.Commit()
@@ -167,6 +201,245 @@ func TestWrapUpdateL2GasPriceFnNoUpdates(t *testing.T) {
 	tryUpdate(1, true)
 }
 
+func TestWrapUpdateL2GasPriceFnAsymmetricSignificance(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	increase, decrease := 0.1, 0.5
+	cfg := &Config{
+		privateKey:                           key,
+		l2ChainID:                            big.NewInt(1337),
+		gasPriceOracleAddress:                addr,
+		gasPrice:                             big.NewInt(875000000),
+		l2GasPriceSignificanceFactor:         0.25,
+		l2GasPriceIncreaseSignificanceFactor: &increase,
+		l2GasPriceDecreaseSignificanceFactor: &decrease,
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		price  uint64
+		expect uint64
+	}{
+		{price: 100, expect: 100},
+		// Increases of at least 10% are significant
+		{price: 105, expect: 100},
+		{price: 112, expect: 112},
+		// Decreases must be at least 50%
+		{price: 70, expect: 112},
+		{price: 55, expect: 55},
+	}
+	for _, tc := range tests {
+		if err := updateL2GasPriceFn(new(big.Int).SetUint64(tc.price)); err != nil {
+			t.Fatal(err)
+		}
+		sim.Commit()
+		gasPrice, err := gpo.GasPrice(&bind.CallOpts{Context: context.Background()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gasPrice.Uint64() != tc.expect {
+			t.Fatalf("update to %d: expected %d, got %d", tc.price, tc.expect, gasPrice)
+		}
+	}
+}
+
+func TestWrapUpdateL2GasPriceFnRounding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	rounding, err := gasprices.NewRounding(gasprices.RoundingIncrement, big.NewInt(1000), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+		gasPriceRounding:      rounding,
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := updateL2GasPriceFn(big.NewInt(1001)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	gasPrice, err := gpo.GasPrice(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.Uint64() != 2000 {
+		t.Fatalf("gas price not rounded, got %d", gasPrice)
+	}
+}
+
+func TestWrapUpdateL2GasPriceFnRateLimit(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+		minUpdateInterval:     time.Hour,
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first update is always sent
+	if err := updateL2GasPriceFn(big.NewInt(10)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	// The second update is within the minimum update interval
+	if err := updateL2GasPriceFn(big.NewInt(20)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	gasPrice, err := gpo.GasPrice(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.Uint64() != 10 {
+		t.Fatalf("update was not rate limited, got %d", gasPrice)
+	}
+}
+
+func TestWrapUpdateL2GasPriceFnDryRun(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	// No private key is required for a dry run
+	cfg := &Config{
+		gasPriceOracleAddress:        addr,
+		l2GasPriceSignificanceFactor: 0.05,
+		dryRun:                       true,
+	}
+
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	gasPrice, err := gpo.GasPrice(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.Sign() != 0 {
+		t.Fatalf("dry run updated the gas price to %d", gasPrice)
+	}
+	nonce, err := sim.PendingNonceAt(context.Background(), opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 1 {
+		t.Fatalf("dry run sent a transaction")
+	}
+}
+
+func TestWrapUpdateL2GasPriceFnPaused(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	cfg := &Config{
+		privateKey:                   key,
+		l2ChainID:                    big.NewInt(1337),
+		gasPriceOracleAddress:        addr,
+		gasPrice:                     big.NewInt(783460975),
+		l2GasPriceSignificanceFactor: 0.05,
+	}
+	cfg.controls = newControls(cfg)
+	cfg.controls.setPaused(true)
+
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if cfg.controls.lastDecision == nil || cfg.controls.lastDecision.Reason != ReasonPaused {
+		t.Fatalf("unexpected decision %+v", cfg.controls.lastDecision)
+	}
+
+	gasPrice, err := gpo.GasPrice(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.Sign() != 0 {
+		t.Fatalf("paused oracle updated the gas price to %d", gasPrice)
+	}
+
+	// Once resumed the update is sent
+	cfg.controls.setPaused(false)
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	gasPrice, err = gpo.GasPrice(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.Uint64() != 100 {
+		t.Fatalf("expected the gas price to be updated, got %d", gasPrice)
+	}
+	if cfg.controls.updates != 1 || len(cfg.controls.pending) != 1 {
+		t.Fatalf("expected the update to be recorded, got %d and %v", cfg.controls.updates, cfg.controls.pending)
+	}
+}
+
 func TestIsDifferenceSignificant(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -179,11 +452,14 @@ func TestIsDifferenceSignificant(t *testing.T) {
 		{name: "test 2", a: 4, b: 1, sig: 0.25, expect: true},
 		{name: "test 3", a: 3, b: 1, sig: 0.1, expect: true},
 		{name: "test 4", a: 4, b: 1, sig: 0.9, expect: false},
+		{name: "test 5", a: 0, b: 0, sig: 0.05, expect: false},
+		{name: "test 6", a: 0, b: 1, sig: 0.05, expect: true},
+		{name: "test 7", a: 100, b: 90, sig: 0.1, expect: true},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := isDifferenceSignificant(tc.a, tc.b, tc.sig)
+			result := isDifferenceSignificant(new(big.Int).SetUint64(tc.a), new(big.Int).SetUint64(tc.b), tc.sig)
 			if result != tc.expect {
 				t.Fatalf("mismatch %s", tc.name)
 			}
@@ -200,3 +476,98 @@ func newSimulatedBackend(key *ecdsa.PrivateKey) (*backends.SimulatedBackend, eth
 	sim := backends.NewSimulatedBackendWithDatabase(db, genAlloc, gasLimit)
 	return sim, db
 }
+
+type chanNotifier chan *notify.Event
+
+func (c chanNotifier) Notify(ctx context.Context, event *notify.Event) error {
+	c <- event
+	return nil
+}
+
+func TestWrapUpdateL2GasPriceFnNotifies(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, _, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	events := make(chanNotifier, 1)
+	cfg := &Config{
+		privateKey:            key,
+		l2ChainID:             big.NewInt(1337),
+		gasPriceOracleAddress: addr,
+		gasPrice:              big.NewInt(875000000),
+		maxGasPrice:           big.NewInt(100),
+		notifier:              notify.NewDispatcher(events),
+	}
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := func() *notify.Event {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("event not delivered")
+		}
+		return nil
+	}
+
+	if err := updateL2GasPriceFn(big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	event := next()
+	if event.Type != notify.EventUpdateSent || event.TxHash == "" || event.GasPrice.Uint64() != 100 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	// The gas price is already at the max gas price
+	if err := updateL2GasPriceFn(big.NewInt(200)); err != nil {
+		t.Fatal(err)
+	}
+	event = next()
+	if event.Type != notify.EventUpdateSkipped || event.Bound != string(ReasonClampedMax) {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if event.ChainID.Uint64() != 1337 {
+		t.Fatalf("unexpected chain id %d", event.ChainID)
+	}
+
+	// The price stays at the cap, which was notified already
+	if err := updateL2GasPriceFn(big.NewInt(300)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The price leaves the cap and reaches it again
+	if err := updateL2GasPriceFn(big.NewInt(50)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if event := next(); event.Type != notify.EventUpdateSent {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if err := updateL2GasPriceFn(big.NewInt(200)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if event := next(); event.Type != notify.EventUpdateSent || event.GasPrice.Uint64() != 100 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if err := updateL2GasPriceFn(big.NewInt(200)); err != nil {
+		t.Fatal(err)
+	}
+	if event := next(); event.Type != notify.EventUpdateSkipped {
+		t.Fatalf("expected the cap to be notified again, got %+v", event)
+	}
+}
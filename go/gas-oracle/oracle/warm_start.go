@@ -0,0 +1,126 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// gasPriceUpdate is a GasPriceUpdated event of the gas price oracle
+type gasPriceUpdate struct {
+	block    uint64
+	time     time.Time
+	gasPrice *big.Int
+}
+
+// warmStart is the recent trajectory of the L2 gas price that was rebuilt
+// from the updates on chain when the oracle started, so that the rate limit,
+// the daily change budget and the stale update alarm do not start from a
+// blank slate
+type warmStart struct {
+	// updates are in the order that they were emitted
+	updates []gasPriceUpdate
+}
+
+// fetchWarmStart scans the last warmStartBlocks blocks up to the tip for
+// updates of the L2 gas price. It returns nil when the warm start is
+// disabled.
+func fetchWarmStart(ctx context.Context, cfg *Config, backend L2Backend, tip uint64) (*warmStart, error) {
+	if cfg.warmStartBlocks == 0 {
+		return nil, nil
+	}
+	start := uint64(0)
+	if tip >= cfg.warmStartBlocks {
+		start = tip - cfg.warmStartBlocks + 1
+	}
+	contract, err := bindings.NewGasPriceOracle(cfg.gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	w := new(warmStart)
+	timestamps := make(map[uint64]uint64)
+	for from := start; from <= tip; from += historyChunkSize {
+		to := from + historyChunkSize - 1
+		if to > tip {
+			to = tip
+		}
+		log.Debug("Scanning gas price updates", "start", from, "end", to)
+		iter, err := contract.FilterGasPriceUpdated(&bind.FilterOpts{Start: from, End: &to, Context: ctx})
+		if err != nil {
+			return nil, err
+		}
+		for iter.Next() {
+			block := iter.Event.Raw.BlockNumber
+			timestamp, ok := timestamps[block]
+			if !ok {
+				header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+				if err != nil {
+					iter.Close()
+					return nil, err
+				}
+				timestamp = header.Time
+				timestamps[block] = timestamp
+			}
+			w.updates = append(w.updates, gasPriceUpdate{
+				block:    block,
+				time:     time.Unix(int64(timestamp), 0),
+				gasPrice: iter.Event.Arg0,
+			})
+		}
+		iter.Close()
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+	}
+	log.Info("Scanned recent gas price updates", "start", start, "end", tip, "updates", len(w.updates))
+	return w, nil
+}
+
+// last returns the latest update, nil when there was none
+func (w *warmStart) last() *gasPriceUpdate {
+	if w == nil || len(w.updates) == 0 {
+		return nil
+	}
+	return &w.updates[len(w.updates)-1]
+}
+
+// lastUpdateTime returns the time of the latest update, the zero time when
+// there was none
+func (w *warmStart) lastUpdateTime() time.Time {
+	last := w.last()
+	if last == nil {
+		return time.Time{}
+	}
+	return last.time
+}
+
+// pastPriceRecorder is implemented by the pricers with a daily change budget
+type pastPriceRecorder interface {
+	RecordPastPrice(price *big.Int, at time.Time)
+}
+
+// apply seeds the daily change budget of the pricer and the stale update
+// monitor with the updates. The rate limiter is seeded when the update
+// function is created.
+func (w *warmStart) apply(cfg *Config, pricer gasprices.Pricer) {
+	last := w.last()
+	if last == nil {
+		return
+	}
+	if recorder, ok := pricer.(pastPriceRecorder); ok {
+		for _, u := range w.updates {
+			recorder.RecordPastPrice(u.gasPrice, u.time)
+		}
+	}
+	if cfg.staleUpdates != nil {
+		cfg.staleUpdates.landed(last.gasPrice, last.block, last.time)
+	}
+	log.Info("Warm started from the last gas price update", "gas-price", last.gasPrice,
+		"block", last.block, "time", last.time)
+}
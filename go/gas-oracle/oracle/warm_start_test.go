@@ -0,0 +1,95 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/bindings"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestWarmStart(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	for _, price := range []int64{100, 200} {
+		if _, err := gpo.SetGasPrice(opts, big.NewInt(price)); err != nil {
+			t.Fatal(err)
+		}
+		sim.Commit()
+	}
+	// An empty block after the updates
+	sim.Commit()
+
+	cfg := &Config{gasPriceOracleAddress: addr, minUpdateInterval: time.Hour}
+	w, err := fetchWarmStart(context.Background(), cfg, sim, 4)
+	if err != nil || w != nil {
+		t.Fatalf("expected no warm start when disabled, got %v, %v", w, err)
+	}
+
+	// The first update is outside of the scanned blocks
+	cfg.warmStartBlocks = 2
+	w, err = fetchWarmStart(context.Background(), cfg, sim, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.updates) != 1 {
+		t.Fatalf("expected 1 update, got %d", len(w.updates))
+	}
+	cfg.warmStartBlocks = 100
+	w, err = fetchWarmStart(context.Background(), cfg, sim, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.updates) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(w.updates))
+	}
+	last := w.last()
+	if last.block != 3 || last.gasPrice.Uint64() != 200 || last.time.IsZero() {
+		t.Fatalf("unexpected last update %+v", last)
+	}
+
+	// The stale update monitor starts from the last update
+	cfg.staleUpdateEpochs = 10
+	cfg.staleUpdates = newStaleUpdateMonitor(cfg)
+	target := func() float64 { return 11_000_000 }
+	pricer, err := gasprices.NewGasPricer(big.NewInt(200), big.NewInt(1), target, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.apply(cfg, pricer)
+	if cfg.staleUpdates.lastUpdateBlock != 3 || cfg.staleUpdates.lastPrice.Uint64() != 200 {
+		t.Fatalf("unexpected stale update monitor %+v", cfg.staleUpdates)
+	}
+
+	// An update is rate limited by the last update on chain
+	cfg.warmStart = &warmStart{updates: []gasPriceUpdate{{block: 3, time: time.Now(), gasPrice: big.NewInt(200)}}}
+	cfg.privateKey = key
+	cfg.l2ChainID = big.NewInt(1337)
+	cfg.floorPrice = big.NewInt(1)
+	cfg.enableL2GasPrice = true
+	updateL2GasPrice, err := wrapUpdateL2GasPriceFn(sim, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := updateL2GasPrice(big.NewInt(400)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	price, err := gpo.GasPrice(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Uint64() != 200 {
+		t.Fatalf("expected the update to be rate limited, got %d", price)
+	}
+}
@@ -0,0 +1,105 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/go/gas-oracle/metrics"
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/reporting"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	watchdogTimeoutCounter = metrics.NewRegisteredCounter("watchdog/timeout", ometrics.DefaultRegistry)
+	watchdogStuckCounter   = metrics.NewRegisteredCounter("watchdog/stuck", ometrics.DefaultRegistry)
+)
+
+// errEpochStuck represents the error when an epoch did not return after it
+// was cancelled
+var errEpochStuck = errors.New("epoch did not return after it was cancelled")
+
+const (
+	// watchdogGrace is how long a cancelled epoch has to return before it
+	// is considered stuck
+	watchdogGrace = 5 * time.Second
+	// watchdogRestarts is the number of times an epoch that exceeded the
+	// deadline is restarted
+	watchdogRestarts = 1
+)
+
+// watchdog bounds the time that an epoch can take. An epoch that exceeds
+// the deadline, for example because a request hangs, is cancelled and
+// restarted so that the loop keeps updating the gas price.
+type watchdog struct {
+	deadline time.Duration
+	grace    time.Duration
+	// pending receives the result of an epoch that did not return after
+	// it was cancelled. No epoch is started until it returns since the
+	// gas price updater is still in use.
+	pending chan error
+}
+
+func newWatchdog(cfg *Config) *watchdog {
+	deadline := cfg.epochDeadline
+	if deadline <= 0 {
+		deadline = cfg.epochLength
+	}
+	return &watchdog{deadline: deadline, grace: watchdogGrace}
+}
+
+// run runs the epoch under the watchdog, a nil watchdog runs it without a
+// deadline
+func (w *watchdog) run(parent context.Context, epoch func(context.Context) error) error {
+	if w == nil {
+		return epoch(parent)
+	}
+	if w.pending != nil {
+		select {
+		case <-w.pending:
+			log.Info("Stuck epoch returned")
+			w.pending = nil
+		default:
+			watchdogStuckCounter.Inc(1)
+			return errEpochStuck
+		}
+	}
+	for restarts := 0; ; restarts++ {
+		timedOut, err := w.attempt(parent, epoch)
+		if !timedOut {
+			return err
+		}
+		watchdogTimeoutCounter.Inc(1)
+		if w.pending != nil || restarts == watchdogRestarts {
+			return err
+		}
+		log.Warn("Epoch exceeded the deadline, restarting", "deadline", w.deadline, "message", err)
+	}
+}
+
+// attempt runs the epoch once, returning true when it exceeded the deadline
+func (w *watchdog) attempt(parent context.Context, epoch func(context.Context) error) (bool, error) {
+	ctx, cancel := context.WithTimeout(parent, w.deadline)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		defer reporting.Recover()
+		done <- epoch(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded), err
+	case <-ctx.Done():
+	}
+	timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+	select {
+	case err := <-done:
+		return timedOut, err
+	case <-time.After(w.grace):
+		log.Error("Epoch did not return after it was cancelled", "deadline", w.deadline)
+		w.pending = done
+		return timedOut, errEpochStuck
+	}
+}
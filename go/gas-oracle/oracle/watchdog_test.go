@@ -0,0 +1,65 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	var w *watchdog
+	if err := w.run(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	w = &watchdog{deadline: 10 * time.Millisecond, grace: 10 * time.Millisecond}
+
+	// An epoch that hangs until it is cancelled is restarted
+	calls := 0
+	err := w.run(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+
+	// An epoch is restarted once
+	calls = 0
+	err = w.run(context.Background(), func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || calls != 2 {
+		t.Fatalf("expected the deadline to be exceeded twice, got %v after %d calls", err, calls)
+	}
+
+	// An epoch that ignores the cancellation blocks the next epochs until
+	// it returns
+	release := make(chan struct{})
+	err = w.run(context.Background(), func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	if !errors.Is(err, errEpochStuck) {
+		t.Fatalf("expected a stuck epoch, got %v", err)
+	}
+	next := func(context.Context) error { return nil }
+	if err := w.run(context.Background(), next); !errors.Is(err, errEpochStuck) {
+		t.Fatalf("expected a stuck epoch, got %v", err)
+	}
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+	if err := w.run(context.Background(), next); err != nil {
+		t.Fatal(err)
+	}
+}
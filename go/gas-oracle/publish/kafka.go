@@ -0,0 +1,79 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// kafkaJSONContentType is the content type of the JSON embedded format of
+// the v2 API of the Kafka REST proxy
+const kafkaJSONContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaREST publishes messages to a topic through a Kafka REST proxy, which
+// does not need a Kafka client in the oracle
+type KafkaREST struct {
+	url    string
+	client *http.Client
+}
+
+// NewKafkaREST creates a KafkaREST that produces to the topic of the proxy
+// at baseURL
+func NewKafkaREST(baseURL, topic string) *KafkaREST {
+	return &KafkaREST{
+		url:    strings.TrimSuffix(baseURL, "/") + "/topics/" + url.PathEscape(topic),
+		client: new(http.Client),
+	}
+}
+
+type kafkaRecord struct {
+	Key   string   `json:"key,omitempty"`
+	Value *Message `json:"value"`
+}
+
+// Publish produces the message as a record keyed by the chain ID, so that
+// the records of a chain stay ordered within a partition
+func (k *KafkaREST) Publish(ctx context.Context, msg *Message) error {
+	record := kafkaRecord{Value: msg}
+	if msg.ChainID != nil {
+		record.Key = msg.ChainID.String()
+	}
+	body, err := json.Marshal(map[string][]kafkaRecord{"records": {record}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaJSONContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("kafka REST proxy returned status %d", resp.StatusCode)
+	}
+	// The proxy reports the failure of a record in its offset
+	var result struct {
+		Offsets []struct {
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil
+	}
+	for _, offset := range result.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("kafka REST proxy error %d: %s", *offset.ErrorCode, offset.Error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/nats-io/nats.go"
+)
+
+// natsHandshakeTimeout bounds the time to connect to a NATS server
+const natsHandshakeTimeout = 5 * time.Second
+
+// NATS publishes messages to a subject of a NATS server. The connection is
+// opened on the first message, the client reconnects after it fails.
+type NATS struct {
+	url     string
+	subject string
+
+	mu   sync.Mutex
+	conn *nats.Conn
+}
+
+// NewNATS creates a NATS that publishes to the subject of the server at the
+// url, a tls:// url uses TLS and the user info of the url authenticates
+func NewNATS(rawURL, subject string) (*NATS, error) {
+	if err := checkSubject(subject); err != nil {
+		return nil, err
+	}
+	return &NATS{url: rawURL, subject: subject}, nil
+}
+
+// checkSubject returns an error when the subject cannot be published to,
+// it is made of tokens separated by dots without wildcards or white space
+func checkSubject(subject string) error {
+	for _, token := range strings.Split(subject, ".") {
+		if token == "" {
+			return fmt.Errorf("invalid subject %q, empty token", subject)
+		}
+		if strings.ContainsAny(token, "*> \t\r\n") {
+			return fmt.Errorf("invalid subject %q, wildcard or white space", subject)
+		}
+	}
+	return nil
+}
+
+// Publish publishes the message encoded as JSON, and flushes it so that the
+// errors of the server are returned
+func (n *NATS) Publish(ctx context.Context, msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// A connection that the server closed is not reconnected by the client
+	if n.conn == nil || n.conn.IsClosed() {
+		conn, err := nats.Connect(n.url,
+			nats.Name("gas-oracle"),
+			nats.Timeout(natsHandshakeTimeout),
+			nats.MaxReconnects(-1),
+			nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+				log.Warn("NATS server error", "message", err)
+			}))
+		if err != nil {
+			return fmt.Errorf("cannot connect to NATS: %w", err)
+		}
+		n.conn = conn
+	}
+	// The server answers a rejected publish with an error before the pong
+	// of the flush
+	previous := n.conn.LastError()
+	if err := n.conn.Publish(n.subject, data); err != nil {
+		return err
+	}
+	if err := n.conn.FlushWithContext(ctx); err != nil {
+		return err
+	}
+	if err := n.conn.LastError(); err != nil && err != previous {
+		return err
+	}
+	return nil
+}
+
+// Close closes the connection
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn = nil
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/queue"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// MessageType is the kind of record that a message is published for
+type MessageType string
+
+const (
+	// MessageDecision is the decision of an epoch
+	MessageDecision MessageType = "decision"
+	// MessageTransaction is an update transaction that was sent
+	MessageTransaction MessageType = "transaction"
+	// MessageReceipt is the receipt of an update transaction
+	MessageReceipt MessageType = "receipt"
+)
+
+// Message is the envelope of a published record
+type Message struct {
+	Type    MessageType `json:"type"`
+	Time    time.Time   `json:"time"`
+	ChainID *big.Int    `json:"chainId,omitempty"`
+	// Decision is the decision record of the oracle
+	Decision    interface{}  `json:"decision,omitempty"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Receipt     *Receipt     `json:"receipt,omitempty"`
+}
+
+// Transaction is an update transaction that was sent
+type Transaction struct {
+	// Kind is the value that the transaction updates, l2_gas_price or
+	// l1_base_fee
+	Kind     string   `json:"kind"`
+	Hash     string   `json:"hash"`
+	Nonce    uint64   `json:"nonce"`
+	GasPrice *big.Int `json:"gasPrice"`
+	Value    *big.Int `json:"value"`
+}
+
+// Receipt is the receipt of an update transaction
+type Receipt struct {
+	Hash        string `json:"hash"`
+	BlockNumber uint64 `json:"blockNumber"`
+	GasUsed     uint64 `json:"gasUsed"`
+	Status      uint64 `json:"status"`
+}
+
+// Publisher delivers messages to a topic of a message bus
+type Publisher interface {
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// publishTimeout bounds the time spent publishing a single message so that a
+// slow bus cannot hold up the queue
+const publishTimeout = 10 * time.Second
+
+// queueSize is larger than the queue of the notifications, since every epoch
+// publishes its decision and each update its transaction and receipt
+const queueSize = 256
+
+// New creates the publisher of the url. A nats:// or tls:// url publishes to
+// the subject of the topic of a NATS server and an http:// or https:// url
+// publishes to the topic of a Kafka REST proxy.
+func New(rawURL, topic string) (Publisher, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("no topic")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "nats", "tls":
+		return NewNATS(rawURL, topic)
+	case "http", "https":
+		return NewKafkaREST(rawURL, topic), nil
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q, expected nats, tls, http or https", u.Scheme)
+	}
+}
+
+// Dispatcher publishes the messages of the oracle to the publishers from a
+// queue.Queue. The stores record the messages from a queue of their own,
+// which waits instead of dropping messages.
+type Dispatcher struct {
+	publishers []Publisher
+	queue      *queue.Queue
+	stores     []Publisher
+	storeQueue *queue.Queue
+}
+
+// NewDispatcher creates a Dispatcher and starts publishing messages to the
+// stores and the publishers
+func NewDispatcher(stores []Publisher, publishers ...Publisher) *Dispatcher {
+	d := &Dispatcher{publishers: publishers, stores: stores}
+	d.queue = queue.New(queueSize, func(item interface{}) { publishTo(d.publishers, item.(*Message)) })
+	d.storeQueue = queue.New(queueSize, func(item interface{}) { publishTo(d.stores, item.(*Message)) })
+	return d
+}
+
+// Publish queues a message. The message is dropped for the publishers when
+// their queue is full, and waits for room in the queue of the stores. A nil
+// Dispatcher drops all messages.
+func (d *Dispatcher) Publish(msg *Message) {
+	if d == nil {
+		return
+	}
+	if len(d.publishers) > 0 && !d.queue.Push(msg) {
+		log.Warn("Dropping published message", "type", msg.Type)
+	}
+	if len(d.stores) > 0 && !d.storeQueue.Push(msg) {
+		log.Warn("Waiting for the store to record earlier messages", "type", msg.Type)
+		d.storeQueue.Wait(msg)
+	}
+}
+
+// Close stops accepting messages and waits for the queued messages to be
+// published, the publishers that hold a connection are closed afterwards
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	d.queue.Close()
+	d.storeQueue.Close()
+	for _, publishers := range [][]Publisher{d.publishers, d.stores} {
+		for _, p := range publishers {
+			if closer, ok := p.(io.Closer); ok {
+				closer.Close()
+			}
+		}
+	}
+}
+
+func publishTo(publishers []Publisher, msg *Message) {
+	for _, p := range publishers {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		if err := p.Publish(ctx, msg); err != nil {
+			log.Error("cannot publish message", "type", msg.Type, "message", err)
+		}
+		cancel()
+	}
+}
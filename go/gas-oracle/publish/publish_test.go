@@ -0,0 +1,229 @@
+package publish
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type chanPublisher chan *Message
+
+func (c chanPublisher) Publish(ctx context.Context, msg *Message) error {
+	c <- msg
+	return nil
+}
+
+func TestDispatcher(t *testing.T) {
+	messages := make(chanPublisher, 1)
+	d := NewDispatcher(nil, messages)
+	d.Publish(&Message{Type: MessageDecision})
+	select {
+	case msg := <-messages:
+		if msg.Type != MessageDecision {
+			t.Fatalf("unexpected message %s", msg.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not published")
+	}
+	d.Close()
+
+	// A nil dispatcher drops messages
+	var nilDispatcher *Dispatcher
+	nilDispatcher.Publish(&Message{Type: MessageDecision})
+	nilDispatcher.Close()
+}
+
+func TestDispatcherStores(t *testing.T) {
+	// Neither the store nor the bus receive until the test reads
+	store, bus := make(chanPublisher), make(chanPublisher)
+	d := NewDispatcher([]Publisher{store}, bus)
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < 2*queueSize; i++ {
+			d.Publish(&Message{Type: MessageDecision})
+		}
+	}()
+	count := 0
+	for count < 2*queueSize {
+		select {
+		case <-store:
+			count++
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected every message to be stored, got %d", count)
+		}
+	}
+	<-published
+	// The bus dropped the messages that did not fit in its queue
+	go func() {
+		for range bus {
+		}
+	}()
+	d.Close()
+	close(bus)
+}
+
+func TestNew(t *testing.T) {
+	for _, rawURL := range []string{"nats://localhost", "tls://nats.internal:4222"} {
+		if p, err := New(rawURL, "gas-oracle.decisions"); err != nil || p.(*NATS).subject != "gas-oracle.decisions" {
+			t.Fatalf("unexpected publisher of %s: %v %v", rawURL, p, err)
+		}
+	}
+	if p, err := New("https://kafka:8082/", "gas-oracle"); err != nil ||
+		p.(*KafkaREST).url != "https://kafka:8082/topics/gas-oracle" {
+		t.Fatalf("unexpected publisher %v %v", p, err)
+	}
+	if _, err := New("kafka://localhost:9092", "gas-oracle"); err == nil {
+		t.Fatal("expected an error for an unsupported scheme")
+	}
+	if _, err := New("nats://localhost", ""); err == nil {
+		t.Fatal("expected an error without a topic")
+	}
+	for _, subject := range []string{"gas oracle", "gas-oracle\r\nPUB other 2", "gas-oracle.>", "gas-oracle..decisions", "gas-oracle."} {
+		if _, err := New("nats://localhost", subject); err == nil {
+			t.Fatalf("expected an error for the subject %q", subject)
+		}
+	}
+}
+
+func TestKafkaREST(t *testing.T) {
+	var body struct {
+		Records []struct {
+			Key   string   `json:"key"`
+			Value *Message `json:"value"`
+		} `json:"records"`
+	}
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/gas-oracle" || r.Header.Get("Content-Type") != kafkaJSONContentType {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		if fail {
+			w.Write([]byte(`{"offsets": [{"partition": null, "offset": null, "error_code": 50002, "error": "timeout"}]}`))
+			return
+		}
+		w.Write([]byte(`{"offsets": [{"partition": 0, "offset": 1, "error_code": null, "error": null}]}`))
+	}))
+	defer server.Close()
+
+	k := NewKafkaREST(server.URL, "gas-oracle")
+	msg := &Message{Type: MessageTransaction, ChainID: big.NewInt(10),
+		Transaction: &Transaction{Kind: "l2_gas_price", Hash: "0x01", Value: big.NewInt(100)}}
+	if err := k.Publish(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Records) != 1 || body.Records[0].Key != "10" ||
+		body.Records[0].Value.Transaction.Value.Uint64() != 100 {
+		t.Fatalf("unexpected records %+v", body.Records)
+	}
+	fail = true
+	if err := k.Publish(context.Background(), msg); err == nil {
+		t.Fatal("expected the error of the record")
+	}
+}
+
+// newFakeNATS accepts a connection and sends the lines of the client to
+// received. The publishes to the rejected subject are answered with a
+// permissions violation.
+func newFakeNATS(t *testing.T, rejected string) (string, chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	received := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n"))
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "CONNECT "):
+				received <- line
+			case line == "PING\r\n":
+				conn.Write([]byte("PONG\r\n"))
+			case strings.HasPrefix(line, "PUB "):
+				payload, _ := r.ReadString('\n')
+				if strings.HasPrefix(line, "PUB "+rejected+" ") {
+					conn.Write([]byte("-ERR 'Permissions Violation for Publish to \"" + rejected + "\"'\r\n"))
+					continue
+				}
+				received <- line + payload
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestNATS(t *testing.T) {
+	addr, received := newFakeNATS(t, "")
+	n, err := NewNATS("nats://user:pass@"+addr, "gas-oracle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := n.Publish(ctx, &Message{Type: MessageDecision, ChainID: big.NewInt(10)}); err != nil {
+		t.Fatal(err)
+	}
+
+	next := func() string {
+		select {
+		case line := <-received:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out")
+			return ""
+		}
+	}
+	if connect := next(); !strings.Contains(connect, `"user":"user"`) || !strings.Contains(connect, `"pass":"pass"`) {
+		t.Fatalf("unexpected connect %q", connect)
+	}
+	line := next()
+	if !strings.HasPrefix(line, "PUB gas-oracle ") {
+		t.Fatalf("unexpected line %q", line)
+	}
+	payload := strings.SplitN(line, "\r\n", 2)[1]
+	var msg Message
+	if err := json.Unmarshal([]byte(strings.TrimSpace(payload)), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Type != MessageDecision || msg.ChainID.Uint64() != 10 {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+}
+
+func TestNATSRejected(t *testing.T) {
+	addr, _ := newFakeNATS(t, "gas-oracle")
+	n, err := NewNATS("nats://"+addr, "gas-oracle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if err := n.Publish(ctx, &Message{Type: MessageDecision}); err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+			t.Fatalf("expected the rejection of the publish, got %v", err)
+		}
+	}
+}
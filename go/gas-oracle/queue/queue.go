@@ -0,0 +1,54 @@
+// Package queue delivers the items of the oracle, such as notifications and
+// published messages, in the background
+package queue
+
+// Queue delivers items in the background so that a slow receiver does not
+// delay the oracle. The items are delivered in order by a single goroutine.
+type Queue struct {
+	deliver func(item interface{})
+	items   chan interface{}
+	done    chan struct{}
+}
+
+// New creates a Queue that holds up to size items and starts delivering
+// them with deliver
+func New(size int, deliver func(item interface{})) *Queue {
+	q := &Queue{
+		deliver: deliver,
+		items:   make(chan interface{}, size),
+		done:    make(chan struct{}),
+	}
+	go q.loop()
+	return q
+}
+
+// Push queues an item for delivery. It returns false when the queue is full
+// and the item is dropped.
+func (q *Queue) Push(item interface{}) bool {
+	select {
+	case q.items <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait queues an item for delivery, waiting for room when the queue is full,
+// for the items that must not be dropped
+func (q *Queue) Wait(item interface{}) {
+	q.items <- item
+}
+
+// Close stops accepting items and waits for the queued items to be
+// delivered
+func (q *Queue) Close() {
+	close(q.items)
+	<-q.done
+}
+
+func (q *Queue) loop() {
+	defer close(q.done)
+	for item := range q.items {
+		q.deliver(item)
+	}
+}
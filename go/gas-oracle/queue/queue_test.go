@@ -0,0 +1,39 @@
+package queue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	var delivered []interface{}
+	started := make(chan struct{}, 1)
+	block := make(chan struct{})
+	q := New(2, func(item interface{}) {
+		started <- struct{}{}
+		<-block
+		delivered = append(delivered, item)
+	})
+	if !q.Push(0) {
+		t.Fatal("expected the first item to be queued")
+	}
+	// The delivery holds the first item, the queue holds the next two and
+	// the last one is dropped
+	<-started
+	for i, expected := range []bool{true, true, false} {
+		if queued := q.Push(i + 1); queued != expected {
+			t.Fatalf("item %d: expected queued %t, got %t", i+1, expected, queued)
+		}
+	}
+	close(block)
+	go func() {
+		for range started {
+		}
+	}()
+	// The queued items are delivered before Close returns
+	q.Close()
+	close(started)
+	if !reflect.DeepEqual(delivered, []interface{}{0, 1, 2}) {
+		t.Fatalf("unexpected deliveries %v", delivered)
+	}
+}
@@ -0,0 +1,98 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/getsentry/sentry-go"
+)
+
+// flushTimeout bounds the time spent sending buffered reports on exit
+const flushTimeout = 5 * time.Second
+
+// Setup initializes the Sentry client with the DSN. The tags describe the
+// configuration of the oracle and are attached to every report. The
+// returned function sends the buffered reports. Until Setup is called
+// nothing is reported.
+func Setup(dsn, environment, release string, tags map[string]string) (func(), error) {
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              dsn,
+		Environment:      environment,
+		Release:          release,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sentry.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+	})
+	return func() { sentry.Flush(flushTimeout) }, nil
+}
+
+// Recover reports a panic along with its stack trace and then panics again
+// so that the process still exits. It must be deferred at the top of each
+// goroutine.
+func Recover() {
+	if r := recover(); r != nil {
+		sentry.CurrentHub().Recover(r)
+		sentry.Flush(flushTimeout)
+		panic(r)
+	}
+}
+
+// Sentry reports the events of repeated error conditions: epochs that keep
+// failing, a signer that is not the owner and a low signer balance. A single
+// failed epoch is not reported since it is usually transient.
+type Sentry struct {
+	hub *sentry.Hub
+	// failureThreshold is the number of consecutive failed epochs that is
+	// reported
+	failureThreshold uint64
+}
+
+// NewSentry creates a Sentry that reports to the client set up by Setup
+func NewSentry(failureThreshold uint64) *Sentry {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &Sentry{
+		hub:              sentry.CurrentHub(),
+		failureThreshold: failureThreshold,
+	}
+}
+
+// Notify reports the event when it is an error condition
+func (s *Sentry) Notify(ctx context.Context, event *notify.Event) error {
+	var message string
+	switch event.Type {
+	case notify.EventUpdateFailed:
+		if event.ConsecutiveFailures != s.failureThreshold {
+			return nil
+		}
+		message = fmt.Sprintf("gas price update failed for %d consecutive epochs: %s",
+			event.ConsecutiveFailures, event.Error)
+	case notify.EventOwnerMismatch:
+		message = fmt.Sprintf("signer %s is not the owner %s", event.Signer, event.Owner)
+	case notify.EventLowBalance:
+		message = fmt.Sprintf("signer %s balance %s is below %s", event.Signer, event.Balance, event.Threshold)
+	case notify.EventStaleUpdate:
+		message = fmt.Sprintf("no gas price update landed for %d epochs", event.EpochsSinceUpdate)
+	default:
+		return nil
+	}
+
+	s.hub.WithScope(func(scope *sentry.Scope) {
+		// Group reports of the same condition regardless of the message
+		scope.SetFingerprint([]string{string(event.Type)})
+		scope.SetTag("event", string(event.Type))
+		if event.ChainID != nil {
+			scope.SetTag("chain_id", event.ChainID.String())
+		}
+		scope.SetLevel(sentry.LevelError)
+		s.hub.CaptureMessage(message)
+	})
+	return nil
+}
@@ -0,0 +1,61 @@
+package reporting
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/notify"
+	"github.com/getsentry/sentry-go"
+)
+
+type mockTransport struct {
+	events []*sentry.Event
+}
+
+func (m *mockTransport) Configure(options sentry.ClientOptions) {}
+
+func (m *mockTransport) SendEvent(event *sentry.Event) {
+	m.events = append(m.events, event)
+}
+
+func (m *mockTransport) Flush(timeout time.Duration) bool {
+	return true
+}
+
+func TestSentry(t *testing.T) {
+	transport := new(mockTransport)
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSentry(3)
+	s.hub = sentry.NewHub(client, sentry.NewScope())
+
+	events := []*notify.Event{
+		{Type: notify.EventUpdateFailed, ConsecutiveFailures: 1},
+		{Type: notify.EventUpdateFailed, ConsecutiveFailures: 3, Error: "timeout", ChainID: big.NewInt(10)},
+		{Type: notify.EventUpdateSent},
+		{Type: notify.EventOwnerMismatch, Signer: "0xaa", Owner: "0xbb"},
+	}
+	for _, event := range events {
+		if err := s.Notify(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(transport.events) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(transport.events))
+	}
+	failed := transport.events[0]
+	if failed.Message != "gas price update failed for 3 consecutive epochs: timeout" {
+		t.Fatalf("unexpected message %q", failed.Message)
+	}
+	if failed.Tags["chain_id"] != "10" || failed.Fingerprint[0] != "update_failed" {
+		t.Fatalf("unexpected report %+v", failed)
+	}
+	if transport.events[1].Tags["event"] != "owner_mismatch" {
+		t.Fatalf("unexpected report %+v", transport.events[1])
+	}
+}
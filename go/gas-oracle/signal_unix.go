@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// handlePauseSignal toggles the pause of the updates of every chain on
+// SIGUSR1
+func handlePauseSignal(chains *oracle.ChainSet) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	go func() {
+		for range signals {
+			log.Info("Received SIGUSR1, toggling the pause")
+			for _, gpo := range chains.Oracles() {
+				gpo.TogglePause()
+			}
+		}
+	}()
+}
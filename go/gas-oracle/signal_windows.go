@@ -0,0 +1,6 @@
+package main
+
+import "github.com/ethereum-optimism/optimism/go/gas-oracle/oracle"
+
+// handlePauseSignal does nothing since there is no SIGUSR1 on Windows
+func handlePauseSignal(chains *oracle.ChainSet) {}
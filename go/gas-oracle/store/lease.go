@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PostgresLease is a lease in the leases table of a PostgreSQL database. The
+// expiry is kept by the clock of the database, so that the replicas do not
+// depend on their own clocks.
+type PostgresLease struct {
+	db   *Postgres
+	name string
+}
+
+// OpenPostgresLease connects to the database of the postgres:// url, which is
+// migrated like the database of the records, and uses the lease of the name
+func OpenPostgresLease(ctx context.Context, url, name string) (*PostgresLease, error) {
+	db, err := OpenPostgres(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresLease{db: db, name: name}, nil
+}
+
+// Acquire takes the lease for the holder when it is free or expired, or
+// renews it when the holder has it, and returns true when the holder has the
+// lease for the ttl
+func (l *PostgresLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	return l.held(ctx, `INSERT INTO leases (name, holder, expires_at)
+		VALUES ($1, $2, now() + $3::bigint * interval '1 millisecond')
+		ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+		WHERE leases.holder = EXCLUDED.holder OR leases.expires_at < now()
+		RETURNING holder`, l.name, holder, ttl.Milliseconds())
+}
+
+// Release gives up the lease when the holder has it, so that another
+// replica does not wait for it to expire
+func (l *PostgresLease) Release(ctx context.Context, holder string) error {
+	_, err := l.db.db.ExecContext(ctx, "DELETE FROM leases WHERE name = $1 AND holder = $2", l.name, holder)
+	return err
+}
+
+// SaveState replaces the state of the lease when the holder has it. The
+// lease is checked in the same statement, so that a holder whose lease
+// expired does not overwrite the state.
+func (l *PostgresLease) SaveState(ctx context.Context, holder string, state []byte) (bool, error) {
+	return l.held(ctx, `INSERT INTO lease_states (name, state, updated_at)
+		SELECT name, $1::text, now() FROM leases WHERE name = $2 AND holder = $3 AND expires_at > now()
+		ON CONFLICT (name) DO UPDATE SET state = EXCLUDED.state, updated_at = EXCLUDED.updated_at
+		RETURNING name`, string(state), l.name, holder)
+}
+
+// held runs a statement that returns a row when the holder has the lease
+func (l *PostgresLease) held(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var returned string
+	err := l.db.db.QueryRowContext(ctx, query, args...).Scan(&returned)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// LoadState returns the state of the lease, it is nil when no state was
+// saved
+func (l *PostgresLease) LoadState(ctx context.Context) ([]byte, error) {
+	var state string
+	err := l.db.db.QueryRowContext(ctx, "SELECT state FROM lease_states WHERE name = $1", l.name).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(state), nil
+}
+
+// Close closes the connections
+func (l *PostgresLease) Close() error {
+	return l.db.Close()
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// leaseServer answers the statements of the lease like a database where the
+// lease never expires
+type leaseServer struct {
+	recorder
+	mu     sync.Mutex
+	holder string
+	state  *string
+}
+
+func (s *leaseServer) handle(query string, args []driver.Value) ([][]driver.Value, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case strings.HasPrefix(query, "INSERT INTO leases"):
+		if holder := args[1].(string); s.holder == "" || s.holder == holder {
+			s.holder = holder
+			return [][]driver.Value{{s.holder}}, nil
+		}
+		return nil, nil
+	case strings.HasPrefix(query, "DELETE FROM leases"):
+		if s.holder == args[1].(string) {
+			s.holder = ""
+		}
+		return nil, nil
+	case strings.HasPrefix(query, "INSERT INTO lease_states"):
+		if s.holder == "" || s.holder != args[2].(string) {
+			return nil, nil
+		}
+		state := args[0].(string)
+		s.state = &state
+		return [][]driver.Value{{args[1]}}, nil
+	case strings.HasPrefix(query, "SELECT state FROM lease_states"):
+		if s.state == nil {
+			return nil, nil
+		}
+		return [][]driver.Value{{*s.state}}, nil
+	}
+	return s.recorder.handle(query, args)
+}
+
+func TestPostgresLease(t *testing.T) {
+	s := new(leaseServer)
+	ctx := context.Background()
+	lease, err := OpenPostgresLease(ctx, newFakeDatabase(t, s.handle), "gas-oracle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease.Close()
+
+	for _, step := range []struct {
+		holder string
+		held   bool
+	}{
+		{"a", true},
+		// The holder renews the lease that the other replica cannot take
+		{"b", false},
+		{"a", true},
+	} {
+		held, err := lease.Acquire(ctx, step.holder, 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held != step.held {
+			t.Fatalf("expected %s to hold the lease: %t, got %t", step.holder, step.held, held)
+		}
+	}
+	if err := lease.Release(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if held, err := lease.Acquire(ctx, "b", 10*time.Second); err != nil || !held {
+		t.Fatalf("expected the released lease to be taken, got %t %v", held, err)
+	}
+}
+
+func TestPostgresLeaseState(t *testing.T) {
+	s := new(leaseServer)
+	ctx := context.Background()
+	lease, err := OpenPostgresLease(ctx, newFakeDatabase(t, s.handle), "gas-oracle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease.Close()
+
+	if state, err := lease.LoadState(ctx); err != nil || state != nil {
+		t.Fatalf("expected no state, got %q %v", state, err)
+	}
+	if _, err := lease.Acquire(ctx, "a", 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	// Only the holder saves the state
+	if saved, err := lease.SaveState(ctx, "b", []byte(`{"epoch":1}`)); err != nil || saved {
+		t.Fatalf("expected the state of b to be rejected, got %t %v", saved, err)
+	}
+	if saved, err := lease.SaveState(ctx, "a", []byte(`{"epoch":2}`)); err != nil || !saved {
+		t.Fatalf("expected the state of a to be saved, got %t %v", saved, err)
+	}
+	if state, err := lease.LoadState(ctx); err != nil || string(state) != `{"epoch":2}` {
+		t.Fatalf("unexpected state %q %v", state, err)
+	}
+}
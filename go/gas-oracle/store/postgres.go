@@ -0,0 +1,279 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/publish"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// migrationLock is the key of the advisory lock that serializes the
+// migrations of oracles that start at the same time
+const migrationLock = 0x6761736f7261636c
+
+// migrations are the versions of the schema, a migration is never changed
+// once it is released
+var migrations = []string{
+	// 1: epochs, transactions and receipts
+	`CREATE TABLE epochs (
+		id BIGSERIAL PRIMARY KEY,
+		chain_id NUMERIC,
+		time TIMESTAMPTZ NOT NULL,
+		avg_gas_per_second DOUBLE PRECISION NOT NULL,
+		current_price NUMERIC,
+		computed_price NUMERIC,
+		gas_price NUMERIC,
+		change DOUBLE PRECISION NOT NULL,
+		significance_factor DOUBLE PRECISION NOT NULL,
+		send BOOLEAN NOT NULL,
+		reason TEXT NOT NULL,
+		bound TEXT NOT NULL
+	);
+	CREATE INDEX epochs_time_idx ON epochs (time);
+	CREATE TABLE transactions (
+		hash TEXT PRIMARY KEY,
+		chain_id NUMERIC,
+		kind TEXT NOT NULL,
+		time TIMESTAMPTZ NOT NULL,
+		nonce BIGINT NOT NULL,
+		gas_price NUMERIC,
+		value NUMERIC
+	);
+	CREATE INDEX transactions_time_idx ON transactions (time);
+	CREATE TABLE receipts (
+		hash TEXT PRIMARY KEY,
+		time TIMESTAMPTZ NOT NULL,
+		block_number BIGINT NOT NULL,
+		gas_used BIGINT NOT NULL,
+		status BIGINT NOT NULL
+	);
+	CREATE INDEX receipts_block_number_idx ON receipts (block_number);`,
+	// 2: leases of the replicas in high availability mode
+	`CREATE TABLE leases (
+		name TEXT PRIMARY KEY,
+		holder TEXT NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL
+	);`,
+	// 3: state that the leader hands off to the next leader
+	`CREATE TABLE lease_states (
+		name TEXT PRIMARY KEY,
+		state TEXT NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	);`,
+}
+
+// Postgres records the epochs, transactions and receipts of the oracle in a
+// PostgreSQL database. It is a publish.Publisher, so that it is written in
+// the background like a message bus. The connections of the pool are opened
+// again after they fail.
+type Postgres struct {
+	db *sql.DB
+}
+
+// OpenPostgres connects to the database of the postgres:// url and migrates
+// its schema to the latest version
+func OpenPostgres(ctx context.Context, url string) (*Postgres, error) {
+	if err := CheckURL(url); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(postgresDriver, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot migrate postgres: %w", err)
+	}
+	return &Postgres{db: db}, nil
+}
+
+// Close closes the connections
+func (p *Postgres) Close() error {
+	return p.db.Close()
+}
+
+// migrate applies the migrations that the database does not have yet, each
+// in its own transaction. The advisory lock is held by the session, so the
+// migrations run on a single connection.
+func migrate(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", int64(migrationLock)); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", int64(migrationLock))
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return err
+	}
+	var current int
+	if err := conn.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current); err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("the database schema version %d is newer than this binary, %d", current, len(migrations))
+	}
+	for version := current + 1; version <= len(migrations); version++ {
+		log.Info("Migrating postgres schema", "version", version)
+		if err := applyMigration(ctx, conn, version); err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs a migration and records its version in a transaction
+func applyMigration(ctx context.Context, conn *sql.Conn, version int) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// A migration has several statements, which are only accepted without
+	// parameters
+	if _, err := tx.ExecContext(ctx, migrations[version-1]); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// decisionRecord holds the fields of the decision of an epoch that are
+// stored
+type decisionRecord struct {
+	AvgGasPerSecond    float64  `json:"avgGasPerSecond"`
+	CurrentPrice       *big.Int `json:"currentPrice"`
+	ComputedPrice      *big.Int `json:"computedPrice"`
+	GasPrice           *big.Int `json:"gasPrice"`
+	Change             float64  `json:"change"`
+	SignificanceFactor float64  `json:"significanceFactor"`
+	Send               bool     `json:"send"`
+	Reason             string   `json:"reason"`
+	Bound              string   `json:"bound"`
+}
+
+// Publish inserts the epoch, transaction or receipt of the message
+func (p *Postgres) Publish(ctx context.Context, msg *publish.Message) error {
+	var query string
+	var args []interface{}
+	switch msg.Type {
+	case publish.MessageDecision:
+		data, err := json.Marshal(msg.Decision)
+		if err != nil {
+			return err
+		}
+		var d decisionRecord
+		if err := json.Unmarshal(data, &d); err != nil {
+			return err
+		}
+		query = `INSERT INTO epochs (chain_id, time, avg_gas_per_second, current_price,
+			computed_price, gas_price, change, significance_factor, send, reason, bound)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+		args = []interface{}{numeric(msg.ChainID), msg.Time.UTC(), d.AvgGasPerSecond, numeric(d.CurrentPrice),
+			numeric(d.ComputedPrice), numeric(d.GasPrice), d.Change, d.SignificanceFactor,
+			d.Send, d.Reason, d.Bound}
+	case publish.MessageTransaction:
+		tx := msg.Transaction
+		query = `INSERT INTO transactions (hash, chain_id, kind, time, nonce, gas_price, value)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (hash) DO NOTHING`
+		args = []interface{}{tx.Hash, numeric(msg.ChainID), tx.Kind, msg.Time.UTC(), int64(tx.Nonce),
+			numeric(tx.GasPrice), numeric(tx.Value)}
+	case publish.MessageReceipt:
+		r := msg.Receipt
+		query = `INSERT INTO receipts (hash, time, block_number, gas_used, status)
+			VALUES ($1, $2, $3, $4, $5) ON CONFLICT (hash) DO NOTHING`
+		args = []interface{}{r.Hash, msg.Time.UTC(), int64(r.BlockNumber), int64(r.GasUsed), int64(r.Status)}
+	default:
+		return nil
+	}
+	_, err := p.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// Update is an update transaction of the oracle that was mined
+type Update struct {
+	Kind        string
+	Hash        string
+	BlockNumber uint64
+	// Time is when the receipt was seen by the oracle
+	Time time.Time
+	// OldValue is the value of the previous update of the kind that is
+	// recorded, it is nil for the first one
+	OldValue *big.Int
+	NewValue *big.Int
+}
+
+// Updates returns the successful update transactions that were mined from
+// start to end, in the order of their blocks
+func (p *Postgres) Updates(ctx context.Context, start, end uint64) ([]*Update, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT kind, hash, block_number, EXTRACT(EPOCH FROM time), old_value, value FROM (
+			SELECT t.kind, t.hash, r.block_number, r.time, t.value, t.nonce,
+				LAG(t.value) OVER (PARTITION BY t.kind ORDER BY r.block_number, t.nonce) AS old_value
+			FROM transactions t JOIN receipts r ON r.hash = t.hash
+			WHERE r.status = 1
+		) updates
+		WHERE block_number BETWEEN $1 AND $2
+		ORDER BY block_number, nonce`, clampInt64(start), clampInt64(end))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var updates []*Update
+	for rows.Next() {
+		u := new(Update)
+		var seconds float64
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(&u.Kind, &u.Hash, &u.BlockNumber, &seconds, &oldValue, &newValue); err != nil {
+			return nil, err
+		}
+		u.Time = time.Unix(0, int64(seconds*1e9)).UTC()
+		u.OldValue, u.NewValue = parseNumeric(oldValue), parseNumeric(newValue)
+		updates = append(updates, u)
+	}
+	return updates, rows.Err()
+}
+
+// numeric returns the parameter of a NUMERIC column, which is sent as its
+// decimal text
+func numeric(v *big.Int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return v.String()
+}
+
+// clampInt64 returns a block number as a BIGINT parameter, the blocks above
+// the largest BIGINT cannot be stored
+func clampInt64(v uint64) int64 {
+	if v > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(v)
+}
+
+func parseNumeric(s sql.NullString) *big.Int {
+	if !s.Valid {
+		return nil
+	}
+	n, ok := new(big.Int).SetString(s.String, 10)
+	if !ok {
+		return nil
+	}
+	return n
+}
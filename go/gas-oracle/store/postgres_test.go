@@ -0,0 +1,338 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/go/gas-oracle/publish"
+)
+
+// handler answers a statement with the rows of its result
+type handler func(query string, args []driver.Value) ([][]driver.Value, error)
+
+// fakeDriver is a database/sql driver that passes the statements to the
+// handler of the database of the url
+type fakeDriver struct{}
+
+var (
+	fakeMu        sync.Mutex
+	fakeDatabases = make(map[string]handler)
+	fakeCount     int
+)
+
+func init() {
+	sql.Register("fake-postgres", fakeDriver{})
+	postgresDriver = "fake-postgres"
+}
+
+// newFakeDatabase returns the url of a database that is answered by handle
+func newFakeDatabase(t *testing.T, handle handler) string {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	fakeCount++
+	name := fmt.Sprintf("db%d", fakeCount)
+	fakeDatabases[name] = handle
+	t.Cleanup(func() {
+		fakeMu.Lock()
+		defer fakeMu.Unlock()
+		delete(fakeDatabases, name)
+	})
+	return "postgres://oracle@fake/" + name
+}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	handle, ok := fakeDatabases[strings.TrimPrefix(u.Path, "/")]
+	if !ok {
+		return nil, errors.New("unknown database")
+	}
+	return &fakeConn{handle: handle}, nil
+}
+
+type fakeConn struct {
+	handle handler
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not prepared")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	if _, err := c.handle("BEGIN", nil); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	_, err := c.handle("COMMIT", nil)
+	return err
+}
+
+func (c *fakeConn) Rollback() error {
+	_, err := c.handle("ROLLBACK", nil)
+	return err
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	rows, err := c.handle(query, values(args))
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(len(rows)), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.handle(query, values(args))
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{rows: rows}, nil
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	vs := make([]driver.Value, len(args))
+	for i, arg := range args {
+		vs[i] = arg.Value
+	}
+	return vs
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// statement is a statement that the store ran with its parameters
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+// recorder answers the statements of the store like an empty database and
+// records them
+type recorder struct {
+	mu         sync.Mutex
+	statements []statement
+	version    int64
+	updates    [][]driver.Value
+}
+
+func (r *recorder) handle(query string, args []driver.Value) ([][]driver.Value, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, statement{query: query, args: args})
+	switch {
+	case strings.HasPrefix(query, "SELECT COALESCE(MAX(version), 0)"):
+		return [][]driver.Value{{r.version}}, nil
+	case strings.HasPrefix(query, "SELECT kind, hash"):
+		return r.updates, nil
+	}
+	return nil, nil
+}
+
+func (r *recorder) find(prefix string) []statement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []statement
+	for _, s := range r.statements {
+		if strings.HasPrefix(strings.TrimSpace(s.query), prefix) {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+func TestOpenPostgresMigrates(t *testing.T) {
+	r := &recorder{}
+	p, err := OpenPostgres(context.Background(), newFakeDatabase(t, r.handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+	if locks := r.find("SELECT pg_advisory_lock($1)"); len(locks) != 1 || locks[0].args[0] != int64(migrationLock) {
+		t.Fatalf("unexpected locks %v", locks)
+	}
+	applied := r.find("INSERT INTO schema_migrations")
+	if len(applied) != len(migrations) || len(r.find("COMMIT")) != len(migrations) {
+		t.Fatalf("expected %d migrations, got %d", len(migrations), len(applied))
+	}
+	for i, s := range applied {
+		if !reflect.DeepEqual(s.args, []driver.Value{int64(i + 1)}) {
+			t.Fatalf("unexpected version %v", s.args)
+		}
+	}
+	if len(r.find("CREATE TABLE epochs")) != 1 {
+		t.Fatal("expected the first migration to create the epochs")
+	}
+
+	// A migrated database is left as is
+	r = &recorder{version: int64(len(migrations))}
+	p, err = OpenPostgres(context.Background(), newFakeDatabase(t, r.handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+	if applied := r.find("INSERT INTO schema_migrations"); len(applied) != 0 {
+		t.Fatalf("unexpected migrations %v", applied)
+	}
+
+	// A database of a newer binary is refused
+	r = &recorder{version: 100}
+	if _, err := OpenPostgres(context.Background(), newFakeDatabase(t, r.handle)); err == nil {
+		t.Fatal("expected an error for a newer schema")
+	}
+
+	// A failed migration is rolled back
+	failing := func(query string, args []driver.Value) ([][]driver.Value, error) {
+		if strings.Contains(query, "CREATE TABLE leases") {
+			return nil, errors.New("permission denied")
+		}
+		return r.handle(query, args)
+	}
+	r = &recorder{version: 1}
+	if _, err := OpenPostgres(context.Background(), newFakeDatabase(t, failing)); err == nil {
+		t.Fatal("expected an error for a failed migration")
+	}
+	if len(r.find("ROLLBACK")) != 1 || len(r.find("INSERT INTO schema_migrations")) != 0 {
+		t.Fatal("expected the failed migration to be rolled back")
+	}
+}
+
+func TestPostgresPublish(t *testing.T) {
+	r := &recorder{version: int64(len(migrations))}
+	p, err := OpenPostgres(context.Background(), newFakeDatabase(t, r.handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	decision := map[string]interface{}{
+		"avgGasPerSecond": 22_000_000,
+		"currentPrice":    big.NewInt(1000),
+		"computedPrice":   big.NewInt(1100),
+		"gasPrice":        big.NewInt(1100),
+		"change":          0.1,
+		"send":            true,
+		"reason":          "it's significant",
+		"bound":           "none",
+	}
+	messages := []*publish.Message{
+		{Type: publish.MessageDecision, Time: now, ChainID: big.NewInt(10), Decision: decision},
+		{Type: publish.MessageTransaction, Time: now, ChainID: big.NewInt(10), Transaction: &publish.Transaction{
+			Kind: "l2_gas_price", Hash: "0x01", Nonce: 3, GasPrice: big.NewInt(1), Value: big.NewInt(1100),
+		}},
+		{Type: publish.MessageReceipt, Time: now, Receipt: &publish.Receipt{
+			Hash: "0x01", BlockNumber: 7, GasUsed: 30_000, Status: 1,
+		}},
+	}
+	for _, msg := range messages {
+		if err := p.Publish(context.Background(), msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The values are bound as parameters rather than formatted in the query
+	for _, tt := range []struct {
+		prefix string
+		args   []driver.Value
+	}{
+		{"INSERT INTO epochs", []driver.Value{"10", now, 2.2e7, "1000", "1100", "1100", 0.1, 0.0, true, "it's significant", "none"}},
+		{"INSERT INTO transactions", []driver.Value{"0x01", "10", "l2_gas_price", now, int64(3), "1", "1100"}},
+		{"INSERT INTO receipts", []driver.Value{"0x01", now, int64(7), int64(30_000), int64(1)}},
+	} {
+		found := r.find(tt.prefix)
+		if len(found) != 1 || !reflect.DeepEqual(found[0].args, tt.args) {
+			t.Fatalf("unexpected %s: %v", tt.prefix, found)
+		}
+	}
+}
+
+func TestPostgresUpdates(t *testing.T) {
+	r := &recorder{version: int64(len(migrations)), updates: [][]driver.Value{
+		{"l2_gas_price", "0x01", int64(7), []byte("1640995200.5"), nil, []byte("1100")},
+		{"l1_base_fee", "0x02", int64(8), []byte("1640995210"), []byte("20"), []byte("30")},
+	}}
+	p, err := OpenPostgres(context.Background(), newFakeDatabase(t, r.handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	updates, err := p.Updates(context.Background(), 5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(updates))
+	}
+	first := updates[0]
+	if first.Kind != "l2_gas_price" || first.BlockNumber != 7 || first.OldValue != nil || first.NewValue.Uint64() != 1100 ||
+		!first.Time.Equal(time.Date(2022, 1, 1, 0, 0, 0, 5e8, time.UTC)) {
+		t.Fatalf("unexpected update %+v", first)
+	}
+	if updates[1].OldValue.Uint64() != 20 || updates[1].NewValue.Uint64() != 30 {
+		t.Fatalf("unexpected update %+v", updates[1])
+	}
+	if q := r.find("SELECT kind, hash"); len(q) != 1 || !reflect.DeepEqual(q[0].args, []driver.Value{int64(5), int64(10)}) {
+		t.Fatalf("unexpected query %v", q)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, url := range []string{
+		"postgres://oracle@localhost/oracle",
+		"postgresql://oracle:secret@db:5433/?sslmode=verify-full",
+		"postgres://oracle@db/oracle?sslmode=verify-ca&sslrootcert=/etc/ca.pem",
+	} {
+		if err := CheckURL(url); err != nil {
+			t.Fatalf("%s: %v", url, err)
+		}
+	}
+	for _, url := range []string{
+		"mysql://oracle@localhost",
+		"postgres://localhost/oracle",
+		"postgres://oracle@localhost?sslmode=allow",
+		"postgres://oracle@localhost?sslmode=prefer",
+	} {
+		if err := CheckURL(url); err == nil {
+			t.Fatalf("%s: expected an error", url)
+		}
+	}
+}
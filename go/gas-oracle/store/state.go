@@ -0,0 +1,218 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+var (
+	// stateKey is the key of the state in the database
+	stateKey = []byte("state")
+	// pricePrefix is the prefix of the keys of the price history
+	pricePrefix = []byte("price/")
+	// decisionPrefix is the prefix of the keys of the decisions
+	decisionPrefix = []byte("decision/")
+)
+
+// State is the state of the oracle that is kept across restarts, so that a
+// restarted oracle continues the epoch that was running
+type State struct {
+	ChainID *big.Int `json:"chainId"`
+	// Address is the address of the gas price oracle contract
+	Address string `json:"address"`
+	// EpochStartBlockNumber is the last block of the previous epoch
+	EpochStartBlockNumber uint64    `json:"epochStartBlockNumber"`
+	EpochStartTime        time.Time `json:"epochStartTime"`
+	// GasPrice is the gas price of the gas pricer, which differs from the
+	// gas price on chain when the last change was not significant
+	GasPrice *big.Int `json:"gasPrice"`
+	// OnChainGasPrice is the gas price on chain when the state was saved
+	OnChainGasPrice *big.Int `json:"onChainGasPrice"`
+	// SmoothingSamples are the demands of the recent epochs of the
+	// smoothing filter
+	SmoothingSamples []float64 `json:"smoothingSamples,omitempty"`
+	// LastSentGasPrice is the gas price of the last update transaction
+	// that was confirmed
+	LastSentGasPrice *big.Int `json:"lastSentGasPrice,omitempty"`
+	// PendingTransaction is an update transaction that was signed and is
+	// not confirmed yet
+	PendingTransaction *PendingTransaction `json:"pendingTransaction,omitempty"`
+}
+
+// PendingTransaction is an update transaction that was signed, it is saved
+// before it is sent so that a restart knows about it
+type PendingTransaction struct {
+	Hash     string    `json:"hash"`
+	Nonce    uint64    `json:"nonce"`
+	GasPrice *big.Int  `json:"gasPrice"`
+	SignedAt time.Time `json:"signedAt"`
+}
+
+// PricePoint is the gas price of the gas pricer and on chain at the end of
+// an epoch
+type PricePoint struct {
+	Time            time.Time `json:"time"`
+	GasPrice        *big.Int  `json:"gasPrice"`
+	OnChainGasPrice *big.Int  `json:"onChainGasPrice"`
+}
+
+// StateDB keeps the State, the price history and the decisions in an
+// embedded LevelDB database
+type StateDB struct {
+	db *leveldb.DB
+}
+
+// OpenStateDB opens the database in the directory, it is created when it
+// does not exist
+func OpenStateDB(path string) (*StateDB, error) {
+	return openStateDB(path, nil)
+}
+
+// OpenStateDBReadOnly opens an existing database without writing to it. It
+// fails while an oracle has the database open.
+func OpenStateDBReadOnly(path string) (*StateDB, error) {
+	return openStateDB(path, &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+}
+
+func openStateDB(path string, options *opt.Options) (*StateDB, error) {
+	db, err := leveldb.OpenFile(path, options)
+	if err != nil {
+		return nil, err
+	}
+	return &StateDB{db: db}, nil
+}
+
+// Load returns the saved state, it is nil when no state was saved
+func (s *StateDB) Load() (*State, error) {
+	data, err := s.db.Get(stateKey, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := new(State)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save replaces the saved state, the write is synced so that it survives a
+// crash
+func (s *StateDB) Save(state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(stateKey, data, &opt.WriteOptions{Sync: true})
+}
+
+// AppendPrice adds a point to the price history
+func (s *StateDB) AppendPrice(p *PricePoint) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(recordKey(pricePrefix, p.Time), data, nil)
+}
+
+// Prices returns the points of the price history from from to to, oldest
+// first
+func (s *StateDB) Prices(from, to time.Time) ([]*PricePoint, error) {
+	var points []*PricePoint
+	err := s.records(pricePrefix, from, to, func(data []byte) error {
+		p := new(PricePoint)
+		if err := json.Unmarshal(data, p); err != nil {
+			return err
+		}
+		points = append(points, p)
+		return nil
+	})
+	return points, err
+}
+
+// PrunePrices deletes the points of the price history and the decisions
+// before the time
+func (s *StateDB) PrunePrices(before time.Time) error {
+	batch := new(leveldb.Batch)
+	for _, prefix := range [][]byte{pricePrefix, decisionPrefix} {
+		it := s.db.NewIterator(&util.Range{Start: prefix, Limit: recordKey(prefix, before)}, nil)
+		for it.Next() {
+			batch.Delete(append([]byte(nil), it.Key()...))
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return err
+		}
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	return s.db.Write(batch, nil)
+}
+
+// AppendDecision adds the JSON record of the decision of an epoch at the
+// time
+func (s *StateDB) AppendDecision(t time.Time, record json.RawMessage) error {
+	return s.db.Put(recordKey(decisionPrefix, t), record, nil)
+}
+
+// Decisions returns the JSON records of the decisions from from to to,
+// oldest first
+func (s *StateDB) Decisions(from, to time.Time) ([]json.RawMessage, error) {
+	var records []json.RawMessage
+	err := s.records(decisionPrefix, from, to, func(data []byte) error {
+		records = append(records, append(json.RawMessage(nil), data...))
+		return nil
+	})
+	return records, err
+}
+
+// LastDecision returns the JSON record of the most recent decision, nil
+// when there is none
+func (s *StateDB) LastDecision() (json.RawMessage, error) {
+	it := s.db.NewIterator(util.BytesPrefix(decisionPrefix), nil)
+	defer it.Release()
+	if !it.Last() {
+		return nil, it.Error()
+	}
+	return append(json.RawMessage(nil), it.Value()...), nil
+}
+
+// records calls fn with the values of the prefix from from to to
+func (s *StateDB) records(prefix []byte, from, to time.Time, fn func([]byte) error) error {
+	it := s.db.NewIterator(&util.Range{Start: recordKey(prefix, from), Limit: recordKey(prefix, to.Add(time.Nanosecond))}, nil)
+	defer it.Release()
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// recordKey is the prefix followed by the big endian unix nanoseconds of
+// the time, so that the records are ordered by time
+func recordKey(prefix []byte, t time.Time) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	nanos := t.UnixNano()
+	if nanos < 0 {
+		nanos = 0
+	}
+	binary.BigEndian.PutUint64(key[len(prefix):], uint64(nanos))
+	return key
+}
+
+// Close closes the database
+func (s *StateDB) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestStateDB(t *testing.T) {
+	path := t.TempDir()
+	db, err := OpenStateDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := db.Load()
+	if err != nil || state != nil {
+		t.Fatalf("expected no state, got %+v, %v", state, err)
+	}
+
+	saved := &State{
+		ChainID:               big.NewInt(10),
+		EpochStartBlockNumber: 100,
+		EpochStartTime:        time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		GasPrice:              big.NewInt(1100),
+		OnChainGasPrice:       big.NewInt(1000),
+		SmoothingSamples:      []float64{1, 2, 3},
+	}
+	if err := db.Save(saved); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The state is read back after reopening
+	db, err = OpenStateDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	state, err = db.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.EpochStartBlockNumber != 100 || state.GasPrice.Uint64() != 1100 || len(state.SmoothingSamples) != 3 ||
+		!state.EpochStartTime.Equal(saved.EpochStartTime) || state.ChainID.Uint64() != 10 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+}
+
+func TestStateDBPrices(t *testing.T) {
+	db, err := OpenStateDB(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		err := db.AppendPrice(&PricePoint{
+			Time:            start.Add(time.Duration(i) * time.Minute),
+			GasPrice:        big.NewInt(int64(1000 + i)),
+			OnChainGasPrice: big.NewInt(1000),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The state is not part of the history
+	if err := db.Save(&State{EpochStartBlockNumber: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := db.Prices(start.Add(2*time.Minute), start.Add(4*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 || points[0].GasPrice.Uint64() != 1002 || points[2].GasPrice.Uint64() != 1004 {
+		t.Fatalf("unexpected points %+v", points)
+	}
+
+	if err := db.PrunePrices(start.Add(5 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	points, err = db.Prices(start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 5 || points[0].GasPrice.Uint64() != 1005 {
+		t.Fatalf("unexpected points after pruning %+v", points)
+	}
+	if state, err := db.Load(); err != nil || state.EpochStartBlockNumber != 1 {
+		t.Fatalf("unexpected state %+v, %v", state, err)
+	}
+}
+
+func TestStateDBDecisions(t *testing.T) {
+	path := t.TempDir()
+	db, err := OpenStateDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, record := range []string{`{"reason":"UPDATED"}`, `{"reason":"UNCHANGED"}`, `{"reason":"FLOORED"}`} {
+		if err := db.AppendDecision(start.Add(time.Duration(i)*time.Minute), json.RawMessage(record)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.PrunePrices(start.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db, err = OpenStateDBReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	records, err := db.Decisions(start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || string(records[0]) != `{"reason":"UNCHANGED"}` {
+		t.Fatalf("unexpected decisions %s", records)
+	}
+	if last, err := db.LastDecision(); err != nil || string(last) != `{"reason":"FLOORED"}` {
+		t.Fatalf("unexpected last decision %s, %v", last, err)
+	}
+	if _, err := OpenStateDBReadOnly(t.TempDir() + "/missing"); err == nil {
+		t.Fatal("expected an error for a missing database")
+	}
+}
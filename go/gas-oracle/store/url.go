@@ -0,0 +1,38 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	// Registers the postgres driver of database/sql
+	_ "github.com/lib/pq"
+)
+
+// postgresDriver is the database/sql driver that the postgres:// urls are
+// opened with
+var postgresDriver = "postgres"
+
+// CheckURL returns an error when the url cannot be connected to. The sslmode
+// is one of the driver: disable, require, which is the default and only
+// verifies the certificate of the server against an sslrootcert, verify-ca
+// and verify-full. There is no prefer, which would fall back to a plain
+// connection.
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("unsupported scheme %q, expected postgres", u.Scheme)
+	}
+	if u.User.Username() == "" {
+		return errors.New("no user in the postgres url")
+	}
+	switch mode := u.Query().Get("sslmode"); mode {
+	case "", "disable", "require", "verify-ca", "verify-full":
+	default:
+		return fmt.Errorf("unsupported sslmode %q, expected disable, require, verify-ca or verify-full", mode)
+	}
+	return nil
+}